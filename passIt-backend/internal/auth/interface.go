@@ -14,9 +14,13 @@ type KeycloakClient interface {
 	
 	// User management in Keycloak (auth only)
 	CreateKeycloakUser(ctx context.Context, user *models.User, password string) (string, error)
+	// SendVerifyEmail asks Keycloak to email the user a link verifying their email address
+	SendVerifyEmail(ctx context.Context, keycloakUserID string) error
 	UpdateKeycloakUser(ctx context.Context, user *models.User) error
 	UpdatePassword(ctx context.Context, keycloakUserID string, newPassword string) error
 	DeleteKeycloakUser(ctx context.Context, userID string) error
+	// SyncKeycloakUserRoles assigns the Keycloak roles mapped onto the PassIt roles of the user
+	SyncKeycloakUserRoles(ctx context.Context, keycloakUserID string, roles []string) error
 }
 
 // Ensure Client implements KeycloakClient
@@ -42,6 +42,8 @@ type Config struct {
 	AdminUsername string // keycloak admin username
 	AdminPassword string // keycloak admin password
 	FrontendURL   string // frontend URL for redirects
+
+	RoleMapping RoleMapping // Keycloak roles granting PassIt roles, empty to only use roles assigned in PassIt
 }
 
 // Client struct holds all components needed for authentication
@@ -218,6 +220,32 @@ func (c *Client) DeleteKeycloakUser(ctx context.Context, userID string) error {
 	return nil
 }
 
+// SendVerifyEmail sends the Keycloak email verification link to the user
+func (c *Client) SendVerifyEmail(ctx context.Context, keycloakUserID string) error {
+	realm := c.Config.Realm
+
+	// Admin login to Keycloak
+	token, err := c.Client.LoginAdmin(
+		ctx,
+		c.Config.AdminUsername,
+		c.Config.AdminPassword,
+		realm,
+	)
+	if err != nil {
+		return fmt.Errorf("keycloak admin login failed: %w", err)
+	}
+
+	err = c.Client.SendVerifyEmail(ctx, token.AccessToken, keycloakUserID, realm, gocloak.SendVerificationMailParams{
+		ClientID:    gocloak.StringP(c.Config.ClientID),
+		RedirectURI: gocloak.StringP(c.FrontendURL),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	return nil
+}
+
 // UpdatePassword updates a user's password in Keycloak
 func (c *Client) UpdatePassword(ctx context.Context, keycloakUserID string, newPassword string) error {
 	realm := c.Config.Realm
@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Nerzal/gocloak/v13"
+)
+
+// KeycloakRole is a realm role, or a role of the PassIt client when Client is set
+type KeycloakRole struct {
+	Client bool
+	Name   string
+}
+
+func (r KeycloakRole) String() string {
+	if r.Client {
+		return "client:" + r.Name
+	}
+	return "realm:" + r.Name
+}
+
+// RoleMapping maps Keycloak roles onto the names of PassIt roles. Users get the PassIt
+// roles of the Keycloak roles in their access token on top of the roles assigned in PassIt.
+type RoleMapping map[KeycloakRole]string
+
+// ParseRoleMapping reads a mapping like "realm:passit-admin=admin,client:door=scanner".
+// Roles without a realm: or client: prefix are realm roles. An empty string disables the mapping.
+func ParseRoleMapping(value string) (RoleMapping, error) {
+	mapping := RoleMapping{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(entry, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid role mapping %q, expected keycloak-role=passit-role", entry)
+		}
+
+		role := KeycloakRole{Name: from}
+		if name, found := strings.CutPrefix(from, "client:"); found {
+			role = KeycloakRole{Client: true, Name: name}
+		} else if name, found := strings.CutPrefix(from, "realm:"); found {
+			role.Name = name
+		}
+		if role.Name == "" {
+			return nil, fmt.Errorf("invalid role mapping %q, missing keycloak role name", entry)
+		}
+		mapping[role] = to
+	}
+	return mapping, nil
+}
+
+// RolesFromClaims returns the PassIt roles mapped from the realm roles and the roles of
+// the client in the claims of a verified access token
+func (m RoleMapping) RolesFromClaims(claims map[string]any, clientID string) []string {
+	if len(m) == 0 {
+		return nil
+	}
+
+	var roles []string
+	add := func(access any, client bool) {
+		holder, _ := access.(map[string]any)
+		list, _ := holder["roles"].([]any)
+		for _, item := range list {
+			name, _ := item.(string)
+			if role, ok := m[KeycloakRole{Client: client, Name: name}]; ok {
+				roles = append(roles, role)
+			}
+		}
+	}
+	add(claims["realm_access"], false)
+	if resources, ok := claims["resource_access"].(map[string]any); ok {
+		add(resources[clientID], true)
+	}
+
+	slices.Sort(roles)
+	return slices.Compact(roles)
+}
+
+// KeycloakRoles splits the mapped Keycloak roles into those a user with the PassIt
+// roles should have and those they should not
+func (m RoleMapping) KeycloakRoles(roles []string) (grant, revoke []KeycloakRole) {
+	for keycloakRole, role := range m {
+		if slices.Contains(roles, role) {
+			grant = append(grant, keycloakRole)
+		} else {
+			revoke = append(revoke, keycloakRole)
+		}
+	}
+	byName := func(a, b KeycloakRole) int { return strings.Compare(a.String(), b.String()) }
+	slices.SortFunc(grant, byName)
+	slices.SortFunc(revoke, byName)
+	return grant, revoke
+}
+
+// SyncKeycloakUserRoles gives the user the Keycloak roles mapped onto their PassIt roles
+// and takes away the other mapped ones. Keycloak roles outside the mapping are left alone.
+func (c *Client) SyncKeycloakUserRoles(ctx context.Context, keycloakUserID string, roles []string) error {
+	if len(c.Config.RoleMapping) == 0 {
+		return nil
+	}
+	realm := c.Config.Realm
+
+	// Admin login to Keycloak
+	token, err := c.Client.LoginAdmin(
+		ctx,
+		c.Config.AdminUsername,
+		c.Config.AdminPassword,
+		realm,
+	)
+	if err != nil {
+		return fmt.Errorf("keycloak admin login failed: %w", err)
+	}
+
+	grant, revoke := c.Config.RoleMapping.KeycloakRoles(roles)
+	var realmGrant, realmRevoke, clientGrant, clientRevoke []string
+	for _, role := range grant {
+		if role.Client {
+			clientGrant = append(clientGrant, role.Name)
+		} else {
+			realmGrant = append(realmGrant, role.Name)
+		}
+	}
+	for _, role := range revoke {
+		if role.Client {
+			clientRevoke = append(clientRevoke, role.Name)
+		} else {
+			realmRevoke = append(realmRevoke, role.Name)
+		}
+	}
+
+	// Realm roles
+	current, err := c.Client.GetRealmRolesByUserID(ctx, token.AccessToken, realm, keycloakUserID)
+	if err != nil {
+		return fmt.Errorf("failed to get realm roles of user: %w", err)
+	}
+	add, remove, err := roleChanges(current, realmGrant, realmRevoke, func(name string) (*gocloak.Role, error) {
+		return c.Client.GetRealmRole(ctx, token.AccessToken, realm, name)
+	})
+	if err != nil {
+		return err
+	}
+	if len(add) > 0 {
+		if err := c.Client.AddRealmRoleToUser(ctx, token.AccessToken, realm, keycloakUserID, add); err != nil {
+			return fmt.Errorf("failed to add realm roles to user: %w", err)
+		}
+	}
+	if len(remove) > 0 {
+		if err := c.Client.DeleteRealmRoleFromUser(ctx, token.AccessToken, realm, keycloakUserID, remove); err != nil {
+			return fmt.Errorf("failed to remove realm roles from user: %w", err)
+		}
+	}
+
+	// Roles of the PassIt client
+	if len(clientGrant) == 0 && len(clientRevoke) == 0 {
+		return nil
+	}
+	clients, err := c.Client.GetClients(ctx, token.AccessToken, realm, gocloak.GetClientsParams{ClientID: gocloak.StringP(c.Config.ClientID)})
+	if err != nil {
+		return fmt.Errorf("failed to get keycloak client: %w", err)
+	}
+	if len(clients) == 0 || clients[0].ID == nil {
+		return fmt.Errorf("keycloak client %s not found", c.Config.ClientID)
+	}
+	idOfClient := *clients[0].ID
+
+	current, err = c.Client.GetClientRolesByUserID(ctx, token.AccessToken, realm, idOfClient, keycloakUserID)
+	if err != nil {
+		return fmt.Errorf("failed to get client roles of user: %w", err)
+	}
+	add, remove, err = roleChanges(current, clientGrant, clientRevoke, func(name string) (*gocloak.Role, error) {
+		return c.Client.GetClientRole(ctx, token.AccessToken, realm, idOfClient, name)
+	})
+	if err != nil {
+		return err
+	}
+	if len(add) > 0 {
+		if err := c.Client.AddClientRolesToUser(ctx, token.AccessToken, realm, idOfClient, keycloakUserID, add); err != nil {
+			return fmt.Errorf("failed to add client roles to user: %w", err)
+		}
+	}
+	if len(remove) > 0 {
+		if err := c.Client.DeleteClientRolesFromUser(ctx, token.AccessToken, realm, idOfClient, keycloakUserID, remove); err != nil {
+			return fmt.Errorf("failed to remove client roles from user: %w", err)
+		}
+	}
+	return nil
+}
+
+// roleChanges compares the current roles of a user with the wanted ones, looking up
+// the roles to add so Keycloak gets their IDs
+func roleChanges(current []*gocloak.Role, grant, revoke []string, lookup func(name string) (*gocloak.Role, error)) (add, remove []gocloak.Role, err error) {
+	has := map[string]*gocloak.Role{}
+	for _, role := range current {
+		if role != nil && role.Name != nil {
+			has[*role.Name] = role
+		}
+	}
+
+	for _, name := range grant {
+		if _, ok := has[name]; ok {
+			continue
+		}
+		role, err := lookup(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get keycloak role %s: %w", name, err)
+		}
+		add = append(add, *role)
+	}
+	for _, name := range revoke {
+		if role, ok := has[name]; ok {
+			remove = append(remove, *role)
+		}
+	}
+	return add, remove, nil
+}
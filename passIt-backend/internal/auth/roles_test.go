@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRoleMapping(t *testing.T) {
+	mapping, err := ParseRoleMapping(" realm:passit-admin=admin, client:door = scanner,support=support ")
+	require.NoError(t, err)
+	assert.Equal(t, RoleMapping{
+		{Name: "passit-admin"}:           "admin",
+		{Client: true, Name: "door"}:     "scanner",
+		{Client: false, Name: "support"}: "support",
+	}, mapping)
+
+	empty, err := ParseRoleMapping("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestParseRoleMapping_Invalid(t *testing.T) {
+	for _, value := range []string{"admin", "realm:admin=", "=admin", "client:=scanner"} {
+		t.Run(value, func(t *testing.T) {
+			_, err := ParseRoleMapping(value)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRoleMapping_RolesFromClaims(t *testing.T) {
+	mapping := RoleMapping{
+		{Name: "passit-admin"}:       "admin",
+		{Name: "support"}:            "support",
+		{Client: true, Name: "door"}: "scanner",
+		{Client: true, Name: "ops"}:  "support",
+	}
+	claims := map[string]any{
+		"realm_access": map[string]any{"roles": []any{"offline_access", "passit-admin"}},
+		"resource_access": map[string]any{
+			"passit":       map[string]any{"roles": []any{"door", "ops"}},
+			"other-client": map[string]any{"roles": []any{"support"}},
+		},
+	}
+
+	assert.Equal(t, []string{"admin", "scanner", "support"}, mapping.RolesFromClaims(claims, "passit"))
+	assert.Equal(t, []string{"admin"}, mapping.RolesFromClaims(claims, "other-client"),
+		"client roles only count for the PassIt client")
+	assert.Empty(t, mapping.RolesFromClaims(map[string]any{}, "passit"))
+	assert.Empty(t, RoleMapping{}.RolesFromClaims(claims, "passit"))
+}
+
+func TestRoleMapping_KeycloakRoles(t *testing.T) {
+	mapping := RoleMapping{
+		{Name: "passit-admin"}:       "admin",
+		{Client: true, Name: "door"}: "scanner",
+		{Client: true, Name: "ops"}:  "support",
+	}
+
+	grant, revoke := mapping.KeycloakRoles([]string{"scanner", "support", "unmapped"})
+	assert.Equal(t, []KeycloakRole{{Client: true, Name: "door"}, {Client: true, Name: "ops"}}, grant)
+	assert.Equal(t, []KeycloakRole{{Name: "passit-admin"}}, revoke)
+
+	grant, revoke = mapping.KeycloakRoles(nil)
+	assert.Empty(t, grant)
+	assert.Len(t, revoke, 3)
+}
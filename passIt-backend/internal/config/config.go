@@ -6,11 +6,17 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	//  "strconv"
 
 	"passIt/internal/auth"
 	"passIt/internal/database"
+	"passIt/internal/invoices"
+	"passIt/internal/payments"
+	"passIt/internal/tickets"
+	"passIt/internal/waitingroom"
 
 	"github.com/joho/godotenv"
 	"github.com/redis/go-redis/v9"
@@ -21,11 +27,16 @@ type Config struct {
 	Auth        *auth.Config
 	DB          *database.DBConfig
 	RedisClient *redis.Options
+	Payments    *payments.Config
+	Tickets     *tickets.Config
+	Invoices    *invoices.Config
+	WaitingRoom *waitingroom.Config
 }
 type AppConfig struct {
 	Port                   int
 	ENV                    string
 	FrontendURL            string
+	TrustedProxies         []string // addresses or CIDRs allowed to set X-Forwarded-For, none by default
 	BootstrapAdminUsername string
 	BootstrapAdminEmail    string
 	BootstrapAdminPassword string
@@ -56,11 +67,35 @@ func LoadFromEnv() (*Config, error) {
 	if err != nil {
 		log.Fatal("failed to convert PORT to int")
 	}
+
+	var fakePaymentDelay time.Duration
+	if value := os.Getenv("PAYMENT_FAKE_DELAY"); value != "" {
+		fakePaymentDelay, err = time.ParseDuration(value)
+		if err != nil {
+			log.Fatal("failed to parse PAYMENT_FAKE_DELAY")
+		}
+	}
+	var allowFakePayments bool
+	if value := os.Getenv("PAYMENT_ALLOW_FAKE"); value != "" {
+		allowFakePayments, err = strconv.ParseBool(value)
+		if err != nil {
+			log.Fatal("failed to parse PAYMENT_ALLOW_FAKE")
+		}
+	}
+	env := requireEnv("ENV")
+	// Development fallbacks that would lose money or invalidate tickets are refused in production
+	production := env == "production"
+
+	roleMapping, err := auth.ParseRoleMapping(os.Getenv("KEYCLOAK_ROLE_MAPPING"))
+	if err != nil {
+		log.Fatalf("failed to parse KEYCLOAK_ROLE_MAPPING: %v", err)
+	}
 	return &Config{
 		App: &AppConfig{
 			Port:                   port,
-			ENV:                    requireEnv("ENV"),
+			ENV:                    env,
 			FrontendURL:            requireEnv("FRONTEND_URL"),
+			TrustedProxies:         getEnvList("TRUSTED_PROXIES"),         // Optional, e.g. 10.0.0.0/8,127.0.0.1
 			BootstrapAdminUsername: os.Getenv("BOOTSTRAP_ADMIN_USERNAME"), // Optional
 			BootstrapAdminEmail:    os.Getenv("BOOTSTRAP_ADMIN_EMAIL"),    // Optional
 			BootstrapAdminPassword: os.Getenv("BOOTSTRAP_ADMIN_PASSWORD"), // Optional
@@ -82,6 +117,7 @@ func LoadFromEnv() (*Config, error) {
 			AdminUsername: requireEnv("KEYCLOAK_ADMIN_USERNAME"),
 			AdminPassword: requireEnv("KEYCLOAK_ADMIN_PASSWORD"),
 			FrontendURL:   requireEnv("FRONTEND_URL"),
+			RoleMapping:   roleMapping, // Optional, e.g. realm:passit-admin=admin,client:door=scanner
 		},
 		RedisClient: &redis.Options{
 			Addr:     fmt.Sprintf("%s:%s", requireEnv("REDIS_HOST"), requireEnv("REDIS_PORT")),
@@ -89,6 +125,27 @@ func LoadFromEnv() (*Config, error) {
 			Password: requireEnv("REDIS_PASSWORD"),
 			DB:       redisDB,
 		},
+		Payments: &payments.Config{
+			Provider:      getEnv("PAYMENT_PROVIDER", "fake"),
+			WebhookSecret: os.Getenv("PAYMENT_WEBHOOK_SECRET"), // Optional for the fake provider
+			WebhookURL:    getEnv("PAYMENT_WEBHOOK_URL", fmt.Sprintf("http://localhost:%d/webhooks/payments", port)),
+			FakeDelay:     fakePaymentDelay,
+			Production:    production,
+			AllowFake:     allowFakePayments,
+		},
+		Tickets: &tickets.Config{
+			SigningKey: os.Getenv("TICKET_SIGNING_KEY"), // Optional in development
+			Production: production,
+		},
+		Invoices: &invoices.Config{
+			IssuerName:    getEnv("INVOICE_ISSUER_NAME", "PassIt"),
+			IssuerAddress: os.Getenv("INVOICE_ISSUER_ADDRESS"), // Optional
+			IssuerTaxID:   os.Getenv("INVOICE_ISSUER_TAX_ID"),  // Optional
+		},
+		WaitingRoom: &waitingroom.Config{
+			SigningKey: os.Getenv("QUEUE_SIGNING_KEY"), // Optional in development
+			Production: production,
+		},
 	}, nil
 }
 
@@ -99,3 +156,22 @@ func requireEnv(key string) string {
 	}
 	return value
 }
+
+// getEnvList returns the comma separated values of an optional environment variable
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnv returns the value of an optional environment variable or the fallback when it is unset
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
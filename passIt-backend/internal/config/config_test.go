@@ -25,6 +25,26 @@ func TestRequireEnv_ReturnsValue(t *testing.T) {
 	assert.Equal(t, testValue, result)
 }
 
+func TestGetEnv_Fallback(t *testing.T) {
+	testKey := "TEST_OPTIONAL_VAR_12345"
+
+	assert.Equal(t, "fallback", getEnv(testKey, "fallback"))
+
+	os.Setenv(testKey, "value")
+	defer os.Unsetenv(testKey)
+	assert.Equal(t, "value", getEnv(testKey, "fallback"))
+}
+
+func TestGetEnvList(t *testing.T) {
+	testKey := "TEST_LIST_VAR_12345"
+
+	assert.Empty(t, getEnvList(testKey))
+
+	os.Setenv(testKey, " 10.0.0.0/8, ,127.0.0.1 ")
+	defer os.Unsetenv(testKey)
+	assert.Equal(t, []string{"10.0.0.0/8", "127.0.0.1"}, getEnvList(testKey))
+}
+
 func TestConfig_Structure(t *testing.T) {
 	// Test that Config struct has expected structure
 	cfg := &Config{
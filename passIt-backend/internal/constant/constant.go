@@ -7,4 +7,40 @@ import "time"
 var (
 	// SessionDuration defines the duration for which a session is valid
 	SessionDuration = time.Duration(0.5 * float64(time.Hour))
+
+	// HoldDuration defines how long selected tickets stay reserved for a buyer
+	HoldDuration = 10 * time.Minute
+
+	// HoldReaperInterval defines how often expired holds are returned to sale
+	HoldReaperInterval = 15 * time.Second
+
+	// OrderPaymentWindow defines how long an order waits for payment before it expires
+	OrderPaymentWindow = 15 * time.Minute
+
+	// OrderExpiryInterval defines how often unpaid orders are checked for expiry
+	OrderExpiryInterval = 30 * time.Second
+
+	// TicketIssueInterval defines how often paid orders without tickets are fulfilled again
+	TicketIssueInterval = time.Minute
+
+	// WaitlistOfferWindow defines how long tickets offered to the next person on a waitlist stay reserved
+	WaitlistOfferWindow = 30 * time.Minute
+
+	// WaitlistInterval defines how often freed inventory is offered to waitlists and lapsed offers are passed on
+	WaitlistInterval = 15 * time.Second
+
+	// OrderReviewWindow defines how long an order routed to manual review waits for an admin before it expires
+	OrderReviewWindow = 2 * time.Hour
+
+	// WaitingRoomRetention defines how long a waiting room queue is kept in Redis after it closes
+	WaitingRoomRetention = time.Hour
+
+	// AvailabilityHeartbeat defines how often an idle availability stream sends a comment to keep the connection open
+	AvailabilityHeartbeat = 15 * time.Second
+
+	// AvailabilityStreamDuration defines how long an availability stream stays open, clients reconnect and authenticate again
+	AvailabilityStreamDuration = 5 * time.Minute
+
+	// ResaleFeeBasisPoints defines the share of a resale price kept by the platform, in basis points
+	ResaleFeeBasisPoints int64 = 1000
 )
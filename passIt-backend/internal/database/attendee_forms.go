@@ -0,0 +1,109 @@
+package database
+
+import (
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// AttendeeFormStore is the persistence contract for attendee forms and their answers.
+// Answers are stored together with their order.
+type AttendeeFormStore interface {
+	// SaveAttendeeForm creates the form or replaces the questions of an existing one
+	SaveAttendeeForm(form *models.AttendeeForm) error
+
+	FindAttendeeFormById(id uuid.UUID) (models.AttendeeForm, error)
+
+	// FindAttendeeForm returns the form of a ticket type, or the event-wide form when ticketTypeID is nil
+	FindAttendeeForm(eventID uuid.UUID, ticketTypeID *uuid.UUID) (models.AttendeeForm, error)
+
+	ListAttendeeFormsByEvent(eventID uuid.UUID) ([]models.AttendeeForm, error)
+
+	DeleteAttendeeForm(id uuid.UUID) error
+
+	// ListAttendeeAnswersByEvent returns the answers of the paid orders of an event with
+	// the ticket each answer belongs to
+	ListAttendeeAnswersByEvent(eventID uuid.UUID) ([]models.AttendeeAnswerRow, error)
+}
+
+func (s *service) SaveAttendeeForm(form *models.AttendeeForm) error {
+	var err error
+	if form.ID == uuid.Nil {
+		err = s.GetGormDB().Create(form).Error
+	} else {
+		err = s.GetGormDB().Model(form).Select("fields").Updates(form).Error
+	}
+	if err != nil {
+		log.Println("Error saving attendee form:", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) FindAttendeeFormById(id uuid.UUID) (models.AttendeeForm, error) {
+	var form models.AttendeeForm
+	result := s.GetGormDB().First(&form, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding attendee form by ID:", result.Error)
+		return models.AttendeeForm{}, result.Error
+	}
+	return form, nil
+}
+
+func (s *service) FindAttendeeForm(eventID uuid.UUID, ticketTypeID *uuid.UUID) (models.AttendeeForm, error) {
+	var form models.AttendeeForm
+	query := s.GetGormDB().Where("event_id = ?", eventID)
+	if ticketTypeID == nil {
+		query = query.Where("ticket_type_id IS NULL")
+	} else {
+		query = query.Where("ticket_type_id = ?", *ticketTypeID)
+	}
+	result := query.First(&form)
+	if result.Error != nil {
+		return models.AttendeeForm{}, result.Error
+	}
+	return form, nil
+}
+
+func (s *service) ListAttendeeFormsByEvent(eventID uuid.UUID) ([]models.AttendeeForm, error) {
+	var forms []models.AttendeeForm
+	result := s.GetGormDB().Where("event_id = ?", eventID).
+		Order("ticket_type_id IS NOT NULL, created_at ASC").
+		Find(&forms)
+	if result.Error != nil {
+		log.Println("Error listing attendee forms:", result.Error)
+		return nil, result.Error
+	}
+	return forms, nil
+}
+
+func (s *service) DeleteAttendeeForm(id uuid.UUID) error {
+	result := s.GetGormDB().Delete(&models.AttendeeForm{}, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error deleting attendee form:", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *service) ListAttendeeAnswersByEvent(eventID uuid.UUID) ([]models.AttendeeAnswerRow, error) {
+	var rows []models.AttendeeAnswerRow
+	result := s.GetGormDB().Model(&models.AttendeeAnswers{}).
+		Select(`attendee_answers.order_id, users.email AS buyer_email,
+			attendee_answers.ticket_type_id, order_items.name AS ticket_type, attendee_answers.number,
+			tickets.id AS ticket_id, tickets.status AS ticket_status, attendee_answers.answers`).
+		Joins("JOIN orders ON orders.id = attendee_answers.order_id").
+		Joins("JOIN order_items ON order_items.id = attendee_answers.order_item_id").
+		Joins("JOIN users ON users.id = orders.user_id").
+		Joins("LEFT JOIN tickets ON tickets.order_item_id = attendee_answers.order_item_id AND tickets.number = attendee_answers.number").
+		Where("attendee_answers.event_id = ? AND orders.status IN ?", eventID,
+			[]models.OrderStatus{models.OrderStatusPaid, models.OrderStatusFulfilled}).
+		Order("orders.paid_at ASC, attendee_answers.order_id, order_items.created_at, order_items.id, attendee_answers.number").
+		Scan(&rows)
+	if result.Error != nil {
+		log.Println("Error listing attendee answers:", result.Error)
+		return nil, result.Error
+	}
+	return rows, nil
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"log"
+	"passIt/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BoxOfficeStore is the persistence contract for box office reconciliation. Box office
+// orders and payments are stored through the order and payment stores.
+type BoxOfficeStore interface {
+	// BoxOfficeReport sums up the box office payments captured in [from, to) per staff
+	// member, payment method and currency, optionally only for the events of an
+	// organization or the payments of one staff member
+	BoxOfficeReport(from, to time.Time, orgID, staffID *uuid.UUID) ([]models.BoxOfficeReportLine, error)
+}
+
+func (s *service) BoxOfficeReport(from, to time.Time, orgID, staffID *uuid.UUID) ([]models.BoxOfficeReportLine, error) {
+	query := s.GetGormDB().Model(&models.Payment{}).
+		Select(`payments.collected_by_id AS staff_id, users.email AS staff_email,
+			payments.method AS method, payments.currency AS currency,
+			COUNT(payments.id) AS orders,
+			COALESCE(SUM((SELECT SUM(quantity) FROM order_items WHERE order_items.order_id = orders.id)), 0) AS tickets,
+			COALESCE(SUM(payments.amount), 0) AS amount,
+			COALESCE(SUM(orders.refunded), 0) AS refunded`).
+		Joins("JOIN orders ON orders.id = payments.order_id").
+		Joins("LEFT JOIN users ON users.id = payments.collected_by_id").
+		Where("payments.provider = ? AND payments.status IN ?", models.BoxOfficeProvider,
+			[]models.PaymentStatus{models.PaymentStatusCaptured, models.PaymentStatusRefunded}).
+		Where("payments.captured_at >= ? AND payments.captured_at < ?", from, to)
+	if orgID != nil {
+		query = query.Joins("JOIN events ON events.id = orders.event_id").Where("events.organization_id = ?", *orgID)
+	}
+	if staffID != nil {
+		query = query.Where("payments.collected_by_id = ?", *staffID)
+	}
+
+	var lines []models.BoxOfficeReportLine
+	result := query.
+		Group("payments.collected_by_id, users.email, payments.method, payments.currency").
+		Order("users.email, payments.method, payments.currency").
+		Scan(&lines)
+	if result.Error != nil {
+		log.Println("Error building box office report:", result.Error)
+		return nil, result.Error
+	}
+	return lines, nil
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"passIt/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckInStore is the persistence contract for ticket scans at the door
+type CheckInStore interface {
+	// MarkTicketUsed admits a valid ticket of the given version and reports whether it did.
+	// Only one of several concurrent scans of the same ticket succeeds.
+	MarkTicketUsed(ticketID uuid.UUID, version int, usedAt time.Time) (bool, error)
+
+	// MarkPassUsed admits a valid pass ticket of the given version to one of the events it
+	// admits to and reports whether it did. A pass is admitted once per event.
+	MarkPassUsed(ticketID, eventID uuid.UUID, version int, usedAt time.Time) (bool, error)
+
+	CreateCheckIn(checkIn *models.CheckIn) error
+
+	// FindCheckInByClientScan returns an uploaded scan by the IDs the scanner gave it
+	FindCheckInByClientScan(deviceID, clientScanID string) (models.CheckIn, error)
+
+	// ListTicketsByEvent returns every ticket issued for an event
+	ListTicketsByEvent(eventID uuid.UUID) ([]models.Ticket, error)
+
+	// ListPassTicketsByEvent returns the tickets of every pass admitting to an event,
+	// including passes sold on it
+	ListPassTicketsByEvent(eventID uuid.UUID) ([]models.Ticket, error)
+
+	ListPassAdmissionsByEvent(eventID uuid.UUID) ([]models.PassAdmission, error)
+}
+
+func (s *service) MarkTicketUsed(ticketID uuid.UUID, version int, usedAt time.Time) (bool, error) {
+	result := s.GetGormDB().Model(&models.Ticket{}).
+		Where("id = ? AND version = ? AND status = ?", ticketID, version, models.TicketStatusValid).
+		Updates(map[string]interface{}{
+			"status":  models.TicketStatusUsed,
+			"used_at": usedAt,
+		})
+	if result.Error != nil {
+		log.Println("Error marking ticket used:", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (s *service) MarkPassUsed(ticketID, eventID uuid.UUID, version int, usedAt time.Time) (bool, error) {
+	result := s.GetGormDB().Exec(`INSERT INTO pass_admissions (ticket_id, event_id, used_at)
+		SELECT id, ?, ? FROM tickets WHERE id = ? AND version = ? AND status = ?
+		ON CONFLICT DO NOTHING`, eventID, usedAt, ticketID, version, models.TicketStatusValid)
+	if result.Error != nil {
+		log.Println("Error marking pass used:", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (s *service) CreateCheckIn(checkIn *models.CheckIn) error {
+	result := s.GetGormDB().Create(checkIn)
+	if result.Error != nil {
+		log.Println("Error creating check-in:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no rows affected, check-in not created")
+	}
+	return nil
+}
+
+func (s *service) FindCheckInByClientScan(deviceID, clientScanID string) (models.CheckIn, error) {
+	var checkIn models.CheckIn
+	result := s.GetGormDB().First(&checkIn, "device_id = ? AND client_scan_id = ?", deviceID, clientScanID)
+	if result.Error != nil {
+		return models.CheckIn{}, result.Error
+	}
+	return checkIn, nil
+}
+
+func (s *service) ListTicketsByEvent(eventID uuid.UUID) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	result := s.GetGormDB().Where("event_id = ?", eventID).Order("issued_at ASC, id ASC").Find(&tickets)
+	if result.Error != nil {
+		log.Println("Error listing event tickets:", result.Error)
+		return nil, result.Error
+	}
+	return tickets, nil
+}
+
+func (s *service) ListPassTicketsByEvent(eventID uuid.UUID) ([]models.Ticket, error) {
+	var event models.Event
+	if err := s.GetGormDB().First(&event, "id = ?", eventID).Error; err != nil {
+		return nil, err
+	}
+	passes, err := seriesPasses(s.GetGormDB(), event)
+	if err != nil {
+		log.Println("Error listing series passes:", err)
+		return nil, err
+	}
+	var passIDs []uuid.UUID
+	for _, pass := range passes {
+		if pass.EventID == eventID || pass.Admits(eventID) {
+			passIDs = append(passIDs, pass.ID)
+		}
+	}
+	if len(passIDs) == 0 {
+		return nil, nil
+	}
+
+	var tickets []models.Ticket
+	result := s.GetGormDB().Where("ticket_type_id IN ?", passIDs).Order("issued_at ASC, id ASC").Find(&tickets)
+	if result.Error != nil {
+		log.Println("Error listing pass tickets:", result.Error)
+		return nil, result.Error
+	}
+	return tickets, nil
+}
+
+func (s *service) ListPassAdmissionsByEvent(eventID uuid.UUID) ([]models.PassAdmission, error) {
+	var admissions []models.PassAdmission
+	result := s.GetGormDB().Where("event_id = ?", eventID).Find(&admissions)
+	if result.Error != nil {
+		log.Println("Error listing pass admissions:", result.Error)
+		return nil, result.Error
+	}
+	return admissions, nil
+}
@@ -44,6 +44,29 @@ type Service interface {
 	GetAllUsers() ([]models.User, error)
 
 	GetInactiveUsers() ([]models.User, error)
+
+	EventStore
+	VenueStore
+	TicketTypeStore
+	OrderStore
+	PaymentStore
+	RefundStore
+	TicketStore
+	CheckInStore
+	TransferStore
+	ResaleStore
+	WaitlistStore
+	PromoCodeStore
+	PricingStore
+	InvoiceStore
+	OrganizationStore
+	RoleStore
+	AttendeeFormStore
+	EventSeriesStore
+	BoxOfficeStore
+	GuestListStore
+	WaitingRoomStore
+	RiskStore
 }
 
 type service struct {
@@ -108,7 +131,43 @@ func (s *service) Migration() {
 	log.Println("uuid-ossp extension enabled successfully.")
 
 	// Migrate the schema, creating tables, constraints, etc.
-	err = s.gormDB.AutoMigrate(&models.User{})
+	err = s.gormDB.AutoMigrate(
+		&models.User{},
+		&models.Event{},
+		&models.Venue{},
+		&models.VenueSection{},
+		&models.VenueRow{},
+		&models.VenueSeat{},
+		&models.EventSeat{},
+		&models.TicketType{},
+		&models.Order{},
+		&models.OrderItem{},
+		&models.Payment{},
+		&models.Refund{},
+		&models.RefundItem{},
+		&models.Ticket{},
+		&models.CheckIn{},
+		&models.TicketTransfer{},
+		&models.ResaleListing{},
+		&models.WaitlistEntry{},
+		&models.PromoCode{},
+		&models.PromoRedemption{},
+		&models.FeeRule{},
+		&models.TaxRate{},
+		&models.Invoice{},
+		&models.InvoiceCounter{},
+		&models.Organization{},
+		&models.OrgMembership{},
+		&models.Role{},
+		&models.UserRole{},
+		&models.AttendeeForm{},
+		&models.AttendeeAnswers{},
+		&models.EventSeries{},
+		&models.PassAdmission{},
+		&models.GuestList{},
+		&models.GuestListEntry{},
+		&models.WaitingRoom{},
+	)
 	if err != nil {
 		log.Fatalf("Failed to migrate database schema: %v", err)
 	}
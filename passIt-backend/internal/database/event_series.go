@@ -0,0 +1,101 @@
+package database
+
+import (
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EventSeriesStore is the persistence contract for recurring events. The occurrences of
+// a series are events and are written together with it.
+type EventSeriesStore interface {
+	// CreateSeries stores a series with its occurrences and their ticket types in one transaction
+	CreateSeries(series *models.EventSeries, occurrences []models.Event) error
+
+	// FindSeriesById returns a series with its occurrences ordered by start time
+	FindSeriesById(id uuid.UUID) (models.EventSeries, error)
+
+	// UpdateSeries stores the series and applies the changes to its occurrences in one transaction
+	UpdateSeries(series *models.EventSeries, changes models.SeriesChanges) error
+}
+
+func (s *service) CreateSeries(series *models.EventSeries, occurrences []models.Event) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(series).Error; err != nil {
+			return err
+		}
+		return createOccurrences(tx, series, occurrences)
+	})
+	if err != nil {
+		log.Println("Error creating event series:", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) FindSeriesById(id uuid.UUID) (models.EventSeries, error) {
+	var series models.EventSeries
+	result := s.GetGormDB().
+		Preload("Occurrences", func(db *gorm.DB) *gorm.DB { return db.Order("starts_at ASC") }).
+		First(&series, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding event series by ID:", result.Error)
+		return models.EventSeries{}, result.Error
+	}
+	return series, nil
+}
+
+func (s *service) UpdateSeries(series *models.EventSeries, changes models.SeriesChanges) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", series.ID).Select("*").Omit("created_at", clause.Associations).Updates(series).Error; err != nil {
+			return err
+		}
+		for i := range changes.Updated {
+			occurrence := &changes.Updated[i]
+			if err := tx.Model(occurrence).
+				Select("title", "description", "venue", "time_zone", "capacity", "starts_at", "ends_at").
+				Updates(occurrence).Error; err != nil {
+				return err
+			}
+		}
+		if len(changes.Removed) > 0 {
+			if err := tx.Delete(&models.Event{}, "id IN ?", eventIDs(changes.Removed)).Error; err != nil {
+				return err
+			}
+		}
+		return createOccurrences(tx, series, changes.Created)
+	})
+	if err != nil {
+		log.Println("Error updating event series:", err)
+		return err
+	}
+	return nil
+}
+
+// createOccurrences stores new occurrences with the ticket types of the series templates
+func createOccurrences(tx *gorm.DB, series *models.EventSeries, occurrences []models.Event) error {
+	for i := range occurrences {
+		occurrence := &occurrences[i]
+		occurrence.SeriesID = &series.ID
+		if err := tx.Omit(clause.Associations).Create(occurrence).Error; err != nil {
+			return err
+		}
+		if ticketTypes := series.NewTicketTypes(occurrence.ID); len(ticketTypes) > 0 {
+			if err := tx.Create(&ticketTypes).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func eventIDs(events []models.Event) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
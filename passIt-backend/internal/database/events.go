@@ -0,0 +1,68 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// EventStore is the persistence contract for the event catalog
+type EventStore interface {
+	CreateEvent(event *models.Event) error
+
+	FindEventById(id uuid.UUID) (models.Event, error)
+
+	UpdateEvent(event *models.Event) error
+
+	// ListEvents returns events with the given statuses ordered by start time.
+	// An empty status list returns every event.
+	ListEvents(statuses ...models.EventStatus) ([]models.Event, error)
+}
+
+func (s *service) CreateEvent(event *models.Event) error {
+	result := s.GetGormDB().Create(event)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no rows affected, event not created")
+	}
+	return nil
+}
+
+func (s *service) FindEventById(id uuid.UUID) (models.Event, error) {
+	var event models.Event
+	result := s.GetGormDB().First(&event, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding event by ID:", result.Error)
+		return models.Event{}, result.Error
+	}
+	return event, nil
+}
+
+func (s *service) UpdateEvent(event *models.Event) error {
+	// Use Select("*") so zero values (e.g. capacity 0) are written as well
+	result := s.GetGormDB().Where("id = ?", event.ID).Select("*").Omit("created_at", clause.Associations).Updates(event)
+	if result.Error != nil {
+		log.Println("Error updating event by ID:", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *service) ListEvents(statuses ...models.EventStatus) ([]models.Event, error) {
+	var events []models.Event
+	query := s.GetGormDB().Order("starts_at ASC")
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	result := query.Find(&events)
+	if result.Error != nil {
+		log.Println("Error listing events:", result.Error)
+		return nil, result.Error
+	}
+	return events, nil
+}
@@ -0,0 +1,232 @@
+package database
+
+import (
+	"log"
+	"passIt/internal/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GuestListStore is the persistence contract for guest lists and their guests.
+// Quotas are checked while the guest list is locked so concurrent additions cannot exceed them.
+type GuestListStore interface {
+	CreateGuestList(list *models.GuestList) error
+
+	// UpdateGuestList stores the name, quota and ticket type of a guest list, the quota
+	// cannot drop below the guests already on it
+	UpdateGuestList(list *models.GuestList) error
+
+	// FindGuestListById returns a guest list with its guests sorted by name
+	FindGuestListById(id uuid.UUID) (models.GuestList, error)
+
+	// ListGuestListsByEvent returns the guest lists of an event with their guest counts
+	ListGuestListsByEvent(eventID uuid.UUID) ([]models.GuestList, error)
+
+	// DeleteGuestList removes a guest list and its guests
+	DeleteGuestList(id uuid.UUID) error
+
+	// AddGuestListEntries adds guests to a list, all of them or none if the quota would be exceeded
+	AddGuestListEntries(listID uuid.UUID, entries []models.GuestListEntry) error
+
+	FindGuestListEntryById(id uuid.UUID) (models.GuestListEntry, error)
+
+	DeleteGuestListEntry(id uuid.UUID) error
+
+	// SetGuestListEntryOrder records the comp order issued to a guest, it returns false
+	// if the guest was issued one already
+	SetGuestListEntryOrder(id, orderID uuid.UUID) (bool, error)
+
+	// SearchGuestListEntries finds the guests of an event whose name, email or company
+	// contains the query
+	SearchGuestListEntries(eventID uuid.UUID, query string) ([]models.GuestListEntry, error)
+
+	// CheckInGuests admits guests of an entry, it returns false if fewer than that many
+	// are left to check in
+	CheckInGuests(id uuid.UUID, guests int, staffID uuid.UUID, at time.Time) (bool, error)
+}
+
+func (s *service) CreateGuestList(list *models.GuestList) error {
+	if err := s.GetGormDB().Omit("Entries").Create(list).Error; err != nil {
+		log.Println("Error creating guest list:", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) UpdateGuestList(list *models.GuestList) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		if _, err := lockGuestList(tx, list.ID); err != nil {
+			return err
+		}
+		guests, err := guestCount(tx, list.ID)
+		if err != nil {
+			return err
+		}
+		if err := list.CheckQuota(guests, 0); err != nil {
+			return err
+		}
+		return tx.Model(list).Select("name", "quota", "ticket_type_id").Updates(list).Error
+	})
+	if err != nil {
+		log.Println("Error updating guest list:", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) FindGuestListById(id uuid.UUID) (models.GuestList, error) {
+	var list models.GuestList
+	result := s.GetGormDB().
+		Preload("Entries", func(db *gorm.DB) *gorm.DB { return db.Order("name ASC, created_at ASC") }).
+		First(&list, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding guest list by ID:", result.Error)
+		return models.GuestList{}, result.Error
+	}
+	list.Guests = models.GuestAdmissions(list.Entries)
+	return list, nil
+}
+
+func (s *service) ListGuestListsByEvent(eventID uuid.UUID) ([]models.GuestList, error) {
+	var lists []models.GuestList
+	result := s.GetGormDB().Where("event_id = ?", eventID).Order("name ASC").Find(&lists)
+	if result.Error != nil {
+		log.Println("Error listing guest lists:", result.Error)
+		return nil, result.Error
+	}
+	for i := range lists {
+		guests, err := guestCount(s.GetGormDB(), lists[i].ID)
+		if err != nil {
+			log.Println("Error counting guests:", err)
+			return nil, err
+		}
+		lists[i].Guests = guests
+	}
+	return lists, nil
+}
+
+func (s *service) DeleteGuestList(id uuid.UUID) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.GuestListEntry{}, "guest_list_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.GuestList{}, "id = ?", id).Error
+	})
+	if err != nil {
+		log.Println("Error deleting guest list:", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) AddGuestListEntries(listID uuid.UUID, entries []models.GuestListEntry) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		list, err := lockGuestList(tx, listID)
+		if err != nil {
+			return err
+		}
+		guests, err := guestCount(tx, listID)
+		if err != nil {
+			return err
+		}
+		if err := list.CheckQuota(guests, models.GuestAdmissions(entries)); err != nil {
+			return err
+		}
+		return tx.Create(&entries).Error
+	})
+	if err != nil {
+		log.Println("Error adding guests:", err)
+		return err
+	}
+	return nil
+}
+
+// lockGuestList locks a guest list row until the end of the transaction
+func lockGuestList(tx *gorm.DB, id uuid.UUID) (models.GuestList, error) {
+	var list models.GuestList
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&list, "id = ?", id).Error
+	return list, err
+}
+
+// guestCount sums the guests of a list, plus-ones included
+func guestCount(tx *gorm.DB, listID uuid.UUID) (int, error) {
+	var guests int
+	err := tx.Model(&models.GuestListEntry{}).
+		Where("guest_list_id = ?", listID).
+		Select("COALESCE(SUM(1 + plus_ones), 0)").
+		Scan(&guests).Error
+	return guests, err
+}
+
+func (s *service) FindGuestListEntryById(id uuid.UUID) (models.GuestListEntry, error) {
+	var entry models.GuestListEntry
+	result := s.GetGormDB().First(&entry, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding guest by ID:", result.Error)
+		return models.GuestListEntry{}, result.Error
+	}
+	return entry, nil
+}
+
+func (s *service) DeleteGuestListEntry(id uuid.UUID) error {
+	result := s.GetGormDB().Delete(&models.GuestListEntry{}, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error deleting guest:", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *service) SetGuestListEntryOrder(id, orderID uuid.UUID) (bool, error) {
+	result := s.GetGormDB().Model(&models.GuestListEntry{}).
+		Where("id = ? AND order_id IS NULL", id).
+		Update("order_id", orderID)
+	if result.Error != nil {
+		log.Println("Error recording comp order of guest:", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (s *service) SearchGuestListEntries(eventID uuid.UUID, query string) ([]models.GuestListEntry, error) {
+	var entries []models.GuestListEntry
+	pattern := "%" + escapeLike(query) + "%"
+	result := s.GetGormDB().Model(&models.GuestListEntry{}).
+		Select("guest_list_entries.*, guest_lists.name AS guest_list_name").
+		Joins("JOIN guest_lists ON guest_lists.id = guest_list_entries.guest_list_id AND guest_lists.deleted_at IS NULL").
+		Where("guest_list_entries.event_id = ?", eventID).
+		Where("guest_list_entries.name ILIKE ? OR guest_list_entries.email ILIKE ? OR guest_list_entries.company ILIKE ?",
+			pattern, pattern, pattern).
+		Order("guest_list_entries.name ASC").
+		Limit(50).
+		Find(&entries)
+	if result.Error != nil {
+		log.Println("Error searching guests:", result.Error)
+		return nil, result.Error
+	}
+	return entries, nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern so user input matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (s *service) CheckInGuests(id uuid.UUID, guests int, staffID uuid.UUID, at time.Time) (bool, error) {
+	result := s.GetGormDB().Model(&models.GuestListEntry{}).
+		Where("id = ? AND checked_in + ? <= 1 + plus_ones", id, guests).
+		Updates(map[string]any{
+			"checked_in":       gorm.Expr("checked_in + ?", guests),
+			"checked_in_at":    gorm.Expr("COALESCE(checked_in_at, ?)", at),
+			"checked_in_by_id": staffID,
+		})
+	if result.Error != nil {
+		log.Println("Error checking in guests:", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InvoiceStore is the persistence contract for invoices
+type InvoiceStore interface {
+	FindInvoiceByOrder(orderID uuid.UUID) (models.Invoice, error)
+
+	// CreateInvoice assigns the next number of the invoice year and stores the invoice
+	// with the PDF rendered for that number. Both happen in one transaction, so a failed
+	// rendering or a concurrent invoice for the same order leaves no gap in the numbers.
+	CreateInvoice(invoice *models.Invoice, render func(*models.Invoice) ([]byte, error)) error
+}
+
+func (s *service) FindInvoiceByOrder(orderID uuid.UUID) (models.Invoice, error) {
+	var invoice models.Invoice
+	result := s.GetGormDB().First(&invoice, "order_id = ?", orderID)
+	if result.Error != nil {
+		log.Println("Error finding invoice by order:", result.Error)
+		return models.Invoice{}, result.Error
+	}
+	return invoice, nil
+}
+
+func (s *service) CreateInvoice(invoice *models.Invoice, render func(*models.Invoice) ([]byte, error)) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		year := invoice.IssuedAt.Year()
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.InvoiceCounter{Year: year}).Error; err != nil {
+			return err
+		}
+
+		// The update locks the counter row until the invoice is stored
+		var counter models.InvoiceCounter
+		if err := tx.Model(&counter).
+			Clauses(clause.Returning{}).
+			Where("year = ?", year).
+			Update("last", gorm.Expr("last + 1")).Error; err != nil {
+			return err
+		}
+		invoice.Number = models.InvoiceNumber(year, counter.Last)
+
+		pdf, err := render(invoice)
+		if err != nil {
+			return err
+		}
+		invoice.PDF = pdf
+		return tx.Create(invoice).Error
+	})
+	if err != nil {
+		log.Println("Error creating invoice:", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"passIt/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrderStore is the persistence contract for orders and their line items
+type OrderStore interface {
+	// CreateOrder stores an order together with its items
+	CreateOrder(order *models.Order) error
+
+	FindOrderById(id uuid.UUID) (models.Order, error)
+
+	// FindOrderByHoldId returns the order a hold was checked out into
+	FindOrderByHoldId(holdID string) (models.Order, error)
+
+	ListOrdersByUser(userID uuid.UUID) ([]models.Order, error)
+
+	// TransitionOrder writes the status and timestamps of the order only if it is still
+	// in the given status and reports whether it did. Seats of paid orders are marked
+	// sold and promo codes of cancelled or expired orders are released in the same transaction.
+	TransitionOrder(order *models.Order, from models.OrderStatus) (bool, error)
+
+	// ListOrdersByEvent returns the orders of an event with the given statuses.
+	// An empty status list returns every order.
+	ListOrdersByEvent(eventID uuid.UUID, statuses ...models.OrderStatus) ([]models.Order, error)
+
+	// ListOrdersByStatus returns the orders with one of the given statuses, oldest first
+	ListOrdersByStatus(statuses ...models.OrderStatus) ([]models.Order, error)
+
+	// ListExpiredOrders returns unpaid orders whose payment window closed before now
+	ListExpiredOrders(now time.Time) ([]models.Order, error)
+
+	// CountTakenTickets returns per ticket type how many ordered tickets have not been given back to sale
+	CountTakenTickets(ticketTypeIDs []uuid.UUID) (map[uuid.UUID]int, error)
+}
+
+func preloadOrderItems(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC, id ASC") })
+}
+
+func (s *service) CreateOrder(order *models.Order) error {
+	result := s.GetGormDB().Create(order)
+	if result.Error != nil {
+		log.Println("Error creating order:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no rows affected, order not created")
+	}
+	return nil
+}
+
+func (s *service) FindOrderById(id uuid.UUID) (models.Order, error) {
+	var order models.Order
+	result := preloadOrderItems(s.GetGormDB()).Preload("Redemptions").Preload("Attendees").First(&order, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding order by ID:", result.Error)
+		return models.Order{}, result.Error
+	}
+	return order, nil
+}
+
+func (s *service) FindOrderByHoldId(holdID string) (models.Order, error) {
+	var order models.Order
+	result := s.GetGormDB().First(&order, "hold_id = ?", holdID)
+	if result.Error != nil {
+		return models.Order{}, result.Error
+	}
+	return order, nil
+}
+
+func (s *service) ListOrdersByUser(userID uuid.UUID) ([]models.Order, error) {
+	var orders []models.Order
+	result := preloadOrderItems(s.GetGormDB()).Where("user_id = ?", userID).Order("created_at DESC").Find(&orders)
+	if result.Error != nil {
+		log.Println("Error listing orders:", result.Error)
+		return nil, result.Error
+	}
+	return orders, nil
+}
+
+func (s *service) TransitionOrder(order *models.Order, from models.OrderStatus) (bool, error) {
+	var updated bool
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Order{}).
+			Where("id = ? AND status = ?", order.ID, from).
+			Updates(map[string]interface{}{
+				"status":       order.Status,
+				"paid_at":      order.PaidAt,
+				"fulfilled_at": order.FulfilledAt,
+				"cancelled_at": order.CancelledAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		updated = true
+
+		if order.Status.ReleasesInventory() {
+			return releasePromoRedemptions(tx, order.ID)
+		}
+		if order.Status != models.OrderStatusPaid {
+			return nil
+		}
+
+		var seatIDs []uuid.UUID
+		for _, item := range order.Items {
+			if item.EventSeatID != nil {
+				seatIDs = append(seatIDs, *item.EventSeatID)
+			}
+		}
+		if len(seatIDs) == 0 {
+			return nil
+		}
+		return tx.Model(&models.EventSeat{}).Where("id IN ?", seatIDs).Update("status", models.EventSeatSold).Error
+	})
+	if err != nil {
+		log.Println("Error transitioning order:", err)
+		return false, err
+	}
+	return updated, nil
+}
+
+func (s *service) ListOrdersByEvent(eventID uuid.UUID, statuses ...models.OrderStatus) ([]models.Order, error) {
+	var orders []models.Order
+	query := preloadOrderItems(s.GetGormDB()).Where("event_id = ?", eventID).Order("created_at ASC")
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	result := query.Find(&orders)
+	if result.Error != nil {
+		log.Println("Error listing event orders:", result.Error)
+		return nil, result.Error
+	}
+	return orders, nil
+}
+
+func (s *service) ListOrdersByStatus(statuses ...models.OrderStatus) ([]models.Order, error) {
+	var orders []models.Order
+	result := preloadOrderItems(s.GetGormDB()).Where("status IN ?", statuses).Order("created_at ASC").Find(&orders)
+	if result.Error != nil {
+		log.Println("Error listing orders by status:", result.Error)
+		return nil, result.Error
+	}
+	return orders, nil
+}
+
+func (s *service) ListExpiredOrders(now time.Time) ([]models.Order, error) {
+	var orders []models.Order
+	result := preloadOrderItems(s.GetGormDB()).
+		Where("status IN ? AND expires_at < ?", []models.OrderStatus{models.OrderStatusPending, models.OrderStatusAwaitingPayment}, now).
+		Find(&orders)
+	if result.Error != nil {
+		log.Println("Error listing expired orders:", result.Error)
+		return nil, result.Error
+	}
+	return orders, nil
+}
+
+func (s *service) CountTakenTickets(ticketTypeIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		TicketTypeID uuid.UUID
+		Taken        int
+	}
+	result := s.GetGormDB().Model(&models.OrderItem{}).
+		Select("order_items.ticket_type_id, COALESCE(SUM(order_items.quantity - order_items.restocked_quantity), 0) AS taken").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("order_items.ticket_type_id IN ?", ticketTypeIDs).
+		// Resale orders sell tickets that were already taken
+		Where("orders.resale_listing_id IS NULL").
+		Where("orders.status IN ?", []models.OrderStatus{
+			models.OrderStatusPending,
+			models.OrderStatusAwaitingPayment,
+			models.OrderStatusPaid,
+			models.OrderStatusFulfilled,
+			models.OrderStatusRefunded,
+		}).
+		Group("order_items.ticket_type_id").
+		Scan(&rows)
+	if result.Error != nil {
+		log.Println("Error counting taken tickets:", result.Error)
+		return nil, result.Error
+	}
+
+	taken := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		taken[row.TicketTypeID] = row.Taken
+	}
+	return taken, nil
+}
@@ -0,0 +1,241 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrganizationStore is the persistence contract for organizations, their members and
+// the queries scoped to one organization
+type OrganizationStore interface {
+	// CreateOrganization stores an organization together with its first owner
+	CreateOrganization(org *models.Organization, ownerID uuid.UUID) error
+
+	FindOrganizationById(id uuid.UUID) (models.Organization, error)
+	FindOrganizationBySlug(slug string) (models.Organization, error)
+	ListOrganizations() ([]models.Organization, error)
+
+	// ListMembershipsByUser returns the memberships of a user with their organizations
+	ListMembershipsByUser(userID uuid.UUID) ([]models.OrgMembership, error)
+
+	FindMembership(orgID, userID uuid.UUID) (models.OrgMembership, error)
+	ListMemberships(orgID uuid.UUID) ([]models.OrgMembership, error)
+	CreateMembership(membership *models.OrgMembership) error
+
+	// UpdateMembershipRole and DeleteMembership fail with models.ErrOrgLastOwner
+	// rather than leave the organization without an owner
+	UpdateMembershipRole(orgID, userID uuid.UUID, role models.OrgRole) error
+	DeleteMembership(orgID, userID uuid.UUID) error
+
+	// ListEventsByOrganization returns the events of an organization with the given
+	// statuses ordered by start time. An empty status list returns every event.
+	ListEventsByOrganization(orgID uuid.UUID, statuses ...models.EventStatus) ([]models.Event, error)
+
+	// ListOrdersByOrganization returns the orders of the events of an organization,
+	// newest first, optionally only those of one event
+	ListOrdersByOrganization(orgID uuid.UUID, eventID *uuid.UUID) ([]models.Order, error)
+
+	// SalesReportByOrganization sums up the paid orders of every event of an organization
+	SalesReportByOrganization(orgID uuid.UUID) ([]models.EventSalesReport, error)
+}
+
+func (s *service) CreateOrganization(org *models.Organization, ownerID uuid.UUID) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.OrgMembership{
+			OrganizationID: org.ID,
+			UserID:         ownerID,
+			Role:           models.OrgRoleOwner,
+		}).Error
+	})
+	if err != nil {
+		log.Println("Error creating organization:", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) FindOrganizationById(id uuid.UUID) (models.Organization, error) {
+	var org models.Organization
+	result := s.GetGormDB().First(&org, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding organization by ID:", result.Error)
+		return models.Organization{}, result.Error
+	}
+	return org, nil
+}
+
+func (s *service) FindOrganizationBySlug(slug string) (models.Organization, error) {
+	var org models.Organization
+	result := s.GetGormDB().Unscoped().First(&org, "slug = ?", slug)
+	if result.Error != nil {
+		return models.Organization{}, result.Error
+	}
+	return org, nil
+}
+
+func (s *service) ListOrganizations() ([]models.Organization, error) {
+	var orgs []models.Organization
+	result := s.GetGormDB().Order("name").Find(&orgs)
+	if result.Error != nil {
+		log.Println("Error listing organizations:", result.Error)
+		return nil, result.Error
+	}
+	return orgs, nil
+}
+
+func (s *service) ListMembershipsByUser(userID uuid.UUID) ([]models.OrgMembership, error) {
+	var memberships []models.OrgMembership
+	result := s.GetGormDB().
+		Preload("Organization").
+		Joins("JOIN organizations ON organizations.id = org_memberships.organization_id AND organizations.deleted_at IS NULL").
+		Where("org_memberships.user_id = ?", userID).
+		Order("organizations.name").
+		Find(&memberships)
+	if result.Error != nil {
+		log.Println("Error listing memberships of user:", result.Error)
+		return nil, result.Error
+	}
+	return memberships, nil
+}
+
+func (s *service) FindMembership(orgID, userID uuid.UUID) (models.OrgMembership, error) {
+	var membership models.OrgMembership
+	result := s.GetGormDB().First(&membership, "organization_id = ? AND user_id = ?", orgID, userID)
+	if result.Error != nil {
+		return models.OrgMembership{}, result.Error
+	}
+	return membership, nil
+}
+
+func (s *service) ListMemberships(orgID uuid.UUID) ([]models.OrgMembership, error) {
+	var memberships []models.OrgMembership
+	result := s.GetGormDB().Preload("User").Where("organization_id = ?", orgID).Order("created_at").Find(&memberships)
+	if result.Error != nil {
+		log.Println("Error listing memberships:", result.Error)
+		return nil, result.Error
+	}
+	return memberships, nil
+}
+
+func (s *service) CreateMembership(membership *models.OrgMembership) error {
+	result := s.GetGormDB().Omit("User", "Organization").Create(membership)
+	if result.Error != nil {
+		log.Println("Error creating membership:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no rows affected, membership not created")
+	}
+	return nil
+}
+
+func (s *service) UpdateMembershipRole(orgID, userID uuid.UUID, role models.OrgRole) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.OrgMembership{}).
+			Where("organization_id = ? AND user_id = ?", orgID, userID).
+			Update("role", role)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return ensureOrgOwner(tx, orgID)
+	})
+	if err != nil && !errors.Is(err, models.ErrOrgLastOwner) {
+		log.Println("Error updating membership role:", err)
+	}
+	return err
+}
+
+func (s *service) DeleteMembership(orgID, userID uuid.UUID) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("organization_id = ? AND user_id = ?", orgID, userID).Delete(&models.OrgMembership{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return ensureOrgOwner(tx, orgID)
+	})
+	if err != nil && !errors.Is(err, models.ErrOrgLastOwner) {
+		log.Println("Error deleting membership:", err)
+	}
+	return err
+}
+
+// ensureOrgOwner rolls back a membership change that removed the last owner. The owner
+// rows are locked so two owners cannot demote each other at the same time.
+func ensureOrgOwner(tx *gorm.DB, orgID uuid.UUID) error {
+	var owners []uuid.UUID
+	if err := tx.Model(&models.OrgMembership{}).
+		Where("organization_id = ? AND role = ?", orgID, models.OrgRoleOwner).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Pluck("id", &owners).Error; err != nil {
+		return err
+	}
+	if len(owners) == 0 {
+		return models.ErrOrgLastOwner
+	}
+	return nil
+}
+
+func (s *service) ListEventsByOrganization(orgID uuid.UUID, statuses ...models.EventStatus) ([]models.Event, error) {
+	var events []models.Event
+	query := s.GetGormDB().Where("organization_id = ?", orgID).Order("starts_at ASC")
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	result := query.Find(&events)
+	if result.Error != nil {
+		log.Println("Error listing events of organization:", result.Error)
+		return nil, result.Error
+	}
+	return events, nil
+}
+
+func (s *service) ListOrdersByOrganization(orgID uuid.UUID, eventID *uuid.UUID) ([]models.Order, error) {
+	var orders []models.Order
+	query := preloadOrderItems(s.GetGormDB()).
+		Joins("JOIN events ON events.id = orders.event_id").
+		Where("events.organization_id = ?", orgID).
+		Order("orders.created_at DESC")
+	if eventID != nil {
+		query = query.Where("orders.event_id = ?", *eventID)
+	}
+	result := query.Find(&orders)
+	if result.Error != nil {
+		log.Println("Error listing orders of organization:", result.Error)
+		return nil, result.Error
+	}
+	return orders, nil
+}
+
+func (s *service) SalesReportByOrganization(orgID uuid.UUID) ([]models.EventSalesReport, error) {
+	var report []models.EventSalesReport
+	result := s.GetGormDB().Model(&models.Order{}).
+		Select(`events.id AS event_id, events.title AS title, orders.currency AS currency,
+			COUNT(orders.id) AS orders,
+			COALESCE(SUM((SELECT SUM(quantity - refunded_quantity) FROM order_items WHERE order_items.order_id = orders.id)), 0) AS tickets,
+			COALESCE(SUM(orders.total), 0) AS gross,
+			COALESCE(SUM(orders.refunded), 0) AS refunded`).
+		Joins("JOIN events ON events.id = orders.event_id").
+		Where("events.organization_id = ? AND orders.paid_at IS NOT NULL", orgID).
+		Group("events.id, events.title, orders.currency").
+		Order("MIN(events.starts_at), events.title").
+		Scan(&report)
+	if result.Error != nil {
+		log.Println("Error building sales report:", result.Error)
+		return nil, result.Error
+	}
+	return report, nil
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// PaymentStore is the persistence contract for order payments
+type PaymentStore interface {
+	CreatePayment(payment *models.Payment) error
+
+	FindPaymentByIntentID(intentID string) (models.Payment, error)
+
+	// FindLatestPaymentByOrder returns the most recently created payment of an order
+	FindLatestPaymentByOrder(orderID uuid.UUID) (models.Payment, error)
+
+	// TransitionPayment writes the status of the payment only if it is still in one of
+	// the given statuses and reports whether it did
+	TransitionPayment(payment *models.Payment, from ...models.PaymentStatus) (bool, error)
+}
+
+func (s *service) CreatePayment(payment *models.Payment) error {
+	result := s.GetGormDB().Create(payment)
+	if result.Error != nil {
+		log.Println("Error creating payment:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no rows affected, payment not created")
+	}
+	return nil
+}
+
+func (s *service) FindPaymentByIntentID(intentID string) (models.Payment, error) {
+	var payment models.Payment
+	result := s.GetGormDB().First(&payment, "intent_id = ?", intentID)
+	if result.Error != nil {
+		log.Println("Error finding payment by intent ID:", result.Error)
+		return models.Payment{}, result.Error
+	}
+	return payment, nil
+}
+
+func (s *service) FindLatestPaymentByOrder(orderID uuid.UUID) (models.Payment, error) {
+	var payment models.Payment
+	result := s.GetGormDB().Where("order_id = ?", orderID).Order("created_at DESC").First(&payment)
+	if result.Error != nil {
+		return models.Payment{}, result.Error
+	}
+	return payment, nil
+}
+
+func (s *service) TransitionPayment(payment *models.Payment, from ...models.PaymentStatus) (bool, error) {
+	result := s.GetGormDB().Model(&models.Payment{}).
+		Where("id = ? AND status IN ?", payment.ID, from).
+		Updates(map[string]interface{}{
+			"status":         payment.Status,
+			"failure_reason": payment.FailureReason,
+			"captured_at":    payment.CapturedAt,
+		})
+	if result.Error != nil {
+		log.Println("Error transitioning payment:", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
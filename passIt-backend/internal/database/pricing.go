@@ -0,0 +1,153 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// PricingStore is the persistence contract for service fee rules and tax rates
+type PricingStore interface {
+	CreateFeeRule(fee *models.FeeRule) error
+	FindFeeRuleById(id uuid.UUID) (models.FeeRule, error)
+	ListFeeRules() ([]models.FeeRule, error)
+
+	// ListFeeRulesForOrder returns the fee rules that apply to an order of the event in
+	// the currency: the rules of the event if it has any, else the global rules
+	ListFeeRulesForOrder(eventID uuid.UUID, currency string) ([]models.FeeRule, error)
+
+	DeleteFeeRule(id uuid.UUID) error
+
+	CreateTaxRate(tax *models.TaxRate) error
+	FindTaxRateById(id uuid.UUID) (models.TaxRate, error)
+	ListTaxRates() ([]models.TaxRate, error)
+
+	// ListTaxRatesForEvent returns the tax rates of the jurisdiction of the event venue
+	ListTaxRatesForEvent(eventID uuid.UUID) ([]models.TaxRate, error)
+
+	DeleteTaxRate(id uuid.UUID) error
+}
+
+func (s *service) CreateFeeRule(fee *models.FeeRule) error {
+	result := s.GetGormDB().Create(fee)
+	if result.Error != nil {
+		log.Println("Error creating fee rule:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no rows affected, fee rule not created")
+	}
+	return nil
+}
+
+func (s *service) FindFeeRuleById(id uuid.UUID) (models.FeeRule, error) {
+	var fee models.FeeRule
+	result := s.GetGormDB().First(&fee, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding fee rule by ID:", result.Error)
+		return models.FeeRule{}, result.Error
+	}
+	return fee, nil
+}
+
+func (s *service) ListFeeRules() ([]models.FeeRule, error) {
+	var fees []models.FeeRule
+	result := s.GetGormDB().Order("event_id NULLS FIRST, currency, created_at").Find(&fees)
+	if result.Error != nil {
+		log.Println("Error listing fee rules:", result.Error)
+		return nil, result.Error
+	}
+	return fees, nil
+}
+
+func (s *service) ListFeeRulesForOrder(eventID uuid.UUID, currency string) ([]models.FeeRule, error) {
+	var fees []models.FeeRule
+	result := s.GetGormDB().
+		Where("event_id = ? AND currency = ?", eventID, currency).
+		Order("created_at").
+		Find(&fees)
+	if result.Error != nil {
+		log.Println("Error listing event fee rules:", result.Error)
+		return nil, result.Error
+	}
+	if len(fees) > 0 {
+		return fees, nil
+	}
+
+	result = s.GetGormDB().
+		Where("event_id IS NULL AND currency = ?", currency).
+		Order("created_at").
+		Find(&fees)
+	if result.Error != nil {
+		log.Println("Error listing global fee rules:", result.Error)
+		return nil, result.Error
+	}
+	return fees, nil
+}
+
+func (s *service) DeleteFeeRule(id uuid.UUID) error {
+	result := s.GetGormDB().Delete(&models.FeeRule{}, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error deleting fee rule:", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *service) CreateTaxRate(tax *models.TaxRate) error {
+	result := s.GetGormDB().Create(tax)
+	if result.Error != nil {
+		log.Println("Error creating tax rate:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no rows affected, tax rate not created")
+	}
+	return nil
+}
+
+func (s *service) FindTaxRateById(id uuid.UUID) (models.TaxRate, error) {
+	var tax models.TaxRate
+	result := s.GetGormDB().First(&tax, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding tax rate by ID:", result.Error)
+		return models.TaxRate{}, result.Error
+	}
+	return tax, nil
+}
+
+func (s *service) ListTaxRates() ([]models.TaxRate, error) {
+	var taxes []models.TaxRate
+	result := s.GetGormDB().Order("jurisdiction, created_at").Find(&taxes)
+	if result.Error != nil {
+		log.Println("Error listing tax rates:", result.Error)
+		return nil, result.Error
+	}
+	return taxes, nil
+}
+
+func (s *service) ListTaxRatesForEvent(eventID uuid.UUID) ([]models.TaxRate, error) {
+	var taxes []models.TaxRate
+	result := s.GetGormDB().
+		Joins("JOIN venues ON venues.tax_jurisdiction = tax_rates.jurisdiction AND venues.deleted_at IS NULL").
+		Joins("JOIN events ON events.venue_id = venues.id").
+		Where("events.id = ?", eventID).
+		Order("tax_rates.created_at").
+		Find(&taxes)
+	if result.Error != nil {
+		log.Println("Error listing tax rates of event:", result.Error)
+		return nil, result.Error
+	}
+	return taxes, nil
+}
+
+func (s *service) DeleteTaxRate(id uuid.UUID) error {
+	result := s.GetGormDB().Delete(&models.TaxRate{}, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error deleting tax rate:", result.Error)
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package database
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"passIt/internal/models"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PromoCodeStore is the persistence contract for promo codes and their redemptions
+type PromoCodeStore interface {
+	CreatePromoCode(code *models.PromoCode) error
+
+	FindPromoCodeById(id uuid.UUID) (models.PromoCode, error)
+
+	// FindPromoCodesByCode returns the promo codes with the given normalized codes
+	FindPromoCodesByCode(codes []string) ([]models.PromoCode, error)
+
+	ListPromoCodes() ([]models.PromoCode, error)
+
+	// UpdatePromoCode writes the editable fields, leaving the redemption counter alone
+	UpdatePromoCode(code *models.PromoCode) error
+
+	DeletePromoCode(id uuid.UUID) error
+
+	// CreateOrderWithRedemptions stores an order and books its promo code redemptions in
+	// one transaction. Booking locks each code, so concurrent checkouts cannot go past the
+	// limits: it fails with models.ErrPromoCodeExhausted or models.ErrPromoCodeUserLimit.
+	CreateOrderWithRedemptions(order *models.Order, redemptions []models.PromoRedemption) error
+}
+
+func (s *service) CreatePromoCode(code *models.PromoCode) error {
+	result := s.GetGormDB().Create(code)
+	if result.Error != nil {
+		log.Println("Error creating promo code:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no rows affected, promo code not created")
+	}
+	return nil
+}
+
+func (s *service) FindPromoCodeById(id uuid.UUID) (models.PromoCode, error) {
+	var code models.PromoCode
+	result := s.GetGormDB().First(&code, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding promo code by ID:", result.Error)
+		return models.PromoCode{}, result.Error
+	}
+	return code, nil
+}
+
+func (s *service) FindPromoCodesByCode(codes []string) ([]models.PromoCode, error) {
+	var promoCodes []models.PromoCode
+	result := s.GetGormDB().Where("code IN ?", codes).Find(&promoCodes)
+	if result.Error != nil {
+		log.Println("Error finding promo codes:", result.Error)
+		return nil, result.Error
+	}
+	return promoCodes, nil
+}
+
+func (s *service) ListPromoCodes() ([]models.PromoCode, error) {
+	var promoCodes []models.PromoCode
+	result := s.GetGormDB().Order("created_at DESC").Find(&promoCodes)
+	if result.Error != nil {
+		log.Println("Error listing promo codes:", result.Error)
+		return nil, result.Error
+	}
+	return promoCodes, nil
+}
+
+func (s *service) UpdatePromoCode(code *models.PromoCode) error {
+	result := s.GetGormDB().Where("id = ?", code.ID).
+		Select("*").
+		Omit("created_at", "created_by_id", "redemptions").
+		Updates(code)
+	if result.Error != nil {
+		log.Println("Error updating promo code:", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *service) DeletePromoCode(id uuid.UUID) error {
+	result := s.GetGormDB().Delete(&models.PromoCode{}, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error deleting promo code:", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *service) CreateOrderWithRedemptions(order *models.Order, redemptions []models.PromoRedemption) error {
+	// Lock the codes in ID order so checkouts stacking the same codes in a different
+	// order cannot deadlock, the redemptions keep the order they were applied in
+	locking := slices.Clone(redemptions)
+	slices.SortFunc(locking, func(a, b models.PromoRedemption) int {
+		return bytes.Compare(a.PromoCodeID[:], b.PromoCodeID[:])
+	})
+
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		for _, redemption := range locking {
+			// The update locks the code row, so the per-user count below cannot race
+			// with another checkout of the same code
+			result := tx.Model(&models.PromoCode{}).
+				Where("id = ? AND (max_redemptions = 0 OR redemptions < max_redemptions)", redemption.PromoCodeID).
+				Update("redemptions", gorm.Expr("redemptions + 1"))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return models.ErrPromoCodeExhausted
+			}
+
+			var code models.PromoCode
+			if err := tx.Select("max_per_user").First(&code, "id = ?", redemption.PromoCodeID).Error; err != nil {
+				return err
+			}
+			if code.MaxPerUser > 0 {
+				var used int64
+				if err := tx.Model(&models.PromoRedemption{}).
+					Where("promo_code_id = ? AND user_id = ? AND released_at IS NULL", redemption.PromoCodeID, redemption.UserID).
+					Count(&used).Error; err != nil {
+					return err
+				}
+				if int(used) >= code.MaxPerUser {
+					return models.ErrPromoCodeUserLimit
+				}
+			}
+		}
+
+		if err := tx.Omit("Redemptions").Create(order).Error; err != nil {
+			return err
+		}
+		for i := range redemptions {
+			redemptions[i].OrderID = order.ID
+		}
+		if len(redemptions) > 0 {
+			if err := tx.Create(&redemptions).Error; err != nil {
+				return err
+			}
+		}
+		order.Redemptions = redemptions
+		return nil
+	})
+	if err != nil {
+		log.Println("Error creating order with promo codes:", err)
+		return err
+	}
+	return nil
+}
+
+// releasePromoRedemptions gives the redemptions of an order that will never be paid back
+// to their codes. Releasing twice is a no-op.
+func releasePromoRedemptions(tx *gorm.DB, orderID uuid.UUID) error {
+	var redemptions []models.PromoRedemption
+	if err := tx.Where("order_id = ? AND released_at IS NULL", orderID).Find(&redemptions).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, redemption := range redemptions {
+		result := tx.Model(&models.PromoRedemption{}).
+			Where("id = ? AND released_at IS NULL", redemption.ID).
+			Update("released_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		if err := tx.Model(&models.PromoCode{}).
+			Where("id = ?", redemption.PromoCodeID).
+			Update("redemptions", gorm.Expr("redemptions - 1")).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
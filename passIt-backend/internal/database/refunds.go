@@ -0,0 +1,147 @@
+package database
+
+import (
+	"log"
+	"passIt/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RefundStore is the persistence contract for refund transactions
+type RefundStore interface {
+	// CreateRefund stores a pending refund and books its tickets and amount on the order
+	// in one transaction. It fails with models.ErrRefundExceedsTickets if a concurrent
+	// refund already took some of the tickets.
+	CreateRefund(refund *models.Refund) error
+
+	// CompleteRefund marks a pending refund as succeeded, voids the refunded tickets,
+	// withdraws their resale listings and gives restocked seats back to sale
+	CompleteRefund(refund *models.Refund, seatIDs []uuid.UUID) error
+
+	// FailRefund marks a pending refund as failed and releases its tickets and amount again
+	FailRefund(refund *models.Refund) error
+
+	ListRefundsByOrder(orderID uuid.UUID) ([]models.Refund, error)
+}
+
+func (s *service) CreateRefund(refund *models.Refund) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		for _, item := range refund.Items {
+			updates := map[string]interface{}{
+				"refunded_quantity": gorm.Expr("refunded_quantity + ?", item.Quantity),
+			}
+			if refund.Restock {
+				updates["restocked_quantity"] = gorm.Expr("restocked_quantity + ?", item.Quantity)
+			}
+			result := tx.Model(&models.OrderItem{}).
+				Where("id = ? AND order_id = ? AND quantity - refunded_quantity - resold_quantity >= ?", item.OrderItemID, refund.OrderID, item.Quantity).
+				Updates(updates)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return models.ErrRefundExceedsTickets
+			}
+		}
+
+		if err := tx.Model(&models.Order{}).
+			Where("id = ?", refund.OrderID).
+			Update("refunded", gorm.Expr("refunded + ?", refund.Amount)).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(refund).Error
+	})
+	if err != nil {
+		log.Println("Error creating refund:", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) CompleteRefund(refund *models.Refund, seatIDs []uuid.UUID) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Refund{}).Where("id = ?", refund.ID).Updates(map[string]interface{}{
+			"status":             models.RefundStatusSucceeded,
+			"provider_refund_id": refund.ProviderRefundID,
+		}).Error; err != nil {
+			return err
+		}
+		for _, item := range refund.Items {
+			var voided []uuid.UUID
+			if err := tx.Model(&models.Ticket{}).
+				Where("order_item_id = ? AND status IN ?", item.OrderItemID, []models.TicketStatus{models.TicketStatusValid, models.TicketStatusListed}).
+				Order("number DESC").
+				Limit(item.Quantity).
+				Pluck("id", &voided).Error; err != nil {
+				return err
+			}
+			if len(voided) == 0 {
+				continue
+			}
+			if err := tx.Model(&models.Ticket{}).Where("id IN ?", voided).Updates(map[string]interface{}{
+				"status":    models.TicketStatusVoid,
+				"voided_at": time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.ResaleListing{}).
+				Where("ticket_id IN ? AND status = ?", voided, models.ResaleListingActive).
+				Update("status", models.ResaleListingCancelled).Error; err != nil {
+				return err
+			}
+		}
+		if len(seatIDs) == 0 {
+			return nil
+		}
+		return tx.Model(&models.EventSeat{}).Where("id IN ?", seatIDs).Update("status", models.EventSeatAvailable).Error
+	})
+	if err != nil {
+		log.Println("Error completing refund:", err)
+		return err
+	}
+	refund.Status = models.RefundStatusSucceeded
+	return nil
+}
+
+func (s *service) FailRefund(refund *models.Refund) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		for _, item := range refund.Items {
+			updates := map[string]interface{}{
+				"refunded_quantity": gorm.Expr("refunded_quantity - ?", item.Quantity),
+			}
+			if refund.Restock {
+				updates["restocked_quantity"] = gorm.Expr("restocked_quantity - ?", item.Quantity)
+			}
+			if err := tx.Model(&models.OrderItem{}).Where("id = ?", item.OrderItemID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.Order{}).
+			Where("id = ?", refund.OrderID).
+			Update("refunded", gorm.Expr("refunded - ?", refund.Amount)).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Refund{}).Where("id = ?", refund.ID).Update("status", models.RefundStatusFailed).Error
+	})
+	if err != nil {
+		log.Println("Error failing refund:", err)
+		return err
+	}
+	refund.Status = models.RefundStatusFailed
+	return nil
+}
+
+func (s *service) ListRefundsByOrder(orderID uuid.UUID) ([]models.Refund, error) {
+	var refunds []models.Refund
+	result := s.GetGormDB().Preload("Items").Where("order_id = ?", orderID).Order("created_at ASC").Find(&refunds)
+	if result.Error != nil {
+		log.Println("Error listing refunds:", result.Error)
+		return nil, result.Error
+	}
+	return refunds, nil
+}
@@ -0,0 +1,291 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"passIt/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ResaleStore is the persistence contract for the resale marketplace
+type ResaleStore interface {
+	// ListTicketForResale moves a valid ticket to listed with the listing's version and stores
+	// the listing in one transaction. It fails with models.ErrResaleTicketNotAllowed when the
+	// ticket changed since it was read.
+	ListTicketForResale(listing *models.ResaleListing) error
+
+	FindResaleListingById(id uuid.UUID) (models.ResaleListing, error)
+
+	// FindResaleListingByOrder returns the listing a resale order is buying
+	FindResaleListingByOrder(orderID uuid.UUID) (models.ResaleListing, error)
+
+	// ListActiveResaleListingsByEvent returns the listings buyers can purchase, cheapest first
+	ListActiveResaleListingsByEvent(eventID uuid.UUID) ([]models.ResaleListing, error)
+
+	// ListResaleListingsBySeller returns every listing of a seller, newest first
+	ListResaleListingsBySeller(sellerID uuid.UUID) ([]models.ResaleListing, error)
+
+	// CancelResaleListing withdraws an active listing and gives the ticket back to the
+	// seller with its listed version. It reports whether the listing was still active.
+	CancelResaleListing(listing *models.ResaleListing) (bool, error)
+
+	// ReserveResaleListing reserves an active listing for the buyer of the order and stores
+	// the order in one transaction. It reports false without storing anything when the
+	// listing is no longer active.
+	ReserveResaleListing(listing *models.ResaleListing, order *models.Order) (bool, error)
+
+	// ReleaseResaleListing puts the listing reserved by an unpaid order back on sale
+	ReleaseResaleListing(orderID uuid.UUID) error
+
+	// CompleteResale hands the ticket of a reserved listing to the buyer of its order with
+	// a new version and books it off the seller's order. It reports false without changing
+	// anything when the listing is not reserved for the order or the ticket is gone.
+	CompleteResale(listing *models.ResaleListing, order *models.Order) (bool, error)
+
+	// UpdateResalePayout records the outcome of paying the seller
+	UpdateResalePayout(listing *models.ResaleListing) error
+}
+
+func (s *service) ListTicketForResale(listing *models.ResaleListing) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Ticket{}).
+			Where("id = ? AND owner_id = ? AND version = ? AND status = ?",
+				listing.TicketID, listing.SellerID, listing.TicketVersion-1, models.TicketStatusValid).
+			Updates(map[string]interface{}{
+				"status":  models.TicketStatusListed,
+				"version": listing.TicketVersion,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrResaleTicketNotAllowed
+		}
+		return tx.Create(listing).Error
+	})
+	if err != nil {
+		log.Println("Error listing ticket for resale:", err)
+		return err
+	}
+	return nil
+}
+
+func preloadResaleListing(db *gorm.DB) *gorm.DB {
+	return db.Preload("Event").Preload("TicketType").Preload("EventSeat")
+}
+
+func (s *service) FindResaleListingById(id uuid.UUID) (models.ResaleListing, error) {
+	var listing models.ResaleListing
+	result := preloadResaleListing(s.GetGormDB()).First(&listing, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding resale listing by ID:", result.Error)
+		return models.ResaleListing{}, result.Error
+	}
+	return listing, nil
+}
+
+func (s *service) FindResaleListingByOrder(orderID uuid.UUID) (models.ResaleListing, error) {
+	var listing models.ResaleListing
+	result := s.GetGormDB().First(&listing, "order_id = ?", orderID)
+	if result.Error != nil {
+		log.Println("Error finding resale listing by order:", result.Error)
+		return models.ResaleListing{}, result.Error
+	}
+	return listing, nil
+}
+
+func (s *service) ListActiveResaleListingsByEvent(eventID uuid.UUID) ([]models.ResaleListing, error) {
+	var listings []models.ResaleListing
+	result := s.GetGormDB().
+		Preload("TicketType").
+		Preload("EventSeat").
+		Where("event_id = ? AND status = ?", eventID, models.ResaleListingActive).
+		Order("price ASC, created_at ASC").
+		Find(&listings)
+	if result.Error != nil {
+		log.Println("Error listing resale listings:", result.Error)
+		return nil, result.Error
+	}
+	return listings, nil
+}
+
+func (s *service) ListResaleListingsBySeller(sellerID uuid.UUID) ([]models.ResaleListing, error) {
+	var listings []models.ResaleListing
+	result := preloadResaleListing(s.GetGormDB()).
+		Where("seller_id = ?", sellerID).
+		Order("created_at DESC").
+		Find(&listings)
+	if result.Error != nil {
+		log.Println("Error listing seller resale listings:", result.Error)
+		return nil, result.Error
+	}
+	return listings, nil
+}
+
+func (s *service) CancelResaleListing(listing *models.ResaleListing) (bool, error) {
+	var cancelled bool
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ResaleListing{}).
+			Where("id = ? AND status = ?", listing.ID, models.ResaleListingActive).
+			Update("status", models.ResaleListingCancelled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		// The seller keeps the listed version, their old QR code stays revoked
+		if err := tx.Model(&models.Ticket{}).
+			Where("id = ? AND status = ?", listing.TicketID, models.TicketStatusListed).
+			Update("status", models.TicketStatusValid).Error; err != nil {
+			return err
+		}
+
+		listing.Status = models.ResaleListingCancelled
+		cancelled = true
+		return nil
+	})
+	if err != nil {
+		log.Println("Error cancelling resale listing:", err)
+		return false, err
+	}
+	return cancelled, nil
+}
+
+func (s *service) ReserveResaleListing(listing *models.ResaleListing, order *models.Order) (bool, error) {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.ResaleListing{}).
+			Where("id = ? AND status = ?", listing.ID, models.ResaleListingActive).
+			Updates(map[string]interface{}{
+				"status":   models.ResaleListingReserved,
+				"buyer_id": order.UserID,
+				"order_id": order.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errListingTaken
+		}
+		return nil
+	})
+	if errors.Is(err, errListingTaken) {
+		return false, nil
+	}
+	if err != nil {
+		log.Println("Error reserving resale listing:", err)
+		return false, err
+	}
+
+	listing.Status = models.ResaleListingReserved
+	listing.BuyerID = &order.UserID
+	listing.OrderID = &order.ID
+	return true, nil
+}
+
+var errListingTaken = errors.New("listing is no longer active")
+
+func (s *service) ReleaseResaleListing(orderID uuid.UUID) error {
+	result := s.GetGormDB().Model(&models.ResaleListing{}).
+		Where("order_id = ? AND status = ?", orderID, models.ResaleListingReserved).
+		Updates(map[string]interface{}{
+			"status":   models.ResaleListingActive,
+			"buyer_id": nil,
+			"order_id": nil,
+		})
+	if result.Error != nil {
+		log.Println("Error releasing resale listing:", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *service) CompleteResale(listing *models.ResaleListing, order *models.Order) (bool, error) {
+	if len(order.Items) != 1 {
+		return false, models.ErrResaleListingNotOnOrder
+	}
+
+	var completed bool
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.ResaleListing{}).
+			Where("id = ? AND order_id = ? AND status = ?", listing.ID, order.ID, models.ResaleListingReserved).
+			Updates(map[string]interface{}{
+				"status":        models.ResaleListingSold,
+				"sold_at":       now,
+				"payout_status": models.PayoutStatusPending,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errListingTaken
+		}
+
+		var ticket models.Ticket
+		if err := tx.First(&ticket, "id = ?", listing.TicketID).Error; err != nil {
+			return err
+		}
+
+		result = tx.Model(&models.Ticket{}).
+			Where("id = ? AND version = ? AND status = ?", ticket.ID, listing.TicketVersion, models.TicketStatusListed).
+			Updates(map[string]interface{}{
+				"status":        models.TicketStatusValid,
+				"owner_id":      order.UserID,
+				"version":       listing.TicketVersion + 1,
+				"order_id":      order.ID,
+				"order_item_id": order.Items[0].ID,
+				"number":        1,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// The ticket was refunded meanwhile
+			return errListingTaken
+		}
+
+		if err := tx.Model(&models.OrderItem{}).
+			Where("id = ?", ticket.OrderItemID).
+			Update("resold_quantity", gorm.Expr("resold_quantity + 1")).Error; err != nil {
+			return err
+		}
+
+		listing.Status = models.ResaleListingSold
+		listing.SoldAt = &now
+		listing.PayoutStatus = models.PayoutStatusPending
+		completed = true
+		return nil
+	})
+	if errors.Is(err, errListingTaken) {
+		return false, nil
+	}
+	if err != nil {
+		log.Println("Error completing resale:", err)
+		return false, err
+	}
+	return completed, nil
+}
+
+func (s *service) UpdateResalePayout(listing *models.ResaleListing) error {
+	result := s.GetGormDB().Model(&models.ResaleListing{}).
+		Where("id = ?", listing.ID).
+		Updates(map[string]interface{}{
+			"payout_status": listing.PayoutStatus,
+			"payout_id":     listing.PayoutID,
+			"paid_out_at":   listing.PaidOutAt,
+		})
+	if result.Error != nil {
+		log.Println("Error updating resale payout:", result.Error)
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// RiskStore is the persistence contract for purchase limits and the review of suspicious orders
+type RiskStore interface {
+	// CountUserTickets returns how many tickets of an event the user bought online and
+	// still holds, the starting point of the user's purchase limit counter
+	CountUserTickets(eventID, userID uuid.UUID) (int, error)
+
+	// CountPaymentMethodTickets returns how many tickets of an event were paid for, or are
+	// being paid for, with a payment method
+	CountPaymentMethodTickets(eventID uuid.UUID, fingerprint string) (int, error)
+
+	// SetPaymentMethod writes the payment method of a payment about to be confirmed and
+	// resets it to pending, only if the payment is pending or failed. It reports whether it did.
+	SetPaymentMethod(payment *models.Payment) (bool, error)
+
+	// ReviewOrder writes the review decision and expiry of an order only if it still
+	// awaits review and reports whether it did
+	ReviewOrder(order *models.Order) (bool, error)
+
+	// ListOrdersForReview returns the unpaid orders awaiting review, oldest first
+	ListOrdersForReview() ([]models.Order, error)
+}
+
+// limitedOrderStatuses are the statuses of orders whose tickets count against purchase limits
+var limitedOrderStatuses = []models.OrderStatus{
+	models.OrderStatusPending,
+	models.OrderStatusAwaitingPayment,
+	models.OrderStatusPaid,
+	models.OrderStatusFulfilled,
+	models.OrderStatusRefunded,
+}
+
+func (s *service) CountUserTickets(eventID, userID uuid.UUID) (int, error) {
+	var taken int
+	result := s.GetGormDB().Model(&models.OrderItem{}).
+		Select("COALESCE(SUM(order_items.quantity - order_items.restocked_quantity), 0)").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.event_id = ? AND orders.user_id = ?", eventID, userID).
+		// Resale purchases and box office sales do not count against the limit
+		Where("orders.resale_listing_id IS NULL AND orders.sold_by_id IS NULL").
+		Where("orders.status IN ?", limitedOrderStatuses).
+		Scan(&taken)
+	if result.Error != nil {
+		log.Println("Error counting user tickets:", result.Error)
+		return 0, result.Error
+	}
+	return taken, nil
+}
+
+func (s *service) CountPaymentMethodTickets(eventID uuid.UUID, fingerprint string) (int, error) {
+	var taken int
+	result := s.GetGormDB().Model(&models.Payment{}).
+		Select("COALESCE(SUM(payments.limited_tickets), 0)").
+		Joins("JOIN orders ON orders.id = payments.order_id AND orders.deleted_at IS NULL").
+		Where("orders.event_id = ? AND payments.method_fingerprint = ?", eventID, fingerprint).
+		Where("orders.status IN ?", limitedOrderStatuses).
+		// Failed payments gave their tickets back to the payment method
+		Where("payments.status IN ?", []models.PaymentStatus{
+			models.PaymentStatusProcessing,
+			models.PaymentStatusCaptured,
+			models.PaymentStatusRefunded,
+		}).
+		Scan(&taken)
+	if result.Error != nil {
+		log.Println("Error counting payment method tickets:", result.Error)
+		return 0, result.Error
+	}
+	return taken, nil
+}
+
+func (s *service) SetPaymentMethod(payment *models.Payment) (bool, error) {
+	result := s.GetGormDB().Model(&models.Payment{}).
+		Where("id = ? AND status IN ?", payment.ID, []models.PaymentStatus{models.PaymentStatusPending, models.PaymentStatusFailed}).
+		Updates(map[string]interface{}{
+			"status":             models.PaymentStatusPending,
+			"failure_reason":     "",
+			"method_fingerprint": payment.MethodFingerprint,
+			"limited_tickets":    payment.LimitedTickets,
+		})
+	if result.Error != nil {
+		log.Println("Error setting payment method:", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (s *service) ReviewOrder(order *models.Order) (bool, error) {
+	result := s.GetGormDB().Model(&models.Order{}).
+		Where("id = ? AND review_status = ?", order.ID, models.ReviewStatusPending).
+		Updates(map[string]interface{}{
+			"review_status":  order.ReviewStatus,
+			"reviewed_by_id": order.ReviewedByID,
+			"reviewed_at":    order.ReviewedAt,
+			"expires_at":     order.ExpiresAt,
+		})
+	if result.Error != nil {
+		log.Println("Error reviewing order:", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (s *service) ListOrdersForReview() ([]models.Order, error) {
+	var orders []models.Order
+	result := preloadOrderItems(s.GetGormDB()).
+		Where("review_status = ? AND status IN ?", models.ReviewStatusPending,
+			[]models.OrderStatus{models.OrderStatusPending, models.OrderStatusAwaitingPayment}).
+		Order("created_at ASC").
+		Find(&orders)
+	if result.Error != nil {
+		log.Println("Error listing orders for review:", result.Error)
+		return nil, result.Error
+	}
+	return orders, nil
+}
@@ -0,0 +1,194 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoleStore interface {
+	CreateRole(role *models.Role) error
+	FindRoleById(id uuid.UUID) (models.Role, error)
+	FindRoleByName(name string) (models.Role, error)
+	// FindRolesByName returns the roles that exist among the names
+	FindRolesByName(names []string) ([]models.Role, error)
+	ListRoles() ([]models.Role, error)
+	UpdateRole(role *models.Role) error
+	// DeleteRole removes a role and takes it away from its users
+	DeleteRole(id uuid.UUID) error
+
+	ListRolesByUser(userID uuid.UUID) ([]models.Role, error)
+	// ListRolesByUserEmail returns the roles of the user with the email, used to authorize requests
+	ListRolesByUserEmail(email string) ([]models.Role, error)
+	// SetUserRoles replaces the roles of a user and keeps users.is_admin in line with the
+	// admin role. Fails with models.ErrLastAdmin if nobody would keep the admin role.
+	SetUserRoles(userID uuid.UUID, roleIDs []uuid.UUID) error
+	// GrantRoleToAdmins gives the role to every user flagged is_admin who does not have it
+	GrantRoleToAdmins(roleID uuid.UUID) error
+}
+
+func (s *service) CreateRole(role *models.Role) error {
+	result := s.GetGormDB().Create(role)
+	if result.Error != nil {
+		log.Println("Error creating role:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no rows affected, role not created")
+	}
+	return nil
+}
+
+func (s *service) FindRoleById(id uuid.UUID) (models.Role, error) {
+	var role models.Role
+	result := s.GetGormDB().First(&role, "id = ?", id)
+	if result.Error != nil {
+		return models.Role{}, result.Error
+	}
+	return role, nil
+}
+
+func (s *service) FindRoleByName(name string) (models.Role, error) {
+	var role models.Role
+	result := s.GetGormDB().First(&role, "name = ?", name)
+	if result.Error != nil {
+		return models.Role{}, result.Error
+	}
+	return role, nil
+}
+
+func (s *service) FindRolesByName(names []string) ([]models.Role, error) {
+	var roles []models.Role
+	result := s.GetGormDB().Where("name IN ?", names).Order("name ASC").Find(&roles)
+	if result.Error != nil {
+		log.Println("Error finding roles by name:", result.Error)
+		return nil, result.Error
+	}
+	return roles, nil
+}
+
+func (s *service) ListRoles() ([]models.Role, error) {
+	var roles []models.Role
+	result := s.GetGormDB().Order("name ASC").Find(&roles)
+	if result.Error != nil {
+		log.Println("Error listing roles:", result.Error)
+		return nil, result.Error
+	}
+	return roles, nil
+}
+
+func (s *service) UpdateRole(role *models.Role) error {
+	result := s.GetGormDB().Model(&models.Role{}).Where("id = ?", role.ID).
+		Select("name", "description", "permissions").Updates(role)
+	if result.Error != nil {
+		log.Println("Error updating role:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *service) DeleteRole(id uuid.UUID) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Role{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println("Error deleting role:", err)
+	}
+	return err
+}
+
+func (s *service) ListRolesByUser(userID uuid.UUID) ([]models.Role, error) {
+	var roles []models.Role
+	result := s.GetGormDB().
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name ASC").
+		Find(&roles)
+	if result.Error != nil {
+		log.Println("Error listing roles of user:", result.Error)
+		return nil, result.Error
+	}
+	return roles, nil
+}
+
+func (s *service) ListRolesByUserEmail(email string) ([]models.Role, error) {
+	var roles []models.Role
+	result := s.GetGormDB().
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Joins("JOIN users ON users.id = user_roles.user_id").
+		Where("users.email = ? AND users.deleted_at IS NULL AND users.is_active", email).
+		Find(&roles)
+	if result.Error != nil {
+		log.Println("Error listing roles of user:", result.Error)
+		return nil, result.Error
+	}
+	return roles, nil
+}
+
+func (s *service) SetUserRoles(userID uuid.UUID, roleIDs []uuid.UUID) error {
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		var admin models.Role
+		if err := tx.First(&admin, "name = ?", models.AdminRoleName).Error; err != nil {
+			return err
+		}
+		// Lock the admin assignments so two admins cannot revoke each other at the same time
+		var admins []uuid.UUID
+		if err := tx.Model(&models.UserRole{}).Where("role_id = ?", admin.ID).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Pluck("user_id", &admins).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		isAdmin := false
+		if len(roleIDs) > 0 {
+			assignments := make([]models.UserRole, 0, len(roleIDs))
+			for _, roleID := range roleIDs {
+				assignments = append(assignments, models.UserRole{UserID: userID, RoleID: roleID})
+				isAdmin = isAdmin || roleID == admin.ID
+			}
+			if err := tx.Create(&assignments).Error; err != nil {
+				return err
+			}
+		}
+
+		if !isAdmin && len(admins) == 1 && admins[0] == userID {
+			return models.ErrLastAdmin
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).Update("is_admin", isAdmin).Error
+	})
+	if err != nil && !errors.Is(err, models.ErrLastAdmin) {
+		log.Println("Error setting user roles:", err)
+	}
+	return err
+}
+
+func (s *service) GrantRoleToAdmins(roleID uuid.UUID) error {
+	result := s.GetGormDB().Exec(`INSERT INTO user_roles (user_id, role_id, created_at)
+		SELECT users.id, ?, NOW() FROM users WHERE users.is_admin AND users.deleted_at IS NULL
+		ON CONFLICT DO NOTHING`, roleID)
+	if result.Error != nil {
+		log.Println("Error granting role to admins:", result.Error)
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TicketTypeStore is the persistence contract for event ticket types
+type TicketTypeStore interface {
+	// SaveTicketTypeWithinCapacity creates or updates a ticket type while holding a lock
+	// on its event, so concurrent edits cannot push the total quantity past the capacity
+	SaveTicketTypeWithinCapacity(ticketType *models.TicketType) error
+
+	FindTicketTypeById(id uuid.UUID) (models.TicketType, error)
+
+	ListTicketTypesByEvent(eventID uuid.UUID) ([]models.TicketType, error)
+
+	DeleteTicketType(id uuid.UUID) error
+
+	// SumTicketTypeQuantities sums the quantities of the ticket types of an event, counting
+	// the passes of its series that admit to it
+	SumTicketTypeQuantities(eventID uuid.UUID) (int, error)
+
+	// ListPassesBySeries returns the passes sold on the occurrences of a series
+	ListPassesBySeries(seriesID uuid.UUID) ([]models.TicketType, error)
+}
+
+func (s *service) SaveTicketTypeWithinCapacity(ticketType *models.TicketType) error {
+	return s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		// A pass takes a seat at every occurrence it admits to, all of them are locked in
+		// the order of their IDs so concurrent edits cannot deadlock
+		eventIDs := append([]uuid.UUID{ticketType.EventID}, ticketType.PassEventIDs...)
+		var events []models.Event
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", eventIDs).Order("id").Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) != len(eventIDs) {
+			return gorm.ErrRecordNotFound
+		}
+
+		for _, event := range events {
+			allocated, err := allocatedQuantity(tx, event, ticketType.ID)
+			if err != nil {
+				return err
+			}
+			if allocated+ticketType.Quantity > event.Capacity {
+				return models.ErrTicketCapacityExceeded
+			}
+		}
+
+		if ticketType.ID == uuid.Nil {
+			return tx.Create(ticketType).Error
+		}
+		return tx.Where("id = ?", ticketType.ID).Select("*").Omit("created_at").Updates(ticketType).Error
+	})
+}
+
+// allocatedQuantity sums the quantities of the ticket types of an event and of the passes
+// sold on other occurrences of its series that admit to it, leaving out one ticket type
+func allocatedQuantity(tx *gorm.DB, event models.Event, excludeID uuid.UUID) (int, error) {
+	var allocated int
+	if err := tx.Model(&models.TicketType{}).
+		Where("event_id = ? AND id <> ?", event.ID, excludeID).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&allocated).Error; err != nil {
+		return 0, err
+	}
+
+	passes, err := seriesPasses(tx, event)
+	if err != nil {
+		return 0, err
+	}
+	for _, pass := range passes {
+		if pass.ID != excludeID && pass.EventID != event.ID && pass.Admits(event.ID) {
+			allocated += pass.Quantity
+		}
+	}
+	return allocated, nil
+}
+
+// seriesPasses returns the passes sold on the occurrences of the series of an event
+func seriesPasses(tx *gorm.DB, event models.Event) ([]models.TicketType, error) {
+	if event.SeriesID == nil {
+		return nil, nil
+	}
+	var ticketTypes []models.TicketType
+	if err := tx.Where("event_id IN (?)", tx.Model(&models.Event{}).Select("id").Where("series_id = ?", *event.SeriesID)).
+		Find(&ticketTypes).Error; err != nil {
+		return nil, err
+	}
+	passes := ticketTypes[:0]
+	for _, ticketType := range ticketTypes {
+		if ticketType.IsPass() {
+			passes = append(passes, ticketType)
+		}
+	}
+	return passes, nil
+}
+
+func (s *service) FindTicketTypeById(id uuid.UUID) (models.TicketType, error) {
+	var ticketType models.TicketType
+	result := s.GetGormDB().First(&ticketType, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding ticket type by ID:", result.Error)
+		return models.TicketType{}, result.Error
+	}
+	return ticketType, nil
+}
+
+func (s *service) ListTicketTypesByEvent(eventID uuid.UUID) ([]models.TicketType, error) {
+	var ticketTypes []models.TicketType
+	result := s.GetGormDB().Where("event_id = ?", eventID).Order("position ASC, created_at ASC").Find(&ticketTypes)
+	if result.Error != nil {
+		log.Println("Error listing ticket types:", result.Error)
+		return nil, result.Error
+	}
+	return ticketTypes, nil
+}
+
+func (s *service) DeleteTicketType(id uuid.UUID) error {
+	result := s.GetGormDB().Delete(&models.TicketType{}, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error deleting ticket type:", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *service) SumTicketTypeQuantities(eventID uuid.UUID) (int, error) {
+	var event models.Event
+	if err := s.GetGormDB().First(&event, "id = ?", eventID).Error; err != nil {
+		return 0, err
+	}
+	return allocatedQuantity(s.GetGormDB(), event, uuid.Nil)
+}
+
+func (s *service) ListPassesBySeries(seriesID uuid.UUID) ([]models.TicketType, error) {
+	passes, err := seriesPasses(s.GetGormDB(), models.Event{SeriesID: &seriesID})
+	if err != nil {
+		log.Println("Error listing series passes:", err)
+		return nil, err
+	}
+	return passes, nil
+}
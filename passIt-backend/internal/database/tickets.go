@@ -0,0 +1,78 @@
+package database
+
+import (
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TicketStore is the persistence contract for issued tickets
+type TicketStore interface {
+	// IssueTickets stores new tickets, skipping those already issued for the same
+	// order line and number so issuing an order twice is harmless
+	IssueTickets(tickets []models.Ticket) error
+
+	FindTicketById(id uuid.UUID) (models.Ticket, error)
+
+	// ListTicketsByOwner returns the tickets of a user with their event, ticket type and seat
+	ListTicketsByOwner(ownerID uuid.UUID) ([]models.Ticket, error)
+
+	ListTicketsByOrder(orderID uuid.UUID) ([]models.Ticket, error)
+}
+
+func preloadTicketDetails(db *gorm.DB) *gorm.DB {
+	return db.Preload("Event").Preload("TicketType").Preload("EventSeat")
+}
+
+func (s *service) IssueTickets(tickets []models.Ticket) error {
+	if len(tickets) == 0 {
+		return nil
+	}
+	result := s.GetGormDB().
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "order_item_id"}, {Name: "number"}},
+			DoNothing: true,
+		}).
+		Create(&tickets)
+	if result.Error != nil {
+		log.Println("Error issuing tickets:", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *service) FindTicketById(id uuid.UUID) (models.Ticket, error) {
+	var ticket models.Ticket
+	result := preloadTicketDetails(s.GetGormDB()).First(&ticket, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding ticket by ID:", result.Error)
+		return models.Ticket{}, result.Error
+	}
+	return ticket, nil
+}
+
+func (s *service) ListTicketsByOwner(ownerID uuid.UUID) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	result := preloadTicketDetails(s.GetGormDB()).
+		Where("owner_id = ?", ownerID).
+		Order("issued_at DESC, number ASC").
+		Find(&tickets)
+	if result.Error != nil {
+		log.Println("Error listing tickets:", result.Error)
+		return nil, result.Error
+	}
+	return tickets, nil
+}
+
+func (s *service) ListTicketsByOrder(orderID uuid.UUID) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	result := s.GetGormDB().Where("order_id = ?", orderID).Order("order_item_id ASC, number ASC").Find(&tickets)
+	if result.Error != nil {
+		log.Println("Error listing order tickets:", result.Error)
+		return nil, result.Error
+	}
+	return tickets, nil
+}
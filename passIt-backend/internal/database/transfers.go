@@ -0,0 +1,161 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"passIt/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TransferStore is the persistence contract for ticket transfers
+type TransferStore interface {
+	// CreateTransfer stores a pending transfer. A ticket can only have one pending transfer.
+	CreateTransfer(transfer *models.TicketTransfer) error
+
+	FindTransferById(id uuid.UUID) (models.TicketTransfer, error)
+
+	// FindPendingTransferByTicket returns the open transfer of a ticket, if any
+	FindPendingTransferByTicket(ticketID uuid.UUID) (models.TicketTransfer, error)
+
+	// ListTransfersByUser returns the transfers a user sent or received with their tickets, newest first
+	ListTransfersByUser(userID uuid.UUID) ([]models.TicketTransfer, error)
+
+	// ListAcceptedTransfersByTicket returns the ownership chain of a ticket, oldest first
+	ListAcceptedTransfersByTicket(ticketID uuid.UUID) ([]models.TicketTransfer, error)
+
+	// CloseTransfer moves a pending transfer to a final status and reports whether it did
+	CloseTransfer(transfer *models.TicketTransfer) (bool, error)
+
+	// AcceptTransfer hands the ticket to the recipient with a new version in one transaction.
+	// It reports false without changing anything when the transfer is no longer pending or
+	// the ticket changed since the transfer was started.
+	AcceptTransfer(transfer *models.TicketTransfer) (bool, error)
+}
+
+func (s *service) CreateTransfer(transfer *models.TicketTransfer) error {
+	result := s.GetGormDB().Create(transfer)
+	if result.Error != nil {
+		log.Println("Error creating transfer:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no rows affected, transfer not created")
+	}
+	return nil
+}
+
+func (s *service) FindTransferById(id uuid.UUID) (models.TicketTransfer, error) {
+	var transfer models.TicketTransfer
+	result := s.GetGormDB().Preload("Ticket.Event").First(&transfer, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding transfer by ID:", result.Error)
+		return models.TicketTransfer{}, result.Error
+	}
+	return transfer, nil
+}
+
+func (s *service) FindPendingTransferByTicket(ticketID uuid.UUID) (models.TicketTransfer, error) {
+	var transfer models.TicketTransfer
+	result := s.GetGormDB().First(&transfer, "ticket_id = ? AND status = ?", ticketID, models.TransferStatusPending)
+	if result.Error != nil {
+		return models.TicketTransfer{}, result.Error
+	}
+	return transfer, nil
+}
+
+func (s *service) ListTransfersByUser(userID uuid.UUID) ([]models.TicketTransfer, error) {
+	var transfers []models.TicketTransfer
+	result := s.GetGormDB().
+		Preload("Ticket.Event").
+		Preload("Ticket.TicketType").
+		Where("from_user_id = ? OR to_user_id = ?", userID, userID).
+		Order("created_at DESC").
+		Find(&transfers)
+	if result.Error != nil {
+		log.Println("Error listing transfers:", result.Error)
+		return nil, result.Error
+	}
+	return transfers, nil
+}
+
+func (s *service) ListAcceptedTransfersByTicket(ticketID uuid.UUID) ([]models.TicketTransfer, error) {
+	var transfers []models.TicketTransfer
+	result := s.GetGormDB().
+		Where("ticket_id = ? AND status = ?", ticketID, models.TransferStatusAccepted).
+		Order("responded_at ASC").
+		Find(&transfers)
+	if result.Error != nil {
+		log.Println("Error listing ticket transfers:", result.Error)
+		return nil, result.Error
+	}
+	return transfers, nil
+}
+
+func (s *service) CloseTransfer(transfer *models.TicketTransfer) (bool, error) {
+	result := s.GetGormDB().Model(&models.TicketTransfer{}).
+		Where("id = ? AND status = ?", transfer.ID, models.TransferStatusPending).
+		Updates(map[string]interface{}{
+			"status":       transfer.Status,
+			"responded_at": transfer.RespondedAt,
+		})
+	if result.Error != nil {
+		log.Println("Error closing transfer:", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (s *service) AcceptTransfer(transfer *models.TicketTransfer) (bool, error) {
+	var accepted bool
+	err := s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.TicketTransfer{}).
+			Where("id = ? AND status = ?", transfer.ID, models.TransferStatusPending).
+			Updates(map[string]interface{}{
+				"status":       models.TransferStatusAccepted,
+				"to_version":   transfer.FromVersion + 1,
+				"responded_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		result = tx.Model(&models.Ticket{}).
+			Where("id = ? AND owner_id = ? AND version = ? AND status = ?",
+				transfer.TicketID, transfer.FromUserID, transfer.FromVersion, models.TicketStatusValid).
+			Updates(map[string]interface{}{
+				"owner_id": transfer.ToUserID,
+				"version":  transfer.FromVersion + 1,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// The ticket was used, refunded or reissued meanwhile, keep the transfer pending
+			// so the caller can cancel it
+			return errStaleTransfer
+		}
+
+		transfer.Status = models.TransferStatusAccepted
+		transfer.ToVersion = transfer.FromVersion + 1
+		transfer.RespondedAt = &now
+		accepted = true
+		return nil
+	})
+	if errors.Is(err, errStaleTransfer) {
+		return false, nil
+	}
+	if err != nil {
+		log.Println("Error accepting transfer:", err)
+		return false, err
+	}
+	return accepted, nil
+}
+
+var errStaleTransfer = errors.New("ticket changed since the transfer was started")
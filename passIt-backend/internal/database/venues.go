@@ -0,0 +1,162 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VenueStore is the persistence contract for venues and their seating layouts
+type VenueStore interface {
+	// CreateVenue stores a venue together with its nested sections, rows and seats
+	CreateVenue(venue *models.Venue) error
+
+	// FindVenueById loads a venue with its full seating layout
+	FindVenueById(id uuid.UUID) (models.Venue, error)
+
+	ListVenues() ([]models.Venue, error)
+
+	UpdateVenueDetails(venue *models.Venue) error
+
+	// ReplaceVenueLayout swaps the seating layout of a venue in a single transaction
+	ReplaceVenueLayout(venueID uuid.UUID, sections []models.VenueSection) error
+
+	CountEventsWithSeatsForVenue(venueID uuid.UUID) (int64, error)
+
+	// AttachVenueToEvent links the venue to the event and regenerates its seat inventory
+	AttachVenueToEvent(event *models.Event, seats []models.EventSeat) error
+
+	ListEventSeats(eventID uuid.UUID) ([]models.EventSeat, error)
+
+	CountSoldEventSeats(eventID uuid.UUID) (int64, error)
+}
+
+// preloadLayout loads sections, rows and seats in their layout order
+func preloadLayout(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Sections", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Sections.Rows", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Sections.Rows.Seats", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") })
+}
+
+func (s *service) CreateVenue(venue *models.Venue) error {
+	result := s.GetGormDB().Create(venue)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no rows affected, venue not created")
+	}
+	return nil
+}
+
+func (s *service) FindVenueById(id uuid.UUID) (models.Venue, error) {
+	var venue models.Venue
+	result := preloadLayout(s.GetGormDB()).First(&venue, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding venue by ID:", result.Error)
+		return models.Venue{}, result.Error
+	}
+	return venue, nil
+}
+
+func (s *service) ListVenues() ([]models.Venue, error) {
+	var venues []models.Venue
+	result := s.GetGormDB().Order("name ASC").Find(&venues)
+	if result.Error != nil {
+		log.Println("Error listing venues:", result.Error)
+		return nil, result.Error
+	}
+	return venues, nil
+}
+
+func (s *service) UpdateVenueDetails(venue *models.Venue) error {
+	result := s.GetGormDB().Model(&models.Venue{}).Where("id = ?", venue.ID).Updates(map[string]interface{}{
+		"name":      venue.Name,
+		"address":   venue.Address,
+		"city":      venue.City,
+		"country":   venue.Country,
+		"time_zone": venue.TimeZone,
+
+		"tax_jurisdiction": venue.TaxJurisdiction,
+	})
+	if result.Error != nil {
+		log.Println("Error updating venue by ID:", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *service) ReplaceVenueLayout(venueID uuid.UUID, sections []models.VenueSection) error {
+	return s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		// Rows and seats are removed by the ON DELETE CASCADE constraints
+		if err := tx.Where("venue_id = ?", venueID).Delete(&models.VenueSection{}).Error; err != nil {
+			return err
+		}
+		for i := range sections {
+			sections[i].VenueID = venueID
+		}
+		if err := tx.Create(&sections).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Venue{}).Where("id = ?", venueID).Update("updated_at", gorm.Expr("NOW()")).Error
+	})
+}
+
+func (s *service) CountEventsWithSeatsForVenue(venueID uuid.UUID) (int64, error) {
+	var count int64
+	result := s.GetGormDB().Model(&models.Event{}).
+		Where("venue_id = ?", venueID).
+		Where("EXISTS (SELECT 1 FROM event_seats WHERE event_seats.event_id = events.id)").
+		Count(&count)
+	if result.Error != nil {
+		log.Println("Error counting events for venue:", result.Error)
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+func (s *service) AttachVenueToEvent(event *models.Event, seats []models.EventSeat) error {
+	return s.GetGormDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ?", event.ID).Delete(&models.EventSeat{}).Error; err != nil {
+			return err
+		}
+		if len(seats) > 0 {
+			if err := tx.CreateInBatches(&seats, 500).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Event{}).Where("id = ?", event.ID).Updates(map[string]interface{}{
+			"venue_id": event.VenueID,
+			"venue":    event.Venue,
+			"capacity": event.Capacity,
+		}).Error
+	})
+}
+
+func (s *service) ListEventSeats(eventID uuid.UUID) ([]models.EventSeat, error) {
+	var seats []models.EventSeat
+	result := s.GetGormDB().
+		Where("event_id = ?", eventID).
+		Order("position ASC").
+		Find(&seats)
+	if result.Error != nil {
+		log.Println("Error listing event seats:", result.Error)
+		return nil, result.Error
+	}
+	return seats, nil
+}
+
+func (s *service) CountSoldEventSeats(eventID uuid.UUID) (int64, error) {
+	var count int64
+	result := s.GetGormDB().Model(&models.EventSeat{}).
+		Where("event_id = ? AND status = ?", eventID, models.EventSeatSold).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
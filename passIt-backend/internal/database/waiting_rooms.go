@@ -0,0 +1,46 @@
+package database
+
+import (
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// WaitingRoomStore is the persistence contract for the waiting rooms of events
+type WaitingRoomStore interface {
+	// SaveWaitingRoom creates or replaces the waiting room of an event
+	SaveWaitingRoom(room *models.WaitingRoom) error
+	FindWaitingRoomByEvent(eventID uuid.UUID) (models.WaitingRoom, error)
+	DeleteWaitingRoom(eventID uuid.UUID) error
+}
+
+func (s *service) SaveWaitingRoom(room *models.WaitingRoom) error {
+	err := s.GetGormDB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "opens_at", "closes_at", "admit_per_minute", "admission_minutes"}),
+	}).Create(room).Error
+	if err != nil {
+		log.Println("Error saving waiting room:", err)
+		return err
+	}
+	return nil
+}
+
+func (s *service) FindWaitingRoomByEvent(eventID uuid.UUID) (models.WaitingRoom, error) {
+	var room models.WaitingRoom
+	result := s.GetGormDB().First(&room, "event_id = ?", eventID)
+	if result.Error != nil {
+		return models.WaitingRoom{}, result.Error
+	}
+	return room, nil
+}
+
+func (s *service) DeleteWaitingRoom(eventID uuid.UUID) error {
+	if err := s.GetGormDB().Delete(&models.WaitingRoom{}, "event_id = ?", eventID).Error; err != nil {
+		log.Println("Error deleting waiting room:", err)
+		return err
+	}
+	return nil
+}
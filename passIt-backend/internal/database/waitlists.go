@@ -0,0 +1,188 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// WaitlistStore is the persistence contract for ticket type waitlists
+type WaitlistStore interface {
+	// CreateWaitlistEntry queues a user. A user can only have one open entry per ticket type.
+	CreateWaitlistEntry(entry *models.WaitlistEntry) error
+
+	FindWaitlistEntryById(id uuid.UUID) (models.WaitlistEntry, error)
+
+	// FindOpenWaitlistEntry returns the waiting or offered entry of a user for a ticket type, if any
+	FindOpenWaitlistEntry(ticketTypeID, userID uuid.UUID) (models.WaitlistEntry, error)
+
+	// ListWaitlistEntriesByUser returns every entry of a user with its ticket type, newest first
+	ListWaitlistEntriesByUser(userID uuid.UUID) ([]models.WaitlistEntry, error)
+
+	// ListWaitingEntries returns the waiting entries of a ticket type in queue order
+	ListWaitingEntries(ticketTypeID uuid.UUID) ([]models.WaitlistEntry, error)
+
+	// ListWaitlistedTicketTypes returns the ticket types that have someone waiting
+	ListWaitlistedTicketTypes() ([]uuid.UUID, error)
+
+	// ListOfferedWaitlistEntries returns every entry with an outstanding offer
+	ListOfferedWaitlistEntries() ([]models.WaitlistEntry, error)
+
+	// CountWaitingEntries returns per ticket type how many waiting users could still be offered
+	// tickets, users whose online purchases already put them over the event limit do not count
+	CountWaitingEntries(ticketTypeIDs []uuid.UUID) (map[uuid.UUID]int, error)
+
+	// WaitlistPosition returns the 1-based place of a waiting entry in its queue
+	WaitlistPosition(entry *models.WaitlistEntry) (int, error)
+
+	// UpdateWaitlistEntry writes the status and offer of the entry only if it is still
+	// in the given status and reports whether it did
+	UpdateWaitlistEntry(entry *models.WaitlistEntry, from models.WaitlistStatus) (bool, error)
+}
+
+func (s *service) CreateWaitlistEntry(entry *models.WaitlistEntry) error {
+	result := s.GetGormDB().Create(entry)
+	if result.Error != nil {
+		log.Println("Error creating waitlist entry:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("no rows affected, waitlist entry not created")
+	}
+	return nil
+}
+
+func (s *service) FindWaitlistEntryById(id uuid.UUID) (models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	result := s.GetGormDB().Preload("TicketType").First(&entry, "id = ?", id)
+	if result.Error != nil {
+		log.Println("Error finding waitlist entry by ID:", result.Error)
+		return models.WaitlistEntry{}, result.Error
+	}
+	return entry, nil
+}
+
+func (s *service) FindOpenWaitlistEntry(ticketTypeID, userID uuid.UUID) (models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	result := s.GetGormDB().First(&entry, "ticket_type_id = ? AND user_id = ? AND status IN ?",
+		ticketTypeID, userID, []models.WaitlistStatus{models.WaitlistStatusWaiting, models.WaitlistStatusOffered})
+	if result.Error != nil {
+		return models.WaitlistEntry{}, result.Error
+	}
+	return entry, nil
+}
+
+func (s *service) ListWaitlistEntriesByUser(userID uuid.UUID) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	result := s.GetGormDB().
+		Preload("TicketType").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&entries)
+	if result.Error != nil {
+		log.Println("Error listing waitlist entries:", result.Error)
+		return nil, result.Error
+	}
+	return entries, nil
+}
+
+func (s *service) ListWaitingEntries(ticketTypeID uuid.UUID) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	result := s.GetGormDB().
+		Where("ticket_type_id = ? AND status = ?", ticketTypeID, models.WaitlistStatusWaiting).
+		Order("created_at ASC, id ASC").
+		Find(&entries)
+	if result.Error != nil {
+		log.Println("Error listing waiting entries:", result.Error)
+		return nil, result.Error
+	}
+	return entries, nil
+}
+
+func (s *service) ListWaitlistedTicketTypes() ([]uuid.UUID, error) {
+	var ticketTypeIDs []uuid.UUID
+	result := s.GetGormDB().Model(&models.WaitlistEntry{}).
+		Where("status = ?", models.WaitlistStatusWaiting).
+		Distinct().
+		Pluck("ticket_type_id", &ticketTypeIDs)
+	if result.Error != nil {
+		log.Println("Error listing waitlisted ticket types:", result.Error)
+		return nil, result.Error
+	}
+	return ticketTypeIDs, nil
+}
+
+func (s *service) ListOfferedWaitlistEntries() ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	result := s.GetGormDB().
+		Where("status = ?", models.WaitlistStatusOffered).
+		Order("offer_expires_at ASC").
+		Find(&entries)
+	if result.Error != nil {
+		log.Println("Error listing waitlist offers:", result.Error)
+		return nil, result.Error
+	}
+	return entries, nil
+}
+
+func (s *service) CountWaitingEntries(ticketTypeIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		TicketTypeID uuid.UUID
+		Waiting      int
+	}
+	bought := s.GetGormDB().Model(&models.OrderItem{}).
+		Select("COALESCE(SUM(order_items.quantity - order_items.restocked_quantity), 0)").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.event_id = waitlist_entries.event_id AND orders.user_id = waitlist_entries.user_id").
+		Where("orders.resale_listing_id IS NULL AND orders.sold_by_id IS NULL").
+		Where("orders.status IN ?", limitedOrderStatuses)
+	result := s.GetGormDB().Model(&models.WaitlistEntry{}).
+		Select("waitlist_entries.ticket_type_id, COUNT(*) AS waiting").
+		Joins("JOIN events ON events.id = waitlist_entries.event_id").
+		Where("waitlist_entries.ticket_type_id IN ? AND waitlist_entries.status = ?", ticketTypeIDs, models.WaitlistStatusWaiting).
+		Where("events.max_tickets_per_user <= 0 OR waitlist_entries.quantity + (?) <= events.max_tickets_per_user", bought).
+		Group("waitlist_entries.ticket_type_id").
+		Scan(&rows)
+	if result.Error != nil {
+		log.Println("Error counting waiting entries:", result.Error)
+		return nil, result.Error
+	}
+
+	waiting := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		waiting[row.TicketTypeID] = row.Waiting
+	}
+	return waiting, nil
+}
+
+func (s *service) WaitlistPosition(entry *models.WaitlistEntry) (int, error) {
+	var ahead int64
+	result := s.GetGormDB().Model(&models.WaitlistEntry{}).
+		Where("ticket_type_id = ? AND status = ?", entry.TicketTypeID, models.WaitlistStatusWaiting).
+		Where("created_at < ? OR (created_at = ? AND id < ?)", entry.CreatedAt, entry.CreatedAt, entry.ID).
+		Count(&ahead)
+	if result.Error != nil {
+		log.Println("Error computing waitlist position:", result.Error)
+		return 0, result.Error
+	}
+	return int(ahead) + 1, nil
+}
+
+func (s *service) UpdateWaitlistEntry(entry *models.WaitlistEntry, from models.WaitlistStatus) (bool, error) {
+	result := s.GetGormDB().Model(&models.WaitlistEntry{}).
+		Where("id = ? AND status = ?", entry.ID, from).
+		Updates(map[string]interface{}{
+			"status":           entry.Status,
+			"hold_id":          entry.HoldID,
+			"offered_at":       entry.OfferedAt,
+			"offer_expires_at": entry.OfferExpiresAt,
+			"skip_reason":      entry.SkipReason,
+		})
+	if result.Error != nil {
+		log.Println("Error updating waitlist entry:", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
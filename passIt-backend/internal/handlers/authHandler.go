@@ -174,8 +174,20 @@ func (a *AuthHandler) CallbackHandler(c *gin.Context) {
 		return
 	}
 	
-	// Fetch user from database to get admin status
-	dbUser, err := a.userService.GetUserByEmail(c, userInfo.Email)
+	// Fetch user from database to get admin status, a box office guest record of the email
+	// becomes the account once the email is verified
+	dbUser, err := a.userService.ClaimGuestAccount(c, services.GuestClaim{
+		KeycloakID:    userInfo.Subject,
+		Email:         userInfo.Email,
+		EmailVerified: userInfo.EmailVerified,
+		Username:      userInfo.Username,
+		FirstName:     userInfo.GivenName,
+		LastName:      userInfo.FamilyName,
+	})
+	if errors.Is(err, services.ErrEmailNotVerified) {
+		c.Redirect(http.StatusTemporaryRedirect, fmt.Sprintf("%s/login?error=email_not_verified", a.frontendURL))
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user from database"})
 		log.Printf("Failed to fetch user: %v", err)
@@ -245,8 +257,12 @@ func (a *AuthHandler) tokenExchange(c *gin.Context) (*oauth2.Token, error) {
 }
 
 type oidcClaims struct {
-	Email    string `json:"email"`
-	Username string `json:"preferred_username"`
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Username      string `json:"preferred_username"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
 }
 
 // ValidateIDToken verifies the id token from the oauth2token
@@ -323,6 +339,7 @@ type SignupRequest struct {
 // @Produce      json
 // @Param        user body SignupRequest true "User signup data"
 // @Success      201 {object} map[string]interface{}
+// @Success      202 {object} map[string]interface{} "The email belongs to box office orders and must be verified first"
 // @Failure      400 {object} map[string]string
 // @Failure      500 {object} map[string]string
 // @Router       /auth/signup [post]
@@ -344,6 +361,12 @@ func (a *AuthHandler) SignupHandler(c *gin.Context) {
 	}
 
 	err := a.userService.CreateUser(c, user, req.Password)
+	if errors.Is(err, services.ErrEmailVerificationRequired) {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "User created successfully. Please verify your email before logging in.",
+		})
+		return
+	}
 	if err != nil {
 		log.Printf("Failed to create user: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create user: %v", err)})
@@ -0,0 +1,171 @@
+package invoices
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"passIt/internal/pricing"
+
+	"github.com/go-pdf/fpdf"
+)
+
+type Config struct {
+	IssuerName    string // legal name of the seller printed on invoices
+	IssuerAddress string // postal address of the seller, one line per \n
+	IssuerTaxID   string // VAT or tax registration number of the seller, optional
+}
+
+// Party is the seller or the buyer of an invoice
+type Party struct {
+	Name    string
+	Company string
+	Address string
+	TaxID   string
+	Email   string
+}
+
+// Line is one itemized line of an invoice. Amounts are in minor units.
+type Line struct {
+	Description string
+	Quantity    int
+	UnitPrice   int64
+	Discount    int64
+	Total       int64
+}
+
+// Document holds everything printed on an invoice
+type Document struct {
+	Number    string
+	IssuedAt  time.Time
+	PaidAt    time.Time
+	Reference string // order the invoice is for
+	Event     string
+	Seller    Party
+	Buyer     Party
+	Currency  string
+	Lines     []Line
+	Subtotal  int64
+	Discount  int64
+	Fees      []pricing.Charge
+	Taxes     []pricing.Charge
+	Total     int64
+}
+
+var ErrIncompleteDocument = errors.New("invoice needs a number, a currency and at least one line")
+
+// Render lays out the document as an A4 PDF. Only the core PDF fonts are used, so
+// rendering needs no font files or external services.
+func Render(doc Document) ([]byte, error) {
+	if doc.Number == "" || doc.Currency == "" || len(doc.Lines) == 0 {
+		return nil, ErrIncompleteDocument
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Invoice "+doc.Number, true)
+	pdf.SetCreator("PassIt", true)
+	pdf.SetCreationDate(doc.IssuedAt)
+	pdf.SetModificationDate(doc.IssuedAt)
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+	// Core fonts use cp1252, translate the UTF-8 input so accented names print correctly
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	amount := func(value int64) string { return pricing.Format(value, doc.Currency) }
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, tr("Invoice"), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 5, tr("Invoice number: "+doc.Number), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, tr("Invoice date: "+doc.IssuedAt.Format("2006-01-02")), "", 1, "L", false, 0, "")
+	if !doc.PaidAt.IsZero() {
+		pdf.CellFormat(0, 5, tr("Paid on: "+doc.PaidAt.Format("2006-01-02")), "", 1, "L", false, 0, "")
+	}
+	if doc.Reference != "" {
+		pdf.CellFormat(0, 5, tr("Order: "+doc.Reference), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	top := pdf.GetY()
+	writeParty(pdf, tr, "From", doc.Seller, 20, top)
+	sellerBottom := pdf.GetY()
+	writeParty(pdf, tr, "Bill to", doc.Buyer, 110, top)
+	pdf.SetXY(20, max(sellerBottom, pdf.GetY())+8)
+
+	if doc.Event != "" {
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(0, 6, tr(doc.Event), "", 1, "L", false, 0, "")
+		pdf.Ln(2)
+	}
+
+	widths := []float64{70, 15, 30, 25, 30}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(235, 235, 235)
+	for i, header := range []string{"Description", "Qty", "Unit price", "Discount", "Amount"} {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		pdf.CellFormat(widths[i], 7, header, "B", 0, align, true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range doc.Lines {
+		discount := ""
+		if line.Discount > 0 {
+			discount = "-" + amount(line.Discount)
+		}
+		pdf.CellFormat(widths[0], 6, tr(line.Description), "", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 6, strconv.Itoa(line.Quantity), "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 6, amount(line.UnitPrice), "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 6, discount, "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 6, amount(line.Total), "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(2)
+
+	total := func(label, value string, bold bool) {
+		style := ""
+		if bold {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 10)
+		pdf.CellFormat(140, 6, tr(label), "", 0, "R", false, 0, "")
+		pdf.CellFormat(30, 6, value, "", 1, "R", false, 0, "")
+	}
+	total("Subtotal", amount(doc.Subtotal), false)
+	if doc.Discount > 0 {
+		total("Discount", "-"+amount(doc.Discount), false)
+	}
+	for _, fee := range doc.Fees {
+		total(fee.Name, amount(fee.Amount), false)
+	}
+	for _, tax := range doc.Taxes {
+		total(tax.Name, amount(tax.Amount), false)
+	}
+	total("Total", amount(doc.Total), true)
+
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, fmt.Errorf("failed to render invoice: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+func writeParty(pdf *fpdf.Fpdf, tr func(string) string, title string, party Party, x, y float64) {
+	pdf.SetXY(x, y)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(80, 5, tr(title), "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, text := range []string{party.Company, party.Name, party.Address, party.Email} {
+		if text != "" {
+			pdf.MultiCell(80, 5, tr(text), "", "L", false)
+			pdf.SetX(x)
+		}
+	}
+	if party.TaxID != "" {
+		pdf.CellFormat(80, 5, tr("Tax ID: "+party.TaxID), "", 2, "L", false, 0, "")
+	}
+}
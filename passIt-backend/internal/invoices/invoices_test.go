@@ -0,0 +1,71 @@
+package invoices
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"passIt/internal/pricing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDocument() Document {
+	issuedAt := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	return Document{
+		Number:    "INV-2026-000042",
+		IssuedAt:  issuedAt,
+		PaidAt:    issuedAt,
+		Reference: "5f0c6e1e-8d7a-4d8e-9a53-3c1c2b0a9f11",
+		Event:     "Open Air Festival",
+		Seller:    Party{Name: "PassIt GmbH", Address: "Hauptstraße 1\n10115 Berlin", TaxID: "DE123456789"},
+		Buyer:     Party{Name: "Zoë Müller", Company: "Acme AG", Address: "Bahnhofstraße 5\n8001 Zürich", Email: "zoe@example.com"},
+		Currency:  "EUR",
+		Lines: []Line{
+			{Description: "Weekend pass", Quantity: 2, UnitPrice: 12000, Discount: 2400, Total: 21600},
+		},
+		Subtotal: 24000,
+		Discount: 2400,
+		Fees:     []pricing.Charge{{Name: "Service fee", Amount: 500}},
+		Taxes:    []pricing.Charge{{Name: "VAT", Amount: 4199}},
+		Total:    26299,
+	}
+}
+
+func TestRender(t *testing.T) {
+	pdf, err := Render(testDocument())
+	require.NoError(t, err)
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")), "output should be a PDF document")
+	assert.True(t, bytes.Contains(pdf, []byte("%%EOF")), "output should be a complete PDF document")
+}
+
+func TestRender_Deterministic(t *testing.T) {
+	first, err := Render(testDocument())
+	require.NoError(t, err)
+	second, err := Render(testDocument())
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second, "rendering the same invoice twice should give the same file")
+}
+
+func TestRender_Incomplete(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Document)
+	}{
+		{"missing number", func(d *Document) { d.Number = "" }},
+		{"missing currency", func(d *Document) { d.Currency = "" }},
+		{"no lines", func(d *Document) { d.Lines = nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := testDocument()
+			tt.modify(&doc)
+			_, err := Render(doc)
+			assert.ErrorIs(t, err, ErrIncompleteDocument)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"passIt/internal/auth"
 	"passIt/internal/database"
+	"passIt/internal/models"
 	"passIt/internal/store"
 
 	"github.com/coreos/go-oidc/v3/oidc"
@@ -102,6 +103,12 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 				c.Abort()
 				return
 			}
+			// Box office guest records are only claimed by signing in with a verified email
+			if user.IsGuest {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized - sign in to claim the account of this email"})
+				c.Abort()
+				return
+			}
 
 			// Create session data for Bearer token authentication
 			sessionData := &store.SessionData{
@@ -115,6 +122,29 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			c.Set("user_session", sessionData)
 		}
 
+		// Permissions are read on every request rather than cached in the session,
+		// so role changes apply to signed in users immediately
+		session := c.MustGet("user_session").(*store.SessionData)
+		roles, err := m.dbService.ListRolesByUserEmail(session.UserInfo.Email)
+		if err != nil {
+			log.Printf("Failed to fetch user roles: %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		// Keycloak roles in the verified token add the PassIt roles they are mapped onto
+		if names := m.authClient.Config.RoleMapping.RolesFromClaims(claims, m.authClient.Config.ClientID); len(names) > 0 {
+			mapped, err := m.dbService.FindRolesByName(names)
+			if err != nil {
+				log.Printf("Failed to fetch mapped roles: %v", err)
+				c.AbortWithStatus(http.StatusInternalServerError)
+				return
+			}
+			roles = append(roles, mapped...)
+		}
+		permissions := models.PermissionsOf(roles)
+		session.UserInfo.IsAdmin = permissions.IsAdmin()
+		c.Set("user_permissions", permissions)
+
 		// Store the validated claims and auth type in the context
 		c.Set("user_claims", claims)
 		c.Set("auth_type", authType)
@@ -122,26 +152,17 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	}
 }
 
-// RequireAdmin middleware ensures the user is an admin
+// RequireAdmin middleware ensures the user holds the admin role
 func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Get session data (set by RequireAuth middleware)
-		sessionData, exists := c.Get("user_session")
-		if !exists {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden - admin access required"})
-			c.Abort()
-			return
-		}
-
-		session, ok := sessionData.(*store.SessionData)
-		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid session data"})
-			c.Abort()
-			return
-		}
+	return m.RequirePermission(models.PermAll)
+}
 
-		if !session.UserInfo.IsAdmin {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden - admin access required"})
+// RequirePermission middleware ensures one of the roles of the user grants the permission.
+// Must run after RequireAuth.
+func (m *AuthMiddleware) RequirePermission(permission models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !Permissions(c).Has(permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden - missing permission " + string(permission)})
 			c.Abort()
 			return
 		}
@@ -149,3 +170,10 @@ func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// Permissions returns the permissions of the authenticated user, set by RequireAuth
+func Permissions(c *gin.Context) models.PermissionSet {
+	permissions, _ := c.Get("user_permissions")
+	set, _ := permissions.(models.PermissionSet)
+	return set
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	"passIt/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrgScope tells RequireOrgAccess how to find the organization of a request
+type OrgScope int
+
+const (
+	// ScopeOrganization reads the organization from the :orgId path parameter
+	ScopeOrganization OrgScope = iota
+	// ScopeEvent uses the organization of the event in the :id path parameter
+	ScopeEvent
+	// ScopeOrder uses the organization of the event of the order in the :id path parameter
+	ScopeOrder
+	// ScopeSeries uses the organization of the event series in the :id path parameter
+	ScopeSeries
+)
+
+// RequireOrgAccess middleware ensures the user has a role with the capability in the
+// organization the request is about. Platform staff whose permissions include the
+// capability can act in every organization and on events without one. Must run after RequireAuth.
+func (m *AuthMiddleware) RequireOrgAccess(capability models.OrgCapability, scope OrgScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionData, exists := c.Get("user_session")
+		session, ok := sessionData.(*store.SessionData)
+		if !exists || !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden - organization access required"})
+			c.Abort()
+			return
+		}
+
+		orgID, status, message := m.resolveOrganization(c, scope)
+		if message != "" {
+			c.JSON(status, gin.H{"error": message})
+			c.Abort()
+			return
+		}
+
+		if Permissions(c).Has(capability.Permission()) {
+			if orgID != nil {
+				c.Set("organization_id", *orgID)
+			}
+			c.Next()
+			return
+		}
+		if orgID == nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden - missing permission " + string(capability.Permission())})
+			c.Abort()
+			return
+		}
+
+		user, err := m.dbService.FindUserByEmail(session.UserInfo.Email)
+		if err != nil {
+			log.Printf("Failed to fetch user for organization access: %v", err)
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden - organization access required"})
+			c.Abort()
+			return
+		}
+		membership, err := m.dbService.FindMembership(*orgID, user.ID)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("Failed to fetch organization membership: %v", err)
+			}
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden - organization access required"})
+			c.Abort()
+			return
+		}
+		if !membership.Role.Can(capability) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden - your organization role does not allow this"})
+			c.Abort()
+			return
+		}
+
+		c.Set("organization_id", *orgID)
+		c.Set("org_role", membership.Role)
+		c.Next()
+	}
+}
+
+// RequireOrgAccessUnlessPublished lets everybody follow a published event in the :id path
+// parameter and otherwise requires the capability like RequireOrgAccess, so organizers can
+// use public event routes on their drafts. Must run after RequireAuth.
+func (m *AuthMiddleware) RequireOrgAccessUnlessPublished(capability models.OrgCapability) gin.HandlerFunc {
+	requireOrgAccess := m.RequireOrgAccess(capability, ScopeEvent)
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID format"})
+			c.Abort()
+			return
+		}
+		event, err := m.dbService.FindEventById(id)
+		if err != nil {
+			status, message := lookupFailure(err, "Event not found")
+			c.JSON(status, gin.H{"error": message})
+			c.Abort()
+			return
+		}
+		if event.Status == models.EventStatusPublished {
+			c.Next()
+			return
+		}
+		requireOrgAccess(c)
+	}
+}
+
+// resolveOrganization returns the organization the request is about, or nil for
+// events that belong to no organization. On failure it returns the status and message to respond with.
+func (m *AuthMiddleware) resolveOrganization(c *gin.Context, scope OrgScope) (*uuid.UUID, int, string) {
+	param := "id"
+	if scope == ScopeOrganization {
+		param = "orgId"
+	}
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		return nil, http.StatusBadRequest, "invalid UUID format"
+	}
+
+	switch scope {
+	case ScopeOrganization:
+		return &id, 0, ""
+	case ScopeOrder:
+		order, err := m.dbService.FindOrderById(id)
+		if err != nil {
+			status, message := lookupFailure(err, "Order not found")
+			return nil, status, message
+		}
+		id = order.EventID
+	case ScopeSeries:
+		series, err := m.dbService.FindSeriesById(id)
+		if err != nil {
+			status, message := lookupFailure(err, "Event series not found")
+			return nil, status, message
+		}
+		return series.OrganizationID, 0, ""
+	}
+
+	event, err := m.dbService.FindEventById(id)
+	if err != nil {
+		status, message := lookupFailure(err, "Event not found")
+		return nil, status, message
+	}
+	return event.OrganizationID, 0, ""
+}
+
+func lookupFailure(err error, notFound string) (int, string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusNotFound, notFound
+	}
+	log.Printf("Failed to check organization access: %v", err)
+	return http.StatusInternalServerError, "Failed to check organization access"
+}
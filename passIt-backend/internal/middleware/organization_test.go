@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/store"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// orgDB serves one event and the organization memberships of one user, other database
+// calls are not expected
+type orgDB struct {
+	database.Service
+	event       models.Event
+	user        models.User
+	memberships map[uuid.UUID]models.OrgRole
+}
+
+func (db *orgDB) FindEventById(id uuid.UUID) (models.Event, error) {
+	if id != db.event.ID {
+		return models.Event{}, gorm.ErrRecordNotFound
+	}
+	return db.event, nil
+}
+
+func (db *orgDB) FindUserByEmail(email string) (models.User, error) {
+	return db.user, nil
+}
+
+func (db *orgDB) FindMembership(orgID, userID uuid.UUID) (models.OrgMembership, error) {
+	role, ok := db.memberships[orgID]
+	if !ok || userID != db.user.ID {
+		return models.OrgMembership{}, gorm.ErrRecordNotFound
+	}
+	return models.OrgMembership{OrganizationID: orgID, UserID: userID, Role: role}, nil
+}
+
+func TestRequireOrgAccessUnlessPublished(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	orgID, otherOrgID := uuid.New(), uuid.New()
+	user := models.User{ID: uuid.New(), Email: "organizer@example.com"}
+
+	tests := []struct {
+		name        string
+		status      models.EventStatus
+		memberships map[uuid.UUID]models.OrgRole
+		expected    int
+	}{
+		{"Published event is public", models.EventStatusPublished, nil, http.StatusOK},
+		{"Organizer follows their draft", models.EventStatusDraft, map[uuid.UUID]models.OrgRole{orgID: models.OrgRoleManager}, http.StatusOK},
+		{"Draft of another organization", models.EventStatusDraft, map[uuid.UUID]models.OrgRole{otherOrgID: models.OrgRoleOwner}, http.StatusForbidden},
+		{"Role without the capability", models.EventStatusDraft, map[uuid.UUID]models.OrgRole{orgID: models.OrgRoleScanner}, http.StatusForbidden},
+		{"Draft hidden from the public", models.EventStatusDraft, nil, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := models.Event{ID: uuid.New(), OrganizationID: &orgID, Status: tt.status}
+			m := &AuthMiddleware{dbService: &orgDB{event: event, user: user, memberships: tt.memberships}}
+
+			r := gin.New()
+			r.GET("/events/:id/availability", func(c *gin.Context) {
+				c.Set("user_session", &store.SessionData{UserInfo: store.UserInfo{Email: user.Email}})
+				c.Next()
+			}, m.RequireOrgAccessUnlessPublished(models.OrgCapManageEvents), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events/"+event.ID.String()+"/availability", nil))
+			assert.Equal(t, tt.expected, w.Code)
+
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events/"+uuid.NewString()+"/availability", nil))
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		})
+	}
+}
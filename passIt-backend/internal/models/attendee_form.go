@@ -0,0 +1,322 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// FormFieldType is the kind of answer a question of an attendee form expects
+type FormFieldType string
+
+const (
+	FormFieldText        FormFieldType = "text"
+	FormFieldNumber      FormFieldType = "number"
+	FormFieldEmail       FormFieldType = "email"
+	FormFieldDate        FormFieldType = "date" // answered as YYYY-MM-DD
+	FormFieldSelect      FormFieldType = "select"
+	FormFieldMultiSelect FormFieldType = "multi_select"
+	FormFieldCheckbox    FormFieldType = "checkbox" // a required checkbox must be ticked, e.g. to give consent
+)
+
+type AttendeeForm struct {
+	// AttendeeForm holds the questions asked at checkout for every ticket of an event,
+	// or only for the tickets of one ticket type
+	ID           uuid.UUID   `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+	EventID      uuid.UUID   `gorm:"type:uuid;not null;index" json:"event_id"`
+	TicketTypeID *uuid.UUID  `gorm:"type:uuid;index" json:"ticket_type_id,omitempty"` // nil asks the questions for every ticket of the event
+	Fields       []FormField `gorm:"serializer:json" json:"fields"`
+}
+
+// FormField is one question of an attendee form with the rules its answer must follow
+type FormField struct {
+	Key      string        `json:"key"` // identifies the answer, e.g. tshirt_size
+	Label    string        `json:"label"`
+	Type     FormFieldType `json:"type"`
+	Required bool          `json:"required"`
+	HelpText string        `json:"help_text,omitempty"`
+	Options  []string      `json:"options,omitempty"` // choices of select and multi_select fields
+	// Min and Max bound numbers, the length of text answers and the number of choices
+	// of multi_select fields
+	Min     *float64 `json:"min,omitempty"`
+	Max     *float64 `json:"max,omitempty"`
+	Pattern string   `json:"pattern,omitempty"` // regular expression text answers must match
+}
+
+type AttendeeAnswers struct {
+	// AttendeeAnswers are the answers given at checkout for one ticket of an order
+	ID           uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	OrderID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"order_id"`
+	OrderItemID  uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_attendee_answers_ticket" json:"order_item_id"`
+	Number       int            `gorm:"not null;uniqueIndex:idx_attendee_answers_ticket" json:"number"` // position of the ticket within its order line, like Ticket.Number
+	EventID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"event_id"`
+	TicketTypeID uuid.UUID      `gorm:"type:uuid;not null;index" json:"ticket_type_id"`
+	Answers      map[string]any `gorm:"serializer:json" json:"answers"`
+}
+
+// AttendeeAnswerRow is one ticket of a paid order in the answer export of an event
+type AttendeeAnswerRow struct {
+	OrderID      uuid.UUID      `json:"order_id"`
+	BuyerEmail   string         `json:"buyer_email"`
+	TicketTypeID uuid.UUID      `json:"ticket_type_id"`
+	TicketType   string         `json:"ticket_type"`
+	Number       int            `json:"number"`
+	TicketID     *uuid.UUID     `json:"ticket_id,omitempty"` // set once the tickets of the order are issued
+	TicketStatus *TicketStatus  `json:"ticket_status,omitempty"`
+	Answers      map[string]any `gorm:"serializer:json" json:"answers"`
+}
+
+var (
+	ErrAttendeeFormInvalid   = errors.New("invalid attendee form")
+	ErrAttendeeAnswerInvalid = errors.New("invalid attendee answer")
+)
+
+var formFieldKey = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// Validate checks the questions an organizer defined
+func (f *AttendeeForm) Validate() error {
+	seen := map[string]bool{}
+	for _, field := range f.Fields {
+		if !formFieldKey.MatchString(field.Key) {
+			return fmt.Errorf("%w: field key %q must be lower case letters, digits and underscores", ErrAttendeeFormInvalid, field.Key)
+		}
+		if seen[field.Key] {
+			return fmt.Errorf("%w: duplicate field %q", ErrAttendeeFormInvalid, field.Key)
+		}
+		seen[field.Key] = true
+		if strings.TrimSpace(field.Label) == "" {
+			return fmt.Errorf("%w: field %q needs a label", ErrAttendeeFormInvalid, field.Key)
+		}
+
+		switch field.Type {
+		case FormFieldText, FormFieldNumber, FormFieldEmail, FormFieldDate, FormFieldCheckbox:
+			if len(field.Options) > 0 {
+				return fmt.Errorf("%w: field %q of type %s cannot have options", ErrAttendeeFormInvalid, field.Key, field.Type)
+			}
+		case FormFieldSelect, FormFieldMultiSelect:
+			if len(field.Options) == 0 {
+				return fmt.Errorf("%w: field %q needs options", ErrAttendeeFormInvalid, field.Key)
+			}
+			if slices.Contains(field.Options, "") || len(slices.Compact(slices.Sorted(slices.Values(field.Options)))) != len(field.Options) {
+				return fmt.Errorf("%w: options of field %q must be unique and not empty", ErrAttendeeFormInvalid, field.Key)
+			}
+		default:
+			return fmt.Errorf("%w: field %q has unknown type %q", ErrAttendeeFormInvalid, field.Key, field.Type)
+		}
+
+		if field.Min != nil && field.Max != nil && *field.Min > *field.Max {
+			return fmt.Errorf("%w: min of field %q is above its max", ErrAttendeeFormInvalid, field.Key)
+		}
+		if field.Pattern != "" {
+			if field.Type != FormFieldText {
+				return fmt.Errorf("%w: only text fields can have a pattern", ErrAttendeeFormInvalid)
+			}
+			if _, err := regexp.Compile(field.Pattern); err != nil {
+				return fmt.Errorf("%w: pattern of field %q does not compile", ErrAttendeeFormInvalid, field.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// FieldsFor returns the questions asked for a ticket of the ticket type: those of the
+// event-wide form followed by those of the form of the ticket type. A ticket type field
+// replaces an event-wide field with the same key.
+func FieldsFor(forms []AttendeeForm, ticketTypeID uuid.UUID) []FormField {
+	var eventWide, specific []FormField
+	for _, form := range forms {
+		switch {
+		case form.TicketTypeID == nil:
+			eventWide = append(eventWide, form.Fields...)
+		case *form.TicketTypeID == ticketTypeID:
+			specific = append(specific, form.Fields...)
+		}
+	}
+
+	fields := make([]FormField, 0, len(eventWide)+len(specific))
+	for _, field := range eventWide {
+		if !slices.ContainsFunc(specific, func(f FormField) bool { return f.Key == field.Key }) {
+			fields = append(fields, field)
+		}
+	}
+	return append(fields, specific...)
+}
+
+// ValidateAnswers checks the answers for one ticket against its questions and returns
+// them normalized: text trimmed, numbers as float64 and choices as string lists.
+// Unanswered optional questions are left out.
+func ValidateAnswers(fields []FormField, answers map[string]any) (map[string]any, error) {
+	for key := range answers {
+		if !slices.ContainsFunc(fields, func(f FormField) bool { return f.Key == key }) {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrAttendeeAnswerInvalid, key)
+		}
+	}
+
+	normalized := make(map[string]any, len(fields))
+	for _, field := range fields {
+		value, err := field.normalize(answers[field.Key])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %s", ErrAttendeeAnswerInvalid, field.Key, err)
+		}
+		if value == nil {
+			if field.Required {
+				return nil, fmt.Errorf("%w: %s is required", ErrAttendeeAnswerInvalid, field.Key)
+			}
+			continue
+		}
+		normalized[field.Key] = value
+	}
+	return normalized, nil
+}
+
+// normalize converts a decoded JSON answer to the type of the field, nil when unanswered
+func (f FormField) normalize(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch f.Type {
+	case FormFieldNumber:
+		number, ok := value.(float64)
+		if !ok {
+			return nil, errors.New("must be a number")
+		}
+		if err := f.checkBounds(number, "must be"); err != nil {
+			return nil, err
+		}
+		return number, nil
+
+	case FormFieldCheckbox:
+		checked, ok := value.(bool)
+		if !ok {
+			return nil, errors.New("must be true or false")
+		}
+		if !checked && f.Required {
+			return nil, nil
+		}
+		return checked, nil
+
+	case FormFieldMultiSelect:
+		list, ok := value.([]any)
+		if !ok {
+			return nil, errors.New("must be a list of options")
+		}
+		choices := make([]string, 0, len(list))
+		for _, item := range list {
+			choice, ok := item.(string)
+			if !ok || !slices.Contains(f.Options, choice) {
+				return nil, fmt.Errorf("has unknown option %v", item)
+			}
+			if !slices.Contains(choices, choice) {
+				choices = append(choices, choice)
+			}
+		}
+		if len(choices) == 0 {
+			return nil, nil
+		}
+		if err := f.checkBounds(float64(len(choices)), "must pick"); err != nil {
+			return nil, err
+		}
+		return choices, nil
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return nil, errors.New("must be text")
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	switch f.Type {
+	case FormFieldEmail:
+		if address, err := mail.ParseAddress(text); err != nil || address.Address != text {
+			return nil, errors.New("must be an email address")
+		}
+	case FormFieldDate:
+		if _, err := time.Parse(time.DateOnly, text); err != nil {
+			return nil, errors.New("must be a date like 2006-01-02")
+		}
+	case FormFieldSelect:
+		if !slices.Contains(f.Options, text) {
+			return nil, fmt.Errorf("has unknown option %q", text)
+		}
+	case FormFieldText:
+		if err := f.checkBounds(float64(utf8.RuneCountInString(text)), "length must be"); err != nil {
+			return nil, err
+		}
+		if f.Pattern != "" && !regexp.MustCompile(f.Pattern).MatchString(text) {
+			return nil, errors.New("has an invalid format")
+		}
+	}
+	return text, nil
+}
+
+func (f FormField) checkBounds(value float64, what string) error {
+	if f.Min != nil && value < *f.Min {
+		return fmt.Errorf("%s at least %v", what, *f.Min)
+	}
+	if f.Max != nil && value > *f.Max {
+		return fmt.Errorf("%s at most %v", what, *f.Max)
+	}
+	return nil
+}
+
+// AttachAnswers validates the answers given per ticket type against the attendee forms
+// and adds them to the order. The n-th answer of a ticket type belongs to its n-th ticket,
+// counting through the lines of the order in order. Tickets without questions get no
+// answers, unanswered tickets must not have required questions.
+func (o *Order) AttachAnswers(forms []AttendeeForm, answers map[uuid.UUID][]map[string]any) error {
+	used := map[uuid.UUID]int{}
+	o.Attendees = nil
+	for i := range o.Items {
+		item := &o.Items[i]
+		fields := FieldsFor(forms, item.TicketTypeID)
+		for number := 1; number <= item.Quantity; number++ {
+			var given map[string]any
+			if next := used[item.TicketTypeID]; next < len(answers[item.TicketTypeID]) {
+				given = answers[item.TicketTypeID][next]
+			}
+			used[item.TicketTypeID]++
+			if len(fields) == 0 {
+				if len(given) > 0 {
+					return fmt.Errorf("%w: ticket type %s has no questions", ErrAttendeeAnswerInvalid, item.TicketTypeID)
+				}
+				continue
+			}
+
+			normalized, err := ValidateAnswers(fields, given)
+			if err != nil {
+				return fmt.Errorf("%w (ticket %d of %s)", err, used[item.TicketTypeID], item.Name)
+			}
+			// Answers point at their line, so its ID is chosen before the order is stored
+			if item.ID == uuid.Nil {
+				item.ID = uuid.New()
+			}
+			o.Attendees = append(o.Attendees, AttendeeAnswers{
+				OrderItemID:  item.ID,
+				Number:       number,
+				EventID:      o.EventID,
+				TicketTypeID: item.TicketTypeID,
+				Answers:      normalized,
+			})
+		}
+	}
+
+	for ticketTypeID, list := range answers {
+		if len(list) > used[ticketTypeID] {
+			return fmt.Errorf("%w: more answers than tickets of ticket type %s", ErrAttendeeAnswerInvalid, ticketTypeID)
+		}
+	}
+	return nil
+}
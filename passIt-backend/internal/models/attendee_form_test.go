@@ -0,0 +1,188 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func float(v float64) *float64 { return &v }
+
+func validAttendeeForm() AttendeeForm {
+	return AttendeeForm{
+		EventID: uuid.New(),
+		Fields: []FormField{
+			{Key: "company", Label: "Company", Type: FormFieldText, Max: float(50)},
+			{Key: "tshirt_size", Label: "T-shirt size", Type: FormFieldSelect, Required: true, Options: []string{"S", "M", "L"}},
+			{Key: "diet", Label: "Dietary needs", Type: FormFieldMultiSelect, Options: []string{"vegan", "gluten_free"}, Max: float(2)},
+			{Key: "age", Label: "Age", Type: FormFieldNumber, Min: float(18)},
+			{Key: "email", Label: "Work email", Type: FormFieldEmail},
+			{Key: "birthday", Label: "Birthday", Type: FormFieldDate},
+			{Key: "photo_consent", Label: "I agree to be photographed", Type: FormFieldCheckbox, Required: true},
+		},
+	}
+}
+
+func TestAttendeeFormModel_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(f *AttendeeForm)
+		valid  bool
+	}{
+		{"Valid", func(f *AttendeeForm) {}, true},
+		{"No fields", func(f *AttendeeForm) { f.Fields = nil }, true},
+		{"Invalid key", func(f *AttendeeForm) { f.Fields[0].Key = "Company Name" }, false},
+		{"Duplicate key", func(f *AttendeeForm) { f.Fields[1].Key = "company" }, false},
+		{"Missing label", func(f *AttendeeForm) { f.Fields[0].Label = " " }, false},
+		{"Unknown type", func(f *AttendeeForm) { f.Fields[0].Type = "file" }, false},
+		{"Select without options", func(f *AttendeeForm) { f.Fields[1].Options = nil }, false},
+		{"Duplicate options", func(f *AttendeeForm) { f.Fields[1].Options = []string{"S", "S"} }, false},
+		{"Options on text", func(f *AttendeeForm) { f.Fields[0].Options = []string{"ACME"} }, false},
+		{"Min above max", func(f *AttendeeForm) { f.Fields[0].Min = float(60) }, false},
+		{"Pattern on number", func(f *AttendeeForm) { f.Fields[3].Pattern = "^1" }, false},
+		{"Broken pattern", func(f *AttendeeForm) { f.Fields[0].Pattern = "(" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := validAttendeeForm()
+			tt.mutate(&form)
+			err := form.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrAttendeeFormInvalid)
+			}
+		})
+	}
+}
+
+func TestValidateAnswers(t *testing.T) {
+	fields := validAttendeeForm().Fields
+	valid := func() map[string]any {
+		return map[string]any{"tshirt_size": "M", "photo_consent": true}
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(a map[string]any)
+		valid  bool
+	}{
+		{"Only required answers", func(a map[string]any) {}, true},
+		{"Every answer", func(a map[string]any) {
+			a["company"], a["diet"], a["age"] = " ACME ", []any{"vegan", "gluten_free"}, float64(30)
+			a["email"], a["birthday"] = "ada@example.com", "1990-12-10"
+		}, true},
+		{"Empty optional answers", func(a map[string]any) { a["company"], a["diet"] = "", []any{} }, true},
+		{"Missing required answer", func(a map[string]any) { delete(a, "tshirt_size") }, false},
+		{"Consent not given", func(a map[string]any) { a["photo_consent"] = false }, false},
+		{"Unknown field", func(a map[string]any) { a["shoe_size"] = "42" }, false},
+		{"Unknown option", func(a map[string]any) { a["tshirt_size"] = "XXL" }, false},
+		{"Unknown choice", func(a map[string]any) { a["diet"] = []any{"keto"} }, false},
+		{"Number below min", func(a map[string]any) { a["age"] = float64(17) }, false},
+		{"Number as text", func(a map[string]any) { a["age"] = "30" }, false},
+		{"Text too long", func(a map[string]any) { a["company"] = string(make([]rune, 51)) + "x" }, false},
+		{"Invalid email", func(a map[string]any) { a["email"] = "ada at example.com" }, false},
+		{"Invalid date", func(a map[string]any) { a["birthday"] = "10/12/1990" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answers := valid()
+			tt.mutate(answers)
+			_, err := ValidateAnswers(fields, answers)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrAttendeeAnswerInvalid)
+			}
+		})
+	}
+}
+
+func TestValidateAnswers_Normalizes(t *testing.T) {
+	answers, err := ValidateAnswers(validAttendeeForm().Fields, map[string]any{
+		"company":       "  ACME ",
+		"tshirt_size":   "L",
+		"diet":          []any{"vegan", "vegan"},
+		"email":         "",
+		"photo_consent": true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"company":       "ACME",
+		"tshirt_size":   "L",
+		"diet":          []string{"vegan"},
+		"photo_consent": true,
+	}, answers)
+}
+
+func TestFieldsFor(t *testing.T) {
+	vip := uuid.New()
+	forms := []AttendeeForm{
+		{Fields: []FormField{{Key: "company"}, {Key: "tshirt_size", Label: "Event"}}},
+		{TicketTypeID: &vip, Fields: []FormField{{Key: "tshirt_size", Label: "VIP"}, {Key: "guest_name"}}},
+	}
+
+	assert.Equal(t, []FormField{{Key: "company"}, {Key: "tshirt_size", Label: "Event"}}, FieldsFor(forms, uuid.New()))
+	assert.Equal(t, []FormField{{Key: "company"}, {Key: "tshirt_size", Label: "VIP"}, {Key: "guest_name"}}, FieldsFor(forms, vip))
+}
+
+func TestOrderModel_AttachAnswers(t *testing.T) {
+	general, vip, parking := uuid.New(), uuid.New(), uuid.New()
+	forms := []AttendeeForm{
+		{TicketTypeID: &general, Fields: []FormField{{Key: "name", Label: "Name", Type: FormFieldText, Required: true}}},
+		{TicketTypeID: &vip, Fields: []FormField{{Key: "diet", Label: "Diet", Type: FormFieldText}}},
+	}
+	newOrder := func() Order {
+		return Order{
+			EventID: uuid.New(),
+			Items: []OrderItem{
+				{TicketTypeID: general, Name: "General", Quantity: 2},
+				{TicketTypeID: parking, Name: "Parking", Quantity: 1},
+				{TicketTypeID: vip, Name: "VIP", Quantity: 1},
+			},
+		}
+	}
+
+	t.Run("Answers every ticket with questions", func(t *testing.T) {
+		order := newOrder()
+		err := order.AttachAnswers(forms, map[uuid.UUID][]map[string]any{
+			general: {{"name": "Ada"}, {"name": "Grace"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, order.Attendees, 3)
+		assert.Equal(t, order.Items[0].ID, order.Attendees[1].OrderItemID)
+		assert.Equal(t, 2, order.Attendees[1].Number)
+		assert.Equal(t, map[string]any{"name": "Grace"}, order.Attendees[1].Answers)
+		assert.Equal(t, order.Items[2].ID, order.Attendees[2].OrderItemID)
+		assert.Empty(t, order.Attendees[2].Answers, "optional questions may stay unanswered")
+		assert.Equal(t, uuid.Nil, order.Items[1].ID, "lines without questions keep their generated ID")
+	})
+
+	t.Run("Missing required answers", func(t *testing.T) {
+		order := newOrder()
+		err := order.AttachAnswers(forms, map[uuid.UUID][]map[string]any{general: {{"name": "Ada"}}})
+		assert.ErrorIs(t, err, ErrAttendeeAnswerInvalid)
+	})
+
+	t.Run("More answers than tickets", func(t *testing.T) {
+		order := newOrder()
+		err := order.AttachAnswers(forms, map[uuid.UUID][]map[string]any{
+			general: {{"name": "Ada"}, {"name": "Grace"}},
+			vip:     {{"diet": "vegan"}, {"diet": "none"}},
+		})
+		assert.ErrorIs(t, err, ErrAttendeeAnswerInvalid)
+	})
+
+	t.Run("Answers for a ticket type without questions", func(t *testing.T) {
+		order := newOrder()
+		err := order.AttachAnswers(forms, map[uuid.UUID][]map[string]any{
+			general: {{"name": "Ada"}, {"name": "Grace"}},
+			parking: {{"plate": "AB-123"}},
+		})
+		assert.ErrorIs(t, err, ErrAttendeeAnswerInvalid)
+	})
+}
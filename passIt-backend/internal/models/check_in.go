@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckInReason explains why a scanned ticket was rejected, it is empty for accepted scans
+type CheckInReason string
+
+const (
+	CheckInReasonNone             CheckInReason = ""
+	CheckInReasonInvalidSignature CheckInReason = "invalid_signature"
+	CheckInReasonUnknownTicket    CheckInReason = "unknown_ticket"
+	CheckInReasonWrongEvent       CheckInReason = "wrong_event"
+	CheckInReasonRevoked          CheckInReason = "revoked"
+	CheckInReasonReissued         CheckInReason = "reissued"
+	CheckInReasonAlreadyUsed      CheckInReason = "already_used"
+	CheckInReasonListed           CheckInReason = "listed_for_resale"
+)
+
+// CheckInSource tells whether a scan was checked by the server or uploaded by an offline scanner
+type CheckInSource string
+
+const (
+	CheckInSourceOnline  CheckInSource = "online"
+	CheckInSourceOffline CheckInSource = "offline"
+)
+
+type CheckIn struct {
+	// CheckIn records one scan of a ticket at the door, accepted or not
+	ID        uuid.UUID     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt time.Time     `json:"created_at"`
+	EventID   uuid.UUID     `gorm:"type:uuid;not null;index" json:"event_id"`
+	TicketID  *uuid.UUID    `gorm:"type:uuid;index" json:"ticket_id,omitempty"` // unset when the QR code could not be read
+	Version   int           `json:"version"`
+	Gate      string        `json:"gate"`
+	StaffID   uuid.UUID     `gorm:"type:uuid;not null;index" json:"staff_id"`
+	ScannedAt time.Time     `gorm:"not null" json:"scanned_at"`
+	Accepted  bool          `gorm:"not null" json:"accepted"`
+	Reason    CheckInReason `gorm:"type:varchar(30)" json:"reason,omitempty"`
+	Source    CheckInSource `gorm:"type:varchar(10);not null;default:'online'" json:"source"`
+	// DeviceID and ClientScanID identify uploaded scans so a scan log can be uploaded again safely
+	DeviceID       string  `gorm:"uniqueIndex:idx_check_in_client_scan" json:"device_id,omitempty"`
+	ClientScanID   *string `gorm:"uniqueIndex:idx_check_in_client_scan" json:"client_scan_id,omitempty"`
+	DeviceAccepted *bool   `json:"device_accepted,omitempty"` // what the offline scanner decided
+}
+
+type PassAdmission struct {
+	// PassAdmission records that a pass was used at one of the occurrences it admits to,
+	// a pass ticket stays valid until every occurrence is over
+	TicketID uuid.UUID `gorm:"type:uuid;primaryKey" json:"ticket_id"`
+	EventID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"event_id"`
+	UsedAt   time.Time `gorm:"not null" json:"used_at"`
+}
+
+// Message returns a short explanation of the reason for door staff
+func (r CheckInReason) Message() string {
+	switch r {
+	case CheckInReasonNone:
+		return "Ticket accepted"
+	case CheckInReasonInvalidSignature:
+		return "QR code is not a valid PassIt ticket"
+	case CheckInReasonUnknownTicket:
+		return "Ticket does not exist"
+	case CheckInReasonWrongEvent:
+		return "Ticket is for another event"
+	case CheckInReasonRevoked:
+		return "Ticket was refunded or cancelled"
+	case CheckInReasonReissued:
+		return "QR code was replaced by a newer one"
+	case CheckInReasonAlreadyUsed:
+		return "Ticket was already scanned"
+	case CheckInReasonListed:
+		return "Ticket is listed for resale"
+	default:
+		return string(r)
+	}
+}
+
+// CheckInReason reports why the ticket cannot be admitted to the event with a QR code
+// of the given version, or CheckInReasonNone if it can. Passes are checked against
+// their ticket type, which must be loaded.
+func (t *Ticket) CheckInReason(eventID uuid.UUID, version int) CheckInReason {
+	switch {
+	case t.EventID != eventID && (t.TicketType == nil || !t.TicketType.Admits(eventID)):
+		return CheckInReasonWrongEvent
+	case t.Status == TicketStatusVoid:
+		return CheckInReasonRevoked
+	case t.Version != version:
+		return CheckInReasonReissued
+	case t.Status == TicketStatusListed:
+		return CheckInReasonListed
+	case t.Status == TicketStatusUsed:
+		return CheckInReasonAlreadyUsed
+	default:
+		return CheckInReasonNone
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketModel_CheckInReason(t *testing.T) {
+	eventID := uuid.New()
+
+	tests := []struct {
+		name     string
+		ticket   Ticket
+		eventID  uuid.UUID
+		version  int
+		expected CheckInReason
+	}{
+		{"Valid ticket", Ticket{EventID: eventID, Status: TicketStatusValid, Version: 1}, eventID, 1, CheckInReasonNone},
+		{"Other event", Ticket{EventID: uuid.New(), Status: TicketStatusValid, Version: 1}, eventID, 1, CheckInReasonWrongEvent},
+		{"Refunded", Ticket{EventID: eventID, Status: TicketStatusVoid, Version: 1}, eventID, 1, CheckInReasonRevoked},
+		{"Old QR code", Ticket{EventID: eventID, Status: TicketStatusValid, Version: 2}, eventID, 1, CheckInReasonReissued},
+		{"Listed for resale", Ticket{EventID: eventID, Status: TicketStatusListed, Version: 1}, eventID, 1, CheckInReasonListed},
+		{"Used", Ticket{EventID: eventID, Status: TicketStatusUsed, Version: 1}, eventID, 1, CheckInReasonAlreadyUsed},
+		{"Revoked wins over reissued", Ticket{EventID: eventID, Status: TicketStatusVoid, Version: 3}, eventID, 1, CheckInReasonRevoked},
+		{"Pass at another occurrence", Ticket{EventID: uuid.New(), Status: TicketStatusValid, Version: 1, TicketType: &TicketType{PassEventIDs: []uuid.UUID{eventID}}}, eventID, 1, CheckInReasonNone},
+		{"Pass at an occurrence it does not cover", Ticket{EventID: uuid.New(), Status: TicketStatusValid, Version: 1, TicketType: &TicketType{PassEventIDs: []uuid.UUID{uuid.New()}}}, eventID, 1, CheckInReasonWrongEvent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.ticket.CheckInReason(tt.eventID, tt.version))
+		})
+	}
+}
+
+func TestCheckInReason_Message(t *testing.T) {
+	assert.Equal(t, "Ticket accepted", CheckInReasonNone.Message())
+	assert.Equal(t, "Ticket was already scanned", CheckInReasonAlreadyUsed.Message())
+	assert.Equal(t, "custom", CheckInReason("custom").Message())
+}
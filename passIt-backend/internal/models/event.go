@@ -0,0 +1,137 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EventStatus is the lifecycle state of an event
+type EventStatus string
+
+const (
+	EventStatusDraft     EventStatus = "draft"
+	EventStatusPublished EventStatus = "published"
+	EventStatusCancelled EventStatus = "cancelled"
+)
+
+type Event struct {
+	// Event represents a ticketed event in the catalog
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	Title       string         `gorm:"not null" json:"title"`
+	Description string         `json:"description"`
+	Venue       string         `json:"venue"`
+	VenueID     *uuid.UUID     `gorm:"type:uuid;index" json:"venue_id,omitempty"`
+	StartsAt    time.Time      `gorm:"not null;index" json:"starts_at"`
+	EndsAt      time.Time      `gorm:"not null" json:"ends_at"`
+	TimeZone    string         `gorm:"not null;default:'UTC'" json:"time_zone"`
+	Status      EventStatus    `gorm:"type:varchar(20);not null;default:'draft';index" json:"status"`
+	Capacity    int            `gorm:"not null;default:0" json:"capacity"`
+	CreatedByID uuid.UUID      `gorm:"type:uuid" json:"created_by_id"`
+	PublishedAt *time.Time     `json:"published_at,omitempty"`
+	CancelledAt *time.Time     `json:"cancelled_at,omitempty"`
+	TicketTypes []TicketType   `gorm:"foreignKey:EventID" json:"ticket_types,omitempty"`
+
+	TransferPolicy      TransferPolicy `gorm:"type:varchar(20);not null;default:'allowed'" json:"transfer_policy"`
+	TransferCutoffHours int            `gorm:"not null;default:0" json:"transfer_cutoff_hours"` // transfers close this many hours before the event starts
+
+	ResaleEnabled       bool `gorm:"not null;default:false" json:"resale_enabled"`
+	ResaleMarkupPercent int  `gorm:"not null;default:0" json:"resale_markup_percent"` // resale price cap above face value, 10 allows face value +10%
+
+	// MaxTicketsPerUser and MaxTicketsPerPaymentMethod cap how many tickets of the event one
+	// account and one card may buy, 0 means no limit
+	MaxTicketsPerUser          int `gorm:"not null;default:0" json:"max_tickets_per_user"`
+	MaxTicketsPerPaymentMethod int `gorm:"not null;default:0" json:"max_tickets_per_payment_method"`
+
+	// OrganizationID is the organizer running the event, platform events have none
+	OrganizationID *uuid.UUID `gorm:"type:uuid;index" json:"organization_id,omitempty"`
+
+	// SeriesID is set on the occurrences of a recurring event series
+	SeriesID *uuid.UUID `gorm:"type:uuid;index" json:"series_id,omitempty"`
+	// OccurrenceStart is when the recurrence rule of the series scheduled the occurrence
+	OccurrenceStart *time.Time `json:"occurrence_start,omitempty"`
+	// SeriesDetached is set once the occurrence is edited on its own, changes to the
+	// whole series leave it alone from then on
+	SeriesDetached bool `gorm:"not null;default:false" json:"series_detached"`
+
+	// AttendeeForms are the questions buyers answer per ticket at checkout
+	AttendeeForms []AttendeeForm `gorm:"foreignKey:EventID" json:"attendee_forms,omitempty"`
+}
+
+var (
+	ErrEventTitleRequired = errors.New("event title is required")
+	ErrEventInvalidWindow = errors.New("event must end after it starts")
+	ErrEventInvalidZone   = errors.New("event time zone is not a valid IANA zone")
+	ErrEventNegativeSeats = errors.New("event capacity cannot be negative")
+	ErrEventInvalidStatus = errors.New("invalid event status transition")
+
+	ErrEventInvalidTransferPolicy  = errors.New("transfer policy must be allowed or forbidden")
+	ErrEventNegativeTransferCutoff = errors.New("transfer cutoff cannot be negative")
+	ErrEventNegativeResaleMarkup   = errors.New("resale markup cannot be negative")
+	ErrEventNegativePurchaseLimit  = errors.New("purchase limits cannot be negative")
+)
+
+// Validate checks the fields an organizer is allowed to edit
+func (e *Event) Validate() error {
+	if strings.TrimSpace(e.Title) == "" {
+		return ErrEventTitleRequired
+	}
+	if !e.EndsAt.After(e.StartsAt) {
+		return ErrEventInvalidWindow
+	}
+	if _, err := time.LoadLocation(e.TimeZone); err != nil || e.TimeZone == "" {
+		return ErrEventInvalidZone
+	}
+	if e.Capacity < 0 {
+		return ErrEventNegativeSeats
+	}
+	switch e.TransferPolicy {
+	case "", TransferPolicyAllowed, TransferPolicyForbidden:
+	default:
+		return ErrEventInvalidTransferPolicy
+	}
+	if e.TransferCutoffHours < 0 {
+		return ErrEventNegativeTransferCutoff
+	}
+	if e.ResaleMarkupPercent < 0 {
+		return ErrEventNegativeResaleMarkup
+	}
+	if e.MaxTicketsPerUser < 0 || e.MaxTicketsPerPaymentMethod < 0 {
+		return ErrEventNegativePurchaseLimit
+	}
+	return nil
+}
+
+// Location returns the event's time zone, falling back to UTC
+func (e *Event) Location() *time.Location {
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// CanTransitionTo reports whether the event may move to the given status.
+// Drafts can be published or cancelled, published events can only be cancelled
+// and cancelled events are final.
+func (s EventStatus) CanTransitionTo(next EventStatus) bool {
+	switch s {
+	case EventStatusDraft:
+		return next == EventStatusPublished || next == EventStatusCancelled
+	case EventStatusPublished:
+		return next == EventStatusCancelled
+	default:
+		return false
+	}
+}
+
+// IsEditable reports whether organizers can still change the event details
+func (s EventStatus) IsEditable() bool {
+	return s == EventStatusDraft || s == EventStatusPublished
+}
@@ -0,0 +1,259 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"passIt/internal/recurrence"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EventSeries struct {
+	// EventSeries is a recurring event, such as a weekly show or the days of a festival.
+	// Every occurrence is an event of its own with its own ticket types and inventory.
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	Title       string         `gorm:"not null" json:"title"`
+	Description string         `json:"description"`
+	Venue       string         `json:"venue"`
+	TimeZone    string         `gorm:"not null;default:'UTC'" json:"time_zone"`
+	// FirstStartsAt is the start of the first occurrence, later ones start at the same local time
+	FirstStartsAt   time.Time `gorm:"not null" json:"first_starts_at"`
+	DurationMinutes int       `gorm:"not null" json:"duration_minutes"`
+	// Recurrence is an RFC 5545 RRULE such as FREQ=WEEKLY;BYDAY=FR;COUNT=10
+	Recurrence  string    `gorm:"not null" json:"recurrence"`
+	Capacity    int       `gorm:"not null;default:0" json:"capacity"` // of every occurrence
+	CreatedByID uuid.UUID `gorm:"type:uuid" json:"created_by_id"`
+	// TicketTypes are copied onto every occurrence when it is created
+	TicketTypes    []SeriesTicketType `gorm:"serializer:json" json:"ticket_types"`
+	OrganizationID *uuid.UUID         `gorm:"type:uuid;index" json:"organization_id,omitempty"`
+	Occurrences    []Event            `gorm:"foreignKey:SeriesID" json:"occurrences,omitempty"`
+	// Passes are the ticket types admitting to several occurrences, filled in on retrieval
+	Passes []TicketType `gorm:"-" json:"passes,omitempty"`
+}
+
+// SeriesTicketType is the template of a ticket type every occurrence of a series gets
+type SeriesTicketType struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       int64  `json:"price"` // in minor units of Currency
+	Currency    string `json:"currency"`
+	Quantity    int    `json:"quantity"`
+	MinPerOrder int    `json:"min_per_order"`
+	MaxPerOrder int    `json:"max_per_order"`
+}
+
+var (
+	ErrSeriesInvalid         = errors.New("invalid event series")
+	ErrSeriesInvalidDuration = errors.New("series duration must be positive")
+	ErrSeriesNoOccurrences   = errors.New("recurrence rule produces no occurrences")
+	ErrSeriesMismatch        = errors.New("event does not belong to the series")
+	ErrPassInvalidEvents     = errors.New("a pass must admit at least two occurrences of its series")
+)
+
+// Validate checks the fields an organizer is allowed to edit, errors wrap ErrSeriesInvalid
+// and the cause
+func (s *EventSeries) Validate() error {
+	if err := s.validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrSeriesInvalid, err)
+	}
+	return nil
+}
+
+func (s *EventSeries) validate() error {
+	if strings.TrimSpace(s.Title) == "" {
+		return ErrEventTitleRequired
+	}
+	if _, err := time.LoadLocation(s.TimeZone); err != nil || s.TimeZone == "" {
+		return ErrEventInvalidZone
+	}
+	if s.DurationMinutes <= 0 {
+		return ErrSeriesInvalidDuration
+	}
+	if s.Capacity < 0 {
+		return ErrEventNegativeSeats
+	}
+	allocated := 0
+	for _, template := range s.TicketTypes {
+		ticketType := template.ticketType(uuid.Nil)
+		if err := ticketType.Validate(); err != nil {
+			return err
+		}
+		allocated += ticketType.Quantity
+	}
+	if allocated > s.Capacity {
+		return ErrTicketCapacityExceeded
+	}
+	_, err := s.Schedule()
+	return err
+}
+
+// Schedule returns the start times of the occurrences in the time zone of the series
+func (s *EventSeries) Schedule() ([]time.Time, error) {
+	rule, err := recurrence.Parse(s.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, ErrEventInvalidZone
+	}
+	starts, err := rule.Occurrences(s.FirstStartsAt.In(loc))
+	if err != nil {
+		return nil, err
+	}
+	if len(starts) == 0 {
+		return nil, ErrSeriesNoOccurrences
+	}
+	return starts, nil
+}
+
+// NewOccurrence builds the draft event of the series starting at the given time
+func (s *EventSeries) NewOccurrence(startsAt time.Time) Event {
+	occurrence := Event{
+		SeriesID:        &s.ID,
+		OccurrenceStart: &startsAt,
+		StartsAt:        startsAt,
+		EndsAt:          startsAt.Add(time.Duration(s.DurationMinutes) * time.Minute),
+		Status:          EventStatusDraft,
+		CreatedByID:     s.CreatedByID,
+		OrganizationID:  s.OrganizationID,
+	}
+	s.ApplyTo(&occurrence)
+	return occurrence
+}
+
+// ApplyTo copies the shared details of the series onto one of its occurrences
+func (s *EventSeries) ApplyTo(occurrence *Event) {
+	occurrence.Title = s.Title
+	occurrence.Description = s.Description
+	occurrence.Venue = s.Venue
+	occurrence.TimeZone = s.TimeZone
+	occurrence.Capacity = s.Capacity
+	if occurrence.OccurrenceStart != nil {
+		occurrence.StartsAt = *occurrence.OccurrenceStart
+		occurrence.EndsAt = occurrence.StartsAt.Add(time.Duration(s.DurationMinutes) * time.Minute)
+	}
+}
+
+// NewTicketTypes builds the ticket types of a new occurrence from the templates of the series
+func (s *EventSeries) NewTicketTypes(eventID uuid.UUID) []TicketType {
+	ticketTypes := make([]TicketType, 0, len(s.TicketTypes))
+	for i, template := range s.TicketTypes {
+		ticketType := template.ticketType(eventID)
+		ticketType.Position = i
+		ticketTypes = append(ticketTypes, ticketType)
+	}
+	return ticketTypes
+}
+
+func (t SeriesTicketType) ticketType(eventID uuid.UUID) TicketType {
+	ticketType := TicketType{
+		EventID:     eventID,
+		Name:        t.Name,
+		Description: t.Description,
+		Price:       t.Price,
+		Currency:    t.Currency,
+		Quantity:    t.Quantity,
+		MinPerOrder: t.MinPerOrder,
+		MaxPerOrder: t.MaxPerOrder,
+	}
+	if ticketType.MinPerOrder == 0 {
+		ticketType.MinPerOrder = 1
+	}
+	return ticketType
+}
+
+// SeriesChanges are what an edit of a series does to its occurrences
+type SeriesChanges struct {
+	Updated []Event `json:"updated"`
+	Created []Event `json:"created"`
+	Removed []Event `json:"removed"`
+	// Kept are published occurrences whose date left the schedule, they are left as they
+	// are and have to be cancelled on their own
+	Kept []Event `json:"kept"`
+}
+
+// PlanChanges matches the occurrences of the series with its schedule by their local
+// date. Occurrences that were edited on their own, cancelled or already started are left
+// as they are; the others take the details and start time of the series. Drafts whose
+// date left the schedule are removed and upcoming dates without an occurrence are added.
+func (s *EventSeries) PlanChanges(now time.Time) (SeriesChanges, error) {
+	starts, err := s.Schedule()
+	if err != nil {
+		return SeriesChanges{}, err
+	}
+	loc, _ := time.LoadLocation(s.TimeZone)
+	dateOf := func(t time.Time) string { return t.In(loc).Format(time.DateOnly) }
+
+	scheduled := make(map[string]time.Time, len(starts))
+	for _, start := range starts {
+		scheduled[dateOf(start)] = start
+	}
+
+	var changes SeriesChanges
+	taken := map[string]bool{}
+	for _, occurrence := range s.Occurrences {
+		date := dateOf(occurrence.StartsAt)
+		if occurrence.OccurrenceStart != nil {
+			date = dateOf(*occurrence.OccurrenceStart)
+		}
+		start, onSchedule := scheduled[date]
+		taken[date] = true
+
+		if occurrence.SeriesDetached || !occurrence.Status.IsEditable() || !occurrence.StartsAt.After(now) {
+			continue
+		}
+		switch {
+		case onSchedule:
+			occurrence.OccurrenceStart = &start
+			s.ApplyTo(&occurrence)
+			changes.Updated = append(changes.Updated, occurrence)
+		case occurrence.Status == EventStatusDraft:
+			changes.Removed = append(changes.Removed, occurrence)
+		default:
+			changes.Kept = append(changes.Kept, occurrence)
+		}
+	}
+	for _, start := range starts {
+		if !taken[dateOf(start)] && start.After(now) {
+			changes.Created = append(changes.Created, s.NewOccurrence(start))
+		}
+	}
+	return changes, nil
+}
+
+// PassEvents checks the occurrences a pass admits to and returns the first one, which
+// the pass is sold on, and the others
+func PassEvents(occurrences []Event, eventIDs []uuid.UUID) (Event, []uuid.UUID, error) {
+	var admitted []Event
+	for _, occurrence := range occurrences {
+		if slices.Contains(eventIDs, occurrence.ID) {
+			admitted = append(admitted, occurrence)
+		}
+	}
+	unique := map[uuid.UUID]bool{}
+	for _, id := range eventIDs {
+		unique[id] = true
+	}
+	if len(admitted) != len(unique) {
+		return Event{}, nil, ErrSeriesMismatch
+	}
+	if len(admitted) < 2 {
+		return Event{}, nil, ErrPassInvalidEvents
+	}
+
+	slices.SortFunc(admitted, func(a, b Event) int { return a.StartsAt.Compare(b.StartsAt) })
+	others := make([]uuid.UUID, 0, len(admitted)-1)
+	for _, occurrence := range admitted[1:] {
+		others = append(others, occurrence.ID)
+	}
+	return admitted[0], others, nil
+}
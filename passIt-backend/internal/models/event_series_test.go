@@ -0,0 +1,156 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"passIt/internal/recurrence"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSeries() EventSeries {
+	return EventSeries{
+		ID:              uuid.New(),
+		Title:           "Friday Jazz",
+		TimeZone:        "Europe/Berlin",
+		FirstStartsAt:   time.Date(2026, 3, 20, 19, 0, 0, 0, time.UTC), // 20:00 in Berlin
+		DurationMinutes: 120,
+		Recurrence:      "FREQ=WEEKLY;COUNT=3",
+		Capacity:        100,
+		TicketTypes:     []SeriesTicketType{{Name: "Standard", Price: 2000, Currency: "EUR", Quantity: 80}},
+	}
+}
+
+func TestEventSeriesModel_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *EventSeries)
+		err    error
+	}{
+		{"Valid", func(s *EventSeries) {}, nil},
+		{"Missing title", func(s *EventSeries) { s.Title = " " }, ErrEventTitleRequired},
+		{"Unknown zone", func(s *EventSeries) { s.TimeZone = "Mars/Olympus" }, ErrEventInvalidZone},
+		{"No duration", func(s *EventSeries) { s.DurationMinutes = 0 }, ErrSeriesInvalidDuration},
+		{"Unbounded rule", func(s *EventSeries) { s.Recurrence = "FREQ=WEEKLY" }, recurrence.ErrUnboundedRule},
+		{"Invalid ticket type", func(s *EventSeries) { s.TicketTypes[0].Currency = "euro" }, ErrTicketTypeInvalidCurrency},
+		{"Ticket types over capacity", func(s *EventSeries) { s.TicketTypes[0].Quantity = 101 }, ErrTicketCapacityExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := newTestSeries()
+			tt.modify(&series)
+			err := series.Validate()
+			if tt.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrSeriesInvalid)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestEventSeriesModel_NewOccurrence(t *testing.T) {
+	series := newTestSeries()
+	starts, err := series.Schedule()
+	require.NoError(t, err)
+	require.Len(t, starts, 3)
+
+	occurrence := series.NewOccurrence(starts[2])
+	assert.Equal(t, EventStatusDraft, occurrence.Status)
+	assert.Equal(t, series.ID, *occurrence.SeriesID)
+	assert.Equal(t, "Friday Jazz", occurrence.Title)
+	// 3 April is in summer time, the show still starts at 20:00 local time
+	assert.True(t, occurrence.StartsAt.Equal(time.Date(2026, 4, 3, 18, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 2*time.Hour, occurrence.EndsAt.Sub(occurrence.StartsAt))
+
+	ticketTypes := series.NewTicketTypes(occurrence.ID)
+	require.Len(t, ticketTypes, 1)
+	assert.Equal(t, 1, ticketTypes[0].MinPerOrder)
+	assert.Equal(t, 80, ticketTypes[0].Quantity)
+}
+
+func TestEventSeriesModel_PlanChanges(t *testing.T) {
+	series := newTestSeries()
+	starts, err := series.Schedule()
+	require.NoError(t, err)
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	occurrences := make([]Event, 0, len(starts))
+	for _, start := range starts {
+		occurrence := series.NewOccurrence(start)
+		occurrence.ID = uuid.New()
+		occurrences = append(occurrences, occurrence)
+	}
+	occurrences[1].Status = EventStatusPublished
+	occurrences[2].SeriesDetached = true
+	series.Occurrences = occurrences
+
+	t.Run("Follows the series", func(t *testing.T) {
+		edited := series
+		edited.Title = "Friday Jazz Club"
+		edited.FirstStartsAt = edited.FirstStartsAt.Add(-time.Hour)
+
+		changes, err := edited.PlanChanges(now)
+		require.NoError(t, err)
+		require.Len(t, changes.Updated, 2)
+		assert.Empty(t, changes.Created)
+		assert.Empty(t, changes.Removed)
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		for _, occurrence := range changes.Updated {
+			assert.Equal(t, "Friday Jazz Club", occurrence.Title)
+			assert.Equal(t, 19, occurrence.StartsAt.In(berlin).Hour())
+		}
+	})
+
+	t.Run("Moves dates", func(t *testing.T) {
+		edited := series
+		edited.Recurrence = "FREQ=WEEKLY;BYDAY=SA;COUNT=2"
+
+		changes, err := edited.PlanChanges(now)
+		require.NoError(t, err)
+		assert.Empty(t, changes.Updated)
+		require.Len(t, changes.Created, 2)
+		assert.Equal(t, time.Saturday, changes.Created[0].StartsAt.Weekday())
+		require.Len(t, changes.Removed, 1)
+		assert.Equal(t, occurrences[0].ID, changes.Removed[0].ID)
+		require.Len(t, changes.Kept, 1)
+		assert.Equal(t, occurrences[1].ID, changes.Kept[0].ID)
+	})
+
+	t.Run("Leaves past occurrences", func(t *testing.T) {
+		changes, err := series.PlanChanges(starts[1])
+		require.NoError(t, err)
+		assert.Empty(t, changes.Updated)
+		assert.Empty(t, changes.Created)
+	})
+}
+
+func TestPassEvents(t *testing.T) {
+	start := time.Date(2026, 7, 3, 18, 0, 0, 0, time.UTC)
+	days := []Event{
+		{ID: uuid.New(), StartsAt: start.AddDate(0, 0, 2)},
+		{ID: uuid.New(), StartsAt: start},
+		{ID: uuid.New(), StartsAt: start.AddDate(0, 0, 1)},
+	}
+
+	anchor, others, err := PassEvents(days, []uuid.UUID{days[0].ID, days[1].ID, days[2].ID})
+	require.NoError(t, err)
+	assert.Equal(t, days[1].ID, anchor.ID)
+	assert.Equal(t, []uuid.UUID{days[2].ID, days[0].ID}, others)
+
+	_, _, err = PassEvents(days, []uuid.UUID{days[0].ID})
+	assert.ErrorIs(t, err, ErrPassInvalidEvents)
+	_, _, err = PassEvents(days, []uuid.UUID{days[0].ID, uuid.New()})
+	assert.ErrorIs(t, err, ErrSeriesMismatch)
+
+	pass := TicketType{EventID: anchor.ID, PassEventIDs: others}
+	assert.True(t, pass.IsPass())
+	assert.True(t, pass.Admits(days[0].ID))
+	assert.False(t, pass.Admits(uuid.New()))
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validEvent() Event {
+	start := time.Date(2026, 6, 1, 19, 0, 0, 0, time.UTC)
+	return Event{
+		Title:    "Summer Concert",
+		StartsAt: start,
+		EndsAt:   start.Add(3 * time.Hour),
+		TimeZone: "Europe/Athens",
+		Capacity: 500,
+	}
+}
+
+func TestEventModel_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(e *Event)
+		expected error
+	}{
+		{"Valid event", func(e *Event) {}, nil},
+		{"Missing title", func(e *Event) { e.Title = "  " }, ErrEventTitleRequired},
+		{"Ends before start", func(e *Event) { e.EndsAt = e.StartsAt.Add(-time.Hour) }, ErrEventInvalidWindow},
+		{"Zero length", func(e *Event) { e.EndsAt = e.StartsAt }, ErrEventInvalidWindow},
+		{"Unknown time zone", func(e *Event) { e.TimeZone = "Mars/Olympus" }, ErrEventInvalidZone},
+		{"Empty time zone", func(e *Event) { e.TimeZone = "" }, ErrEventInvalidZone},
+		{"Negative capacity", func(e *Event) { e.Capacity = -1 }, ErrEventNegativeSeats},
+		{"Transfers forbidden", func(e *Event) { e.TransferPolicy = TransferPolicyForbidden }, nil},
+		{"Unknown transfer policy", func(e *Event) { e.TransferPolicy = "sometimes" }, ErrEventInvalidTransferPolicy},
+		{"Negative transfer cutoff", func(e *Event) { e.TransferCutoffHours = -1 }, ErrEventNegativeTransferCutoff},
+		{"Negative resale markup", func(e *Event) { e.ResaleMarkupPercent = -5 }, ErrEventNegativeResaleMarkup},
+		{"Negative user limit", func(e *Event) { e.MaxTicketsPerUser = -1 }, ErrEventNegativePurchaseLimit},
+		{"Negative payment method limit", func(e *Event) { e.MaxTicketsPerPaymentMethod = -1 }, ErrEventNegativePurchaseLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := validEvent()
+			tt.mutate(&event)
+			assert.Equal(t, tt.expected, event.Validate())
+		})
+	}
+}
+
+func TestEventStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from     EventStatus
+		to       EventStatus
+		expected bool
+	}{
+		{EventStatusDraft, EventStatusPublished, true},
+		{EventStatusDraft, EventStatusCancelled, true},
+		{EventStatusPublished, EventStatusCancelled, true},
+		{EventStatusPublished, EventStatusDraft, false},
+		{EventStatusPublished, EventStatusPublished, false},
+		{EventStatusCancelled, EventStatusPublished, false},
+		{EventStatusCancelled, EventStatusDraft, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestEventModel_Location(t *testing.T) {
+	event := validEvent()
+	assert.Equal(t, "Europe/Athens", event.Location().String())
+
+	event.TimeZone = "invalid"
+	assert.Equal(t, time.UTC, event.Location())
+}
+
+func TestEventStatus_IsEditable(t *testing.T) {
+	assert.True(t, EventStatusDraft.IsEditable())
+	assert.True(t, EventStatusPublished.IsEditable())
+	assert.False(t, EventStatusCancelled.IsEditable())
+}
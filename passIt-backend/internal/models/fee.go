@@ -0,0 +1,102 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"passIt/internal/pricing"
+
+	"github.com/google/uuid"
+)
+
+type FeeRule struct {
+	// FeeRule is a service fee charged per ticket in one currency. Rules of an event
+	// replace the global rules (no EventID) for that event.
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	EventID        *uuid.UUID `gorm:"type:uuid;index" json:"event_id,omitempty"`
+	Name           string     `gorm:"not null" json:"name"`
+	Currency       string     `gorm:"type:char(3);not null" json:"currency"`
+	FixedPerTicket int64      `gorm:"not null;default:0" json:"fixed_per_ticket"` // in minor units
+	BasisPoints    int64      `gorm:"not null;default:0" json:"basis_points"`     // 250 is 2.5% of the ticket price
+}
+
+type TaxRate struct {
+	// TaxRate is a tax charged on tickets of events at venues in a jurisdiction
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Jurisdiction string    `gorm:"not null;index" json:"jurisdiction"` // e.g. "US-CA" or "DE"
+	Name         string    `gorm:"not null" json:"name"`
+	BasisPoints  int64     `gorm:"not null" json:"basis_points"` // 1900 is 19%
+	OnFees       bool      `gorm:"not null;default:false" json:"on_fees"`
+}
+
+var (
+	ErrFeeRuleNameRequired = errors.New("fee name is required")
+	ErrFeeRuleInvalid      = errors.New("fee amounts cannot be negative and at least one must be set")
+	ErrFeeRuleCurrency     = errors.New("fee currency must be an ISO 4217 code")
+	ErrTaxRateInvalid      = errors.New("tax rate needs a jurisdiction, a name and a rate between 0 and 100%")
+)
+
+// Validate checks the fields an admin is allowed to edit
+func (f *FeeRule) Validate() error {
+	if strings.TrimSpace(f.Name) == "" {
+		return ErrFeeRuleNameRequired
+	}
+	if !pricing.ValidCurrency(f.Currency) {
+		return ErrFeeRuleCurrency
+	}
+	if f.FixedPerTicket < 0 || f.BasisPoints < 0 || f.FixedPerTicket+f.BasisPoints == 0 {
+		return ErrFeeRuleInvalid
+	}
+	return nil
+}
+
+// Validate checks the fields an admin is allowed to edit
+func (t *TaxRate) Validate() error {
+	if strings.TrimSpace(t.Jurisdiction) == "" || strings.TrimSpace(t.Name) == "" || t.BasisPoints < 0 || t.BasisPoints > 10000 {
+		return ErrTaxRateInvalid
+	}
+	return nil
+}
+
+// NormalizeJurisdiction makes jurisdiction codes case and whitespace insensitive
+func NormalizeJurisdiction(jurisdiction string) string {
+	return strings.ToUpper(strings.TrimSpace(jurisdiction))
+}
+
+// ApplyPricing adds the fees and taxes of the order to its items and recalculates the
+// totals. The fees and taxes are priced on what is left to pay after the discounts.
+func (o *Order) ApplyPricing(fees []FeeRule, taxes []TaxRate) error {
+	input := pricing.Input{Currency: o.Currency}
+	for _, item := range o.Items {
+		input.Lines = append(input.Lines, pricing.Line{
+			Name:      item.Name,
+			UnitPrice: item.UnitPrice,
+			Quantity:  item.Quantity,
+			Discount:  item.Discount,
+		})
+	}
+	for _, fee := range fees {
+		if fee.Currency == o.Currency {
+			input.Fees = append(input.Fees, pricing.Fee{Name: fee.Name, FixedPerTicket: fee.FixedPerTicket, BasisPoints: fee.BasisPoints})
+		}
+	}
+	for _, tax := range taxes {
+		input.Taxes = append(input.Taxes, pricing.Tax{Name: tax.Name, BasisPoints: tax.BasisPoints, OnFees: tax.OnFees})
+	}
+
+	breakdown, err := pricing.Calculate(input)
+	if err != nil {
+		return err
+	}
+	for i, line := range breakdown.Lines {
+		o.Items[i].ServiceFee = line.Fees
+		o.Items[i].Tax = line.Tax
+	}
+	o.Breakdown = &breakdown
+	return o.CalculateTotals()
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeRuleModel_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		fee      FeeRule
+		expected error
+	}{
+		{"valid fixed", FeeRule{Name: "Service fee", Currency: "EUR", FixedPerTicket: 150}, nil},
+		{"valid percentage", FeeRule{Name: "Service fee", Currency: "EUR", BasisPoints: 250}, nil},
+		{"missing name", FeeRule{Name: " ", Currency: "EUR", FixedPerTicket: 150}, ErrFeeRuleNameRequired},
+		{"invalid currency", FeeRule{Name: "Service fee", Currency: "euro", FixedPerTicket: 150}, ErrFeeRuleCurrency},
+		{"negative amount", FeeRule{Name: "Service fee", Currency: "EUR", FixedPerTicket: -1, BasisPoints: 250}, ErrFeeRuleInvalid},
+		{"no amount", FeeRule{Name: "Service fee", Currency: "EUR"}, ErrFeeRuleInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.fee.Validate())
+		})
+	}
+}
+
+func TestTaxRateModel_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		tax      TaxRate
+		expected error
+	}{
+		{"valid", TaxRate{Jurisdiction: "DE", Name: "VAT", BasisPoints: 1900}, nil},
+		{"zero rate", TaxRate{Jurisdiction: "US-OR", Name: "Sales tax", BasisPoints: 0}, nil},
+		{"missing jurisdiction", TaxRate{Name: "VAT", BasisPoints: 1900}, ErrTaxRateInvalid},
+		{"missing name", TaxRate{Jurisdiction: "DE", BasisPoints: 1900}, ErrTaxRateInvalid},
+		{"above 100%", TaxRate{Jurisdiction: "DE", Name: "VAT", BasisPoints: 10001}, ErrTaxRateInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.tax.Validate())
+		})
+	}
+}
+
+func TestOrderModel_ApplyPricing(t *testing.T) {
+	order := Order{
+		Currency: "EUR",
+		Items: []OrderItem{
+			{TicketTypeID: uuid.New(), Name: "GA", UnitPrice: 2000, Quantity: 2, Discount: 1000},
+			{TicketTypeID: uuid.New(), Name: "Comp", UnitPrice: 0, Quantity: 1},
+		},
+	}
+	fees := []FeeRule{
+		{Name: "Service fee", Currency: "EUR", FixedPerTicket: 100, BasisPoints: 500},
+		{Name: "Dollar fee", Currency: "USD", FixedPerTicket: 999},
+	}
+	taxes := []TaxRate{{Jurisdiction: "DE", Name: "VAT", BasisPoints: 1900, OnFees: true}}
+
+	require.NoError(t, order.ApplyPricing(fees, taxes))
+
+	// Fee: 2 x 100 + 5% of 3000, VAT: 19% of 3350
+	assert.Equal(t, int64(350), order.Items[0].ServiceFee)
+	assert.Equal(t, int64(637), order.Items[0].Tax)
+	assert.Equal(t, int64(3987), order.Items[0].Total)
+	assert.Zero(t, order.Items[1].Total, "free tickets carry no fees")
+
+	assert.Equal(t, int64(4000), order.Subtotal)
+	assert.Equal(t, int64(1000), order.Discount)
+	assert.Equal(t, int64(350), order.ServiceFee)
+	assert.Equal(t, int64(637), order.Tax)
+	assert.Equal(t, int64(3987), order.Total)
+
+	require.NotNil(t, order.Breakdown)
+	assert.Equal(t, order.Total, order.Breakdown.Total)
+	assert.Len(t, order.Breakdown.Fees, 1, "fees in other currencies are ignored")
+
+	// Refunding every ticket returns the fees and taxes too
+	assert.Equal(t, order.Items[0].Total, order.Items[0].RefundAmount(2))
+}
@@ -0,0 +1,205 @@
+package models
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GuestList struct {
+	// GuestList is a category of guests of an event, such as artists, press or sponsors,
+	// admitted by name at the door or with comp tickets issued to them
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	EventID   uuid.UUID      `gorm:"type:uuid;not null;index" json:"event_id"`
+	Name      string         `gorm:"not null" json:"name"`
+	// Quota caps the guests of the list, plus-ones included; 0 means no limit
+	Quota int `gorm:"not null;default:0" json:"quota"`
+	// TicketTypeID is the ticket type comp tickets of the list are issued as, they take
+	// its inventory like any other ticket
+	TicketTypeID *uuid.UUID       `gorm:"type:uuid" json:"ticket_type_id,omitempty"`
+	CreatedByID  uuid.UUID        `gorm:"type:uuid" json:"created_by_id"`
+	Entries      []GuestListEntry `gorm:"foreignKey:GuestListID" json:"entries,omitempty"`
+	// Guests counts the guests on the list, plus-ones included, filled in on retrieval
+	Guests int `gorm:"-" json:"guests"`
+}
+
+type GuestListEntry struct {
+	// GuestListEntry is one guest of a guest list and the people they bring along
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	GuestListID uuid.UUID `gorm:"type:uuid;not null;index" json:"guest_list_id"`
+	EventID     uuid.UUID `gorm:"type:uuid;not null;index" json:"event_id"`
+	Name        string    `gorm:"not null" json:"name"`
+	Email       string    `json:"email,omitempty"` // required to issue comp tickets
+	Company     string    `json:"company,omitempty"`
+	PlusOnes    int       `gorm:"not null;default:0" json:"plus_ones"`
+	Note        string    `json:"note,omitempty"`
+	// OrderID is the comp order issued to the guest, their tickets are scanned at the door
+	OrderID *uuid.UUID `gorm:"type:uuid;index" json:"order_id,omitempty"`
+	// CheckedIn counts the guests of the entry admitted by name so far
+	CheckedIn     int        `gorm:"not null;default:0" json:"checked_in"`
+	CheckedInAt   *time.Time `json:"checked_in_at,omitempty"` // of the first admission
+	CheckedInByID *uuid.UUID `gorm:"type:uuid" json:"checked_in_by_id,omitempty"`
+	AddedByID     uuid.UUID  `gorm:"type:uuid" json:"added_by_id"`
+	// GuestListName is filled in by searches across the guest lists of an event
+	GuestListName string `gorm:"->;-:migration" json:"guest_list_name,omitempty"`
+}
+
+var (
+	ErrGuestListNameRequired   = errors.New("guest list name is required")
+	ErrGuestListInvalidQuota   = errors.New("guest list quota cannot be negative")
+	ErrGuestListQuotaExceeded  = errors.New("guest list quota exceeded")
+	ErrGuestNameRequired       = errors.New("guest name is required")
+	ErrGuestInvalidEmail       = errors.New("guest email is not a valid email address")
+	ErrGuestInvalidPlusOnes    = errors.New("plus-ones cannot be negative")
+	ErrGuestListInvalidCSV     = errors.New("invalid guest list CSV")
+	ErrGuestAlreadyCheckedIn   = errors.New("every guest of the entry is already checked in")
+	ErrGuestCheckInOutOfBounds = errors.New("more guests than the entry has left to check in")
+)
+
+// Validate checks the fields an organizer sets on a guest list
+func (l *GuestList) Validate() error {
+	if strings.TrimSpace(l.Name) == "" {
+		return ErrGuestListNameRequired
+	}
+	if l.Quota < 0 {
+		return ErrGuestListInvalidQuota
+	}
+	return nil
+}
+
+// CheckQuota reports whether adding the given number of guests keeps the list within its
+// quota, guests is the number already on the list
+func (l *GuestList) CheckQuota(guests, adding int) error {
+	if l.Quota != 0 && guests+adding > l.Quota {
+		return ErrGuestListQuotaExceeded
+	}
+	return nil
+}
+
+// Validate trims and checks the details of a guest
+func (e *GuestListEntry) Validate() error {
+	e.Name = strings.TrimSpace(e.Name)
+	e.Email = strings.ToLower(strings.TrimSpace(e.Email))
+	if e.Name == "" {
+		return ErrGuestNameRequired
+	}
+	if e.Email != "" {
+		if address, err := mail.ParseAddress(e.Email); err != nil || address.Address != e.Email {
+			return ErrGuestInvalidEmail
+		}
+	}
+	if e.PlusOnes < 0 {
+		return ErrGuestInvalidPlusOnes
+	}
+	return nil
+}
+
+// Admissions is the number of people the entry admits, the guest and their plus-ones
+func (e *GuestListEntry) Admissions() int {
+	return 1 + e.PlusOnes
+}
+
+// Remaining is the number of people of the entry not checked in yet
+func (e *GuestListEntry) Remaining() int {
+	return max(e.Admissions()-e.CheckedIn, 0)
+}
+
+// FirstAndLastName splits the name of the guest at its first space
+func (e *GuestListEntry) FirstAndLastName() (string, string) {
+	first, last, _ := strings.Cut(e.Name, " ")
+	return first, strings.TrimSpace(last)
+}
+
+// GuestAdmissions sums up the admissions of guest list entries
+func GuestAdmissions(entries []GuestListEntry) int {
+	total := 0
+	for _, entry := range entries {
+		total += entry.Admissions()
+	}
+	return total
+}
+
+// guestCSVColumns are the columns a guest list CSV may have, only name is required
+var guestCSVColumns = []string{"name", "email", "company", "plus_ones", "note"}
+
+// ParseGuestListCSV reads guests from a CSV file whose header names its columns, e.g.
+// name,email,company,plus_ones,note. Errors wrap ErrGuestListInvalidCSV and name the line.
+func ParseGuestListCSV(r io.Reader) ([]GuestListEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: missing header", ErrGuestListInvalidCSV)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrGuestListInvalidCSV, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(guestCSVColumns, name) {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrGuestListInvalidCSV, name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrGuestListInvalidCSV, name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("%w: missing name column", ErrGuestListInvalidCSV)
+	}
+
+	var entries []GuestListEntry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrGuestListInvalidCSV, err)
+		}
+		if slices.IndexFunc(record, func(field string) bool { return strings.TrimSpace(field) != "" }) < 0 {
+			continue
+		}
+
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		entry := GuestListEntry{
+			Name:    value("name"),
+			Email:   value("email"),
+			Company: value("company"),
+			Note:    value("note"),
+		}
+		if plusOnes := value("plus_ones"); plusOnes != "" {
+			if entry.PlusOnes, err = strconv.Atoi(plusOnes); err != nil {
+				return nil, fmt.Errorf("%w: line %d: plus_ones must be a number", ErrGuestListInvalidCSV, line)
+			}
+		}
+		if err := entry.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrGuestListInvalidCSV, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
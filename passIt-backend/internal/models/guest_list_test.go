@@ -0,0 +1,96 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuestListModel_CheckQuota(t *testing.T) {
+	list := GuestList{Name: "Press", Quota: 10}
+	assert.NoError(t, list.Validate())
+	assert.NoError(t, list.CheckQuota(8, 2))
+	assert.ErrorIs(t, list.CheckQuota(8, 3), ErrGuestListQuotaExceeded)
+	assert.ErrorIs(t, list.CheckQuota(11, 0), ErrGuestListQuotaExceeded, "a quota cannot be lowered below the guests on the list")
+
+	unlimited := GuestList{Name: "Artists"}
+	assert.NoError(t, unlimited.CheckQuota(1000, 50))
+
+	assert.ErrorIs(t, (&GuestList{Name: " "}).Validate(), ErrGuestListNameRequired)
+	assert.ErrorIs(t, (&GuestList{Name: "Press", Quota: -1}).Validate(), ErrGuestListInvalidQuota)
+}
+
+func TestGuestListEntryModel_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    GuestListEntry
+		expected error
+	}{
+		{"Name only", GuestListEntry{Name: "Ada Lovelace"}, nil},
+		{"With email and plus-ones", GuestListEntry{Name: "Ada", Email: " Ada@Example.com ", PlusOnes: 2}, nil},
+		{"Missing name", GuestListEntry{Name: "  "}, ErrGuestNameRequired},
+		{"Invalid email", GuestListEntry{Name: "Ada", Email: "ada"}, ErrGuestInvalidEmail},
+		{"Display name email", GuestListEntry{Name: "Ada", Email: "Ada <ada@example.com>"}, ErrGuestInvalidEmail},
+		{"Negative plus-ones", GuestListEntry{Name: "Ada", PlusOnes: -1}, ErrGuestInvalidPlusOnes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.entry.Validate())
+		})
+	}
+}
+
+func TestGuestListEntryModel_Admissions(t *testing.T) {
+	entry := GuestListEntry{Name: "Ada Lovelace", PlusOnes: 2}
+	assert.Equal(t, 3, entry.Admissions())
+	assert.Equal(t, 3, entry.Remaining())
+
+	entry.CheckedIn = 2
+	assert.Equal(t, 1, entry.Remaining())
+
+	first, last := entry.FirstAndLastName()
+	assert.Equal(t, "Ada", first)
+	assert.Equal(t, "Lovelace", last)
+
+	assert.Equal(t, 4, GuestAdmissions([]GuestListEntry{entry, {Name: "Charles"}}))
+}
+
+func TestParseGuestListCSV(t *testing.T) {
+	csv := "\ufeffName,Email,plus_ones,note\n" +
+		"Ada Lovelace, ADA@example.com ,2,Backstage\n" +
+		"\n" +
+		",,,\n" +
+		"\"Babbage, Charles\",,,\n"
+
+	entries, err := ParseGuestListCSV(strings.NewReader(csv))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, GuestListEntry{Name: "Ada Lovelace", Email: "ada@example.com", PlusOnes: 2, Note: "Backstage"}, entries[0])
+	assert.Equal(t, GuestListEntry{Name: "Babbage, Charles"}, entries[1])
+}
+
+func TestParseGuestListCSV_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		message string
+	}{
+		{"Empty file", "", "missing header"},
+		{"Missing name column", "email\nada@example.com\n", "missing name column"},
+		{"Unknown column", "name,phone\nAda,123\n", `unknown column "phone"`},
+		{"Duplicate column", "name,name\nAda,Ada\n", `duplicate column "name"`},
+		{"Plus-ones not a number", "name,plus_ones\nAda,1\nCharles,two\n", "line 3: plus_ones must be a number"},
+		{"Invalid guest", "name,email\nAda,ada@example.com\n,charles@example.com\n", "line 3: " + ErrGuestNameRequired.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseGuestListCSV(strings.NewReader(tt.csv))
+			assert.ErrorIs(t, err, ErrGuestListInvalidCSV)
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+}
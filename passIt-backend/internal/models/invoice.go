@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BillingDetails is who an invoice is made out to
+type BillingDetails struct {
+	Name    string `json:"name"`
+	Company string `json:"company,omitempty"`
+	Address string `json:"address,omitempty"`
+	TaxID   string `json:"tax_id,omitempty"` // VAT number of corporate buyers
+	Email   string `json:"email,omitempty"`
+}
+
+type Invoice struct {
+	// Invoice is the receipt of a paid order. Numbers are sequential per year without gaps.
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	OrderID   uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex" json:"order_id"`
+	UserID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	Number    string         `gorm:"not null;uniqueIndex" json:"number"`
+	IssuedAt  time.Time      `gorm:"not null" json:"issued_at"`
+	Billing   BillingDetails `gorm:"serializer:json" json:"billing"`
+	Currency  string         `gorm:"type:char(3);not null" json:"currency"`
+	Total     int64          `gorm:"not null" json:"total"` // in minor units
+	PDF       []byte         `gorm:"type:bytea;not null" json:"-"`
+}
+
+// InvoiceCounter holds the last invoice number handed out in a year
+type InvoiceCounter struct {
+	Year int   `gorm:"primaryKey;autoIncrement:false"`
+	Last int64 `gorm:"not null;default:0"`
+}
+
+// InvoiceNumber formats the sequence number of an invoice, e.g. INV-2026-000042
+func InvoiceNumber(year int, sequence int64) string {
+	return fmt.Sprintf("INV-%d-%06d", year, sequence)
+}
+
+// BillingDetailsOf returns the billing details entered at checkout, falling back to
+// the profile of the buyer
+func (o *Order) BillingDetailsOf(user User) BillingDetails {
+	if o.Billing != nil && strings.TrimSpace(o.Billing.Name+o.Billing.Company) != "" {
+		return *o.Billing
+	}
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if name == "" {
+		name = user.Username
+	}
+	return BillingDetails{
+		Name:    name,
+		Address: user.Address,
+		Email:   user.Email,
+	}
+}
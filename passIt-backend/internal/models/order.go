@@ -0,0 +1,204 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"passIt/internal/pricing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrderStatus is the lifecycle state of an order
+type OrderStatus string
+
+const (
+	OrderStatusPending         OrderStatus = "pending"
+	OrderStatusAwaitingPayment OrderStatus = "awaiting_payment"
+	OrderStatusPaid            OrderStatus = "paid"
+	OrderStatusFulfilled       OrderStatus = "fulfilled"
+	OrderStatusCancelled       OrderStatus = "cancelled"
+	OrderStatusExpired         OrderStatus = "expired"
+	OrderStatusRefunded        OrderStatus = "refunded"
+)
+
+type Order struct {
+	// Order represents a purchase of tickets for one event
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	UserID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	EventID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"event_id"`
+	HoldID      string         `gorm:"uniqueIndex;not null" json:"hold_id"`
+	Status      OrderStatus    `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	Currency    string         `gorm:"type:char(3);not null" json:"currency"`
+	Subtotal    int64          `gorm:"not null;default:0" json:"subtotal"` // in minor units
+	Total       int64          `gorm:"not null;default:0" json:"total"`    // in minor units
+	Refunded    int64          `gorm:"not null;default:0" json:"refunded"` // in minor units
+	ExpiresAt   time.Time      `gorm:"not null;index" json:"expires_at"`
+	PaidAt      *time.Time     `json:"paid_at,omitempty"`
+	FulfilledAt *time.Time     `json:"fulfilled_at,omitempty"`
+	CancelledAt *time.Time     `json:"cancelled_at,omitempty"`
+	Items       []OrderItem    `gorm:"foreignKey:OrderID" json:"items"`
+
+	// ResaleListingID is set on orders buying a ticket from another fan
+	ResaleListingID *uuid.UUID `gorm:"type:uuid;index" json:"resale_listing_id,omitempty"`
+	// Discount is the sum of the promo code discounts of the lines, in minor units
+	Discount    int64             `gorm:"not null;default:0" json:"discount"`
+	Redemptions []PromoRedemption `gorm:"foreignKey:OrderID" json:"redemptions,omitempty"`
+	// ServiceFee and Tax are the sums of the fees and taxes of the lines, in minor units
+	ServiceFee int64              `gorm:"not null;default:0" json:"service_fee"`
+	Tax        int64              `gorm:"not null;default:0" json:"tax"`
+	Breakdown  *pricing.Breakdown `gorm:"serializer:json" json:"breakdown,omitempty"`
+	// Billing is who the invoice is made out to, entered at checkout
+	Billing *BillingDetails `gorm:"serializer:json" json:"billing,omitempty"`
+	// Attendees are the answers to the attendee questions of the event, one per ticket asked
+	Attendees []AttendeeAnswers `gorm:"foreignKey:OrderID" json:"attendees,omitempty"`
+	// SoldByID is the box office staff member who sold the order on behalf of the buyer
+	SoldByID *uuid.UUID `gorm:"type:uuid;index" json:"sold_by_id,omitempty"`
+
+	// RiskScore rates from 0 to 100 how likely the order was placed by a bot or a scalper,
+	// RiskReasons are the signals that raised it
+	RiskScore   int          `gorm:"not null;default:0" json:"risk_score"`
+	RiskReasons []RiskReason `gorm:"serializer:json" json:"risk_reasons,omitempty"`
+	// ReviewStatus is set on orders routed to manual review
+	ReviewStatus ReviewStatus `gorm:"type:varchar(20);index" json:"review_status,omitempty"`
+	ReviewedByID *uuid.UUID   `gorm:"type:uuid" json:"reviewed_by_id,omitempty"`
+	ReviewedAt   *time.Time   `json:"reviewed_at,omitempty"`
+}
+
+type OrderItem struct {
+	// OrderItem is one line of an order: a ticket type, optionally on a specific seat
+	ID                uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt         time.Time  `json:"created_at"`
+	OrderID           uuid.UUID  `gorm:"type:uuid;not null;index" json:"order_id"`
+	TicketTypeID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"ticket_type_id"`
+	EventSeatID       *uuid.UUID `gorm:"type:uuid;index" json:"event_seat_id,omitempty"`
+	Name              string     `gorm:"not null" json:"name"`
+	UnitPrice         int64      `gorm:"not null" json:"unit_price"` // in minor units
+	Quantity          int        `gorm:"not null" json:"quantity"`
+	Total             int64      `gorm:"not null" json:"total"`                        // in minor units
+	RefundedQuantity  int        `gorm:"not null;default:0" json:"refunded_quantity"`  // tickets of this line that were refunded
+	RestockedQuantity int        `gorm:"not null;default:0" json:"restocked_quantity"` // refunded tickets given back to sale
+	ResoldQuantity    int        `gorm:"not null;default:0" json:"resold_quantity"`    // tickets sold on to other fans
+
+	Discount   int64 `gorm:"not null;default:0" json:"discount"`    // promo code discount on the whole line, in minor units
+	ServiceFee int64 `gorm:"not null;default:0" json:"service_fee"` // service fees of the whole line, in minor units
+	Tax        int64 `gorm:"not null;default:0" json:"tax"`         // taxes of the whole line, in minor units
+}
+
+var (
+	ErrOrderEmpty            = errors.New("order must contain at least one item")
+	ErrOrderMixedCurrency    = errors.New("all items of an order must use the same currency")
+	ErrOrderInvalidStatus    = errors.New("invalid order status transition")
+	ErrOrderInvalidLineItems = errors.New("order items must have a positive quantity and a non-negative price")
+)
+
+// CanTransitionTo reports whether the order may move to the given status.
+// Unpaid orders can be cancelled or expire, paid orders are fulfilled or refunded
+// and cancelled, expired and refunded orders are final.
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	switch s {
+	case OrderStatusPending:
+		return next == OrderStatusAwaitingPayment || next == OrderStatusCancelled || next == OrderStatusExpired
+	case OrderStatusAwaitingPayment:
+		return next == OrderStatusPaid || next == OrderStatusCancelled || next == OrderStatusExpired
+	case OrderStatusPaid:
+		return next == OrderStatusFulfilled || next == OrderStatusRefunded
+	case OrderStatusFulfilled:
+		return next == OrderStatusRefunded
+	default:
+		return false
+	}
+}
+
+// ReleasesInventory reports whether moving to this status gives every ticket of the order back to sale
+func (s OrderStatus) ReleasesInventory() bool {
+	return s == OrderStatusCancelled || s == OrderStatusExpired
+}
+
+// IsUnpaid reports whether the order still waits for payment
+func (s OrderStatus) IsUnpaid() bool {
+	return s == OrderStatusPending || s == OrderStatusAwaitingPayment
+}
+
+// CalculateTotals computes the line and order totals from the item prices, discounts,
+// fees and taxes
+func (o *Order) CalculateTotals() error {
+	if len(o.Items) == 0 {
+		return ErrOrderEmpty
+	}
+
+	var subtotal, discount, serviceFee, tax int64
+	for i := range o.Items {
+		item := &o.Items[i]
+		gross := item.UnitPrice * int64(item.Quantity)
+		if item.Quantity <= 0 || item.UnitPrice < 0 || item.Discount < 0 || item.Discount > gross ||
+			item.ServiceFee < 0 || item.Tax < 0 {
+			return ErrOrderInvalidLineItems
+		}
+		item.Total = gross - item.Discount + item.ServiceFee + item.Tax
+		subtotal += gross
+		discount += item.Discount
+		serviceFee += item.ServiceFee
+		tax += item.Tax
+	}
+
+	o.Subtotal = subtotal
+	o.Discount = discount
+	o.ServiceFee = serviceFee
+	o.Tax = tax
+	o.Total = subtotal - discount + serviceFee + tax
+	return nil
+}
+
+// MakeComplimentary gives the tickets of the order away, dropping their prices, discounts,
+// fees and taxes
+func (o *Order) MakeComplimentary() error {
+	for i := range o.Items {
+		item := &o.Items[i]
+		item.UnitPrice, item.Discount, item.ServiceFee, item.Tax = 0, 0, 0, 0
+	}
+	o.Breakdown = nil
+	return o.CalculateTotals()
+}
+
+// TicketCount returns the number of tickets in the order
+func (o *Order) TicketCount() int {
+	count := 0
+	for _, item := range o.Items {
+		count += item.Quantity
+	}
+	return count
+}
+
+// RefundAmount returns what was paid for the next quantity tickets of the line, discount,
+// fees and taxes included. Shares are rounded so refunding every ticket returns exactly
+// the line total.
+func (i *OrderItem) RefundAmount(quantity int) int64 {
+	paid := i.UnitPrice*int64(i.Quantity) - i.Discount + i.ServiceFee + i.Tax
+	paidFor := func(tickets int) int64 { return paid * int64(tickets) / int64(i.Quantity) }
+	return paidFor(i.RefundedQuantity+quantity) - paidFor(i.RefundedQuantity)
+}
+
+// RemainingQuantity returns how many tickets of the line have not been refunded or resold
+func (i *OrderItem) RemainingQuantity() int {
+	return i.Quantity - i.RefundedQuantity - i.ResoldQuantity
+}
+
+// IsResale reports whether the order buys a ticket on the resale marketplace
+func (o *Order) IsResale() bool {
+	return o.ResaleListingID != nil
+}
+
+// IsFullyRefunded reports whether every ticket of the order was refunded
+func (o *Order) IsFullyRefunded() bool {
+	for _, item := range o.Items {
+		if item.RemainingQuantity() > 0 {
+			return false
+		}
+	}
+	return len(o.Items) > 0
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from     OrderStatus
+		to       OrderStatus
+		expected bool
+	}{
+		{OrderStatusPending, OrderStatusAwaitingPayment, true},
+		{OrderStatusPending, OrderStatusCancelled, true},
+		{OrderStatusPending, OrderStatusExpired, true},
+		{OrderStatusPending, OrderStatusPaid, false},
+		{OrderStatusPending, OrderStatusFulfilled, false},
+		{OrderStatusAwaitingPayment, OrderStatusPaid, true},
+		{OrderStatusAwaitingPayment, OrderStatusCancelled, true},
+		{OrderStatusAwaitingPayment, OrderStatusExpired, true},
+		{OrderStatusAwaitingPayment, OrderStatusPending, false},
+		{OrderStatusPaid, OrderStatusFulfilled, true},
+		{OrderStatusPaid, OrderStatusRefunded, true},
+		{OrderStatusPaid, OrderStatusCancelled, false},
+		{OrderStatusPaid, OrderStatusExpired, false},
+		{OrderStatusFulfilled, OrderStatusRefunded, true},
+		{OrderStatusFulfilled, OrderStatusPaid, false},
+		{OrderStatusCancelled, OrderStatusPending, false},
+		{OrderStatusExpired, OrderStatusAwaitingPayment, false},
+		{OrderStatusRefunded, OrderStatusPaid, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestOrderStatus_ReleasesInventory(t *testing.T) {
+	assert.False(t, OrderStatusPending.ReleasesInventory())
+	assert.False(t, OrderStatusAwaitingPayment.ReleasesInventory())
+	assert.False(t, OrderStatusPaid.ReleasesInventory())
+	assert.False(t, OrderStatusFulfilled.ReleasesInventory())
+	assert.True(t, OrderStatusCancelled.ReleasesInventory())
+	assert.True(t, OrderStatusExpired.ReleasesInventory())
+	assert.False(t, OrderStatusRefunded.ReleasesInventory(), "refunds restock ticket by ticket")
+}
+
+func TestOrderModel_CalculateTotals(t *testing.T) {
+	order := Order{
+		Currency: "EUR",
+		Items: []OrderItem{
+			{TicketTypeID: uuid.New(), UnitPrice: 2500, Quantity: 2},
+			{TicketTypeID: uuid.New(), UnitPrice: 1000, Quantity: 1},
+			{TicketTypeID: uuid.New(), UnitPrice: 0, Quantity: 3},
+		},
+	}
+
+	assert.NoError(t, order.CalculateTotals())
+	assert.Equal(t, int64(5000), order.Items[0].Total)
+	assert.Equal(t, int64(1000), order.Items[1].Total)
+	assert.Equal(t, int64(0), order.Items[2].Total)
+	assert.Equal(t, int64(6000), order.Subtotal)
+	assert.Equal(t, int64(6000), order.Total)
+	assert.Equal(t, 6, order.TicketCount())
+}
+
+func TestOrderModel_MakeComplimentary(t *testing.T) {
+	order := Order{
+		Currency: "EUR",
+		Items: []OrderItem{
+			{TicketTypeID: uuid.New(), UnitPrice: 2500, Quantity: 4, Discount: 1000, ServiceFee: 400, Tax: 475},
+		},
+	}
+	assert.NoError(t, order.CalculateTotals())
+	assert.NotZero(t, order.Total)
+
+	assert.NoError(t, order.MakeComplimentary())
+	assert.Equal(t, int64(0), order.Items[0].Total)
+	assert.Equal(t, int64(0), order.Total)
+	assert.Equal(t, 4, order.TicketCount(), "the tickets are kept")
+}
+
+func TestOrderModel_CalculateTotalsInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []OrderItem
+		expected error
+	}{
+		{"No items", nil, ErrOrderEmpty},
+		{"Zero quantity", []OrderItem{{UnitPrice: 100, Quantity: 0}}, ErrOrderInvalidLineItems},
+		{"Negative price", []OrderItem{{UnitPrice: -1, Quantity: 1}}, ErrOrderInvalidLineItems},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := Order{Items: tt.items}
+			assert.Equal(t, tt.expected, order.CalculateTotals())
+		})
+	}
+}
+
+func refundableOrder() Order {
+	return Order{
+		Currency: "EUR",
+		Status:   OrderStatusPaid,
+		Items: []OrderItem{
+			{ID: uuid.New(), UnitPrice: 2500, Quantity: 4},
+			{ID: uuid.New(), UnitPrice: 4000, Quantity: 1, RefundedQuantity: 1},
+			{ID: uuid.New(), UnitPrice: 1000, Quantity: 2, RefundedQuantity: 1},
+		},
+	}
+}
+
+func TestOrderModel_PlanRefundWholeOrder(t *testing.T) {
+	order := refundableOrder()
+
+	items, amount, err := order.PlanRefund(nil)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2, "fully refunded lines are skipped")
+	assert.Equal(t, 4, items[0].Quantity)
+	assert.Equal(t, int64(10000), items[0].Amount)
+	assert.Equal(t, 1, items[1].Quantity)
+	assert.Equal(t, int64(11000), amount)
+}
+
+func TestOrderModel_PlanRefundPartial(t *testing.T) {
+	order := refundableOrder()
+	first, second, third := order.Items[0].ID, order.Items[1].ID, order.Items[2].ID
+
+	tests := []struct {
+		name       string
+		quantities map[uuid.UUID]int
+		amount     int64
+		expected   error
+	}{
+		{"Some tickets of a line", map[uuid.UUID]int{first: 2}, 5000, nil},
+		{"Several lines", map[uuid.UUID]int{first: 1, third: 1}, 3500, nil},
+		{"More than left", map[uuid.UUID]int{third: 2}, 0, ErrRefundExceedsTickets},
+		{"Already refunded line", map[uuid.UUID]int{second: 1}, 0, ErrRefundExceedsTickets},
+		{"Zero quantity", map[uuid.UUID]int{first: 0}, 0, ErrRefundExceedsTickets},
+		{"Unknown item", map[uuid.UUID]int{uuid.New(): 1}, 0, ErrRefundUnknownItem},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, amount, err := order.PlanRefund(tt.quantities)
+			assert.Equal(t, tt.expected, err)
+			assert.Equal(t, tt.amount, amount)
+		})
+	}
+}
+
+func TestOrderModel_FullyRefunded(t *testing.T) {
+	order := refundableOrder()
+	assert.False(t, order.IsFullyRefunded())
+
+	refunded := Order{Items: []OrderItem{{ID: uuid.New(), Quantity: 1, RefundedQuantity: 1}}}
+	_, _, err := refunded.PlanRefund(nil)
+	assert.Equal(t, ErrRefundNothingToRefund, err)
+
+	for i := range order.Items {
+		order.Items[i].RefundedQuantity = order.Items[i].Quantity
+	}
+	assert.True(t, order.IsFullyRefunded())
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrgRole is the role of a member within an organization
+type OrgRole string
+
+const (
+	OrgRoleOwner     OrgRole = "owner"
+	OrgRoleManager   OrgRole = "manager"
+	OrgRoleBoxOffice OrgRole = "box_office"
+	OrgRoleScanner   OrgRole = "scanner"
+)
+
+// OrgCapability is something a member may do within their organization
+type OrgCapability string
+
+const (
+	OrgCapManageMembers OrgCapability = "manage_members"
+	OrgCapManageEvents  OrgCapability = "manage_events"
+	OrgCapPublishEvents OrgCapability = "publish_events"
+	OrgCapViewOrders    OrgCapability = "view_orders"
+	OrgCapRefundOrders  OrgCapability = "refund_orders"
+	OrgCapSellTickets   OrgCapability = "sell_tickets"
+	OrgCapCheckIn       OrgCapability = "check_in"
+	OrgCapViewReports   OrgCapability = "view_reports"
+)
+
+var orgRoleCapabilities = map[OrgRole][]OrgCapability{
+	OrgRoleOwner: {
+		OrgCapManageMembers, OrgCapManageEvents, OrgCapPublishEvents, OrgCapViewOrders,
+		OrgCapRefundOrders, OrgCapSellTickets, OrgCapCheckIn, OrgCapViewReports,
+	},
+	OrgRoleManager: {
+		OrgCapManageEvents, OrgCapPublishEvents, OrgCapViewOrders, OrgCapRefundOrders,
+		OrgCapSellTickets, OrgCapCheckIn, OrgCapViewReports,
+	},
+	OrgRoleBoxOffice: {OrgCapViewOrders, OrgCapSellTickets, OrgCapCheckIn},
+	OrgRoleScanner:   {OrgCapCheckIn},
+}
+
+// orgCapabilityPermissions gives the platform permission that allows staff to act
+// like a member with the capability in every organization
+var orgCapabilityPermissions = map[OrgCapability]Permission{
+	OrgCapManageMembers: PermOrganizationsWrite,
+	OrgCapManageEvents:  PermEventsWrite,
+	OrgCapPublishEvents: PermEventsPublish,
+	OrgCapViewOrders:    PermOrdersRead,
+	OrgCapRefundOrders:  PermOrdersRefund,
+	OrgCapSellTickets:   PermOrdersSell,
+	OrgCapCheckIn:       PermTicketsCheckIn,
+	OrgCapViewReports:   PermReportsRead,
+}
+
+type Organization struct {
+	// Organization is an organizer hosting events on the platform. Its events, orders
+	// and reports are only visible to its members and platform admins.
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	Name      string         `gorm:"not null" json:"name"`
+	Slug      string         `gorm:"not null;uniqueIndex" json:"slug"`
+}
+
+type OrgMembership struct {
+	// OrgMembership gives a user a role in an organization
+	ID             uuid.UUID     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	OrganizationID uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_org_memberships_org_user" json:"organization_id"`
+	UserID         uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_org_memberships_org_user;index" json:"user_id"`
+	Role           OrgRole       `gorm:"type:varchar(20);not null" json:"role"`
+	User           *User         `json:"user,omitempty"`
+	Organization   *Organization `json:"organization,omitempty"`
+}
+
+var (
+	ErrOrgNameRequired = errors.New("organization name is required")
+	ErrOrgInvalidSlug  = errors.New("organization slug may only contain lower case letters, digits and dashes")
+	ErrOrgInvalidRole  = errors.New("role must be owner, manager, box_office or scanner")
+	ErrOrgLastOwner    = errors.New("an organization must keep at least one owner")
+)
+
+// IsValid reports whether the role is one of the known organization roles
+func (r OrgRole) IsValid() bool {
+	_, ok := orgRoleCapabilities[r]
+	return ok
+}
+
+// Can reports whether members with this role have the capability
+func (r OrgRole) Can(capability OrgCapability) bool {
+	return slices.Contains(orgRoleCapabilities[r], capability)
+}
+
+// Permission returns the platform permission that grants the capability in every organization
+func (c OrgCapability) Permission() Permission {
+	if p, ok := orgCapabilityPermissions[c]; ok {
+		return p
+	}
+	return PermAll
+}
+
+// Validate checks the fields an admin is allowed to edit. An empty slug is derived from the name.
+func (o *Organization) Validate() error {
+	o.Name = strings.TrimSpace(o.Name)
+	if o.Name == "" {
+		return ErrOrgNameRequired
+	}
+	if o.Slug == "" {
+		o.Slug = Slugify(o.Name)
+	}
+	if o.Slug == "" || o.Slug != Slugify(o.Slug) {
+		return ErrOrgInvalidSlug
+	}
+	return nil
+}
+
+// Slugify turns a name into a URL friendly identifier, e.g. "Rock & Roll Ltd." becomes "rock-roll-ltd"
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrgRole_Can(t *testing.T) {
+	tests := []struct {
+		role       OrgRole
+		capability OrgCapability
+		expected   bool
+	}{
+		{OrgRoleOwner, OrgCapManageMembers, true},
+		{OrgRoleOwner, OrgCapManageEvents, true},
+		{OrgRoleManager, OrgCapManageMembers, false},
+		{OrgRoleManager, OrgCapManageEvents, true},
+		{OrgRoleManager, OrgCapPublishEvents, true},
+		{OrgRoleManager, OrgCapRefundOrders, true},
+		{OrgRoleBoxOffice, OrgCapSellTickets, true},
+		{OrgRoleBoxOffice, OrgCapViewOrders, true},
+		{OrgRoleBoxOffice, OrgCapManageEvents, false},
+		{OrgRoleBoxOffice, OrgCapRefundOrders, false},
+		{OrgRoleScanner, OrgCapCheckIn, true},
+		{OrgRoleScanner, OrgCapViewOrders, false},
+		{OrgRole("guest"), OrgCapCheckIn, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role)+"/"+string(tt.capability), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.role.Can(tt.capability))
+		})
+	}
+}
+
+func TestOrgCapability_Permission(t *testing.T) {
+	assert.Equal(t, PermEventsWrite, OrgCapManageEvents.Permission())
+	assert.Equal(t, PermEventsPublish, OrgCapPublishEvents.Permission())
+	assert.Equal(t, PermTicketsCheckIn, OrgCapCheckIn.Permission())
+	assert.Equal(t, PermAll, OrgCapability("unknown").Permission())
+}
+
+func TestOrgRole_IsValid(t *testing.T) {
+	assert.True(t, OrgRoleOwner.IsValid())
+	assert.True(t, OrgRoleScanner.IsValid())
+	assert.False(t, OrgRole("admin").IsValid())
+	assert.False(t, OrgRole("").IsValid())
+}
+
+func TestOrganizationModel_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		org          Organization
+		expected     error
+		expectedSlug string
+	}{
+		{"derives slug", Organization{Name: "Rock & Roll Ltd."}, nil, "rock-roll-ltd"},
+		{"keeps slug", Organization{Name: "Rock & Roll Ltd.", Slug: "rnr"}, nil, "rnr"},
+		{"missing name", Organization{Name: "  "}, ErrOrgNameRequired, ""},
+		{"invalid slug", Organization{Name: "Acme", Slug: "Acme Events"}, ErrOrgInvalidSlug, "Acme Events"},
+		{"name without letters", Organization{Name: "!!!"}, ErrOrgInvalidSlug, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.org.Validate())
+			assert.Equal(t, tt.expectedSlug, tt.org.Slug)
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "summer-festival-2026", Slugify("  Summer Festival 2026 "))
+	assert.Equal(t, "a-b", Slugify("a--b"))
+	assert.Equal(t, "", Slugify("---"))
+}
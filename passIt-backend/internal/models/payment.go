@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PaymentStatus is the state of a payment collected for an order
+type PaymentStatus string
+
+const (
+	PaymentStatusPending    PaymentStatus = "pending"
+	PaymentStatusProcessing PaymentStatus = "processing"
+	PaymentStatusCaptured   PaymentStatus = "captured"
+	PaymentStatusFailed     PaymentStatus = "failed"
+	PaymentStatusRefunded   PaymentStatus = "refunded"
+)
+
+// PaymentMethod is how the box office collected the payment of an order
+type PaymentMethod string
+
+const (
+	PaymentMethodCash         PaymentMethod = "cash"
+	PaymentMethodCardTerminal PaymentMethod = "card_terminal"
+	// PaymentMethodComp gives the tickets away, the order is free
+	PaymentMethodComp PaymentMethod = "comp"
+)
+
+// BoxOfficeProvider is the provider of payments collected by staff at the box office
+const BoxOfficeProvider = "box_office"
+
+var ErrPaymentInvalidMethod = errors.New("payment method must be cash, card_terminal or comp")
+
+// Validate checks that the box office accepts the payment method
+func (m PaymentMethod) Validate() error {
+	switch m {
+	case PaymentMethodCash, PaymentMethodCardTerminal, PaymentMethodComp:
+		return nil
+	default:
+		return ErrPaymentInvalidMethod
+	}
+}
+
+type Payment struct {
+	// Payment tracks one payment intent at the payment provider for an order
+	ID            uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	OrderID       uuid.UUID      `gorm:"type:uuid;not null;index" json:"order_id"`
+	Provider      string         `gorm:"not null" json:"provider"`
+	IntentID      string         `gorm:"uniqueIndex;not null" json:"intent_id"`
+	ClientSecret  string         `json:"client_secret,omitempty"`
+	Amount        int64          `gorm:"not null" json:"amount"` // in minor units
+	Currency      string         `gorm:"type:char(3);not null" json:"currency"`
+	Status        PaymentStatus  `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	FailureReason string         `json:"failure_reason,omitempty"`
+	CapturedAt    *time.Time     `json:"captured_at,omitempty"`
+
+	// Method, CollectedByID and Reference are set on payments collected at the box office:
+	// how it was paid, by which staff member and e.g. the receipt number of the card terminal
+	Method        PaymentMethod `gorm:"type:varchar(20)" json:"method,omitempty"`
+	CollectedByID *uuid.UUID    `gorm:"type:uuid;index" json:"collected_by_id,omitempty"`
+	Reference     string        `json:"reference,omitempty"`
+
+	// MethodFingerprint identifies the card or account paying, LimitedTickets are the tickets
+	// the payment counts against the purchase limit of that payment method for the event
+	MethodFingerprint string `gorm:"index" json:"-"`
+	LimitedTickets    int    `gorm:"not null;default:0" json:"-"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentMethod_Validate(t *testing.T) {
+	assert.NoError(t, PaymentMethodCash.Validate())
+	assert.NoError(t, PaymentMethodCardTerminal.Validate())
+	assert.NoError(t, PaymentMethodComp.Validate())
+	assert.ErrorIs(t, PaymentMethod("").Validate(), ErrPaymentInvalidMethod)
+	assert.ErrorIs(t, PaymentMethod("cheque").Validate(), ErrPaymentInvalidMethod)
+}
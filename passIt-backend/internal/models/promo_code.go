@@ -0,0 +1,211 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"passIt/internal/pricing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DiscountType is how a promo code reduces the price
+type DiscountType string
+
+const (
+	DiscountPercentage DiscountType = "percentage"
+	DiscountFixed      DiscountType = "fixed" // a fixed amount off the eligible tickets of the order
+)
+
+type PromoCode struct {
+	// PromoCode is a marketing discount buyers enter at checkout
+	ID             uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	Code           string         `gorm:"not null;uniqueIndex:idx_promo_codes_code,where:deleted_at IS NULL" json:"code"` // stored upper case, matched case-insensitively
+	Description    string         `json:"description"`
+	DiscountType   DiscountType   `gorm:"type:varchar(20);not null" json:"discount_type"`
+	PercentOff     int            `gorm:"not null;default:0" json:"percent_off,omitempty"` // 1-100 for percentage codes
+	AmountOff      int64          `gorm:"not null;default:0" json:"amount_off,omitempty"`  // in minor units of Currency for fixed codes
+	Currency       string         `gorm:"type:char(3)" json:"currency,omitempty"`
+	EventIDs       []uuid.UUID    `gorm:"serializer:json" json:"event_ids"`          // empty means every event
+	TicketTypeIDs  []uuid.UUID    `gorm:"serializer:json" json:"ticket_type_ids"`    // empty means every ticket type
+	MaxRedemptions int            `gorm:"not null;default:0" json:"max_redemptions"` // 0 means unlimited
+	MaxPerUser     int            `gorm:"not null;default:0" json:"max_per_user"`    // 0 means unlimited
+	Redemptions    int            `gorm:"not null;default:0" json:"redemptions"`     // orders currently using the code
+	StartsAt       *time.Time     `json:"starts_at,omitempty"`
+	EndsAt         *time.Time     `json:"ends_at,omitempty"`
+	Stackable      bool           `gorm:"not null;default:false" json:"stackable"` // can be combined with other stackable codes
+	Active         bool           `gorm:"not null;default:true" json:"active"`
+	CreatedByID    uuid.UUID      `gorm:"type:uuid;not null" json:"created_by_id"`
+}
+
+type PromoRedemption struct {
+	// PromoRedemption records a promo code used by an order. Redemptions of orders that
+	// were cancelled or expired are released and no longer count towards the limits.
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	PromoCodeID uuid.UUID  `gorm:"type:uuid;not null;index" json:"promo_code_id"`
+	OrderID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"order_id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Code        string     `gorm:"not null" json:"code"`
+	Amount      int64      `gorm:"not null" json:"amount"` // discount granted, in minor units
+	ReleasedAt  *time.Time `json:"released_at,omitempty"`
+}
+
+var (
+	ErrPromoCodeRequired       = errors.New("promo code is required")
+	ErrPromoCodeInvalidType    = errors.New("discount type must be percentage or fixed")
+	ErrPromoCodeInvalidPercent = errors.New("percentage discount must be between 1 and 100")
+	ErrPromoCodeInvalidAmount  = errors.New("fixed discount must be positive and have a currency")
+	ErrPromoCodeInvalidLimits  = errors.New("redemption limits cannot be negative")
+	ErrPromoCodeInvalidWindow  = errors.New("validity window must end after it starts")
+	ErrPromoCodeNotValid       = errors.New("promo code is not valid at this time")
+	ErrPromoCodeNotApplicable  = errors.New("promo code does not apply to this order")
+	ErrPromoCodeNotStackable   = errors.New("promo code cannot be combined with other codes")
+	ErrPromoCodeDuplicate      = errors.New("promo code was entered twice")
+	ErrPromoCodeExhausted      = errors.New("promo code has reached its redemption limit")
+	ErrPromoCodeUserLimit      = errors.New("you have already used this promo code the maximum number of times")
+)
+
+// NormalizePromoCode makes codes case and whitespace insensitive
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks the fields an admin is allowed to edit
+func (p *PromoCode) Validate() error {
+	if p.Code == "" {
+		return ErrPromoCodeRequired
+	}
+	switch p.DiscountType {
+	case DiscountPercentage:
+		if p.PercentOff < 1 || p.PercentOff > 100 {
+			return ErrPromoCodeInvalidPercent
+		}
+	case DiscountFixed:
+		if p.AmountOff <= 0 || !pricing.ValidCurrency(p.Currency) {
+			return ErrPromoCodeInvalidAmount
+		}
+	default:
+		return ErrPromoCodeInvalidType
+	}
+	if p.MaxRedemptions < 0 || p.MaxPerUser < 0 {
+		return ErrPromoCodeInvalidLimits
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return ErrPromoCodeInvalidWindow
+	}
+	return nil
+}
+
+// IsValidAt reports whether the code is active and inside its validity window
+func (p *PromoCode) IsValidAt(now time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// appliesTo reports whether an order line is in the scope of the code
+func (p *PromoCode) appliesTo(eventID uuid.UUID, item *OrderItem) bool {
+	if len(p.EventIDs) > 0 && !slices.Contains(p.EventIDs, eventID) {
+		return false
+	}
+	return len(p.TicketTypeIDs) == 0 || slices.Contains(p.TicketTypeIDs, item.TicketTypeID)
+}
+
+// ApplyPromoCodes discounts the order lines in the scope of each code and recalculates
+// the totals. Codes are applied in the given order, each on what is left to pay after
+// the previous ones, so stacked codes never discount more than the order is worth.
+func (o *Order) ApplyPromoCodes(codes []PromoCode, now time.Time) ([]PromoRedemption, error) {
+	seen := make(map[uuid.UUID]bool, len(codes))
+	for i := range codes {
+		code := &codes[i]
+		if seen[code.ID] {
+			return nil, ErrPromoCodeDuplicate
+		}
+		seen[code.ID] = true
+		if len(codes) > 1 && !code.Stackable {
+			return nil, ErrPromoCodeNotStackable
+		}
+		if !code.IsValidAt(now) {
+			return nil, ErrPromoCodeNotValid
+		}
+	}
+
+	var redemptions []PromoRedemption
+	for i := range codes {
+		code := &codes[i]
+
+		var eligible []*OrderItem
+		var remaining int64
+		for j := range o.Items {
+			item := &o.Items[j]
+			if code.appliesTo(o.EventID, item) {
+				eligible = append(eligible, item)
+				remaining += item.payable()
+			}
+		}
+		if len(eligible) == 0 || remaining == 0 {
+			return nil, ErrPromoCodeNotApplicable
+		}
+
+		var discount int64
+		switch code.DiscountType {
+		case DiscountPercentage:
+			discount = remaining * int64(code.PercentOff) / 100
+		case DiscountFixed:
+			if code.Currency != o.Currency {
+				return nil, ErrPromoCodeNotApplicable
+			}
+			discount = min(code.AmountOff, remaining)
+		default:
+			return nil, ErrPromoCodeInvalidType
+		}
+
+		// Spread the discount over the eligible lines in proportion to what is left on
+		// each, then hand out the rounding difference to lines that still have room
+		shares := make([]int64, len(eligible))
+		left := discount
+		for j, item := range eligible {
+			shares[j] = discount * item.payable() / remaining
+			left -= shares[j]
+		}
+		for j, item := range eligible {
+			extra := min(left, item.payable()-shares[j])
+			shares[j] += extra
+			left -= extra
+		}
+		for j, item := range eligible {
+			item.Discount += shares[j]
+		}
+
+		redemptions = append(redemptions, PromoRedemption{
+			PromoCodeID: code.ID,
+			UserID:      o.UserID,
+			Code:        code.Code,
+			Amount:      discount,
+		})
+	}
+
+	if err := o.CalculateTotals(); err != nil {
+		return nil, err
+	}
+	return redemptions, nil
+}
+
+// payable returns what is left to pay for the line after the discounts so far
+func (i *OrderItem) payable() int64 {
+	return i.UnitPrice*int64(i.Quantity) - i.Discount
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func validPromoCode() PromoCode {
+	return PromoCode{
+		ID:           uuid.New(),
+		Code:         "SUMMER10",
+		DiscountType: DiscountPercentage,
+		PercentOff:   10,
+		Active:       true,
+	}
+}
+
+func TestPromoCodeModel_Validate(t *testing.T) {
+	start := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		mutate   func(p *PromoCode)
+		expected error
+	}{
+		{"Valid percentage", func(p *PromoCode) {}, nil},
+		{"Valid fixed", func(p *PromoCode) { p.DiscountType, p.AmountOff, p.Currency = DiscountFixed, 500, "EUR" }, nil},
+		{"Missing code", func(p *PromoCode) { p.Code = "" }, ErrPromoCodeRequired},
+		{"Unknown type", func(p *PromoCode) { p.DiscountType = "bogo" }, ErrPromoCodeInvalidType},
+		{"Zero percent", func(p *PromoCode) { p.PercentOff = 0 }, ErrPromoCodeInvalidPercent},
+		{"Over 100 percent", func(p *PromoCode) { p.PercentOff = 101 }, ErrPromoCodeInvalidPercent},
+		{"Fixed without currency", func(p *PromoCode) { p.DiscountType, p.AmountOff = DiscountFixed, 500 }, ErrPromoCodeInvalidAmount},
+		{"Fixed without amount", func(p *PromoCode) { p.DiscountType, p.Currency = DiscountFixed, "EUR" }, ErrPromoCodeInvalidAmount},
+		{"Negative limit", func(p *PromoCode) { p.MaxPerUser = -1 }, ErrPromoCodeInvalidLimits},
+		{"Window ends before start", func(p *PromoCode) { p.StartsAt, p.EndsAt = &start, &before }, ErrPromoCodeInvalidWindow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := validPromoCode()
+			tt.mutate(&code)
+			assert.Equal(t, tt.expected, code.Validate())
+		})
+	}
+}
+
+func TestPromoCodeModel_IsValidAt(t *testing.T) {
+	start := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	code := validPromoCode()
+	code.StartsAt, code.EndsAt = &start, &end
+
+	assert.False(t, code.IsValidAt(start.Add(-time.Second)))
+	assert.True(t, code.IsValidAt(start))
+	assert.False(t, code.IsValidAt(end))
+
+	code.Active = false
+	assert.False(t, code.IsValidAt(start))
+}
+
+func TestNormalizePromoCode(t *testing.T) {
+	assert.Equal(t, "SUMMER10", NormalizePromoCode("  summer10 "))
+}
+
+func discountableOrder() Order {
+	return Order{
+		EventID:  uuid.New(),
+		UserID:   uuid.New(),
+		Currency: "EUR",
+		Items: []OrderItem{
+			{TicketTypeID: uuid.New(), UnitPrice: 5000, Quantity: 2},
+			{TicketTypeID: uuid.New(), UnitPrice: 2500, Quantity: 1},
+		},
+	}
+}
+
+func TestOrderModel_ApplyPromoCodes(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	order := discountableOrder()
+	vip := order.Items[0].TicketTypeID
+
+	percent := func(p int, stackable bool) PromoCode {
+		code := validPromoCode()
+		code.PercentOff, code.Stackable = p, stackable
+		return code
+	}
+	fixed := func(amount int64, stackable bool) PromoCode {
+		code := validPromoCode()
+		code.DiscountType, code.PercentOff, code.AmountOff, code.Currency, code.Stackable = DiscountFixed, 0, amount, "EUR", stackable
+		return code
+	}
+	scoped := func(code PromoCode, ticketTypeID uuid.UUID) PromoCode {
+		code.TicketTypeIDs = []uuid.UUID{ticketTypeID}
+		return code
+	}
+
+	tests := []struct {
+		name     string
+		codes    []PromoCode
+		discount int64
+		lines    []int64
+		expected error
+	}{
+		{"No code", nil, 0, []int64{0, 0}, nil},
+		{"Percentage", []PromoCode{percent(10, false)}, 1250, []int64{1000, 250}, nil},
+		{"Fixed amount", []PromoCode{fixed(1500, false)}, 1500, []int64{1200, 300}, nil},
+		{"Fixed above total", []PromoCode{fixed(20000, false)}, 12500, []int64{10000, 2500}, nil},
+		{"Scoped to ticket type", []PromoCode{scoped(percent(50, false), vip)}, 5000, []int64{5000, 0}, nil},
+		{"Stacked on the rest", []PromoCode{percent(10, true), fixed(1250, true)}, 2500, []int64{2000, 500}, nil},
+		{"Not stackable", []PromoCode{percent(10, false), fixed(500, true)}, 0, nil, ErrPromoCodeNotStackable},
+		{"Wrong event", []PromoCode{func() PromoCode { c := percent(10, false); c.EventIDs = []uuid.UUID{uuid.New()}; return c }()}, 0, nil, ErrPromoCodeNotApplicable},
+		{"Wrong currency", []PromoCode{func() PromoCode { c := fixed(500, false); c.Currency = "USD"; return c }()}, 0, nil, ErrPromoCodeNotApplicable},
+		{"Expired", []PromoCode{func() PromoCode { c := percent(10, false); c.EndsAt = &now; return c }()}, 0, nil, ErrPromoCodeNotValid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := discountableOrder()
+			order.Items[0].TicketTypeID = vip
+			redemptions, err := order.ApplyPromoCodes(tt.codes, now)
+			assert.Equal(t, tt.expected, err)
+			if err != nil {
+				return
+			}
+			assert.NoError(t, order.CalculateTotals())
+			assert.Equal(t, int64(12500), order.Subtotal)
+			assert.Equal(t, tt.discount, order.Discount)
+			assert.Equal(t, order.Subtotal-tt.discount, order.Total)
+			for i, line := range tt.lines {
+				assert.Equal(t, line, order.Items[i].Discount)
+			}
+			assert.Len(t, redemptions, len(tt.codes))
+		})
+	}
+
+	duplicate := percent(10, true)
+	_, err := order.ApplyPromoCodes([]PromoCode{duplicate, duplicate}, now)
+	assert.Equal(t, ErrPromoCodeDuplicate, err)
+}
+
+func TestOrderModel_ApplyPromoCodesRounding(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	order := Order{Currency: "EUR", Items: []OrderItem{
+		{UnitPrice: 5, Quantity: 1},
+		{UnitPrice: 5, Quantity: 1},
+		{UnitPrice: 1, Quantity: 1},
+	}}
+	code := validPromoCode()
+	code.DiscountType, code.PercentOff, code.AmountOff, code.Currency = DiscountFixed, 0, 10, "EUR"
+
+	_, err := order.ApplyPromoCodes([]PromoCode{code}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), order.Discount)
+	for _, item := range order.Items {
+		assert.GreaterOrEqual(t, item.Total, int64(0))
+	}
+}
+
+func TestOrderItem_RefundAmountAfterDiscount(t *testing.T) {
+	item := OrderItem{UnitPrice: 1000, Quantity: 3, Discount: 500}
+
+	first := item.RefundAmount(1)
+	item.RefundedQuantity = 1
+	rest := item.RefundAmount(2)
+	assert.Equal(t, int64(833), first)
+	assert.Equal(t, int64(2500), first+rest, "refunding every ticket returns what was paid")
+}
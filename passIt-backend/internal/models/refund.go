@@ -0,0 +1,98 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefundStatus is the state of a refund at the payment provider
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusSucceeded RefundStatus = "succeeded"
+	RefundStatusFailed    RefundStatus = "failed"
+)
+
+type Refund struct {
+	// Refund records money returned for some or all tickets of an order
+	ID               uuid.UUID    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+	OrderID          uuid.UUID    `gorm:"type:uuid;not null;index" json:"order_id"`
+	PaymentID        uuid.UUID    `gorm:"type:uuid;not null;index" json:"payment_id"`
+	ProviderRefundID string       `json:"provider_refund_id,omitempty"`
+	Amount           int64        `gorm:"not null" json:"amount"` // in minor units
+	Currency         string       `gorm:"type:char(3);not null" json:"currency"`
+	Reason           string       `json:"reason"`
+	Restock          bool         `gorm:"not null;default:true" json:"restock"`
+	Status           RefundStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	CreatedByID      *uuid.UUID   `gorm:"type:uuid" json:"created_by_id,omitempty"`
+	Items            []RefundItem `gorm:"foreignKey:RefundID" json:"items"`
+}
+
+type RefundItem struct {
+	// RefundItem is the number of tickets of one order line covered by a refund
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	RefundID    uuid.UUID `gorm:"type:uuid;not null;index" json:"refund_id"`
+	OrderItemID uuid.UUID `gorm:"type:uuid;not null;index" json:"order_item_id"`
+	Quantity    int       `gorm:"not null" json:"quantity"`
+	Amount      int64     `gorm:"not null" json:"amount"` // in minor units
+}
+
+var (
+	ErrRefundNothingToRefund = errors.New("nothing left to refund")
+	ErrRefundUnknownItem     = errors.New("item does not belong to the order")
+	ErrRefundExceedsTickets  = errors.New("refund exceeds the tickets left on the order line")
+)
+
+// PlanRefund builds the refund lines for the requested quantity per order item.
+// An empty request refunds everything that has not been refunded yet.
+func (o *Order) PlanRefund(quantities map[uuid.UUID]int) ([]RefundItem, int64, error) {
+	lines := make(map[uuid.UUID]bool, len(o.Items))
+	for _, item := range o.Items {
+		lines[item.ID] = true
+	}
+	for id := range quantities {
+		if !lines[id] {
+			return nil, 0, ErrRefundUnknownItem
+		}
+	}
+
+	var (
+		items  []RefundItem
+		amount int64
+	)
+
+	for _, item := range o.Items {
+		quantity := item.RemainingQuantity()
+		if len(quantities) > 0 {
+			requested, ok := quantities[item.ID]
+			if !ok {
+				continue
+			}
+			if requested <= 0 || requested > item.RemainingQuantity() {
+				return nil, 0, ErrRefundExceedsTickets
+			}
+			quantity = requested
+		}
+		if quantity == 0 {
+			continue
+		}
+
+		line := RefundItem{
+			OrderItemID: item.ID,
+			Quantity:    quantity,
+			Amount:      item.RefundAmount(quantity),
+		}
+		items = append(items, line)
+		amount += line.Amount
+	}
+
+	if len(items) == 0 {
+		return nil, 0, ErrRefundNothingToRefund
+	}
+	return items, amount, nil
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventSalesReport sums up the paid orders of an event in one currency
+type EventSalesReport struct {
+	EventID  uuid.UUID `json:"event_id"`
+	Title    string    `json:"title"`
+	Currency string    `json:"currency"`
+	Orders   int64     `json:"orders"`
+	Tickets  int64     `json:"tickets"`  // tickets sold and not refunded
+	Gross    int64     `json:"gross"`    // order totals, in minor units
+	Refunded int64     `json:"refunded"` // in minor units
+}
+
+// BoxOfficeReportLine sums up the payments one staff member collected at the box office
+// with one payment method in one currency
+type BoxOfficeReportLine struct {
+	StaffID    *uuid.UUID    `json:"staff_id,omitempty"` // unset on totals
+	StaffEmail string        `json:"staff_email,omitempty"`
+	Method     PaymentMethod `json:"method"`
+	Currency   string        `json:"currency"`
+	Orders     int64         `json:"orders"`
+	Tickets    int64         `json:"tickets"`
+	Amount     int64         `json:"amount"`   // collected, in minor units
+	Refunded   int64         `json:"refunded"` // refunded since, in minor units
+}
+
+// BoxOfficeReport reconciles the box office payments of a period, usually a day: what
+// every staff member collected and the totals per payment method and currency
+type BoxOfficeReport struct {
+	From   time.Time             `json:"from"`
+	To     time.Time             `json:"to"`
+	Lines  []BoxOfficeReportLine `json:"lines"`
+	Totals []BoxOfficeReportLine `json:"totals"`
+}
+
+// NewBoxOfficeReport adds up the lines of every staff member into totals per payment
+// method and currency
+func NewBoxOfficeReport(from, to time.Time, lines []BoxOfficeReportLine) BoxOfficeReport {
+	report := BoxOfficeReport{
+		From:   from,
+		To:     to,
+		Lines:  lines,
+		Totals: []BoxOfficeReportLine{},
+	}
+	if report.Lines == nil {
+		report.Lines = []BoxOfficeReportLine{}
+	}
+
+	for _, line := range lines {
+		i := slices.IndexFunc(report.Totals, func(t BoxOfficeReportLine) bool {
+			return t.Method == line.Method && t.Currency == line.Currency
+		})
+		if i < 0 {
+			report.Totals = append(report.Totals, BoxOfficeReportLine{Method: line.Method, Currency: line.Currency})
+			i = len(report.Totals) - 1
+		}
+		total := &report.Totals[i]
+		total.Orders += line.Orders
+		total.Tickets += line.Tickets
+		total.Amount += line.Amount
+		total.Refunded += line.Refunded
+	}
+	slices.SortFunc(report.Totals, func(a, b BoxOfficeReportLine) int {
+		return cmp.Or(cmp.Compare(a.Method, b.Method), cmp.Compare(a.Currency, b.Currency))
+	})
+	return report
+}
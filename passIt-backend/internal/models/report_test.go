@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBoxOfficeReport(t *testing.T) {
+	from := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	alice, bob := uuid.New(), uuid.New()
+
+	report := NewBoxOfficeReport(from, to, []BoxOfficeReportLine{
+		{StaffID: &alice, Method: PaymentMethodCash, Currency: "EUR", Orders: 3, Tickets: 5, Amount: 12500, Refunded: 2500},
+		{StaffID: &alice, Method: PaymentMethodComp, Currency: "EUR", Orders: 1, Tickets: 2},
+		{StaffID: &bob, Method: PaymentMethodCash, Currency: "EUR", Orders: 1, Tickets: 1, Amount: 2500},
+		{StaffID: &bob, Method: PaymentMethodCash, Currency: "USD", Orders: 1, Tickets: 2, Amount: 6000},
+		{StaffID: &bob, Method: PaymentMethodCardTerminal, Currency: "EUR", Orders: 2, Tickets: 2, Amount: 5000},
+	})
+
+	assert.Len(t, report.Lines, 5)
+	assert.Equal(t, []BoxOfficeReportLine{
+		{Method: PaymentMethodCardTerminal, Currency: "EUR", Orders: 2, Tickets: 2, Amount: 5000},
+		{Method: PaymentMethodCash, Currency: "EUR", Orders: 4, Tickets: 6, Amount: 15000, Refunded: 2500},
+		{Method: PaymentMethodCash, Currency: "USD", Orders: 1, Tickets: 2, Amount: 6000},
+		{Method: PaymentMethodComp, Currency: "EUR", Orders: 1, Tickets: 2},
+	}, report.Totals)
+}
+
+func TestNewBoxOfficeReport_Empty(t *testing.T) {
+	report := NewBoxOfficeReport(time.Time{}, time.Time{}, nil)
+	assert.NotNil(t, report.Lines, "empty lists are rendered as [] rather than null")
+	assert.NotNil(t, report.Totals)
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResaleListingStatus is the state of a ticket offered on the resale marketplace
+type ResaleListingStatus string
+
+const (
+	ResaleListingActive    ResaleListingStatus = "active"
+	ResaleListingReserved  ResaleListingStatus = "reserved" // a buyer is paying for it
+	ResaleListingSold      ResaleListingStatus = "sold"
+	ResaleListingCancelled ResaleListingStatus = "cancelled"
+)
+
+// PayoutStatus is the state of the money owed to a reseller
+type PayoutStatus string
+
+const (
+	PayoutStatusPending PayoutStatus = "pending"
+	PayoutStatusPaid    PayoutStatus = "paid"
+	PayoutStatusFailed  PayoutStatus = "failed"
+)
+
+type ResaleListing struct {
+	// ResaleListing offers one ticket to other fans at a capped price
+	ID            uuid.UUID           `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+	TicketID      uuid.UUID           `gorm:"type:uuid;not null;index;uniqueIndex:idx_open_resale_listing,where:status IN ('active'\\,'reserved')" json:"ticket_id"`
+	TicketVersion int                 `gorm:"not null" json:"-"` // version of the ticket while it is listed
+	EventID       uuid.UUID           `gorm:"type:uuid;not null;index" json:"event_id"`
+	TicketTypeID  uuid.UUID           `gorm:"type:uuid;not null" json:"ticket_type_id"`
+	EventSeatID   *uuid.UUID          `gorm:"type:uuid" json:"event_seat_id,omitempty"`
+	SellerID      uuid.UUID           `gorm:"type:uuid;not null;index" json:"seller_id"`
+	Currency      string              `gorm:"type:char(3);not null" json:"currency"`
+	FaceValue     int64               `gorm:"not null" json:"face_value"` // in minor units
+	Price         int64               `gorm:"not null" json:"price"`      // in minor units
+	Fee           int64               `gorm:"not null" json:"fee"`        // kept by the platform, in minor units
+	Payout        int64               `gorm:"not null" json:"payout"`     // paid to the seller, in minor units
+	Status        ResaleListingStatus `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
+	BuyerID       *uuid.UUID          `gorm:"type:uuid;index" json:"buyer_id,omitempty"`
+	OrderID       *uuid.UUID          `gorm:"type:uuid;index" json:"order_id,omitempty"`
+	SoldAt        *time.Time          `json:"sold_at,omitempty"`
+	PayoutStatus  PayoutStatus        `gorm:"type:varchar(20)" json:"payout_status,omitempty"`
+	PayoutID      string              `json:"payout_id,omitempty"`
+	PaidOutAt     *time.Time          `json:"paid_out_at,omitempty"`
+	Event         *Event              `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	TicketType    *TicketType         `gorm:"foreignKey:TicketTypeID" json:"ticket_type,omitempty"`
+	EventSeat     *EventSeat          `gorm:"foreignKey:EventSeatID" json:"event_seat,omitempty"`
+}
+
+var (
+	ErrResaleDisabled          = errors.New("tickets of this event cannot be resold")
+	ErrResalePriceAboveCap     = errors.New("price is above the resale price cap")
+	ErrResaleInvalidPrice      = errors.New("resale price must be positive")
+	ErrResaleTicketNotAllowed  = errors.New("only valid tickets can be listed for resale")
+	ErrResaleListingNotActive  = errors.New("listing is no longer available")
+	ErrResaleOwnListing        = errors.New("you cannot buy your own listing")
+	ErrResaleListingNotOnOrder = errors.New("order does not belong to a resale listing")
+)
+
+// ResalePriceCap returns the highest price a ticket with the given face value may be resold for
+func (e *Event) ResalePriceCap(faceValue int64) int64 {
+	return faceValue * int64(100+e.ResaleMarkupPercent) / 100
+}
+
+// CheckResaleAllowed reports whether tickets of the event can be listed or bought at the given time
+func (e *Event) CheckResaleAllowed(now time.Time) error {
+	if !e.ResaleEnabled || e.Status != EventStatusPublished || !now.Before(e.StartsAt) {
+		return ErrResaleDisabled
+	}
+	return nil
+}
+
+// NewResaleListing prices a ticket for resale. The fee is given in basis points of the
+// price and is deducted from what the seller receives.
+func NewResaleListing(ticket *Ticket, event *Event, faceValue, price, feeBasisPoints int64, currency string) (ResaleListing, error) {
+	if ticket.Status != TicketStatusValid {
+		return ResaleListing{}, ErrResaleTicketNotAllowed
+	}
+	if price <= 0 {
+		return ResaleListing{}, ErrResaleInvalidPrice
+	}
+	if price > event.ResalePriceCap(faceValue) {
+		return ResaleListing{}, ErrResalePriceAboveCap
+	}
+
+	fee := price * feeBasisPoints / 10000
+	return ResaleListing{
+		TicketID:      ticket.ID,
+		TicketVersion: ticket.Version + 1,
+		EventID:       ticket.EventID,
+		TicketTypeID:  ticket.TicketTypeID,
+		EventSeatID:   ticket.EventSeatID,
+		SellerID:      ticket.OwnerID,
+		Currency:      currency,
+		FaceValue:     faceValue,
+		Price:         price,
+		Fee:           fee,
+		Payout:        price - fee,
+		Status:        ResaleListingActive,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventModel_ResalePriceCap(t *testing.T) {
+	tests := []struct {
+		name      string
+		markup    int
+		faceValue int64
+		expected  int64
+	}{
+		{"Face value only", 0, 5000, 5000},
+		{"Plus ten percent", 10, 5000, 5500},
+		{"Rounds down", 10, 999, 1098},
+		{"Free ticket", 50, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := Event{ResaleMarkupPercent: tt.markup}
+			assert.Equal(t, tt.expected, event.ResalePriceCap(tt.faceValue))
+		})
+	}
+}
+
+func TestEventModel_CheckResaleAllowed(t *testing.T) {
+	start := time.Date(2026, 6, 1, 19, 0, 0, 0, time.UTC)
+	event := Event{StartsAt: start, Status: EventStatusPublished, ResaleEnabled: true}
+
+	assert.NoError(t, event.CheckResaleAllowed(start.Add(-time.Hour)))
+	assert.Equal(t, ErrResaleDisabled, event.CheckResaleAllowed(start))
+
+	event.ResaleEnabled = false
+	assert.Equal(t, ErrResaleDisabled, event.CheckResaleAllowed(start.Add(-time.Hour)))
+
+	event.ResaleEnabled = true
+	event.Status = EventStatusCancelled
+	assert.Equal(t, ErrResaleDisabled, event.CheckResaleAllowed(start.Add(-time.Hour)))
+}
+
+func TestNewResaleListing(t *testing.T) {
+	event := Event{ResaleMarkupPercent: 10}
+	ticket := Ticket{ID: uuid.New(), EventID: uuid.New(), OwnerID: uuid.New(), Status: TicketStatusValid, Version: 2}
+
+	listing, err := NewResaleListing(&ticket, &event, 5000, 5500, 1000, "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(550), listing.Fee)
+	assert.Equal(t, int64(4950), listing.Payout)
+	assert.Equal(t, 3, listing.TicketVersion, "listing revokes the seller's QR code")
+	assert.Equal(t, ticket.OwnerID, listing.SellerID)
+	assert.Equal(t, ResaleListingActive, listing.Status)
+
+	tests := []struct {
+		name     string
+		status   TicketStatus
+		price    int64
+		expected error
+	}{
+		{"Above cap", TicketStatusValid, 5501, ErrResalePriceAboveCap},
+		{"Zero price", TicketStatusValid, 0, ErrResaleInvalidPrice},
+		{"Used ticket", TicketStatusUsed, 5000, ErrResaleTicketNotAllowed},
+		{"Listed ticket", TicketStatusListed, 5000, ErrResaleTicketNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := ticket
+			ticket.Status = tt.status
+			_, err := NewResaleListing(&ticket, &event, 5000, tt.price, 1000, "EUR")
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewStatus is the state of the manual review of a suspicious order
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending" // the order cannot be paid until an admin approves it
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusRejected ReviewStatus = "rejected" // the order is cancelled
+)
+
+// RiskReason names a signal that raised the risk score of an order
+type RiskReason string
+
+const (
+	RiskUserVelocity RiskReason = "user_velocity" // many holds from the account in a short time
+	RiskIPVelocity   RiskReason = "ip_velocity"   // many holds from the IP address in a short time
+	RiskSharedIP     RiskReason = "shared_ip"     // several accounts buying from the IP address
+	RiskNewAccount   RiskReason = "new_account"
+	RiskMaxTickets   RiskReason = "max_tickets" // the order takes every ticket the buyer is allowed
+	RiskUnscored     RiskReason = "unscored"    // the activity of the buyer could not be read
+)
+
+const (
+	// RiskReviewScore is the score from which orders are routed to manual review
+	RiskReviewScore = 50
+	// RiskMaxScore is the highest risk score
+	RiskMaxScore = 100
+
+	// VelocityWindow is how far back holds are counted for velocity checks
+	VelocityWindow = 10 * time.Minute
+	// Above these counts within the velocity window activity is considered anomalous
+	VelocityMaxUserHolds  = 10
+	VelocityMaxIPHolds    = 30
+	VelocityMaxIPAccounts = 3
+
+	// NewAccountAge is how long accounts are considered new
+	NewAccountAge = 24 * time.Hour
+)
+
+// riskWeights is how much each signal adds to the risk score
+var riskWeights = map[RiskReason]int{
+	RiskUserVelocity: 40,
+	RiskIPVelocity:   30,
+	RiskSharedIP:     30,
+	RiskNewAccount:   15,
+	RiskMaxTickets:   15,
+}
+
+var (
+	ErrOrderUnderReview    = errors.New("order is awaiting review and cannot be paid yet")
+	ErrOrderNotUnderReview = errors.New("order is not awaiting review")
+)
+
+// RiskSignals are what is known about the buyer of an order when it is placed
+type RiskSignals struct {
+	UserHolds  int64 // holds by the account within the velocity window
+	IPHolds    int64 // holds from the IP address within the velocity window
+	IPAccounts int64 // accounts that held tickets from the IP address within the velocity window
+	AccountAge time.Duration
+	Tickets    int // in the order
+	// MaxTicketsPerUser is the purchase limit of the event, 0 when there is none
+	MaxTicketsPerUser int
+}
+
+// AssessRisk scores the signals from 0 to RiskMaxScore and returns the reasons for the score
+func AssessRisk(signals RiskSignals) (int, []RiskReason) {
+	var reasons []RiskReason
+	if signals.UserHolds > VelocityMaxUserHolds {
+		reasons = append(reasons, RiskUserVelocity)
+	}
+	if signals.IPHolds > VelocityMaxIPHolds {
+		reasons = append(reasons, RiskIPVelocity)
+	}
+	if signals.IPAccounts > VelocityMaxIPAccounts {
+		reasons = append(reasons, RiskSharedIP)
+	}
+	if signals.AccountAge < NewAccountAge {
+		reasons = append(reasons, RiskNewAccount)
+	}
+	if signals.MaxTicketsPerUser > 0 && signals.Tickets >= signals.MaxTicketsPerUser {
+		reasons = append(reasons, RiskMaxTickets)
+	}
+
+	score := 0
+	for _, reason := range reasons {
+		score += riskWeights[reason]
+	}
+	return min(score, RiskMaxScore), reasons
+}
+
+// ApplyRisk records the risk score of a new order and routes it to review when the score
+// reaches RiskReviewScore. It reports whether the order awaits review.
+func (o *Order) ApplyRisk(score int, reasons []RiskReason) bool {
+	o.RiskScore = score
+	o.RiskReasons = reasons
+	if score >= RiskReviewScore {
+		o.ReviewStatus = ReviewStatusPending
+	}
+	return o.AwaitsReview()
+}
+
+// AwaitsReview reports whether the order waits for an admin to review it
+func (o *Order) AwaitsReview() bool {
+	return o.ReviewStatus == ReviewStatusPending
+}
+
+// Review records the decision of an admin on an order awaiting review
+func (o *Order) Review(approve bool, reviewerID uuid.UUID, now time.Time) error {
+	if !o.AwaitsReview() || !o.Status.IsUnpaid() {
+		return ErrOrderNotUnderReview
+	}
+	o.ReviewStatus = ReviewStatusRejected
+	if approve {
+		o.ReviewStatus = ReviewStatusApproved
+	}
+	o.ReviewedByID = &reviewerID
+	o.ReviewedAt = &now
+	return nil
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssessRisk(t *testing.T) {
+	established := 30 * 24 * time.Hour
+	tests := []struct {
+		name    string
+		signals RiskSignals
+		score   int
+		reasons []RiskReason
+	}{
+		{"Regular buyer", RiskSignals{UserHolds: 2, IPHolds: 2, IPAccounts: 1, AccountAge: established, Tickets: 2, MaxTicketsPerUser: 4}, 0, nil},
+		{"New account", RiskSignals{AccountAge: time.Hour, Tickets: 1}, 15, []RiskReason{RiskNewAccount}},
+		{"Buys the maximum", RiskSignals{AccountAge: established, Tickets: 4, MaxTicketsPerUser: 4}, 15, []RiskReason{RiskMaxTickets}},
+		{"Holds in a burst", RiskSignals{UserHolds: VelocityMaxUserHolds + 1, AccountAge: established}, 40, []RiskReason{RiskUserVelocity}},
+		{"Accounts sharing an IP", RiskSignals{IPHolds: VelocityMaxIPHolds + 1, IPAccounts: VelocityMaxIPAccounts + 1, AccountAge: established}, 60, []RiskReason{RiskIPVelocity, RiskSharedIP}},
+		{"Everything at once", RiskSignals{UserHolds: 50, IPHolds: 50, IPAccounts: 10, Tickets: 4, MaxTicketsPerUser: 4}, RiskMaxScore,
+			[]RiskReason{RiskUserVelocity, RiskIPVelocity, RiskSharedIP, RiskNewAccount, RiskMaxTickets}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := AssessRisk(tt.signals)
+			assert.Equal(t, tt.score, score)
+			assert.Equal(t, tt.reasons, reasons)
+		})
+	}
+}
+
+func TestOrderModel_Review(t *testing.T) {
+	order := Order{Status: OrderStatusPending}
+	assert.False(t, order.ApplyRisk(RiskReviewScore-1, []RiskReason{RiskNewAccount}))
+	assert.ErrorIs(t, order.Review(true, uuid.New(), time.Now()), ErrOrderNotUnderReview)
+
+	assert.True(t, order.ApplyRisk(RiskReviewScore, []RiskReason{RiskUserVelocity, RiskMaxTickets}))
+	reviewerID, now := uuid.New(), time.Now()
+	assert.NoError(t, order.Review(true, reviewerID, now))
+	assert.Equal(t, ReviewStatusApproved, order.ReviewStatus)
+	assert.Equal(t, &reviewerID, order.ReviewedByID)
+	assert.False(t, order.AwaitsReview())
+	assert.ErrorIs(t, order.Review(false, reviewerID, now), ErrOrderNotUnderReview, "orders are reviewed once")
+
+	rejected := Order{Status: OrderStatusPending, ReviewStatus: ReviewStatusPending}
+	assert.NoError(t, rejected.Review(false, reviewerID, now))
+	assert.Equal(t, ReviewStatusRejected, rejected.ReviewStatus)
+
+	expired := Order{Status: OrderStatusExpired, ReviewStatus: ReviewStatusPending}
+	assert.ErrorIs(t, expired.Review(true, reviewerID, now), ErrOrderNotUnderReview)
+}
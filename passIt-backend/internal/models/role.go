@@ -0,0 +1,129 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission allows a platform staff member to use a group of admin endpoints
+type Permission string
+
+const (
+	// PermAll grants every permission, including ones added later
+	PermAll Permission = "*"
+
+	PermUsersRead          Permission = "users:read"
+	PermUsersWrite         Permission = "users:write"
+	PermRolesRead          Permission = "roles:read"
+	PermRolesWrite         Permission = "roles:write" // assigning roles, so effectively full access
+	PermEventsRead         Permission = "events:read" // drafts and cancelled events
+	PermEventsWrite        Permission = "events:write"
+	PermEventsPublish      Permission = "events:publish"
+	PermOrdersRead         Permission = "orders:read"
+	PermOrdersRefund       Permission = "orders:refund"
+	PermOrdersSell         Permission = "orders:sell"
+	PermOrdersReview       Permission = "orders:review" // suspicious orders routed to manual review
+	PermPayoutsWrite       Permission = "payouts:write"
+	PermTicketsCheckIn     Permission = "tickets:check_in"
+	PermVenuesRead         Permission = "venues:read"
+	PermVenuesWrite        Permission = "venues:write"
+	PermPromoCodesWrite    Permission = "promo_codes:write"
+	PermPricingWrite       Permission = "pricing:write"
+	PermOrganizationsWrite Permission = "organizations:write"
+	PermReportsRead        Permission = "reports:read"
+)
+
+// AllPermissions lists every permission a role can be made of
+var AllPermissions = []Permission{
+	PermUsersRead, PermUsersWrite, PermRolesRead, PermRolesWrite,
+	PermEventsRead, PermEventsWrite, PermEventsPublish,
+	PermOrdersRead, PermOrdersRefund, PermOrdersSell, PermOrdersReview, PermPayoutsWrite, PermTicketsCheckIn,
+	PermVenuesRead, PermVenuesWrite, PermPromoCodesWrite, PermPricingWrite,
+	PermOrganizationsWrite, PermReportsRead,
+}
+
+// AdminRoleName is the built-in role holding every permission. Users flagged
+// is_admin are the holders of this role.
+const AdminRoleName = "admin"
+
+type Role struct {
+	// Role is a named set of permissions assigned to users
+	ID          uuid.UUID    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	Name        string       `gorm:"not null;uniqueIndex" json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `gorm:"serializer:json;not null" json:"permissions"`
+	BuiltIn     bool         `gorm:"not null;default:false" json:"built_in"` // built-in roles cannot be changed
+}
+
+type UserRole struct {
+	// UserRole assigns a role to a user
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	RoleID    uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"role_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+var (
+	ErrRoleNameRequired      = errors.New("role name is required")
+	ErrRoleInvalidName       = errors.New("role name may only contain lower case letters, digits, dashes and underscores")
+	ErrRoleUnknownPermission = errors.New("role contains an unknown permission")
+	ErrRoleBuiltIn           = errors.New("built-in roles cannot be changed or deleted")
+	ErrLastAdmin             = errors.New("at least one user must keep the admin role")
+)
+
+// IsValid reports whether the permission is known
+func (p Permission) IsValid() bool {
+	return p == PermAll || slices.Contains(AllPermissions, p)
+}
+
+// Validate checks the fields an admin is allowed to edit, and sorts and deduplicates the permissions
+func (r *Role) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return ErrRoleNameRequired
+	}
+	for _, c := range r.Name {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return ErrRoleInvalidName
+		}
+	}
+	for _, p := range r.Permissions {
+		if !p.IsValid() {
+			return ErrRoleUnknownPermission
+		}
+	}
+	slices.Sort(r.Permissions)
+	r.Permissions = slices.Compact(r.Permissions)
+	if r.Permissions == nil {
+		r.Permissions = []Permission{}
+	}
+	return nil
+}
+
+// PermissionSet is what a user may do on the platform, the union of the permissions of their roles
+type PermissionSet []Permission
+
+// PermissionsOf merges the permissions of roles
+func PermissionsOf(roles []Role) PermissionSet {
+	var set PermissionSet
+	for _, role := range roles {
+		set = append(set, role.Permissions...)
+	}
+	slices.Sort(set)
+	return slices.Compact(set)
+}
+
+// Has reports whether the set grants the permission
+func (s PermissionSet) Has(p Permission) bool {
+	return slices.Contains(s, PermAll) || slices.Contains(s, p)
+}
+
+// IsAdmin reports whether the set grants every permission
+func (s PermissionSet) IsAdmin() bool {
+	return slices.Contains(s, PermAll)
+}
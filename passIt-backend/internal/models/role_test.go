@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleModel_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     Role
+		expected error
+	}{
+		{"valid", Role{Name: "support", Permissions: []Permission{PermUsersRead, PermOrdersRead}}, nil},
+		{"wildcard", Role{Name: "super_admin", Permissions: []Permission{PermAll}}, nil},
+		{"no permissions", Role{Name: "viewer"}, nil},
+		{"missing name", Role{Name: " "}, ErrRoleNameRequired},
+		{"invalid name", Role{Name: "Box Office"}, ErrRoleInvalidName},
+		{"unknown permission", Role{Name: "support", Permissions: []Permission{"users:delete"}}, ErrRoleUnknownPermission},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.role.Validate())
+		})
+	}
+}
+
+func TestRoleModel_ValidateNormalizesPermissions(t *testing.T) {
+	role := Role{Name: "support", Permissions: []Permission{PermOrdersRead, PermUsersRead, PermOrdersRead}}
+	assert.NoError(t, role.Validate())
+	assert.Equal(t, []Permission{PermOrdersRead, PermUsersRead}, role.Permissions)
+
+	empty := Role{Name: "viewer"}
+	assert.NoError(t, empty.Validate())
+	assert.NotNil(t, empty.Permissions, "an empty role should store an empty list, not null")
+}
+
+func TestPermissionSet(t *testing.T) {
+	support := Role{Permissions: []Permission{PermUsersRead, PermOrdersRead}}
+	finance := Role{Permissions: []Permission{PermOrdersRead, PermOrdersRefund}}
+
+	set := PermissionsOf([]Role{support, finance})
+	assert.Equal(t, PermissionSet{PermOrdersRead, PermOrdersRefund, PermUsersRead}, set)
+	assert.True(t, set.Has(PermOrdersRefund))
+	assert.False(t, set.Has(PermUsersWrite))
+	assert.False(t, set.IsAdmin())
+
+	admin := PermissionsOf([]Role{{Permissions: []Permission{PermAll}}})
+	assert.True(t, admin.Has(PermUsersWrite))
+	assert.True(t, admin.Has(PermReportsRead))
+	assert.True(t, admin.IsAdmin())
+
+	assert.False(t, PermissionsOf(nil).Has(PermUsersRead))
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TicketStatus is the state of an issued ticket
+type TicketStatus string
+
+const (
+	TicketStatusValid  TicketStatus = "valid"
+	TicketStatusUsed   TicketStatus = "used"
+	TicketStatusVoid   TicketStatus = "void"
+	TicketStatusListed TicketStatus = "listed" // offered on the resale marketplace, cannot be used meanwhile
+)
+
+type Ticket struct {
+	// Ticket is one admission issued for a paid order line
+	ID           uuid.UUID    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	OrderID      uuid.UUID    `gorm:"type:uuid;not null;index" json:"order_id"`
+	OrderItemID  uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_ticket_order_item_number" json:"order_item_id"`
+	Number       int          `gorm:"not null;uniqueIndex:idx_ticket_order_item_number" json:"number"` // position of the ticket within its order line
+	EventID      uuid.UUID    `gorm:"type:uuid;not null;index" json:"event_id"`
+	TicketTypeID uuid.UUID    `gorm:"type:uuid;not null;index" json:"ticket_type_id"`
+	EventSeatID  *uuid.UUID   `gorm:"type:uuid;index" json:"event_seat_id,omitempty"`
+	OwnerID      uuid.UUID    `gorm:"type:uuid;not null;index" json:"owner_id"`
+	Status       TicketStatus `gorm:"type:varchar(20);not null;default:'valid';index" json:"status"`
+	Version      int          `gorm:"not null;default:1" json:"version"` // bumped when the QR code is reissued
+	IssuedAt     time.Time    `gorm:"not null" json:"issued_at"`
+	UsedAt       *time.Time   `json:"used_at,omitempty"`
+	VoidedAt     *time.Time   `json:"voided_at,omitempty"`
+	Event        *Event       `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	TicketType   *TicketType  `gorm:"foreignKey:TicketTypeID" json:"ticket_type,omitempty"`
+	EventSeat    *EventSeat   `gorm:"foreignKey:EventSeatID" json:"event_seat,omitempty"`
+}
+
+var (
+	ErrTicketNotValid = errors.New("ticket is no longer valid")
+)
+
+// NewTickets builds one ticket per admission of the order that has not been refunded
+func (o *Order) NewTickets(issuedAt time.Time) []Ticket {
+	var tickets []Ticket
+	for _, item := range o.Items {
+		for number := 1; number <= item.RemainingQuantity(); number++ {
+			tickets = append(tickets, Ticket{
+				OrderID:      o.ID,
+				OrderItemID:  item.ID,
+				Number:       number,
+				EventID:      o.EventID,
+				TicketTypeID: item.TicketTypeID,
+				EventSeatID:  item.EventSeatID,
+				OwnerID:      o.UserID,
+				Status:       TicketStatusValid,
+				Version:      1,
+				IssuedAt:     issuedAt,
+			})
+		}
+	}
+	return tickets
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderModel_NewTickets(t *testing.T) {
+	seatID := uuid.New()
+	order := Order{
+		ID:      uuid.New(),
+		UserID:  uuid.New(),
+		EventID: uuid.New(),
+		Items: []OrderItem{
+			{ID: uuid.New(), TicketTypeID: uuid.New(), Quantity: 3, RefundedQuantity: 1},
+			{ID: uuid.New(), TicketTypeID: uuid.New(), EventSeatID: &seatID, Quantity: 1},
+			{ID: uuid.New(), TicketTypeID: uuid.New(), Quantity: 1, RefundedQuantity: 1},
+		},
+	}
+	issuedAt := time.Now()
+
+	tickets := order.NewTickets(issuedAt)
+	assert.Len(t, tickets, 3, "refunded admissions get no ticket")
+
+	assert.Equal(t, order.Items[0].ID, tickets[0].OrderItemID)
+	assert.Equal(t, 1, tickets[0].Number)
+	assert.Equal(t, 2, tickets[1].Number)
+	assert.Equal(t, &seatID, tickets[2].EventSeatID)
+	for _, ticket := range tickets {
+		assert.Equal(t, order.UserID, ticket.OwnerID)
+		assert.Equal(t, order.EventID, ticket.EventID)
+		assert.Equal(t, TicketStatusValid, ticket.Status)
+		assert.Equal(t, 1, ticket.Version)
+		assert.Equal(t, issuedAt, ticket.IssuedAt)
+	}
+}
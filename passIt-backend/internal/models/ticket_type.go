@@ -0,0 +1,109 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"passIt/internal/pricing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TicketType struct {
+	// TicketType is a priced admission tier of an event (GA, VIP, early bird, student...)
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	EventID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"event_id"`
+	SectionID   *uuid.UUID     `gorm:"type:uuid" json:"section_id,omitempty"` // restricts the type to one venue section
+	Name        string         `gorm:"not null" json:"name"`
+	Description string         `json:"description"`
+	Price       int64          `gorm:"not null;default:0" json:"price"` // in minor units of Currency
+	Currency    string         `gorm:"type:char(3);not null" json:"currency"`
+	Quantity    int            `gorm:"not null" json:"quantity"`
+	SalesStart  *time.Time     `json:"sales_start,omitempty"`
+	SalesEnd    *time.Time     `json:"sales_end,omitempty"`
+	MinPerOrder int            `gorm:"not null;default:1" json:"min_per_order"`
+	MaxPerOrder int            `gorm:"not null;default:0" json:"max_per_order"` // 0 means no per-order limit
+	Position    int            `json:"position"`
+
+	// PassEventIDs makes the ticket type a multi-day pass: its tickets also admit to these
+	// other occurrences of the series of the event it is sold on
+	PassEventIDs []uuid.UUID `gorm:"serializer:json" json:"pass_event_ids,omitempty"`
+}
+
+var (
+	ErrTicketTypeNameRequired     = errors.New("ticket type name is required")
+	ErrTicketTypeInvalidPrice     = errors.New("ticket type price cannot be negative")
+	ErrTicketTypeInvalidCurrency  = errors.New("ticket type currency must be an ISO 4217 code")
+	ErrTicketTypeInvalidQuantity  = errors.New("ticket type quantity must be positive")
+	ErrTicketTypeInvalidLimits    = errors.New("invalid per-order limits")
+	ErrTicketTypeInvalidWindow    = errors.New("sales window must end after it starts")
+	ErrTicketCapacityExceeded     = errors.New("ticket quantities exceed the event capacity")
+	ErrTicketTypeOrderOutOfBounds = errors.New("quantity is outside the per-order limits")
+)
+
+// Validate checks the fields an organizer is allowed to edit
+func (t *TicketType) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return ErrTicketTypeNameRequired
+	}
+	if t.Price < 0 {
+		return ErrTicketTypeInvalidPrice
+	}
+	if !pricing.ValidCurrency(t.Currency) {
+		return ErrTicketTypeInvalidCurrency
+	}
+	if t.Quantity <= 0 {
+		return ErrTicketTypeInvalidQuantity
+	}
+	if t.MinPerOrder < 1 || (t.MaxPerOrder != 0 && t.MaxPerOrder < t.MinPerOrder) {
+		return ErrTicketTypeInvalidLimits
+	}
+	if t.SalesStart != nil && t.SalesEnd != nil && !t.SalesEnd.After(*t.SalesStart) {
+		return ErrTicketTypeInvalidWindow
+	}
+	return nil
+}
+
+// IsPass reports whether the ticket type admits to several occurrences of a series
+func (t *TicketType) IsPass() bool {
+	return len(t.PassEventIDs) > 0
+}
+
+// Admits reports whether tickets of the type admit to the event
+func (t *TicketType) Admits(eventID uuid.UUID) bool {
+	return t.EventID == eventID || slices.Contains(t.PassEventIDs, eventID)
+}
+
+// IsOnSale reports whether the ticket type can be bought at the given time
+func (t *TicketType) IsOnSale(now time.Time) bool {
+	if t.SalesStart != nil && now.Before(*t.SalesStart) {
+		return false
+	}
+	if t.SalesEnd != nil && !now.Before(*t.SalesEnd) {
+		return false
+	}
+	return true
+}
+
+// IsOnSaleAtBoxOffice reports whether staff can sell the ticket type at the given time,
+// the box office keeps selling at the door after online sales end
+func (t *TicketType) IsOnSaleAtBoxOffice(now time.Time) bool {
+	return t.SalesStart == nil || !now.Before(*t.SalesStart)
+}
+
+// CheckOrderQuantity verifies a requested quantity against the per-order limits
+func (t *TicketType) CheckOrderQuantity(quantity int) error {
+	if quantity < t.MinPerOrder {
+		return ErrTicketTypeOrderOutOfBounds
+	}
+	if t.MaxPerOrder != 0 && quantity > t.MaxPerOrder {
+		return ErrTicketTypeOrderOutOfBounds
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validTicketType() TicketType {
+	return TicketType{
+		Name:        "VIP",
+		Price:       12500,
+		Currency:    "EUR",
+		Quantity:    100,
+		MinPerOrder: 1,
+		MaxPerOrder: 4,
+	}
+}
+
+func TestTicketTypeModel_Validate(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		mutate   func(tt *TicketType)
+		expected error
+	}{
+		{"Valid ticket type", func(tt *TicketType) {}, nil},
+		{"Free ticket", func(tt *TicketType) { tt.Price = 0 }, nil},
+		{"Unlimited per order", func(tt *TicketType) { tt.MaxPerOrder = 0 }, nil},
+		{"Missing name", func(tt *TicketType) { tt.Name = "" }, ErrTicketTypeNameRequired},
+		{"Negative price", func(tt *TicketType) { tt.Price = -1 }, ErrTicketTypeInvalidPrice},
+		{"Lowercase currency", func(tt *TicketType) { tt.Currency = "eur" }, ErrTicketTypeInvalidCurrency},
+		{"Long currency", func(tt *TicketType) { tt.Currency = "EURO" }, ErrTicketTypeInvalidCurrency},
+		{"Zero quantity", func(tt *TicketType) { tt.Quantity = 0 }, ErrTicketTypeInvalidQuantity},
+		{"Zero minimum", func(tt *TicketType) { tt.MinPerOrder = 0 }, ErrTicketTypeInvalidLimits},
+		{"Max below min", func(tt *TicketType) { tt.MinPerOrder = 5 }, ErrTicketTypeInvalidLimits},
+		{"Inverted sales window", func(tt *TicketType) { tt.SalesStart = &start; tt.SalesEnd = &end }, ErrTicketTypeInvalidWindow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticketType := validTicketType()
+			tt.mutate(&ticketType)
+			assert.Equal(t, tt.expected, ticketType.Validate())
+		})
+	}
+}
+
+func TestTicketTypeModel_IsOnSale(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	ticketType := validTicketType()
+
+	assert.True(t, ticketType.IsOnSale(start), "no window means always on sale")
+
+	ticketType.SalesStart = &start
+	ticketType.SalesEnd = &end
+	assert.False(t, ticketType.IsOnSale(start.Add(-time.Second)))
+	assert.True(t, ticketType.IsOnSale(start))
+	assert.True(t, ticketType.IsOnSale(end.Add(-time.Second)))
+	assert.False(t, ticketType.IsOnSale(end), "sales end is exclusive")
+}
+
+func TestTicketTypeModel_IsOnSaleAtBoxOffice(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	ticketType := validTicketType()
+	ticketType.SalesStart = &start
+	ticketType.SalesEnd = &end
+
+	assert.False(t, ticketType.IsOnSaleAtBoxOffice(start.Add(-time.Second)))
+	assert.True(t, ticketType.IsOnSaleAtBoxOffice(start))
+	assert.True(t, ticketType.IsOnSaleAtBoxOffice(end.Add(time.Hour)), "the door keeps selling after online sales end")
+}
+
+func TestTicketTypeModel_CheckOrderQuantity(t *testing.T) {
+	ticketType := validTicketType()
+	ticketType.MinPerOrder = 2
+
+	assert.ErrorIs(t, ticketType.CheckOrderQuantity(1), ErrTicketTypeOrderOutOfBounds)
+	assert.NoError(t, ticketType.CheckOrderQuantity(2))
+	assert.NoError(t, ticketType.CheckOrderQuantity(4))
+	assert.ErrorIs(t, ticketType.CheckOrderQuantity(5), ErrTicketTypeOrderOutOfBounds)
+
+	ticketType.MaxPerOrder = 0
+	assert.NoError(t, ticketType.CheckOrderQuantity(50))
+}
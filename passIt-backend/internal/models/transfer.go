@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransferPolicy is the organizer's rule on passing tickets of an event to other users
+type TransferPolicy string
+
+const (
+	TransferPolicyAllowed   TransferPolicy = "allowed"
+	TransferPolicyForbidden TransferPolicy = "forbidden"
+)
+
+// TransferStatus is the state of a ticket transfer
+type TransferStatus string
+
+const (
+	TransferStatusPending   TransferStatus = "pending"
+	TransferStatusAccepted  TransferStatus = "accepted"
+	TransferStatusDeclined  TransferStatus = "declined"
+	TransferStatusCancelled TransferStatus = "cancelled"
+)
+
+type TicketTransfer struct {
+	// TicketTransfer hands a ticket from its owner to another user. Accepted transfers
+	// of a ticket form its ownership chain.
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	TicketID    uuid.UUID      `gorm:"type:uuid;not null;index;uniqueIndex:idx_pending_ticket_transfer,where:status = 'pending'" json:"ticket_id"`
+	FromUserID  uuid.UUID      `gorm:"type:uuid;not null;index" json:"from_user_id"`
+	ToUserID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"to_user_id"`
+	ToEmail     string         `gorm:"not null" json:"to_email"`
+	Status      TransferStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	FromVersion int            `gorm:"not null" json:"from_version"` // ticket version the sender held
+	ToVersion   int            `json:"to_version,omitempty"`         // ticket version issued to the recipient
+	RespondedAt *time.Time     `json:"responded_at,omitempty"`
+	Ticket      *Ticket        `gorm:"foreignKey:TicketID" json:"ticket,omitempty"`
+}
+
+var (
+	ErrTransfersForbidden       = errors.New("tickets of this event cannot be transferred")
+	ErrTransferDeadlinePassed   = errors.New("the transfer deadline for this event has passed")
+	ErrTransferToSelf           = errors.New("ticket cannot be transferred to its owner")
+	ErrTransferInvalidStatus    = errors.New("transfer is no longer pending")
+	ErrTransferTicketNotAllowed = errors.New("only valid tickets can be transferred")
+)
+
+// TransferDeadline returns the last moment tickets of the event can change owner
+func (e *Event) TransferDeadline() time.Time {
+	return e.StartsAt.Add(-time.Duration(e.TransferCutoffHours) * time.Hour)
+}
+
+// CheckTransferAllowed reports whether the organizer's rules allow transfers at the given time
+func (e *Event) CheckTransferAllowed(now time.Time) error {
+	if e.Status != EventStatusPublished || e.TransferPolicy == TransferPolicyForbidden {
+		return ErrTransfersForbidden
+	}
+	if !now.Before(e.TransferDeadline()) {
+		return ErrTransferDeadlinePassed
+	}
+	return nil
+}
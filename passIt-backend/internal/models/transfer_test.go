@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventModel_CheckTransferAllowed(t *testing.T) {
+	start := time.Date(2026, 6, 1, 19, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		mutate   func(e *Event)
+		now      time.Time
+		expected error
+	}{
+		{"Allowed before start", func(e *Event) {}, start.Add(-time.Minute), nil},
+		{"Default policy", func(e *Event) { e.TransferPolicy = "" }, start.Add(-time.Minute), nil},
+		{"At start", func(e *Event) {}, start, ErrTransferDeadlinePassed},
+		{"Before cutoff", func(e *Event) { e.TransferCutoffHours = 24 }, start.Add(-25 * time.Hour), nil},
+		{"Within cutoff", func(e *Event) { e.TransferCutoffHours = 24 }, start.Add(-23 * time.Hour), ErrTransferDeadlinePassed},
+		{"Forbidden", func(e *Event) { e.TransferPolicy = TransferPolicyForbidden }, start.Add(-time.Hour), ErrTransfersForbidden},
+		{"Cancelled event", func(e *Event) { e.Status = EventStatusCancelled }, start.Add(-time.Hour), ErrTransfersForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := validEvent()
+			event.StartsAt = start
+			event.Status = EventStatusPublished
+			event.TransferPolicy = TransferPolicyAllowed
+			tt.mutate(&event)
+			assert.Equal(t, tt.expected, event.CheckTransferAllowed(tt.now))
+		})
+	}
+}
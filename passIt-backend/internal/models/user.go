@@ -23,6 +23,25 @@ type User struct {
 	Address     string         `gorm:"not null" json:"address"`
 	IsActive    bool           `gorm:"default:true" json:"is_active"`
 	IsAdmin     bool           `gorm:"default:false" json:"is_admin"`
+	// IsGuest marks box office customers without a Keycloak account, the record becomes
+	// their account when they sign in with the same email once it is verified
+	IsGuest bool `gorm:"not null;default:false" json:"is_guest"`
+}
+
+// NewGuestUser builds the record of a customer the box office sells to who has no account
+func NewGuestUser(email, firstName, lastName, phoneNumber string) User {
+	id := uuid.New()
+	return User{
+		ID:          id,
+		KeycloackID: "guest:" + id.String(),
+		Username:    "guest-" + id.String(),
+		Email:       email,
+		FirstName:   firstName,
+		LastName:    lastName,
+		PhoneNumber: phoneNumber,
+		IsActive:    true,
+		IsGuest:     true,
+	}
 }
 
 // CustomTime handles custom date formats
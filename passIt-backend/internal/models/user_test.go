@@ -70,3 +70,15 @@ func TestUserModel_UUIDGeneration(t *testing.T) {
 	assert.NotEqual(t, uuid.Nil, user1.ID, "UUID should not be nil")
 	assert.NotEqual(t, uuid.Nil, user2.ID, "UUID should not be nil")
 }
+
+func TestNewGuestUser(t *testing.T) {
+	guest := NewGuestUser("guest@example.com", "Walk", "In", "+1234567890")
+
+	assert.NotEqual(t, uuid.Nil, guest.ID)
+	assert.True(t, guest.IsGuest)
+	assert.True(t, guest.IsActive)
+	assert.Equal(t, "guest@example.com", guest.Email)
+	// Keycloak ID and username are unique columns, guests get placeholders
+	assert.Equal(t, "guest:"+guest.ID.String(), guest.KeycloackID)
+	assert.Equal(t, "guest-"+guest.ID.String(), guest.Username)
+}
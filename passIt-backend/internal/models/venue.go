@@ -0,0 +1,252 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SectionKind distinguishes assigned seating from general-admission areas
+type SectionKind string
+
+const (
+	SectionKindSeated           SectionKind = "seated"
+	SectionKindGeneralAdmission SectionKind = "general_admission"
+)
+
+type Venue struct {
+	// Venue represents a physical location with a seating layout
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	Name      string         `gorm:"not null" json:"name"`
+	Address   string         `json:"address"`
+	City      string         `json:"city"`
+	Country   string         `json:"country"`
+	TimeZone  string         `json:"time_zone"`
+	Sections  []VenueSection `gorm:"constraint:OnDelete:CASCADE" json:"sections,omitempty"`
+
+	// TaxJurisdiction selects the tax rates charged on tickets of events at the venue
+	TaxJurisdiction string `gorm:"not null;default:''" json:"tax_jurisdiction"`
+}
+
+// VenueSection is either a block of rows with assigned seats or a general-admission area
+type VenueSection struct {
+	ID       uuid.UUID   `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	VenueID  uuid.UUID   `gorm:"type:uuid;not null;index" json:"venue_id"`
+	Name     string      `gorm:"not null" json:"name"`
+	Kind     SectionKind `gorm:"type:varchar(20);not null" json:"kind"`
+	Capacity int         `gorm:"not null;default:0" json:"capacity"` // general-admission areas only
+	Position int         `json:"position"`
+	Rows     []VenueRow  `gorm:"foreignKey:SectionID;constraint:OnDelete:CASCADE" json:"rows,omitempty"`
+}
+
+type VenueRow struct {
+	ID        uuid.UUID   `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	SectionID uuid.UUID   `gorm:"type:uuid;not null;index" json:"section_id"`
+	Label     string      `gorm:"not null" json:"label"`
+	Position  int         `json:"position"`
+	Seats     []VenueSeat `gorm:"foreignKey:RowID;constraint:OnDelete:CASCADE" json:"seats,omitempty"`
+}
+
+type VenueSeat struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	RowID      uuid.UUID `gorm:"type:uuid;not null;index" json:"row_id"`
+	Label      string    `gorm:"not null" json:"label"`
+	Position   int       `json:"position"`
+	Accessible bool      `gorm:"default:false" json:"accessible"`
+}
+
+// EventSeatStatus is the sale state of a single seat for one event
+type EventSeatStatus string
+
+const (
+	EventSeatAvailable EventSeatStatus = "available"
+	EventSeatSold      EventSeatStatus = "sold"
+	EventSeatBlocked   EventSeatStatus = "blocked"
+)
+
+// EventSeat is the per-event inventory record generated from a venue seat.
+// Section, row and seat labels are copied so the seat map stays readable
+// even if the venue layout is later edited.
+type EventSeat struct {
+	ID          uuid.UUID       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	EventID     uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_event_seat" json:"event_id"`
+	VenueSeatID uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_event_seat" json:"venue_seat_id"`
+	SectionID   uuid.UUID       `gorm:"type:uuid;not null;index" json:"section_id"`
+	SectionName string          `json:"section_name"`
+	RowLabel    string          `json:"row_label"`
+	SeatLabel   string          `json:"seat_label"`
+	Accessible  bool            `json:"accessible"`
+	Position    int             `json:"position"`
+	Status      EventSeatStatus `gorm:"type:varchar(20);not null;default:'available'" json:"status"`
+}
+
+var (
+	ErrVenueNameRequired  = errors.New("venue name is required")
+	ErrVenueInvalidLayout = errors.New("invalid venue layout")
+	ErrVenueLayoutEmpty   = errors.New("venue layout has no sections")
+	ErrVenueInvalidZone   = errors.New("venue time zone is not a valid IANA zone")
+)
+
+// Validate checks the venue details and its seating layout
+func (v *Venue) Validate() error {
+	if strings.TrimSpace(v.Name) == "" {
+		return ErrVenueNameRequired
+	}
+	if v.TimeZone != "" {
+		if _, err := time.LoadLocation(v.TimeZone); err != nil {
+			return ErrVenueInvalidZone
+		}
+	}
+	return ValidateLayout(v.Sections)
+}
+
+// ValidateLayout checks that a seating layout is well formed: section names are
+// unique, seated sections contain rows with uniquely labelled seats and
+// general-admission areas declare a positive capacity.
+func ValidateLayout(sections []VenueSection) error {
+	if len(sections) == 0 {
+		return ErrVenueLayoutEmpty
+	}
+
+	sectionNames := make(map[string]bool)
+	for _, section := range sections {
+		name := strings.TrimSpace(section.Name)
+		if name == "" {
+			return fmt.Errorf("%w: section name is required", ErrVenueInvalidLayout)
+		}
+		if sectionNames[name] {
+			return fmt.Errorf("%w: duplicate section %q", ErrVenueInvalidLayout, name)
+		}
+		sectionNames[name] = true
+
+		switch section.Kind {
+		case SectionKindGeneralAdmission:
+			if section.Capacity <= 0 {
+				return fmt.Errorf("%w: general admission section %q needs a capacity", ErrVenueInvalidLayout, name)
+			}
+			if len(section.Rows) > 0 {
+				return fmt.Errorf("%w: general admission section %q cannot have rows", ErrVenueInvalidLayout, name)
+			}
+		case SectionKindSeated:
+			if len(section.Rows) == 0 {
+				return fmt.Errorf("%w: seated section %q has no rows", ErrVenueInvalidLayout, name)
+			}
+			if err := validateRows(name, section.Rows); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: section %q has unknown kind %q", ErrVenueInvalidLayout, name, section.Kind)
+		}
+	}
+	return nil
+}
+
+func validateRows(section string, rows []VenueRow) error {
+	rowLabels := make(map[string]bool)
+	for _, row := range rows {
+		label := strings.TrimSpace(row.Label)
+		if label == "" {
+			return fmt.Errorf("%w: row label is required in section %q", ErrVenueInvalidLayout, section)
+		}
+		if rowLabels[label] {
+			return fmt.Errorf("%w: duplicate row %q in section %q", ErrVenueInvalidLayout, label, section)
+		}
+		rowLabels[label] = true
+
+		if len(row.Seats) == 0 {
+			return fmt.Errorf("%w: row %q in section %q has no seats", ErrVenueInvalidLayout, label, section)
+		}
+		seatLabels := make(map[string]bool)
+		for _, seat := range row.Seats {
+			seatLabel := strings.TrimSpace(seat.Label)
+			if seatLabel == "" {
+				return fmt.Errorf("%w: seat label is required in row %q", ErrVenueInvalidLayout, label)
+			}
+			if seatLabels[seatLabel] {
+				return fmt.Errorf("%w: duplicate seat %q in row %q of section %q", ErrVenueInvalidLayout, seatLabel, label, section)
+			}
+			seatLabels[seatLabel] = true
+		}
+	}
+	return nil
+}
+
+// Capacity returns the total number of admissions the layout supports
+func (v *Venue) Capacity() int {
+	total := 0
+	for _, section := range v.Sections {
+		total += section.SeatCount()
+	}
+	return total
+}
+
+// SeatCount returns the number of admissions in the section
+func (s *VenueSection) SeatCount() int {
+	if s.Kind == SectionKindGeneralAdmission {
+		return s.Capacity
+	}
+	total := 0
+	for _, row := range s.Rows {
+		total += len(row.Seats)
+	}
+	return total
+}
+
+// NormalizeLayout clears identifiers from an imported layout and assigns
+// positions from the order in which sections, rows and seats were given.
+func NormalizeLayout(sections []VenueSection) {
+	for i := range sections {
+		sections[i].ID = uuid.Nil
+		sections[i].VenueID = uuid.Nil
+		sections[i].Position = i
+		if sections[i].Kind == SectionKindSeated {
+			sections[i].Capacity = 0
+		}
+		for j := range sections[i].Rows {
+			row := &sections[i].Rows[j]
+			row.ID = uuid.Nil
+			row.SectionID = uuid.Nil
+			row.Position = j
+			for k := range row.Seats {
+				row.Seats[k].ID = uuid.Nil
+				row.Seats[k].RowID = uuid.Nil
+				row.Seats[k].Position = k
+			}
+		}
+	}
+}
+
+// GenerateEventSeats builds the per-seat inventory for an event from the venue layout
+func (v *Venue) GenerateEventSeats(eventID uuid.UUID) []EventSeat {
+	var seats []EventSeat
+	for _, section := range v.Sections {
+		if section.Kind != SectionKindSeated {
+			continue
+		}
+		for _, row := range section.Rows {
+			for _, seat := range row.Seats {
+				seats = append(seats, EventSeat{
+					EventID:     eventID,
+					VenueSeatID: seat.ID,
+					SectionID:   section.ID,
+					SectionName: section.Name,
+					RowLabel:    row.Label,
+					SeatLabel:   seat.Label,
+					Accessible:  seat.Accessible,
+					Position:    len(seats),
+					Status:      EventSeatAvailable,
+				})
+			}
+		}
+	}
+	return seats
+}
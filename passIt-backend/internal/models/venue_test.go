@@ -0,0 +1,111 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleLayout() []VenueSection {
+	return []VenueSection{
+		{
+			Name: "Orchestra",
+			Kind: SectionKindSeated,
+			Rows: []VenueRow{
+				{Label: "A", Seats: []VenueSeat{{Label: "1"}, {Label: "2", Accessible: true}}},
+				{Label: "B", Seats: []VenueSeat{{Label: "1"}, {Label: "2"}, {Label: "3"}}},
+			},
+		},
+		{
+			Name:     "Standing",
+			Kind:     SectionKindGeneralAdmission,
+			Capacity: 200,
+		},
+	}
+}
+
+func TestVenueModel_Validate(t *testing.T) {
+	venue := Venue{Name: "Arena", TimeZone: "Europe/Athens", Sections: sampleLayout()}
+	assert.NoError(t, venue.Validate())
+
+	venue.Name = ""
+	assert.ErrorIs(t, venue.Validate(), ErrVenueNameRequired)
+
+	venue.Name = "Arena"
+	venue.TimeZone = "Nowhere/Land"
+	assert.ErrorIs(t, venue.Validate(), ErrVenueInvalidZone)
+}
+
+func TestValidateLayout(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(sections []VenueSection) []VenueSection
+		expected error
+	}{
+		{"Valid layout", func(s []VenueSection) []VenueSection { return s }, nil},
+		{"Empty layout", func(s []VenueSection) []VenueSection { return nil }, ErrVenueLayoutEmpty},
+		{"Duplicate section", func(s []VenueSection) []VenueSection { s[1].Name = "Orchestra"; return s }, ErrVenueInvalidLayout},
+		{"Unknown kind", func(s []VenueSection) []VenueSection { s[0].Kind = "box"; return s }, ErrVenueInvalidLayout},
+		{"GA without capacity", func(s []VenueSection) []VenueSection { s[1].Capacity = 0; return s }, ErrVenueInvalidLayout},
+		{"GA with rows", func(s []VenueSection) []VenueSection { s[1].Rows = s[0].Rows; return s }, ErrVenueInvalidLayout},
+		{"Seated without rows", func(s []VenueSection) []VenueSection { s[0].Rows = nil; return s }, ErrVenueInvalidLayout},
+		{"Duplicate row", func(s []VenueSection) []VenueSection { s[0].Rows[1].Label = "A"; return s }, ErrVenueInvalidLayout},
+		{"Row without seats", func(s []VenueSection) []VenueSection { s[0].Rows[0].Seats = nil; return s }, ErrVenueInvalidLayout},
+		{"Duplicate seat", func(s []VenueSection) []VenueSection { s[0].Rows[1].Seats[2].Label = "1"; return s }, ErrVenueInvalidLayout},
+		{"Empty seat label", func(s []VenueSection) []VenueSection { s[0].Rows[0].Seats[0].Label = " "; return s }, ErrVenueInvalidLayout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLayout(tt.mutate(sampleLayout()))
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestVenueModel_Capacity(t *testing.T) {
+	venue := Venue{Sections: sampleLayout()}
+	assert.Equal(t, 205, venue.Capacity())
+	assert.Equal(t, 5, venue.Sections[0].SeatCount())
+	assert.Equal(t, 200, venue.Sections[1].SeatCount())
+}
+
+func TestNormalizeLayout(t *testing.T) {
+	sections := sampleLayout()
+	sections[0].ID = uuid.New()
+	sections[0].Capacity = 99
+	sections[0].Rows[1].Seats[2].ID = uuid.New()
+
+	NormalizeLayout(sections)
+
+	assert.Equal(t, uuid.Nil, sections[0].ID)
+	assert.Equal(t, 0, sections[0].Capacity, "seated sections derive capacity from their seats")
+	assert.Equal(t, 200, sections[1].Capacity)
+	assert.Equal(t, 1, sections[1].Position)
+	assert.Equal(t, 1, sections[0].Rows[1].Position)
+	assert.Equal(t, uuid.Nil, sections[0].Rows[1].Seats[2].ID)
+	assert.Equal(t, 2, sections[0].Rows[1].Seats[2].Position)
+}
+
+func TestVenueModel_GenerateEventSeats(t *testing.T) {
+	venue := Venue{Sections: sampleLayout()}
+	eventID := uuid.New()
+
+	seats := venue.GenerateEventSeats(eventID)
+
+	assert.Len(t, seats, 5, "general admission areas do not generate seats")
+	assert.Equal(t, eventID, seats[0].EventID)
+	assert.Equal(t, "Orchestra", seats[0].SectionName)
+	assert.Equal(t, "A", seats[1].RowLabel)
+	assert.Equal(t, "2", seats[1].SeatLabel)
+	assert.True(t, seats[1].Accessible)
+	assert.Equal(t, 4, seats[4].Position)
+	for _, seat := range seats {
+		assert.Equal(t, EventSeatAvailable, seat.Status)
+	}
+}
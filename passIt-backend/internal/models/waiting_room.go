@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultAdmissionMinutes is how long an admitted buyer may hold and check out tickets
+const DefaultAdmissionMinutes = 15
+
+type WaitingRoom struct {
+	// WaitingRoom protects the on-sale of a high-demand event. While it is open buyers
+	// queue in order of arrival and are let into checkout at a fixed rate.
+	EventID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"event_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	OpensAt   time.Time `gorm:"not null" json:"opens_at"`
+	ClosesAt  time.Time `gorm:"not null" json:"closes_at"`
+	// AdmitPerMinute is how many buyers are let into checkout every minute
+	AdmitPerMinute int `gorm:"not null" json:"admit_per_minute"`
+	// AdmissionMinutes is how long an admitted buyer may hold and check out tickets
+	AdmissionMinutes int        `gorm:"not null;default:15" json:"admission_minutes"`
+	CreatedByID      *uuid.UUID `gorm:"type:uuid" json:"created_by_id,omitempty"`
+}
+
+var (
+	ErrWaitingRoomInvalidWindow    = errors.New("waiting room must close after it opens")
+	ErrWaitingRoomInvalidRate      = errors.New("waiting room must admit at least one buyer per minute")
+	ErrWaitingRoomInvalidAdmission = errors.New("admission minutes must be positive")
+)
+
+// Validate checks the settings of the waiting room, a missing admission time gets the default
+func (w *WaitingRoom) Validate() error {
+	if !w.ClosesAt.After(w.OpensAt) {
+		return ErrWaitingRoomInvalidWindow
+	}
+	if w.AdmitPerMinute <= 0 {
+		return ErrWaitingRoomInvalidRate
+	}
+	if w.AdmissionMinutes == 0 {
+		w.AdmissionMinutes = DefaultAdmissionMinutes
+	}
+	if w.AdmissionMinutes < 0 {
+		return ErrWaitingRoomInvalidAdmission
+	}
+	return nil
+}
+
+// IsActive reports whether buyers must queue at the given time
+func (w *WaitingRoom) IsActive(now time.Time) bool {
+	return !now.Before(w.OpensAt) && now.Before(w.ClosesAt)
+}
+
+// Interval is the time between two admissions
+func (w *WaitingRoom) Interval() time.Duration {
+	return time.Minute / time.Duration(w.AdmitPerMinute)
+}
+
+// AdmissionDuration is how long an admission lasts
+func (w *WaitingRoom) AdmissionDuration() time.Duration {
+	return time.Duration(w.AdmissionMinutes) * time.Minute
+}
+
+// EstimatedWait is the expected time until the given number of buyers are admitted
+func (w *WaitingRoom) EstimatedWait(admissions int64) time.Duration {
+	if admissions <= 0 {
+		return 0
+	}
+	return time.Duration(admissions) * w.Interval()
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitingRoomModel_Validate(t *testing.T) {
+	opensAt := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		room     WaitingRoom
+		expected error
+	}{
+		{"Valid", WaitingRoom{OpensAt: opensAt, ClosesAt: opensAt.Add(time.Hour), AdmitPerMinute: 100}, nil},
+		{"Closes before opening", WaitingRoom{OpensAt: opensAt, ClosesAt: opensAt, AdmitPerMinute: 100}, ErrWaitingRoomInvalidWindow},
+		{"No admissions", WaitingRoom{OpensAt: opensAt, ClosesAt: opensAt.Add(time.Hour)}, ErrWaitingRoomInvalidRate},
+		{"Negative admission time", WaitingRoom{OpensAt: opensAt, ClosesAt: opensAt.Add(time.Hour), AdmitPerMinute: 1, AdmissionMinutes: -5}, ErrWaitingRoomInvalidAdmission},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.room.Validate())
+		})
+	}
+}
+
+func TestWaitingRoomModel_Window(t *testing.T) {
+	opensAt := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	room := WaitingRoom{OpensAt: opensAt, ClosesAt: opensAt.Add(time.Hour), AdmitPerMinute: 120}
+	assert.NoError(t, room.Validate())
+	assert.Equal(t, DefaultAdmissionMinutes, room.AdmissionMinutes)
+	assert.Equal(t, 15*time.Minute, room.AdmissionDuration())
+
+	assert.False(t, room.IsActive(opensAt.Add(-time.Second)))
+	assert.True(t, room.IsActive(opensAt))
+	assert.False(t, room.IsActive(room.ClosesAt))
+
+	assert.Equal(t, 500*time.Millisecond, room.Interval())
+	assert.Equal(t, 50*time.Second, room.EstimatedWait(100))
+	assert.Zero(t, room.EstimatedWait(0))
+}
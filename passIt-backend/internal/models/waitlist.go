@@ -0,0 +1,91 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WaitlistStatus is the state of a user's place on a ticket type waitlist
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting  WaitlistStatus = "waiting"
+	WaitlistStatusOffered  WaitlistStatus = "offered"  // tickets are held for the user until the offer expires
+	WaitlistStatusAccepted WaitlistStatus = "accepted" // the user checked out the offer
+	WaitlistStatusExpired  WaitlistStatus = "expired"
+	WaitlistStatusLeft     WaitlistStatus = "left"
+	WaitlistStatusSkipped  WaitlistStatus = "skipped" // the user was passed over, see SkipReason
+)
+
+// WaitlistSkipPurchaseLimit skips a user who reached the event's purchase limit before their turn
+const WaitlistSkipPurchaseLimit = "purchase_limit"
+
+type WaitlistEntry struct {
+	// WaitlistEntry queues a user for a sold-out ticket type, first come first served
+	ID             uuid.UUID      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CreatedAt      time.Time      `gorm:"index" json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	EventID        uuid.UUID      `gorm:"type:uuid;not null;index" json:"event_id"`
+	TicketTypeID   uuid.UUID      `gorm:"type:uuid;not null;index;uniqueIndex:idx_open_waitlist_entry,where:status IN ('waiting'\\,'offered')" json:"ticket_type_id"`
+	UserID         uuid.UUID      `gorm:"type:uuid;not null;index;uniqueIndex:idx_open_waitlist_entry,where:status IN ('waiting'\\,'offered')" json:"user_id"`
+	Quantity       int            `gorm:"not null;default:1" json:"quantity"`
+	Status         WaitlistStatus `gorm:"type:varchar(20);not null;default:'waiting';index" json:"status"`
+	HoldID         string         `json:"hold_id,omitempty"` // hold reserving the offered tickets, checked out like any other hold
+	OfferedAt      *time.Time     `json:"offered_at,omitempty"`
+	OfferExpiresAt *time.Time     `json:"offer_expires_at,omitempty"`
+	SkipReason     string         `gorm:"type:varchar(50)" json:"skip_reason,omitempty"`
+	TicketType     *TicketType    `gorm:"foreignKey:TicketTypeID" json:"ticket_type,omitempty"`
+}
+
+var (
+	ErrWaitlistInvalidStatus = errors.New("invalid waitlist status transition")
+	ErrWaitlistNotSoldOut    = errors.New("tickets are still available, buy them directly")
+)
+
+// CanTransitionTo reports whether a waitlist entry may move to the given status.
+// Waiting entries get an offer, leave or are skipped, offers are accepted, expire or are given up,
+// every other status is final.
+func (s WaitlistStatus) CanTransitionTo(next WaitlistStatus) bool {
+	switch s {
+	case WaitlistStatusWaiting:
+		return next == WaitlistStatusOffered || next == WaitlistStatusLeft || next == WaitlistStatusSkipped
+	case WaitlistStatusOffered:
+		return next == WaitlistStatusAccepted || next == WaitlistStatusExpired || next == WaitlistStatusLeft
+	default:
+		return false
+	}
+}
+
+// IsOpen reports whether the entry still holds a place in the queue
+func (s WaitlistStatus) IsOpen() bool {
+	return s == WaitlistStatusWaiting || s == WaitlistStatusOffered
+}
+
+// Offer moves a waiting entry to offered with the hold reserving its tickets
+func (e *WaitlistEntry) Offer(holdID string, now, expiresAt time.Time) error {
+	if !e.Status.CanTransitionTo(WaitlistStatusOffered) {
+		return ErrWaitlistInvalidStatus
+	}
+	e.Status = WaitlistStatusOffered
+	e.HoldID = holdID
+	e.OfferedAt = &now
+	e.OfferExpiresAt = &expiresAt
+	return nil
+}
+
+// Skip takes a waiting entry out of the queue for good when the user can no longer be offered tickets
+func (e *WaitlistEntry) Skip(reason string) error {
+	if !e.Status.CanTransitionTo(WaitlistStatusSkipped) {
+		return ErrWaitlistInvalidStatus
+	}
+	e.Status = WaitlistStatusSkipped
+	e.SkipReason = reason
+	return nil
+}
+
+// OfferExpired reports whether the user let the offer lapse
+func (e *WaitlistEntry) OfferExpired(now time.Time) bool {
+	return e.Status == WaitlistStatusOffered && e.OfferExpiresAt != nil && !now.Before(*e.OfferExpiresAt)
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitlistStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from     WaitlistStatus
+		to       WaitlistStatus
+		expected bool
+	}{
+		{WaitlistStatusWaiting, WaitlistStatusOffered, true},
+		{WaitlistStatusWaiting, WaitlistStatusLeft, true},
+		{WaitlistStatusWaiting, WaitlistStatusSkipped, true},
+		{WaitlistStatusWaiting, WaitlistStatusAccepted, false},
+		{WaitlistStatusOffered, WaitlistStatusAccepted, true},
+		{WaitlistStatusOffered, WaitlistStatusExpired, true},
+		{WaitlistStatusOffered, WaitlistStatusLeft, true},
+		{WaitlistStatusOffered, WaitlistStatusWaiting, false},
+		{WaitlistStatusAccepted, WaitlistStatusLeft, false},
+		{WaitlistStatusExpired, WaitlistStatusOffered, false},
+		{WaitlistStatusLeft, WaitlistStatusWaiting, false},
+		{WaitlistStatusOffered, WaitlistStatusSkipped, false},
+		{WaitlistStatusSkipped, WaitlistStatusWaiting, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestWaitlistEntry_Offer(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(30 * time.Minute)
+
+	entry := WaitlistEntry{Status: WaitlistStatusWaiting}
+	assert.NoError(t, entry.Offer("hold-1", now, expiresAt))
+	assert.Equal(t, WaitlistStatusOffered, entry.Status)
+	assert.Equal(t, "hold-1", entry.HoldID)
+	assert.False(t, entry.OfferExpired(now))
+	assert.False(t, entry.OfferExpired(expiresAt.Add(-time.Second)))
+	assert.True(t, entry.OfferExpired(expiresAt))
+
+	assert.Equal(t, ErrWaitlistInvalidStatus, entry.Offer("hold-2", now, expiresAt))
+
+	left := WaitlistEntry{Status: WaitlistStatusLeft}
+	assert.Equal(t, ErrWaitlistInvalidStatus, left.Offer("hold-3", now, expiresAt))
+	assert.False(t, left.OfferExpired(expiresAt))
+}
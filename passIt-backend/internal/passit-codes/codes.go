@@ -9,6 +9,284 @@ const (
 	UserLoggedInSuccessfully  = 202
 	JobsRetrievedSuccessfully = 205
 
+	// Event codes
+	EventCreatedSuccessfully   = 1001
+	EventUpdatedSuccessfully   = 1002
+	EventPublishedSuccessfully = 1003
+	EventCancelledSuccessfully = 1004
+
+	// Venue codes
+	VenueCreatedSuccessfully        = 1101
+	VenueUpdatedSuccessfully        = 1102
+	VenueLayoutImportedSuccessfully = 1103
+	VenueAttachedSuccessfully       = 1104
+
+	// Ticket type codes
+	TicketTypeCreatedSuccessfully = 1201
+	TicketTypeUpdatedSuccessfully = 1202
+	TicketTypeDeletedSuccessfully = 1203
+
+	// Hold codes
+	HoldCreatedSuccessfully  = 1301
+	HoldReleasedSuccessfully = 1302
+
+	// Order codes
+	OrderCreatedSuccessfully   = 1401
+	OrderCancelledSuccessfully = 1402
+
+	// Order error codes
+	OrderInvalidRequest    = 1450
+	OrderNotFound          = 1451
+	OrderForbidden         = 1452
+	OrderHoldNotFound      = 1453
+	OrderHoldExpired       = 1454
+	OrderInvalidTransition = 1455
+	OrderInvalidItems      = 1456
+	OrderInternalError     = 1457
+
+	// Payment codes
+	PaymentStartedSuccessfully   = 1501
+	WebhookProcessedSuccessfully = 1502
+
+	// Payment error codes
+	PaymentInvalidRequest   = 1550
+	PaymentInvalidSignature = 1551
+	PaymentNotFound         = 1552
+	PaymentInProgress       = 1553
+	PaymentProviderError    = 1554
+
+	// Refund codes
+	RefundCreatedSuccessfully    = 1601
+	RefundsRetrievedSuccessfully = 1602
+	EventRefundsProcessed        = 1603
+
+	// Refund error codes
+	RefundInvalidRequest     = 1650
+	RefundNothingToRefund    = 1651
+	RefundExceedsTickets     = 1652
+	RefundPaymentNotCaptured = 1653
+	RefundEventNotCancelled  = 1654
+	RefundEventNotFound      = 1655
+
+	// Ticket codes
+	TicketsRetrievedSuccessfully = 1701
+	TicketRetrievedSuccessfully  = 1702
+
+	// Ticket error codes
+	TicketInvalidRequest = 1750
+	TicketNotFound       = 1751
+	TicketForbidden      = 1752
+	TicketNotValid       = 1753
+	TicketInternalError  = 1754
+
+	// Check-in codes
+	CheckInAccepted        = 1801
+	CheckInRejected        = 1802
+	CheckInExportRetrieved = 1803
+	CheckInScansSynced     = 1804
+
+	// Check-in error codes
+	CheckInInvalidRequest = 1850
+	CheckInEventNotFound  = 1851
+	CheckInInternalError  = 1852
+
+	// Transfer codes
+	TransferStartedSuccessfully    = 1901
+	TransfersRetrievedSuccessfully = 1902
+	TransferAcceptedSuccessfully   = 1903
+	TransferDeclinedSuccessfully   = 1904
+	TransferCancelledSuccessfully  = 1905
+	TicketOwnershipRetrieved       = 1906
+
+	// Transfer error codes
+	TransferInvalidRequest    = 1950
+	TransferNotFound          = 1951
+	TransferRecipientNotFound = 1952
+	TransferForbidden         = 1953
+	TransferNotAllowed        = 1954
+	TransferConflict          = 1955
+
+	// Resale codes
+	ResaleListingCreated    = 2001
+	ResaleListingsRetrieved = 2002
+	ResaleListingCancelled  = 2003
+	ResalePayoutSent        = 2004
+
+	// Resale error codes
+	ResaleInvalidRequest  = 2050
+	ResaleListingNotFound = 2051
+	ResaleForbidden       = 2052
+	ResaleNotAllowed      = 2053
+	ResaleConflict        = 2054
+	ResalePayoutFailed    = 2055
+
+	// Waitlist codes
+	WaitlistJoined          = 2101
+	WaitlistLeft            = 2102
+	WaitlistPlaceRetrieved  = 2103
+	WaitlistPlacesRetrieved = 2104
+
+	// Waitlist error codes
+	WaitlistInvalidRequest = 2150
+	WaitlistEntryNotFound  = 2151
+	WaitlistForbidden      = 2152
+	WaitlistConflict       = 2153
+	WaitlistNotSoldOut     = 2154
+	WaitlistInternalError  = 2155
+
+	// Promo code codes
+	PromoCodeCreated    = 2201
+	PromoCodeUpdated    = 2202
+	PromoCodeDeleted    = 2203
+	PromoCodeRetrieved  = 2204
+	PromoCodesRetrieved = 2205
+
+	// Promo code error codes
+	PromoCodeInvalidRequest = 2250
+	PromoCodeNotFound       = 2251
+	PromoCodeConflict       = 2252
+	PromoCodeNotApplicable  = 2253
+	PromoCodeExhausted      = 2254
+	PromoCodeInternalError  = 2255
+
+	// Pricing codes
+	FeeRuleCreated    = 2301
+	FeeRulesRetrieved = 2302
+	FeeRuleDeleted    = 2303
+	TaxRateCreated    = 2304
+	TaxRatesRetrieved = 2305
+	TaxRateDeleted    = 2306
+
+	// Pricing error codes
+	PricingInvalidRequest = 2350
+	FeeRuleNotFound       = 2351
+	TaxRateNotFound       = 2352
+	PricingInternalError  = 2353
+
+	// Invoice error codes
+	InvoiceNotAvailable = 2450
+
+	// Organization codes
+	OrganizationCreated          = 2501
+	OrganizationsRetrieved       = 2502
+	OrganizationMembersRetrieved = 2503
+	OrganizationMemberAdded      = 2504
+	OrganizationMemberUpdated    = 2505
+	OrganizationMemberRemoved    = 2506
+	OrganizationEventsRetrieved  = 2507
+	OrganizationEventCreated     = 2508
+	OrganizationOrdersRetrieved  = 2509
+	OrganizationReportRetrieved  = 2510
+
+	// Organization error codes
+	OrganizationInvalidRequest = 2550
+	OrganizationNotFound       = 2551
+	OrganizationConflict       = 2552
+	OrganizationMemberNotFound = 2553
+	OrganizationLastOwner      = 2554
+	OrganizationInternalError  = 2555
+
+	// Role codes
+	RoleCreated          = 2601
+	RoleUpdated          = 2602
+	RoleDeleted          = 2603
+	RoleRetrieved        = 2604
+	RolesRetrieved       = 2605
+	UserRolesRetrieved   = 2606
+	UserRolesUpdated     = 2607
+	PermissionsRetrieved = 2608
+
+	// Role error codes
+	RoleInvalidRequest = 2650
+	RoleNotFound       = 2651
+	RoleConflict       = 2652
+	RoleBuiltIn        = 2653
+	RoleLastAdmin      = 2654
+	RoleInternalError  = 2655
+
+	// Attendee form codes
+	AttendeeFormsRetrieved  = 2701
+	AttendeeFormSaved       = 2702
+	AttendeeFormDeleted     = 2703
+	AttendeeAnswersExported = 2704
+
+	// Attendee form error codes
+	AttendeeFormInvalidRequest = 2750
+	AttendeeFormNotFound       = 2751
+	AttendeeFormInternalError  = 2752
+	AttendeeAnswersInvalid     = 2753
+
+	// Event series codes
+	SeriesCreated     = 2801
+	SeriesRetrieved   = 2802
+	SeriesUpdated     = 2803
+	SeriesPublished   = 2804
+	SeriesPassCreated = 2805
+
+	// Event series error codes
+	SeriesInvalidRequest = 2850
+	SeriesNotFound       = 2851
+	SeriesConflict       = 2852
+	SeriesInternalError  = 2853
+
+	// Box office codes
+	BoxOfficeOrderCreated    = 2901
+	BoxOfficeReportRetrieved = 2902
+
+	// Box office error codes
+	BoxOfficeInvalidRequest = 2950
+	BoxOfficeNotFound       = 2951
+	BoxOfficeConflict       = 2952
+	BoxOfficeInternalError  = 2953
+
+	// Guest list codes
+	GuestListsRetrieved = 3001
+	GuestListCreated    = 3002
+	GuestListRetrieved  = 3003
+	GuestListUpdated    = 3004
+	GuestListDeleted    = 3005
+	GuestsAdded         = 3006
+	GuestRemoved        = 3007
+	GuestTicketsIssued  = 3008
+	GuestsRetrieved     = 3009
+	GuestCheckedIn      = 3010
+
+	// Guest list error codes
+	GuestListInvalidRequest = 3050
+	GuestListNotFound       = 3051
+	GuestListConflict       = 3052
+	GuestListQuotaExceeded  = 3053
+	GuestAlreadyCheckedIn   = 3054
+	GuestListInternalError  = 3055
+
+	// Waiting room codes
+	WaitingRoomSaved     = 3101
+	WaitingRoomRetrieved = 3102
+	WaitingRoomDeleted   = 3103
+	QueueJoined          = 3104
+	QueueStatusRetrieved = 3105
+
+	// Waiting room error codes
+	WaitingRoomInvalidRequest = 3150
+	WaitingRoomNotFound       = 3151
+	QueueNotOpen              = 3152
+	QueueClosed               = 3153
+	AdmissionRequired         = 3154
+	AdmissionExpired          = 3155
+	WaitingRoomInternalError  = 3156
+
+	// Order review codes
+	OrdersForReviewRetrieved = 3201
+	OrderReviewed            = 3202
+
+	// Order review and purchase limit error codes
+	OrderReviewInvalidRequest = 3250
+	OrderReviewNotFound       = 3251
+	OrderNotUnderReview       = 3252
+	OrderUnderReview          = 3253
+	PurchaseLimitExceeded     = 3254
+	OrderReviewInternalError  = 3255
+
 	// Error codes
 	GetJobBadRequest = 400
 	JobIdNotFound    = 405
@@ -63,3 +63,230 @@ func TestPassItCodes_Values(t *testing.T) {
 		})
 	}
 }
+
+func TestPassItCodes_DomainCodesUnique(t *testing.T) {
+	codes := map[string]int{
+		"EventCreatedSuccessfully":   EventCreatedSuccessfully,
+		"EventUpdatedSuccessfully":   EventUpdatedSuccessfully,
+		"EventPublishedSuccessfully": EventPublishedSuccessfully,
+		"EventCancelledSuccessfully": EventCancelledSuccessfully,
+
+		"VenueCreatedSuccessfully":        VenueCreatedSuccessfully,
+		"VenueUpdatedSuccessfully":        VenueUpdatedSuccessfully,
+		"VenueLayoutImportedSuccessfully": VenueLayoutImportedSuccessfully,
+		"VenueAttachedSuccessfully":       VenueAttachedSuccessfully,
+
+		"TicketTypeCreatedSuccessfully": TicketTypeCreatedSuccessfully,
+		"TicketTypeUpdatedSuccessfully": TicketTypeUpdatedSuccessfully,
+		"TicketTypeDeletedSuccessfully": TicketTypeDeletedSuccessfully,
+
+		"HoldCreatedSuccessfully":  HoldCreatedSuccessfully,
+		"HoldReleasedSuccessfully": HoldReleasedSuccessfully,
+
+		"OrderCreatedSuccessfully":   OrderCreatedSuccessfully,
+		"OrderCancelledSuccessfully": OrderCancelledSuccessfully,
+		"OrderInvalidRequest":        OrderInvalidRequest,
+		"OrderNotFound":              OrderNotFound,
+		"OrderForbidden":             OrderForbidden,
+		"OrderHoldNotFound":          OrderHoldNotFound,
+		"OrderHoldExpired":           OrderHoldExpired,
+		"OrderInvalidTransition":     OrderInvalidTransition,
+		"OrderInvalidItems":          OrderInvalidItems,
+		"OrderInternalError":         OrderInternalError,
+
+		"PaymentStartedSuccessfully":   PaymentStartedSuccessfully,
+		"WebhookProcessedSuccessfully": WebhookProcessedSuccessfully,
+		"PaymentInvalidRequest":        PaymentInvalidRequest,
+		"PaymentInvalidSignature":      PaymentInvalidSignature,
+		"PaymentNotFound":              PaymentNotFound,
+		"PaymentInProgress":            PaymentInProgress,
+		"PaymentProviderError":         PaymentProviderError,
+
+		"RefundCreatedSuccessfully":    RefundCreatedSuccessfully,
+		"RefundsRetrievedSuccessfully": RefundsRetrievedSuccessfully,
+		"EventRefundsProcessed":        EventRefundsProcessed,
+		"RefundInvalidRequest":         RefundInvalidRequest,
+		"RefundNothingToRefund":        RefundNothingToRefund,
+		"RefundExceedsTickets":         RefundExceedsTickets,
+		"RefundPaymentNotCaptured":     RefundPaymentNotCaptured,
+		"RefundEventNotCancelled":      RefundEventNotCancelled,
+		"RefundEventNotFound":          RefundEventNotFound,
+
+		"TicketsRetrievedSuccessfully": TicketsRetrievedSuccessfully,
+		"TicketRetrievedSuccessfully":  TicketRetrievedSuccessfully,
+		"TicketInvalidRequest":         TicketInvalidRequest,
+		"TicketNotFound":               TicketNotFound,
+		"TicketForbidden":              TicketForbidden,
+		"TicketNotValid":               TicketNotValid,
+		"TicketInternalError":          TicketInternalError,
+
+		"CheckInAccepted":        CheckInAccepted,
+		"CheckInRejected":        CheckInRejected,
+		"CheckInExportRetrieved": CheckInExportRetrieved,
+		"CheckInScansSynced":     CheckInScansSynced,
+		"CheckInInvalidRequest":  CheckInInvalidRequest,
+		"CheckInEventNotFound":   CheckInEventNotFound,
+		"CheckInInternalError":   CheckInInternalError,
+
+		"TransferStartedSuccessfully":    TransferStartedSuccessfully,
+		"TransfersRetrievedSuccessfully": TransfersRetrievedSuccessfully,
+		"TransferAcceptedSuccessfully":   TransferAcceptedSuccessfully,
+		"TransferDeclinedSuccessfully":   TransferDeclinedSuccessfully,
+		"TransferCancelledSuccessfully":  TransferCancelledSuccessfully,
+		"TicketOwnershipRetrieved":       TicketOwnershipRetrieved,
+		"TransferInvalidRequest":         TransferInvalidRequest,
+		"TransferNotFound":               TransferNotFound,
+		"TransferRecipientNotFound":      TransferRecipientNotFound,
+		"TransferForbidden":              TransferForbidden,
+		"TransferNotAllowed":             TransferNotAllowed,
+		"TransferConflict":               TransferConflict,
+
+		"ResaleListingCreated":    ResaleListingCreated,
+		"ResaleListingsRetrieved": ResaleListingsRetrieved,
+		"ResaleListingCancelled":  ResaleListingCancelled,
+		"ResalePayoutSent":        ResalePayoutSent,
+		"ResaleInvalidRequest":    ResaleInvalidRequest,
+		"ResaleListingNotFound":   ResaleListingNotFound,
+		"ResaleForbidden":         ResaleForbidden,
+		"ResaleNotAllowed":        ResaleNotAllowed,
+		"ResaleConflict":          ResaleConflict,
+		"ResalePayoutFailed":      ResalePayoutFailed,
+
+		"WaitlistJoined":          WaitlistJoined,
+		"WaitlistLeft":            WaitlistLeft,
+		"WaitlistPlaceRetrieved":  WaitlistPlaceRetrieved,
+		"WaitlistPlacesRetrieved": WaitlistPlacesRetrieved,
+		"WaitlistInvalidRequest":  WaitlistInvalidRequest,
+		"WaitlistEntryNotFound":   WaitlistEntryNotFound,
+		"WaitlistForbidden":       WaitlistForbidden,
+		"WaitlistConflict":        WaitlistConflict,
+		"WaitlistNotSoldOut":      WaitlistNotSoldOut,
+		"WaitlistInternalError":   WaitlistInternalError,
+
+		"PromoCodeCreated":        PromoCodeCreated,
+		"PromoCodeUpdated":        PromoCodeUpdated,
+		"PromoCodeDeleted":        PromoCodeDeleted,
+		"PromoCodeRetrieved":      PromoCodeRetrieved,
+		"PromoCodesRetrieved":     PromoCodesRetrieved,
+		"PromoCodeInvalidRequest": PromoCodeInvalidRequest,
+		"PromoCodeNotFound":       PromoCodeNotFound,
+		"PromoCodeConflict":       PromoCodeConflict,
+		"PromoCodeNotApplicable":  PromoCodeNotApplicable,
+		"PromoCodeExhausted":      PromoCodeExhausted,
+		"PromoCodeInternalError":  PromoCodeInternalError,
+
+		"FeeRuleCreated":        FeeRuleCreated,
+		"FeeRulesRetrieved":     FeeRulesRetrieved,
+		"FeeRuleDeleted":        FeeRuleDeleted,
+		"TaxRateCreated":        TaxRateCreated,
+		"TaxRatesRetrieved":     TaxRatesRetrieved,
+		"TaxRateDeleted":        TaxRateDeleted,
+		"PricingInvalidRequest": PricingInvalidRequest,
+		"FeeRuleNotFound":       FeeRuleNotFound,
+		"TaxRateNotFound":       TaxRateNotFound,
+		"PricingInternalError":  PricingInternalError,
+
+		"InvoiceNotAvailable": InvoiceNotAvailable,
+
+		"OrganizationCreated":          OrganizationCreated,
+		"OrganizationsRetrieved":       OrganizationsRetrieved,
+		"OrganizationMembersRetrieved": OrganizationMembersRetrieved,
+		"OrganizationMemberAdded":      OrganizationMemberAdded,
+		"OrganizationMemberUpdated":    OrganizationMemberUpdated,
+		"OrganizationMemberRemoved":    OrganizationMemberRemoved,
+		"OrganizationEventsRetrieved":  OrganizationEventsRetrieved,
+		"OrganizationEventCreated":     OrganizationEventCreated,
+		"OrganizationOrdersRetrieved":  OrganizationOrdersRetrieved,
+		"OrganizationReportRetrieved":  OrganizationReportRetrieved,
+		"OrganizationInvalidRequest":   OrganizationInvalidRequest,
+		"OrganizationNotFound":         OrganizationNotFound,
+		"OrganizationConflict":         OrganizationConflict,
+		"OrganizationMemberNotFound":   OrganizationMemberNotFound,
+		"OrganizationLastOwner":        OrganizationLastOwner,
+		"OrganizationInternalError":    OrganizationInternalError,
+
+		"RoleCreated":          RoleCreated,
+		"RoleUpdated":          RoleUpdated,
+		"RoleDeleted":          RoleDeleted,
+		"RoleRetrieved":        RoleRetrieved,
+		"RolesRetrieved":       RolesRetrieved,
+		"UserRolesRetrieved":   UserRolesRetrieved,
+		"UserRolesUpdated":     UserRolesUpdated,
+		"PermissionsRetrieved": PermissionsRetrieved,
+		"RoleInvalidRequest":   RoleInvalidRequest,
+		"RoleNotFound":         RoleNotFound,
+		"RoleConflict":         RoleConflict,
+		"RoleBuiltIn":          RoleBuiltIn,
+		"RoleLastAdmin":        RoleLastAdmin,
+		"RoleInternalError":    RoleInternalError,
+
+		"AttendeeFormsRetrieved":     AttendeeFormsRetrieved,
+		"AttendeeFormSaved":          AttendeeFormSaved,
+		"AttendeeFormDeleted":        AttendeeFormDeleted,
+		"AttendeeAnswersExported":    AttendeeAnswersExported,
+		"AttendeeFormInvalidRequest": AttendeeFormInvalidRequest,
+		"AttendeeFormNotFound":       AttendeeFormNotFound,
+		"AttendeeFormInternalError":  AttendeeFormInternalError,
+		"AttendeeAnswersInvalid":     AttendeeAnswersInvalid,
+		"SeriesCreated":              SeriesCreated,
+		"SeriesRetrieved":            SeriesRetrieved,
+		"SeriesUpdated":              SeriesUpdated,
+		"SeriesPublished":            SeriesPublished,
+		"SeriesPassCreated":          SeriesPassCreated,
+		"SeriesInvalidRequest":       SeriesInvalidRequest,
+		"SeriesNotFound":             SeriesNotFound,
+		"SeriesConflict":             SeriesConflict,
+		"SeriesInternalError":        SeriesInternalError,
+		"BoxOfficeOrderCreated":      BoxOfficeOrderCreated,
+		"BoxOfficeReportRetrieved":   BoxOfficeReportRetrieved,
+		"BoxOfficeInvalidRequest":    BoxOfficeInvalidRequest,
+		"BoxOfficeNotFound":          BoxOfficeNotFound,
+		"BoxOfficeConflict":          BoxOfficeConflict,
+		"BoxOfficeInternalError":     BoxOfficeInternalError,
+		"GuestListsRetrieved":        GuestListsRetrieved,
+		"GuestListCreated":           GuestListCreated,
+		"GuestListRetrieved":         GuestListRetrieved,
+		"GuestListUpdated":           GuestListUpdated,
+		"GuestListDeleted":           GuestListDeleted,
+		"GuestsAdded":                GuestsAdded,
+		"GuestRemoved":               GuestRemoved,
+		"GuestTicketsIssued":         GuestTicketsIssued,
+		"GuestsRetrieved":            GuestsRetrieved,
+		"GuestCheckedIn":             GuestCheckedIn,
+		"GuestListInvalidRequest":    GuestListInvalidRequest,
+		"GuestListNotFound":          GuestListNotFound,
+		"GuestListConflict":          GuestListConflict,
+		"GuestListQuotaExceeded":     GuestListQuotaExceeded,
+		"GuestAlreadyCheckedIn":      GuestAlreadyCheckedIn,
+		"GuestListInternalError":     GuestListInternalError,
+		"WaitingRoomSaved":           WaitingRoomSaved,
+		"WaitingRoomRetrieved":       WaitingRoomRetrieved,
+		"WaitingRoomDeleted":         WaitingRoomDeleted,
+		"QueueJoined":                QueueJoined,
+		"QueueStatusRetrieved":       QueueStatusRetrieved,
+		"WaitingRoomInvalidRequest":  WaitingRoomInvalidRequest,
+		"WaitingRoomNotFound":        WaitingRoomNotFound,
+		"QueueNotOpen":               QueueNotOpen,
+		"QueueClosed":                QueueClosed,
+		"AdmissionRequired":          AdmissionRequired,
+		"AdmissionExpired":           AdmissionExpired,
+		"WaitingRoomInternalError":   WaitingRoomInternalError,
+		"OrdersForReviewRetrieved":   OrdersForReviewRetrieved,
+		"OrderReviewed":              OrderReviewed,
+		"OrderReviewInvalidRequest":  OrderReviewInvalidRequest,
+		"OrderReviewNotFound":        OrderReviewNotFound,
+		"OrderNotUnderReview":        OrderNotUnderReview,
+		"OrderUnderReview":           OrderUnderReview,
+		"PurchaseLimitExceeded":      PurchaseLimitExceeded,
+		"OrderReviewInternalError":   OrderReviewInternalError,
+	}
+
+	seenCodes := make(map[int]string)
+	for name, code := range codes {
+		assert.Less(t, code, 10000, "Code should be reasonable range")
+		if existingName, exists := seenCodes[code]; exists {
+			t.Errorf("Duplicate code %d found: %s and %s", code, name, existingName)
+		}
+		seenCodes[code] = name
+	}
+}
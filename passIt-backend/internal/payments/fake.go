@@ -0,0 +1,304 @@
+package payments
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Test payment methods understood by the fake provider. A method may carry a
+// "#suffix", e.g. "pm_card_success#2", standing for another single-use token of the same card.
+const (
+	FakeMethodSuccess  = "pm_card_success"
+	FakeMethodDeclined = "pm_card_declined"
+	FakeMethodDelayed  = "pm_card_delayed"
+)
+
+// defaultFakeDelay is used for delayed confirmations when no delay is configured
+const defaultFakeDelay = 3 * time.Second
+
+// FakeProvider is an in-process payment provider for development and tests.
+// It keeps intents in memory and reports every state change through a signed
+// webhook, just like a real gateway would.
+type FakeProvider struct {
+	mu      sync.Mutex
+	intents map[string]*Intent
+	payouts map[string]*Payout
+	secret  string
+	delay   time.Duration
+
+	// Deliver sends a signed webhook. It defaults to posting to the configured webhook URL.
+	Deliver func(ctx context.Context, payload []byte, signature string) error
+
+	webhookURL string
+	httpClient *http.Client
+}
+
+func NewFakeProvider(config *Config) *FakeProvider {
+	secret := config.WebhookSecret
+	if secret == "" {
+		log.Println("Warning: PAYMENT_WEBHOOK_SECRET not set, signing fake webhooks with a random secret")
+		secret = randomHex(32)
+	}
+	delay := config.FakeDelay
+	if delay <= 0 {
+		delay = defaultFakeDelay
+	}
+
+	provider := &FakeProvider{
+		intents:    make(map[string]*Intent),
+		payouts:    make(map[string]*Payout),
+		secret:     secret,
+		delay:      delay,
+		webhookURL: config.WebhookURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	provider.Deliver = provider.postWebhook
+	return provider
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error) {
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	id := "pi_fake_" + uuid.NewString()
+	intent := &Intent{
+		ID:           id,
+		OrderID:      req.OrderID,
+		Amount:       req.Amount,
+		Currency:     req.Currency,
+		Status:       IntentRequiresConfirmation,
+		ClientSecret: id + "_secret_" + randomHex(8),
+	}
+
+	p.mu.Lock()
+	p.intents[id] = intent
+	p.mu.Unlock()
+
+	copied := *intent
+	return &copied, nil
+}
+
+// ConfirmIntent simulates the customer paying. The outcome depends on the payment method:
+// FakeMethodDeclined fails, FakeMethodDelayed authorizes after the configured delay and
+// anything else authorizes immediately. Failed intents may be confirmed again.
+func (p *FakeProvider) ConfirmIntent(ctx context.Context, intentID string, paymentMethod string) (*Intent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[intentID]
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+	if intent.Status != IntentRequiresConfirmation && intent.Status != IntentFailed {
+		return nil, ErrInvalidIntentState
+	}
+
+	intent.FailureReason = ""
+	switch fakeCard(paymentMethod) {
+	case FakeMethodDeclined:
+		intent.Status = IntentFailed
+		intent.FailureReason = "card_declined"
+		p.emit(intent, EventPaymentFailed)
+	case FakeMethodDelayed:
+		intent.Status = IntentProcessing
+		time.AfterFunc(p.delay, func() { p.authorize(intentID) })
+	default:
+		intent.Status = IntentRequiresCapture
+		p.emit(intent, EventPaymentAuthorized)
+	}
+
+	copied := *intent
+	return &copied, nil
+}
+
+// PaymentMethodFingerprint fingerprints the card of the token, every token of a card
+// has the same fingerprint
+func (p *FakeProvider) PaymentMethodFingerprint(ctx context.Context, paymentMethod string) (string, error) {
+	sum := sha256.Sum256([]byte(fakeCard(paymentMethod)))
+	return "fp_fake_" + hex.EncodeToString(sum[:8]), nil
+}
+
+// fakeCard strips the single-use suffix from a payment method token
+func fakeCard(paymentMethod string) string {
+	card, _, _ := strings.Cut(paymentMethod, "#")
+	return card
+}
+
+// authorize completes a delayed confirmation
+func (p *FakeProvider) authorize(intentID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[intentID]
+	if !ok || intent.Status != IntentProcessing {
+		return
+	}
+	intent.Status = IntentRequiresCapture
+	p.emit(intent, EventPaymentAuthorized)
+}
+
+func (p *FakeProvider) CaptureIntent(ctx context.Context, intentID string) (*Intent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[intentID]
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+	if intent.Status != IntentRequiresCapture {
+		return nil, ErrInvalidIntentState
+	}
+
+	intent.Status = IntentSucceeded
+	p.emit(intent, EventPaymentSucceeded)
+
+	copied := *intent
+	return &copied, nil
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, intentID string, amount int64) (*Refund, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[intentID]
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+	if intent.Status != IntentSucceeded {
+		return nil, ErrInvalidIntentState
+	}
+	if intent.Refunded+amount > intent.Amount {
+		return nil, ErrRefundExceedsAmount
+	}
+
+	intent.Refunded += amount
+	p.emit(intent, EventChargeRefunded)
+
+	return &Refund{
+		ID:       "re_fake_" + uuid.NewString(),
+		IntentID: intent.ID,
+		Amount:   amount,
+	}, nil
+}
+
+// Payout records the payout in memory. Repeating a reference returns the first payout,
+// like the idempotency keys of real gateways.
+func (p *FakeProvider) Payout(ctx context.Context, req PayoutRequest) (*Payout, error) {
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if payout, ok := p.payouts[req.Reference]; ok {
+		return payout, nil
+	}
+	payout := &Payout{
+		ID:          "po_fake_" + uuid.NewString(),
+		Reference:   req.Reference,
+		Destination: req.Destination,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		CreatedAt:   time.Now(),
+	}
+	p.payouts[req.Reference] = payout
+	return payout, nil
+}
+
+func (p *FakeProvider) VerifyWebhookSignature(payload []byte, signature string) (*WebhookEvent, error) {
+	if err := VerifySignature(p.secret, payload, signature, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook event: %w", err)
+	}
+	return &event, nil
+}
+
+// emit signs a webhook for the current state of the intent and delivers it in the background.
+// Must be called with the lock held.
+func (p *FakeProvider) emit(intent *Intent, eventType WebhookEventType) {
+	event := WebhookEvent{
+		ID:            "evt_fake_" + uuid.NewString(),
+		Type:          eventType,
+		IntentID:      intent.ID,
+		OrderID:       intent.OrderID,
+		Amount:        intent.Amount,
+		Currency:      intent.Currency,
+		FailureReason: intent.FailureReason,
+		CreatedAt:     time.Now(),
+	}
+	if eventType == EventChargeRefunded {
+		event.Amount = intent.Refunded
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode fake webhook: %v", err)
+		return
+	}
+	signature := SignPayload(p.secret, payload, event.CreatedAt)
+
+	deliver := p.Deliver
+	go func() {
+		if err := deliver(context.Background(), payload, signature); err != nil {
+			log.Printf("Failed to deliver fake webhook %s: %v", event.ID, err)
+		}
+	}()
+}
+
+func (p *FakeProvider) postWebhook(ctx context.Context, payload []byte, signature string) error {
+	if p.webhookURL == "" {
+		return fmt.Errorf("no webhook URL configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, signature)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint responded with %s", resp.Status)
+	}
+	return nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to read random bytes: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,208 @@
+package payments
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestFakeProvider returns a fake provider that hands its webhooks to a channel
+func newTestFakeProvider(t *testing.T) (*FakeProvider, chan *WebhookEvent) {
+	t.Helper()
+	provider := NewFakeProvider(&Config{WebhookSecret: "test-secret", FakeDelay: 20 * time.Millisecond})
+	events := make(chan *WebhookEvent, 10)
+	provider.Deliver = func(ctx context.Context, payload []byte, signature string) error {
+		event, err := provider.VerifyWebhookSignature(payload, signature)
+		require.NoError(t, err)
+		events <- event
+		return nil
+	}
+	return provider, events
+}
+
+func nextEvent(t *testing.T, events chan *WebhookEvent) *WebhookEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook delivered")
+		return nil
+	}
+}
+
+func TestFakeProvider_Success(t *testing.T) {
+	ctx := context.Background()
+	provider, events := newTestFakeProvider(t)
+
+	intent, err := provider.CreateIntent(ctx, IntentRequest{OrderID: "order-1", Amount: 5000, Currency: "EUR"})
+	require.NoError(t, err)
+	assert.Equal(t, IntentRequiresConfirmation, intent.Status)
+	assert.NotEmpty(t, intent.ClientSecret)
+
+	intent, err = provider.ConfirmIntent(ctx, intent.ID, FakeMethodSuccess)
+	require.NoError(t, err)
+	assert.Equal(t, IntentRequiresCapture, intent.Status)
+
+	event := nextEvent(t, events)
+	assert.Equal(t, EventPaymentAuthorized, event.Type)
+	assert.Equal(t, intent.ID, event.IntentID)
+	assert.Equal(t, "order-1", event.OrderID)
+	assert.Equal(t, int64(5000), event.Amount)
+
+	intent, err = provider.CaptureIntent(ctx, intent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, IntentSucceeded, intent.Status)
+	assert.Equal(t, EventPaymentSucceeded, nextEvent(t, events).Type)
+
+	_, err = provider.CaptureIntent(ctx, intent.ID)
+	assert.ErrorIs(t, err, ErrInvalidIntentState)
+}
+
+func TestFakeProvider_Decline(t *testing.T) {
+	ctx := context.Background()
+	provider, events := newTestFakeProvider(t)
+
+	intent, err := provider.CreateIntent(ctx, IntentRequest{OrderID: "order-1", Amount: 5000, Currency: "EUR"})
+	require.NoError(t, err)
+
+	intent, err = provider.ConfirmIntent(ctx, intent.ID, FakeMethodDeclined)
+	require.NoError(t, err)
+	assert.Equal(t, IntentFailed, intent.Status)
+	assert.Equal(t, "card_declined", intent.FailureReason)
+
+	event := nextEvent(t, events)
+	assert.Equal(t, EventPaymentFailed, event.Type)
+	assert.Equal(t, "card_declined", event.FailureReason)
+
+	_, err = provider.CaptureIntent(ctx, intent.ID)
+	assert.ErrorIs(t, err, ErrInvalidIntentState)
+
+	// The customer may try again with another card
+	intent, err = provider.ConfirmIntent(ctx, intent.ID, FakeMethodSuccess)
+	require.NoError(t, err)
+	assert.Equal(t, IntentRequiresCapture, intent.Status)
+	assert.Equal(t, EventPaymentAuthorized, nextEvent(t, events).Type)
+}
+
+func TestFakeProvider_DelayedConfirmation(t *testing.T) {
+	ctx := context.Background()
+	provider, events := newTestFakeProvider(t)
+
+	intent, err := provider.CreateIntent(ctx, IntentRequest{OrderID: "order-1", Amount: 5000, Currency: "EUR"})
+	require.NoError(t, err)
+
+	intent, err = provider.ConfirmIntent(ctx, intent.ID, FakeMethodDelayed)
+	require.NoError(t, err)
+	assert.Equal(t, IntentProcessing, intent.Status)
+
+	_, err = provider.CaptureIntent(ctx, intent.ID)
+	assert.ErrorIs(t, err, ErrInvalidIntentState, "cannot capture before the confirmation arrives")
+
+	event := nextEvent(t, events)
+	assert.Equal(t, EventPaymentAuthorized, event.Type)
+
+	_, err = provider.CaptureIntent(ctx, intent.ID)
+	assert.NoError(t, err)
+}
+
+func TestFakeProvider_Refund(t *testing.T) {
+	ctx := context.Background()
+	provider, events := newTestFakeProvider(t)
+
+	intent, err := provider.CreateIntent(ctx, IntentRequest{OrderID: "order-1", Amount: 5000, Currency: "EUR"})
+	require.NoError(t, err)
+	_, err = provider.Refund(ctx, intent.ID, 1000)
+	assert.ErrorIs(t, err, ErrInvalidIntentState, "uncaptured intents cannot be refunded")
+
+	_, err = provider.ConfirmIntent(ctx, intent.ID, FakeMethodSuccess)
+	require.NoError(t, err)
+	_, err = provider.CaptureIntent(ctx, intent.ID)
+	require.NoError(t, err)
+	nextEvent(t, events)
+	nextEvent(t, events)
+
+	refund, err := provider.Refund(ctx, intent.ID, 3000)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3000), refund.Amount)
+	assert.Equal(t, EventChargeRefunded, nextEvent(t, events).Type)
+
+	_, err = provider.Refund(ctx, intent.ID, 2001)
+	assert.ErrorIs(t, err, ErrRefundExceedsAmount)
+
+	_, err = provider.Refund(ctx, intent.ID, 2000)
+	assert.NoError(t, err)
+}
+
+func TestFakeProvider_Payout(t *testing.T) {
+	ctx := context.Background()
+	provider, _ := newTestFakeProvider(t)
+
+	payout, err := provider.Payout(ctx, PayoutRequest{Reference: "listing-1", Destination: "seller-1", Amount: 4500, Currency: "EUR"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4500), payout.Amount)
+
+	again, err := provider.Payout(ctx, PayoutRequest{Reference: "listing-1", Destination: "seller-1", Amount: 4500, Currency: "EUR"})
+	require.NoError(t, err)
+	assert.Equal(t, payout.ID, again.ID, "payouts are idempotent per reference")
+
+	_, err = provider.Payout(ctx, PayoutRequest{Reference: "listing-2", Amount: 0, Currency: "EUR"})
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+func TestFakeProvider_PaymentMethodFingerprint(t *testing.T) {
+	ctx := context.Background()
+	provider, events := newTestFakeProvider(t)
+
+	first, err := provider.PaymentMethodFingerprint(ctx, FakeMethodSuccess+"#1")
+	require.NoError(t, err)
+	second, err := provider.PaymentMethodFingerprint(ctx, FakeMethodSuccess+"#2")
+	require.NoError(t, err)
+	assert.Equal(t, first, second, "every token of a card has the same fingerprint")
+
+	other, err := provider.PaymentMethodFingerprint(ctx, FakeMethodDeclined)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, other)
+
+	intent, err := provider.CreateIntent(ctx, IntentRequest{OrderID: "order-1", Amount: 5000, Currency: "EUR"})
+	require.NoError(t, err)
+	intent, err = provider.ConfirmIntent(ctx, intent.ID, FakeMethodDeclined+"#7")
+	require.NoError(t, err)
+	assert.Equal(t, IntentFailed, intent.Status)
+	assert.Equal(t, EventPaymentFailed, nextEvent(t, events).Type)
+}
+
+func TestFakeProvider_InvalidRequests(t *testing.T) {
+	ctx := context.Background()
+	provider, _ := newTestFakeProvider(t)
+
+	_, err := provider.CreateIntent(ctx, IntentRequest{Amount: 0, Currency: "EUR"})
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = provider.ConfirmIntent(ctx, "pi_unknown", FakeMethodSuccess)
+	assert.ErrorIs(t, err, ErrIntentNotFound)
+
+	_, err = provider.VerifyWebhookSignature([]byte(`{}`), "t=1,v1=00")
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestNew_UnknownProvider(t *testing.T) {
+	_, err := New(&Config{Provider: "acme"})
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+
+	provider, err := New(&Config{WebhookSecret: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, "fake", provider.Name())
+
+	_, err = New(&Config{WebhookSecret: "secret", Production: true})
+	assert.ErrorIs(t, err, ErrFakeProviderInProduction)
+	_, err = New(&Config{Provider: "fake", WebhookSecret: "secret", Production: true})
+	assert.ErrorIs(t, err, ErrFakeProviderInProduction)
+
+	provider, err = New(&Config{WebhookSecret: "secret", Production: true, AllowFake: true})
+	require.NoError(t, err, "production may opt in to the fake provider explicitly")
+	assert.Equal(t, "fake", provider.Name())
+}
@@ -0,0 +1,31 @@
+package payments
+
+import (
+	"context"
+)
+
+// PaymentProvider interface for payment operations
+// This allows for swapping payment gateways and for developing and testing offline
+type PaymentProvider interface {
+	// Name identifies the provider on stored payments
+	Name() string
+
+	// Payment intent lifecycle
+	CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error)
+	ConfirmIntent(ctx context.Context, intentID string, paymentMethod string) (*Intent, error)
+	CaptureIntent(ctx context.Context, intentID string) (*Intent, error)
+	Refund(ctx context.Context, intentID string, amount int64) (*Refund, error)
+
+	// PaymentMethodFingerprint identifies the card or account behind a payment method token.
+	// Tokens are issued per use, the fingerprint stays the same for every token of the card.
+	PaymentMethodFingerprint(ctx context.Context, paymentMethod string) (string, error)
+
+	// Payout sends money collected by the platform to a seller
+	Payout(ctx context.Context, req PayoutRequest) (*Payout, error)
+
+	// Webhooks
+	VerifyWebhookSignature(payload []byte, signature string) (*WebhookEvent, error)
+}
+
+// Ensure FakeProvider implements PaymentProvider
+var _ PaymentProvider = (*FakeProvider)(nil)
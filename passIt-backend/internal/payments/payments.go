@@ -0,0 +1,120 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Config struct {
+	Provider      string        // payment provider name, "fake" for the in-process provider
+	WebhookSecret string        // secret used to sign and verify webhooks
+	WebhookURL    string        // where the fake provider delivers its webhooks
+	FakeDelay     time.Duration // how long delayed confirmations take with the fake provider
+	Production    bool          // the fake provider is refused in production, it charges nobody
+	AllowFake     bool          // lets production run on the fake provider until a real one is integrated
+}
+
+// IntentStatus is the state of a payment intent at the provider
+type IntentStatus string
+
+const (
+	IntentRequiresConfirmation IntentStatus = "requires_confirmation"
+	IntentProcessing           IntentStatus = "processing"
+	IntentRequiresCapture      IntentStatus = "requires_capture"
+	IntentSucceeded            IntentStatus = "succeeded"
+	IntentFailed               IntentStatus = "failed"
+)
+
+// WebhookEventType identifies what happened to a payment intent
+type WebhookEventType string
+
+const (
+	// EventPaymentAuthorized is sent once the payment can be captured
+	EventPaymentAuthorized WebhookEventType = "payment_intent.requires_capture"
+	EventPaymentSucceeded  WebhookEventType = "payment_intent.succeeded"
+	EventPaymentFailed     WebhookEventType = "payment_intent.payment_failed"
+	EventChargeRefunded    WebhookEventType = "charge.refunded"
+)
+
+// SignatureHeader is the HTTP header carrying the webhook signature
+const SignatureHeader = "PassIt-Signature"
+
+var (
+	ErrIntentNotFound           = errors.New("payment intent not found")
+	ErrInvalidIntentState       = errors.New("payment intent is not in a valid state for this operation")
+	ErrInvalidAmount            = errors.New("payment amount must be positive")
+	ErrRefundExceedsAmount      = errors.New("refund exceeds the captured amount")
+	ErrInvalidSignature         = errors.New("invalid webhook signature")
+	ErrUnknownProvider          = errors.New("unknown payment provider")
+	ErrFakeProviderInProduction = errors.New("the fake payment provider charges nobody and is refused in production, set PAYMENT_ALLOW_FAKE=true to run on it anyway")
+)
+
+// IntentRequest describes the payment to collect for an order
+type IntentRequest struct {
+	OrderID  string
+	Amount   int64 // in minor units
+	Currency string
+}
+
+// Intent is a payment being collected by the provider
+type Intent struct {
+	ID            string       `json:"id"`
+	OrderID       string       `json:"order_id"`
+	Amount        int64        `json:"amount"`
+	Currency      string       `json:"currency"`
+	Status        IntentStatus `json:"status"`
+	ClientSecret  string       `json:"client_secret,omitempty"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	Refunded      int64        `json:"refunded"`
+}
+
+// Refund is money returned for a captured intent
+type Refund struct {
+	ID       string `json:"id"`
+	IntentID string `json:"intent_id"`
+	Amount   int64  `json:"amount"`
+}
+
+// PayoutRequest describes money to send to a seller
+type PayoutRequest struct {
+	Reference   string // idempotency reference, e.g. the resale listing ID
+	Destination string // the seller's account at the provider
+	Amount      int64  // in minor units
+	Currency    string
+}
+
+// Payout is money sent to a seller
+type Payout struct {
+	ID          string    `json:"id"`
+	Reference   string    `json:"reference"`
+	Destination string    `json:"destination"`
+	Amount      int64     `json:"amount"`
+	Currency    string    `json:"currency"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WebhookEvent is a provider notification about a payment intent
+type WebhookEvent struct {
+	ID            string           `json:"id"`
+	Type          WebhookEventType `json:"type"`
+	IntentID      string           `json:"intent_id"`
+	OrderID       string           `json:"order_id"`
+	Amount        int64            `json:"amount"`
+	Currency      string           `json:"currency"`
+	FailureReason string           `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// New creates the payment provider selected in the configuration
+func New(config *Config) (PaymentProvider, error) {
+	switch config.Provider {
+	case "", "fake":
+		if config.Production && !config.AllowFake {
+			return nil, ErrFakeProviderInProduction
+		}
+		return NewFakeProvider(config), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, config.Provider)
+	}
+}
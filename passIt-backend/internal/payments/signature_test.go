@@ -0,0 +1,39 @@
+package payments
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","type":"payment_intent.succeeded"}`)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	signature := SignPayload("secret", payload, now)
+
+	tests := []struct {
+		name      string
+		secret    string
+		payload   []byte
+		signature string
+		now       time.Time
+		expected  error
+	}{
+		{"Valid signature", "secret", payload, signature, now, nil},
+		{"Slightly delayed delivery", "secret", payload, signature, now.Add(time.Minute), nil},
+		{"Wrong secret", "other", payload, signature, now, ErrInvalidSignature},
+		{"Tampered payload", "secret", []byte(`{"id":"evt_2"}`), signature, now, ErrInvalidSignature},
+		{"Replayed too late", "secret", payload, signature, now.Add(SignatureTolerance + time.Second), ErrInvalidSignature},
+		{"Timestamp in the future", "secret", payload, signature, now.Add(-SignatureTolerance - time.Second), ErrInvalidSignature},
+		{"Missing header", "secret", payload, "", now, ErrInvalidSignature},
+		{"Missing signature", "secret", payload, "t=1772366400", now, ErrInvalidSignature},
+		{"Malformed timestamp", "secret", payload, "t=abc,v1=00", now, ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, VerifySignature(tt.secret, tt.payload, tt.signature, tt.now))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package pricing
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// minorUnitDigits lists the ISO 4217 currencies that do not have two decimals
+var minorUnitDigits = map[string]int{
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// ValidCurrency reports whether the code looks like an ISO 4217 currency code
+func ValidCurrency(currency string) bool {
+	return currencyCode.MatchString(currency)
+}
+
+// MinorUnitDigits returns the number of decimals of the currency, e.g. 2 for EUR and 0 for JPY
+func MinorUnitDigits(currency string) int {
+	if digits, ok := minorUnitDigits[currency]; ok {
+		return digits
+	}
+	return 2
+}
+
+// Format renders an amount in minor units with the decimals of its currency, e.g. "12.50 EUR"
+func Format(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := MinorUnitDigits(currency)
+	if digits == 0 {
+		return fmt.Sprintf("%s%d %s", sign, amount, currency)
+	}
+	scale := int64(1)
+	for range digits {
+		scale *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/scale, digits, amount%scale, currency)
+}
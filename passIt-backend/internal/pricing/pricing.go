@@ -0,0 +1,156 @@
+package pricing
+
+import (
+	"errors"
+)
+
+// All amounts are integers in the minor units of the currency (cents, yen, fils...) so
+// prices add up exactly. Rates are in basis points: 1 bps is 0.01%, 10000 bps is 100%.
+
+// Fee is a service fee charged on every ticket: a fixed amount plus a share of the price
+type Fee struct {
+	Name           string
+	FixedPerTicket int64
+	BasisPoints    int64
+}
+
+// Tax is a rate applied to the ticket price, and to the service fees when OnFees is set
+type Tax struct {
+	Name        string
+	BasisPoints int64
+	OnFees      bool
+}
+
+// Line is one order line to price. Discount is what promo codes took off the whole line.
+type Line struct {
+	Name      string
+	UnitPrice int64
+	Quantity  int
+	Discount  int64
+}
+
+type Input struct {
+	Currency string
+	Lines    []Line
+	Fees     []Fee
+	Taxes    []Tax
+}
+
+// Charge is one fee or tax of the breakdown
+type Charge struct {
+	Name   string `json:"name"`
+	Amount int64  `json:"amount"`
+}
+
+// LineBreakdown is what a line costs. Fees and taxes are rounded per line, so the
+// order amounts are the exact sums of the line amounts.
+type LineBreakdown struct {
+	Name      string `json:"name"`
+	UnitPrice int64  `json:"unit_price"`
+	Quantity  int    `json:"quantity"`
+	Subtotal  int64  `json:"subtotal"`
+	Discount  int64  `json:"discount"`
+	Fees      int64  `json:"fees"`
+	Tax       int64  `json:"tax"`
+	Total     int64  `json:"total"`
+}
+
+// Breakdown itemizes the price of an order
+type Breakdown struct {
+	Currency string          `json:"currency"`
+	Lines    []LineBreakdown `json:"lines"`
+	Subtotal int64           `json:"subtotal"`
+	Discount int64           `json:"discount"`
+	Fees     []Charge        `json:"fees"`
+	FeeTotal int64           `json:"fee_total"`
+	Taxes    []Charge        `json:"taxes"`
+	TaxTotal int64           `json:"tax_total"`
+	Total    int64           `json:"total"`
+}
+
+var (
+	ErrInvalidCurrency = errors.New("currency must be a three-letter ISO 4217 code")
+	ErrInvalidLine     = errors.New("lines must have a positive quantity, a non-negative price and a discount within the price")
+	ErrInvalidFee      = errors.New("fees cannot be negative")
+	ErrInvalidTax      = errors.New("tax rates must be between 0 and 100%")
+)
+
+// Calculate prices the lines with the fees and taxes. Free lines, e.g. fully discounted
+// ones, carry no fees so a free ticket stays free.
+func Calculate(in Input) (Breakdown, error) {
+	if !ValidCurrency(in.Currency) {
+		return Breakdown{}, ErrInvalidCurrency
+	}
+	for _, fee := range in.Fees {
+		if fee.FixedPerTicket < 0 || fee.BasisPoints < 0 {
+			return Breakdown{}, ErrInvalidFee
+		}
+	}
+	for _, tax := range in.Taxes {
+		if tax.BasisPoints < 0 || tax.BasisPoints > 10000 {
+			return Breakdown{}, ErrInvalidTax
+		}
+	}
+
+	breakdown := Breakdown{
+		Currency: in.Currency,
+		Lines:    make([]LineBreakdown, 0, len(in.Lines)),
+		Fees:     make([]Charge, len(in.Fees)),
+		Taxes:    make([]Charge, len(in.Taxes)),
+	}
+	for i, fee := range in.Fees {
+		breakdown.Fees[i].Name = fee.Name
+	}
+	for i, tax := range in.Taxes {
+		breakdown.Taxes[i].Name = tax.Name
+	}
+
+	for _, line := range in.Lines {
+		if line.Quantity <= 0 || line.UnitPrice < 0 {
+			return Breakdown{}, ErrInvalidLine
+		}
+		subtotal := line.UnitPrice * int64(line.Quantity)
+		if line.Discount < 0 || line.Discount > subtotal {
+			return Breakdown{}, ErrInvalidLine
+		}
+		net := subtotal - line.Discount
+
+		priced := LineBreakdown{
+			Name:      line.Name,
+			UnitPrice: line.UnitPrice,
+			Quantity:  line.Quantity,
+			Subtotal:  subtotal,
+			Discount:  line.Discount,
+		}
+		if net > 0 {
+			for i, fee := range in.Fees {
+				amount := fee.FixedPerTicket*int64(line.Quantity) + PercentOf(net, fee.BasisPoints)
+				breakdown.Fees[i].Amount += amount
+				priced.Fees += amount
+			}
+		}
+		for i, tax := range in.Taxes {
+			base := net
+			if tax.OnFees {
+				base += priced.Fees
+			}
+			amount := PercentOf(base, tax.BasisPoints)
+			breakdown.Taxes[i].Amount += amount
+			priced.Tax += amount
+		}
+		priced.Total = net + priced.Fees + priced.Tax
+
+		breakdown.Lines = append(breakdown.Lines, priced)
+		breakdown.Subtotal += priced.Subtotal
+		breakdown.Discount += priced.Discount
+		breakdown.FeeTotal += priced.Fees
+		breakdown.TaxTotal += priced.Tax
+		breakdown.Total += priced.Total
+	}
+	return breakdown, nil
+}
+
+// PercentOf returns basisPoints of a non-negative amount, rounded half up to the minor unit
+func PercentOf(amount, basisPoints int64) int64 {
+	return (amount*basisPoints + 5000) / 10000
+}
@@ -0,0 +1,238 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      int64
+		basisPoints int64
+		expected    int64
+	}{
+		{"zero amount", 0, 1000, 0},
+		{"zero rate", 2500, 0, 0},
+		{"exact", 2500, 1000, 250},
+		{"rounds half up", 5, 1000, 1},         // 0.5
+		{"rounds down below half", 4, 1000, 0}, // 0.4
+		{"fractional rate", 1999, 825, 165},    // 164.9175
+		{"full rate", 1234, 10000, 1234},
+		{"large amount", 9_000_000_000_00, 2000, 1_800_000_000_00},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, PercentOf(tt.amount, tt.basisPoints))
+		})
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Input
+		expected Breakdown
+	}{
+		{
+			name: "no fees or taxes",
+			input: Input{
+				Currency: "EUR",
+				Lines:    []Line{{Name: "GA", UnitPrice: 2500, Quantity: 2}},
+			},
+			expected: Breakdown{
+				Currency: "EUR",
+				Lines:    []LineBreakdown{{Name: "GA", UnitPrice: 2500, Quantity: 2, Subtotal: 5000, Total: 5000}},
+				Subtotal: 5000,
+				Fees:     []Charge{},
+				Taxes:    []Charge{},
+				Total:    5000,
+			},
+		},
+		{
+			name: "fixed and percentage fee",
+			input: Input{
+				Currency: "EUR",
+				Lines:    []Line{{Name: "GA", UnitPrice: 2500, Quantity: 2}},
+				Fees:     []Fee{{Name: "Service fee", FixedPerTicket: 100, BasisPoints: 500}},
+			},
+			expected: Breakdown{
+				Currency: "EUR",
+				Lines:    []LineBreakdown{{Name: "GA", UnitPrice: 2500, Quantity: 2, Subtotal: 5000, Fees: 450, Total: 5450}},
+				Subtotal: 5000,
+				Fees:     []Charge{{Name: "Service fee", Amount: 450}},
+				FeeTotal: 450,
+				Taxes:    []Charge{},
+				Total:    5450,
+			},
+		},
+		{
+			name: "tax on price and fees",
+			input: Input{
+				Currency: "USD",
+				Lines:    []Line{{Name: "GA", UnitPrice: 1999, Quantity: 1}},
+				Fees:     []Fee{{Name: "Service fee", FixedPerTicket: 150}},
+				Taxes: []Tax{
+					{Name: "State tax", BasisPoints: 725, OnFees: true}, // 155.8 of 2149
+					{Name: "City tax", BasisPoints: 100},                // 19.99 of 1999
+				},
+			},
+			expected: Breakdown{
+				Currency: "USD",
+				Lines:    []LineBreakdown{{Name: "GA", UnitPrice: 1999, Quantity: 1, Subtotal: 1999, Fees: 150, Tax: 176, Total: 2325}},
+				Subtotal: 1999,
+				Fees:     []Charge{{Name: "Service fee", Amount: 150}},
+				FeeTotal: 150,
+				Taxes:    []Charge{{Name: "State tax", Amount: 156}, {Name: "City tax", Amount: 20}},
+				TaxTotal: 176,
+				Total:    2325,
+			},
+		},
+		{
+			name: "fees and taxes on the discounted price",
+			input: Input{
+				Currency: "EUR",
+				Lines:    []Line{{Name: "GA", UnitPrice: 2000, Quantity: 2, Discount: 1000}},
+				Fees:     []Fee{{Name: "Service fee", BasisPoints: 1000}},
+				Taxes:    []Tax{{Name: "VAT", BasisPoints: 2000}},
+			},
+			expected: Breakdown{
+				Currency: "EUR",
+				Lines:    []LineBreakdown{{Name: "GA", UnitPrice: 2000, Quantity: 2, Subtotal: 4000, Discount: 1000, Fees: 300, Tax: 600, Total: 3900}},
+				Subtotal: 4000,
+				Discount: 1000,
+				Fees:     []Charge{{Name: "Service fee", Amount: 300}},
+				FeeTotal: 300,
+				Taxes:    []Charge{{Name: "VAT", Amount: 600}},
+				TaxTotal: 600,
+				Total:    3900,
+			},
+		},
+		{
+			name: "free lines carry no fees",
+			input: Input{
+				Currency: "EUR",
+				Lines: []Line{
+					{Name: "Comp", UnitPrice: 0, Quantity: 2},
+					{Name: "Promo", UnitPrice: 1500, Quantity: 1, Discount: 1500},
+				},
+				Fees:  []Fee{{Name: "Service fee", FixedPerTicket: 100, BasisPoints: 500}},
+				Taxes: []Tax{{Name: "VAT", BasisPoints: 2000, OnFees: true}},
+			},
+			expected: Breakdown{
+				Currency: "EUR",
+				Lines: []LineBreakdown{
+					{Name: "Comp", UnitPrice: 0, Quantity: 2},
+					{Name: "Promo", UnitPrice: 1500, Quantity: 1, Subtotal: 1500, Discount: 1500},
+				},
+				Subtotal: 1500,
+				Discount: 1500,
+				Fees:     []Charge{{Name: "Service fee"}},
+				Taxes:    []Charge{{Name: "VAT"}},
+			},
+		},
+		{
+			name: "rounding per line adds up",
+			input: Input{
+				Currency: "EUR",
+				Lines: []Line{
+					{Name: "A", UnitPrice: 333, Quantity: 1},
+					{Name: "B", UnitPrice: 333, Quantity: 1},
+					{Name: "C", UnitPrice: 333, Quantity: 1},
+				},
+				Taxes: []Tax{{Name: "VAT", BasisPoints: 1500}}, // 49.95 per line
+			},
+			expected: Breakdown{
+				Currency: "EUR",
+				Lines: []LineBreakdown{
+					{Name: "A", UnitPrice: 333, Quantity: 1, Subtotal: 333, Tax: 50, Total: 383},
+					{Name: "B", UnitPrice: 333, Quantity: 1, Subtotal: 333, Tax: 50, Total: 383},
+					{Name: "C", UnitPrice: 333, Quantity: 1, Subtotal: 333, Tax: 50, Total: 383},
+				},
+				Subtotal: 999,
+				Fees:     []Charge{},
+				Taxes:    []Charge{{Name: "VAT", Amount: 150}},
+				TaxTotal: 150,
+				Total:    1149,
+			},
+		},
+		{
+			name: "zero decimal currency",
+			input: Input{
+				Currency: "JPY",
+				Lines:    []Line{{Name: "S", UnitPrice: 8800, Quantity: 3}},
+				Fees:     []Fee{{Name: "System fee", FixedPerTicket: 220, BasisPoints: 333}},
+				Taxes:    []Tax{{Name: "Consumption tax", BasisPoints: 1000, OnFees: true}},
+			},
+			expected: Breakdown{
+				Currency: "JPY",
+				Lines:    []LineBreakdown{{Name: "S", UnitPrice: 8800, Quantity: 3, Subtotal: 26400, Fees: 1539, Tax: 2794, Total: 30733}},
+				Subtotal: 26400,
+				Fees:     []Charge{{Name: "System fee", Amount: 1539}}, // 660 + 879.12
+				FeeTotal: 1539,
+				Taxes:    []Charge{{Name: "Consumption tax", Amount: 2794}}, // 2793.9
+				TaxTotal: 2794,
+				Total:    30733,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown, err := Calculate(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, breakdown)
+		})
+	}
+}
+
+func TestCalculate_Errors(t *testing.T) {
+	line := Line{Name: "GA", UnitPrice: 1000, Quantity: 1}
+
+	tests := []struct {
+		name     string
+		input    Input
+		expected error
+	}{
+		{"missing currency", Input{Lines: []Line{line}}, ErrInvalidCurrency},
+		{"lower case currency", Input{Currency: "eur", Lines: []Line{line}}, ErrInvalidCurrency},
+		{"zero quantity", Input{Currency: "EUR", Lines: []Line{{UnitPrice: 1000}}}, ErrInvalidLine},
+		{"negative price", Input{Currency: "EUR", Lines: []Line{{UnitPrice: -1, Quantity: 1}}}, ErrInvalidLine},
+		{"discount above price", Input{Currency: "EUR", Lines: []Line{{UnitPrice: 1000, Quantity: 1, Discount: 1001}}}, ErrInvalidLine},
+		{"negative fee", Input{Currency: "EUR", Lines: []Line{line}, Fees: []Fee{{FixedPerTicket: -1}}}, ErrInvalidFee},
+		{"negative tax", Input{Currency: "EUR", Lines: []Line{line}, Taxes: []Tax{{BasisPoints: -1}}}, ErrInvalidTax},
+		{"tax above 100%", Input{Currency: "EUR", Lines: []Line{line}, Taxes: []Tax{{BasisPoints: 10001}}}, ErrInvalidTax},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Calculate(tt.input)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		expected string
+	}{
+		{1250, "EUR", "12.50 EUR"},
+		{5, "USD", "0.05 USD"},
+		{0, "GBP", "0.00 GBP"},
+		{-1999, "EUR", "-19.99 EUR"},
+		{1500, "JPY", "1500 JPY"},
+		{1250, "KWD", "1.250 KWD"},
+		{7, "BHD", "0.007 BHD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Format(tt.amount, tt.currency))
+		})
+	}
+}
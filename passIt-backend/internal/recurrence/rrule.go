@@ -0,0 +1,345 @@
+// Package recurrence expands RFC 5545 recurrence rules into the start times of the
+// occurrences of an event series.
+//
+// The supported subset covers what organizers schedule: FREQ (DAILY, WEEKLY, MONTHLY,
+// YEARLY), INTERVAL, COUNT, UNTIL, BYDAY (with ordinals for monthly rules), BYMONTHDAY
+// and WKST=MO. Every rule must end through COUNT or UNTIL.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxOccurrences is the most occurrences a rule may produce
+const MaxOccurrences = 500
+
+// Frequency is how often the rule repeats
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry: a weekday, optionally the N-th of the month (negative counts from the end)
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule is a parsed RRULE
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time // inclusive, see untilFloating
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+
+	// untilFloating marks an UNTIL given without time zone, it is read in the zone of the series
+	untilFloating bool
+}
+
+var (
+	ErrInvalidRule        = errors.New("invalid recurrence rule")
+	ErrUnboundedRule      = errors.New("recurrence rule must end with COUNT or UNTIL")
+	ErrTooManyOccurrences = fmt.Errorf("recurrence rule produces more than %d occurrences", MaxOccurrences)
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// Parse reads a rule like "FREQ=WEEKLY;BYDAY=FR,SA;COUNT=10", with or without the RRULE: prefix
+func Parse(value string) (Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	rule := Rule{Interval: 1}
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(part)), "=")
+		if !ok || val == "" {
+			return Rule{}, fmt.Errorf("%w: %q is not NAME=VALUE", ErrInvalidRule, part)
+		}
+		if seen[key] {
+			return Rule{}, fmt.Errorf("%w: %s given twice", ErrInvalidRule, key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(val)
+			if !slices.Contains([]Frequency{Daily, Weekly, Monthly, Yearly}, rule.Freq) {
+				err = fmt.Errorf("unsupported frequency %s", val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = positive(val)
+		case "COUNT":
+			rule.Count, err = positive(val)
+		case "UNTIL":
+			err = rule.parseUntil(val)
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				num, dayErr := parseWeekdayNum(day)
+				if dayErr != nil {
+					err = dayErr
+					break
+				}
+				rule.ByDay = append(rule.ByDay, num)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				n, convErr := strconv.Atoi(day)
+				if convErr != nil || n == 0 || n < -31 || n > 31 {
+					err = fmt.Errorf("invalid month day %s", day)
+					break
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "WKST":
+			if val != "MO" {
+				err = errors.New("only WKST=MO is supported")
+			}
+		default:
+			err = fmt.Errorf("unsupported part %s", key)
+		}
+		if err != nil {
+			return Rule{}, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+	}
+
+	if rule.Freq == "" {
+		return Rule{}, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return Rule{}, fmt.Errorf("%w: COUNT and UNTIL cannot be combined", ErrInvalidRule)
+	}
+	if rule.Count == 0 && rule.Until == nil {
+		return Rule{}, ErrUnboundedRule
+	}
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != Monthly {
+			return Rule{}, fmt.Errorf("%w: numbered BYDAY is only supported for monthly rules", ErrInvalidRule)
+		}
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq == Weekly {
+		return Rule{}, fmt.Errorf("%w: BYMONTHDAY cannot be used with weekly rules", ErrInvalidRule)
+	}
+	return rule, nil
+}
+
+func positive(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive number", value)
+	}
+	return n, nil
+}
+
+func (r *Rule) parseUntil(value string) error {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		until, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		if layout == "20060102" {
+			// A date includes the whole day
+			until = until.Add(24*time.Hour - time.Second)
+		}
+		r.Until = &until
+		r.untilFloating = !strings.HasSuffix(value, "Z")
+		return nil
+	}
+	return fmt.Errorf("invalid UNTIL %s", value)
+}
+
+func parseWeekdayNum(value string) (WeekdayNum, error) {
+	if len(value) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday %s", value)
+	}
+	day, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday %s", value)
+	}
+	num := WeekdayNum{Day: day}
+	if prefix := value[:len(value)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return WeekdayNum{}, fmt.Errorf("invalid weekday %s", value)
+		}
+		num.N = n
+	}
+	return num, nil
+}
+
+// String returns the rule in RRULE syntax, without the RRULE: prefix
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		layout := "20060102T150405Z"
+		if r.untilFloating {
+			layout = "20060102T150405"
+		}
+		parts = append(parts, "UNTIL="+r.Until.Format(layout))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			code := strings.ToUpper(day.Day.String()[:2])
+			if day.N != 0 {
+				code = strconv.Itoa(day.N) + code
+			}
+			days = append(days, code)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, day := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences returns the start times the rule produces on or after start, at the wall
+// clock time of start in its location so a weekly 8pm show stays at 8pm across daylight
+// saving changes
+func (r Rule) Occurrences(start time.Time) ([]time.Time, error) {
+	loc := start.Location()
+	var until time.Time
+	if r.Until != nil {
+		until = *r.Until
+		if r.untilFloating {
+			until = time.Date(until.Year(), until.Month(), until.Day(), until.Hour(), until.Minute(), until.Second(), 0, loc)
+		}
+	}
+
+	var occurrences []time.Time
+	// Rules whose filters never match stop after a bounded number of periods
+	for period := 0; period < MaxOccurrences*31; period++ {
+		for _, candidate := range r.candidates(start, period*r.Interval) {
+			if candidate.Before(start) {
+				continue
+			}
+			if r.Until != nil && candidate.After(until) {
+				return occurrences, nil
+			}
+			if len(occurrences) == MaxOccurrences {
+				return nil, ErrTooManyOccurrences
+			}
+			occurrences = append(occurrences, candidate)
+			if r.Count > 0 && len(occurrences) == r.Count {
+				return occurrences, nil
+			}
+		}
+	}
+	return occurrences, nil
+}
+
+// candidates returns the sorted instants of the period that is offset frequency units after start
+func (r Rule) candidates(start time.Time, offset int) []time.Time {
+	y, m, d := start.Date()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		day := at(y, m, d+offset)
+		if r.matchesDay(day) {
+			days = append(days, day)
+		}
+
+	case Weekly:
+		// Weeks start on Monday
+		monday := d - (int(start.Weekday())+6)%7 + offset*7
+		byDay := r.ByDay
+		if len(byDay) == 0 {
+			byDay = []WeekdayNum{{Day: start.Weekday()}}
+		}
+		for _, weekday := range byDay {
+			days = append(days, at(y, m, monday+(int(weekday.Day)+6)%7))
+		}
+
+	case Monthly:
+		first := time.Date(y, m+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+		length := daysIn(first.Year(), first.Month())
+		for day := 1; day <= length; day++ {
+			candidate := at(first.Year(), first.Month(), day)
+			if r.matchesMonthDay(day, length, d) && r.matchesMonthWeekday(candidate, day, length) {
+				days = append(days, candidate)
+			}
+		}
+
+	case Yearly:
+		if year := y + offset; d <= daysIn(year, m) {
+			days = append(days, at(year, m, d))
+		}
+	}
+
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(days, func(a, b time.Time) bool { return a.Equal(b) })
+}
+
+// matchesDay applies the BYDAY and BYMONTHDAY filters of daily rules
+func (r Rule) matchesDay(day time.Time) bool {
+	if len(r.ByDay) > 0 && !slices.ContainsFunc(r.ByDay, func(w WeekdayNum) bool { return w.Day == day.Weekday() }) {
+		return false
+	}
+	return len(r.ByMonthDay) == 0 || r.matchesMonthDay(day.Day(), daysIn(day.Year(), day.Month()), 0)
+}
+
+// matchesMonthDay reports whether day is one of the BYMONTHDAY days, or the day of the
+// month of the series start when the rule has neither BYMONTHDAY nor BYDAY
+func (r Rule) matchesMonthDay(day, length, startDay int) bool {
+	if len(r.ByMonthDay) == 0 {
+		return len(r.ByDay) > 0 || day == startDay
+	}
+	for _, n := range r.ByMonthDay {
+		if n == day || (n < 0 && length+n+1 == day) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMonthWeekday applies BYDAY to a day of a monthly rule, 2FR being the second Friday
+func (r Rule) matchesMonthWeekday(candidate time.Time, day, length int) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, weekday := range r.ByDay {
+		if weekday.Day != candidate.Weekday() {
+			continue
+		}
+		switch {
+		case weekday.N == 0,
+			weekday.N > 0 && (day-1)/7+1 == weekday.N,
+			weekday.N < 0 && (length-day)/7+1 == -weekday.N:
+			return true
+		}
+	}
+	return false
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,150 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dates(t *testing.T, rule string, start time.Time) []string {
+	t.Helper()
+	parsed, err := Parse(rule)
+	require.NoError(t, err)
+	occurrences, err := parsed.Occurrences(start)
+	require.NoError(t, err)
+
+	formatted := make([]string, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		formatted = append(formatted, occurrence.Format("2006-01-02 Mon 15:04 MST"))
+	}
+	return formatted
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		err  error
+	}{
+		{"Missing frequency", "COUNT=3", ErrInvalidRule},
+		{"Unknown frequency", "FREQ=HOURLY;COUNT=3", ErrInvalidRule},
+		{"Unbounded", "FREQ=WEEKLY", ErrUnboundedRule},
+		{"Count and until", "FREQ=DAILY;COUNT=3;UNTIL=20260101", ErrInvalidRule},
+		{"Zero interval", "FREQ=DAILY;INTERVAL=0;COUNT=3", ErrInvalidRule},
+		{"Unknown weekday", "FREQ=WEEKLY;BYDAY=XX;COUNT=3", ErrInvalidRule},
+		{"Numbered weekday on weekly rule", "FREQ=WEEKLY;BYDAY=1FR;COUNT=3", ErrInvalidRule},
+		{"Month day out of range", "FREQ=MONTHLY;BYMONTHDAY=32;COUNT=3", ErrInvalidRule},
+		{"Unsupported part", "FREQ=DAILY;BYHOUR=10;COUNT=3", ErrInvalidRule},
+		{"Duplicate part", "FREQ=DAILY;COUNT=3;COUNT=4", ErrInvalidRule},
+		{"Not name value", "FREQ=DAILY;COUNT", ErrInvalidRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.rule)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestRule_String(t *testing.T) {
+	for _, rule := range []string{
+		"FREQ=WEEKLY;INTERVAL=2;COUNT=10;BYDAY=FR,SA",
+		"FREQ=MONTHLY;UNTIL=20261231T230000Z;BYDAY=-1SU",
+		"FREQ=DAILY;UNTIL=20260704T235959",
+		"FREQ=MONTHLY;COUNT=3;BYMONTHDAY=1,-1",
+	} {
+		parsed, err := Parse("RRULE:" + rule)
+		require.NoError(t, err)
+		assert.Equal(t, rule, parsed.String())
+	}
+}
+
+func TestRule_Occurrences(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// Friday 20 March 2026, 20:00 in Berlin; clocks move forward on 29 March
+	start := time.Date(2026, 3, 20, 20, 0, 0, 0, berlin)
+
+	t.Run("Weekly keeps the local time across daylight saving", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"2026-03-20 Fri 20:00 CET",
+			"2026-03-27 Fri 20:00 CET",
+			"2026-04-03 Fri 20:00 CEST",
+		}, dates(t, "FREQ=WEEKLY;COUNT=3", start))
+	})
+
+	t.Run("Weekly on several days skips days before the start", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"2026-03-20 Fri 20:00 CET",
+			"2026-03-21 Sat 20:00 CET",
+			"2026-03-23 Mon 20:00 CET",
+			"2026-03-25 Wed 20:00 CET",
+		}, dates(t, "FREQ=WEEKLY;BYDAY=MO,WE,FR,SA;COUNT=4", start))
+	})
+
+	t.Run("Every other week until a date", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"2026-03-20 Fri 20:00 CET",
+			"2026-04-03 Fri 20:00 CEST",
+			"2026-04-17 Fri 20:00 CEST",
+		}, dates(t, "FREQ=WEEKLY;INTERVAL=2;UNTIL=20260417", start))
+	})
+
+	t.Run("Daily festival", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"2026-03-20 Fri 20:00 CET",
+			"2026-03-21 Sat 20:00 CET",
+			"2026-03-22 Sun 20:00 CET",
+		}, dates(t, "FREQ=DAILY;UNTIL=20260322T200000", start))
+	})
+
+	t.Run("Daily on weekends", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"2026-03-21 Sat 20:00 CET",
+			"2026-03-22 Sun 20:00 CET",
+			"2026-03-28 Sat 20:00 CET",
+		}, dates(t, "FREQ=DAILY;BYDAY=SA,SU;COUNT=3", start))
+	})
+
+	t.Run("Monthly on the last Friday", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"2026-03-27 Fri 20:00 CET",
+			"2026-04-24 Fri 20:00 CEST",
+			"2026-05-29 Fri 20:00 CEST",
+		}, dates(t, "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3", start))
+	})
+
+	t.Run("Monthly on the first and last day", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"2026-03-31 Tue 20:00 CEST",
+			"2026-04-01 Wed 20:00 CEST",
+			"2026-04-30 Thu 20:00 CEST",
+		}, dates(t, "FREQ=MONTHLY;BYMONTHDAY=1,-1;COUNT=3", start))
+	})
+
+	t.Run("Monthly skips months without the day", func(t *testing.T) {
+		jan31 := time.Date(2026, 1, 31, 19, 0, 0, 0, time.UTC)
+		assert.Equal(t, []string{
+			"2026-01-31 Sat 19:00 UTC",
+			"2026-03-31 Tue 19:00 UTC",
+			"2026-05-31 Sun 19:00 UTC",
+		}, dates(t, "FREQ=MONTHLY;COUNT=3", jan31))
+	})
+
+	t.Run("Yearly skips years without 29 February", func(t *testing.T) {
+		leap := time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)
+		assert.Equal(t, []string{"2028-02-29 Tue 12:00 UTC", "2032-02-29 Sun 12:00 UTC"},
+			dates(t, "FREQ=YEARLY;COUNT=2", leap))
+	})
+}
+
+func TestRule_Occurrences_TooMany(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;UNTIL=20300101T000000Z")
+	require.NoError(t, err)
+
+	_, err = rule.Occurrences(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrTooManyOccurrences)
+}
@@ -0,0 +1,274 @@
+package server
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AttendeeFormRequestBody struct {
+	// TicketTypeID limits the questions to tickets of one ticket type, omit it to ask them for every ticket
+	TicketTypeID *uuid.UUID         `json:"ticket_type_id,omitempty"`
+	Fields       []models.FormField `json:"fields" binding:"required"`
+}
+
+type AttendeeRequestBody struct {
+	TicketTypeID uuid.UUID `json:"ticket_type_id" binding:"required"`
+	// Answers maps field keys to answers: text, a number, true or false, or a list of options
+	Answers map[string]interface{} `json:"answers"`
+}
+
+// ListAttendeeFormsHandler godoc
+// @Summary      List the attendee forms of an event (Admin or organization member)
+// @Description  Retrieve the questions asked for every ticket of the event and those asked per ticket type
+// @Tags         attendee-forms
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/attendee-forms [get]
+func (s *Server) ListAttendeeFormsHandler(c *gin.Context) {
+	eventID, ok := attendeeFormEventParam(c)
+	if !ok {
+		return
+	}
+
+	forms, err := s.attendeeFormService.ListForms(c, eventID)
+	if err != nil {
+		respondAttendeeFormError(c, err, "Failed to retrieve attendee forms")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.AttendeeFormsRetrieved,
+		Data: forms,
+	})
+}
+
+// SaveAttendeeFormHandler godoc
+// @Summary      Save an attendee form (Admin or organization member)
+// @Description  Set the questions buyers answer per ticket at checkout, for every ticket of the event or for one ticket type. Saving a form again replaces its questions, answers already given are kept
+// @Tags         attendee-forms
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        form body AttendeeFormRequestBody true "Questions"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/attendee-forms [put]
+func (s *Server) SaveAttendeeFormHandler(c *gin.Context) {
+	eventID, ok := attendeeFormEventParam(c)
+	if !ok {
+		return
+	}
+
+	var input AttendeeFormRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.AttendeeFormInvalidRequest, err.Error())
+		return
+	}
+
+	form := models.AttendeeForm{
+		EventID:      eventID,
+		TicketTypeID: input.TicketTypeID,
+		Fields:       input.Fields,
+	}
+	if err := s.attendeeFormService.SaveForm(c, &form); err != nil {
+		respondAttendeeFormError(c, err, "Failed to save attendee form")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.AttendeeFormSaved,
+		Data: form,
+	})
+}
+
+// DeleteAttendeeFormHandler godoc
+// @Summary      Delete an attendee form (Admin or organization member)
+// @Description  Stop asking the questions of the form, answers already given are kept
+// @Tags         attendee-forms
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        formId path string true "Attendee form ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/attendee-forms/{formId} [delete]
+func (s *Server) DeleteAttendeeFormHandler(c *gin.Context) {
+	eventID, ok := attendeeFormEventParam(c)
+	if !ok {
+		return
+	}
+	formID, err := uuid.Parse(c.Param("formId"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.AttendeeFormInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	if err := s.attendeeFormService.DeleteForm(c, eventID, formID); err != nil {
+		respondAttendeeFormError(c, err, "Failed to delete attendee form")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.AttendeeFormDeleted,
+		Data: gin.H{"id": formID},
+	})
+}
+
+// ExportAttendeeAnswersHandler godoc
+// @Summary      Export attendee answers (Admin or organization member)
+// @Description  Retrieve the answers given per ticket of the paid orders of an event, as JSON or with format=csv as a spreadsheet with one column per question
+// @Tags         attendee-forms
+// @Produce      json
+// @Produce      text/csv
+// @Param        id path string true "Event ID"
+// @Param        format query string false "json or csv" Enums(json, csv)
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/attendee-answers [get]
+func (s *Server) ExportAttendeeAnswersHandler(c *gin.Context) {
+	eventID, ok := attendeeFormEventParam(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		respondWithCode(c, http.StatusBadRequest, codes.AttendeeFormInvalidRequest, "format must be json or csv")
+		return
+	}
+
+	export, err := s.attendeeFormService.ExportAnswers(c, eventID)
+	if err != nil {
+		respondAttendeeFormError(c, err, "Failed to export attendee answers")
+		return
+	}
+
+	// Answers hold personal data of attendees
+	c.Header("Cache-Control", "private, no-store")
+	if format == "json" {
+		c.JSON(http.StatusOK, PassItResponseBody{
+			Code: codes.AttendeeAnswersExported,
+			Data: export,
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="attendees-%s.csv"`, eventID))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := writeAttendeeAnswersCSV(csv.NewWriter(c.Writer), export); err != nil {
+		log.Printf("Failed to write attendee answers of event %s: %v", eventID, err)
+	}
+}
+
+// writeAttendeeAnswersCSV writes one line per ticket with a column per question
+func writeAttendeeAnswersCSV(w *csv.Writer, export services.AttendeeAnswerExport) error {
+	header := []string{"order_id", "buyer_email", "ticket_type", "number", "ticket_id", "ticket_status"}
+	for _, field := range export.Fields {
+		header = append(header, field.Label)
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range export.Rows {
+		line := []string{row.OrderID.String(), row.BuyerEmail, row.TicketType, strconv.Itoa(row.Number), "", ""}
+		if row.TicketID != nil {
+			line[4] = row.TicketID.String()
+		}
+		if row.TicketStatus != nil {
+			line[5] = string(*row.TicketStatus)
+		}
+		for _, field := range export.Fields {
+			line = append(line, formatAnswer(row.Answers[field.Key]))
+		}
+		if err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// formatAnswer renders a stored answer as a spreadsheet cell
+func formatAnswer(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, formatAnswer(item))
+		}
+		return strings.Join(parts, "; ")
+	case []string:
+		return strings.Join(v, "; ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// attendeeAnswersByTicketType groups the answers of a checkout per ticket type, keeping their order
+func attendeeAnswersByTicketType(attendees []AttendeeRequestBody) map[uuid.UUID][]map[string]interface{} {
+	if len(attendees) == 0 {
+		return nil
+	}
+	answers := make(map[uuid.UUID][]map[string]interface{})
+	for _, attendee := range attendees {
+		answers[attendee.TicketTypeID] = append(answers[attendee.TicketTypeID], attendee.Answers)
+	}
+	return answers
+}
+
+func attendeeFormEventParam(c *gin.Context) (uuid.UUID, bool) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.AttendeeFormInvalidRequest, "invalid UUID format")
+		return uuid.Nil, false
+	}
+	return eventID, true
+}
+
+// respondAttendeeFormError maps attendee form errors onto coded HTTP responses
+func respondAttendeeFormError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrAttendeeFormNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.AttendeeFormNotFound, "Attendee form not found")
+	case errors.Is(err, services.ErrEventNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.AttendeeFormNotFound, "Event not found")
+	case errors.Is(err, services.ErrTicketTypeNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.AttendeeFormNotFound, "Ticket type not found")
+	case errors.Is(err, models.ErrAttendeeFormInvalid):
+		respondWithCode(c, http.StatusBadRequest, codes.AttendeeFormInvalidRequest, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.AttendeeFormInternalError, fallback)
+	}
+}
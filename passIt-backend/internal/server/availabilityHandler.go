@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"passIt/internal/constant"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamAvailabilityHandler godoc
+// @Summary      Stream ticket and seat availability of an event
+// @Description  Server-Sent Events stream of the inventory of an event. The first "availability" event lists how many tickets of every ticket type are left, the following ones are sent as holds are made or released and orders are paid, with the seats that changed. Unpublished events can only be followed by members of their organization who manage events. Browsers authenticate with the session cookie, the stream closes after a few minutes and EventSource reconnects
+// @Tags         events
+// @Produce      text/event-stream
+// @Param        id path string true "Event ID"
+// @Success      200 {object} services.AvailabilityChange
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events/{id}/availability [get]
+func (s *Server) StreamAvailabilityHandler(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), constant.AvailabilityStreamDuration)
+	defer cancel()
+
+	changes, err := s.availabilityService.Watch(ctx, id)
+	if err != nil {
+		log.Println(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow event availability"})
+		return
+	}
+
+	// The stream outlives the server write timeout
+	deadline := time.Now().Add(constant.AvailabilityStreamDuration + constant.AvailabilityHeartbeat)
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(deadline); err != nil {
+		log.Printf("Failed to extend write deadline of availability stream: %v", err)
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // stop reverse proxies from buffering events
+
+	heartbeat := time.NewTicker(constant.AvailabilityHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case change, ok := <-changes:
+			if !ok {
+				return false
+			}
+			c.SSEvent("availability", change)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		}
+	})
+}
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+	"passIt/internal/store"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BoxOfficeCustomerRequestBody struct {
+	// Email finds the account of the customer, a guest record is created when there is none
+	Email       string `json:"email" binding:"required,email"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+type BoxOfficeOrderRequestBody struct {
+	Customer BoxOfficeCustomerRequestBody `json:"customer" binding:"required"`
+	// Items are not limited by the maximum per order of their ticket type, so groups can be
+	// booked in one order
+	Items []HoldItemRequestBody `json:"items" binding:"required,dive"`
+	// PaymentMethod is cash, card_terminal or comp; comp issues the tickets free of charge
+	PaymentMethod models.PaymentMethod       `json:"payment_method" binding:"required"`
+	Reference     string                     `json:"reference"` // e.g. the receipt number of the card terminal
+	PromoCodes    []string                   `json:"promo_codes"`
+	Billing       *BillingDetailsRequestBody `json:"billing,omitempty"`
+	Attendees     []AttendeeRequestBody      `json:"attendees" binding:"dive"`
+}
+
+// CreateBoxOfficeOrderHandler godoc
+// @Summary      Sell tickets at the box office
+// @Description  Create and pay an order on behalf of a customer, found by email or recorded as a guest without an account. The payment is collected in cash, on a card terminal or waived for complimentary tickets, and the tickets are issued right away. The order is attributed to you for the end-of-day reconciliation. Requires a role that may sell tickets
+// @Tags         box-office
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        order body BoxOfficeOrderRequestBody true "Customer, tickets and payment"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/box-office/orders [post]
+func (s *Server) CreateBoxOfficeOrderHandler(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.BoxOfficeInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	var input BoxOfficeOrderRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.BoxOfficeInvalidRequest, err.Error())
+		return
+	}
+
+	staff, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	sale := services.BoxOfficeSale{
+		EventID: eventID,
+		StaffID: staff.ID,
+		Customer: services.BoxOfficeCustomer{
+			Email:       input.Customer.Email,
+			FirstName:   input.Customer.FirstName,
+			LastName:    input.Customer.LastName,
+			PhoneNumber: input.Customer.PhoneNumber,
+		},
+		Items: make([]store.HoldItem, len(input.Items)),
+		Payment: services.BoxOfficePayment{
+			Method:    input.PaymentMethod,
+			Reference: input.Reference,
+		},
+		Checkout: services.CheckoutOptions{
+			PromoCodes: input.PromoCodes,
+			Attendees:  attendeeAnswersByTicketType(input.Attendees),
+		},
+	}
+	for i, item := range input.Items {
+		sale.Items[i] = store.HoldItem{
+			TicketTypeID: item.TicketTypeID,
+			Quantity:     item.Quantity,
+			SeatIDs:      item.SeatIDs,
+		}
+	}
+	if input.Billing != nil {
+		sale.Checkout.Billing = &models.BillingDetails{
+			Name:    input.Billing.Name,
+			Company: input.Billing.Company,
+			Address: input.Billing.Address,
+			TaxID:   input.Billing.TaxID,
+			Email:   input.Billing.Email,
+		}
+	}
+
+	receipt, err := s.boxOfficeService.Sell(c, sale)
+	if err != nil {
+		respondBoxOfficeError(c, err, "Failed to sell tickets")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.BoxOfficeOrderCreated,
+		Data: receipt,
+	})
+}
+
+// GetBoxOfficeReportHandler godoc
+// @Summary      Box office reconciliation report (reports:read)
+// @Description  Sum up the payments collected at the box office on one day per staff member, payment method and currency, with the refunds made on them since
+// @Tags         box-office
+// @Produce      json
+// @Param        date query string false "Day of the report as YYYY-MM-DD, today when omitted"
+// @Param        time_zone query string false "IANA time zone the day is taken in, UTC when omitted"
+// @Param        staff_id query string false "Only the payments collected by this staff member"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} map[string]string
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/reports/box-office [get]
+func (s *Server) GetBoxOfficeReportHandler(c *gin.Context) {
+	s.boxOfficeReport(c, nil)
+}
+
+// GetOrgBoxOfficeReportHandler godoc
+// @Summary      Organization box office reconciliation report
+// @Description  Sum up the payments collected at the box office on one day for the events of an organization, per staff member, payment method and currency. Requires a role that may view reports
+// @Tags         organizations
+// @Produce      json
+// @Param        orgId path string true "Organization ID"
+// @Param        date query string false "Day of the report as YYYY-MM-DD, today when omitted"
+// @Param        time_zone query string false "IANA time zone the day is taken in, UTC when omitted"
+// @Param        staff_id query string false "Only the payments collected by this staff member"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orgs/{orgId}/reports/box-office [get]
+func (s *Server) GetOrgBoxOfficeReportHandler(c *gin.Context) {
+	orgID, ok := parseOrgIDParam(c)
+	if !ok {
+		return
+	}
+	// Admins pass the middleware for organizations that do not exist
+	if _, err := s.organizationService.GetOrganization(c, orgID); err != nil {
+		respondOrganizationError(c, err, "Failed to retrieve organization")
+		return
+	}
+	s.boxOfficeReport(c, &orgID)
+}
+
+func (s *Server) boxOfficeReport(c *gin.Context, orgID *uuid.UUID) {
+	loc, err := time.LoadLocation(c.DefaultQuery("time_zone", "UTC"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.BoxOfficeInvalidRequest, "invalid time_zone")
+		return
+	}
+	day := time.Now().In(loc)
+	if raw := c.Query("date"); raw != "" {
+		if day, err = time.ParseInLocation(time.DateOnly, raw, loc); err != nil {
+			respondWithCode(c, http.StatusBadRequest, codes.BoxOfficeInvalidRequest, "date must be formatted as YYYY-MM-DD")
+			return
+		}
+	}
+	filter := services.BoxOfficeReportFilter{OrganizationID: orgID}
+	if raw := c.Query("staff_id"); raw != "" {
+		staffID, err := uuid.Parse(raw)
+		if err != nil {
+			respondWithCode(c, http.StatusBadRequest, codes.BoxOfficeInvalidRequest, "invalid staff_id")
+			return
+		}
+		filter.StaffID = &staffID
+	}
+
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	report, err := s.boxOfficeService.Report(c, from, from.AddDate(0, 0, 1), filter)
+	if err != nil {
+		respondBoxOfficeError(c, err, "Failed to build box office report")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.BoxOfficeReportRetrieved,
+		Data: report,
+	})
+}
+
+// respondBoxOfficeError maps the errors of the hold, checkout and payment steps of a box
+// office sale onto coded HTTP responses
+func respondBoxOfficeError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrEventNotFound),
+		errors.Is(err, services.ErrTicketTypeNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.BoxOfficeNotFound, err.Error())
+	case errors.Is(err, store.ErrSoldOut),
+		errors.Is(err, store.ErrSeatUnavailable),
+		errors.Is(err, services.ErrTicketTypeNotOnSale),
+		errors.Is(err, models.ErrPromoCodeExhausted),
+		errors.Is(err, models.ErrPromoCodeUserLimit),
+		errors.Is(err, models.ErrOrderInvalidStatus),
+		errors.Is(err, services.ErrOrderConflict):
+		respondWithCode(c, http.StatusConflict, codes.BoxOfficeConflict, err.Error())
+	case errors.Is(err, models.ErrPaymentInvalidMethod),
+		errors.Is(err, services.ErrHoldEmpty),
+		errors.Is(err, services.ErrSeatNotInEvent),
+		errors.Is(err, services.ErrSeatNotInSection),
+		errors.Is(err, services.ErrSeatSelectionMismatch),
+		errors.Is(err, models.ErrTicketTypeOrderOutOfBounds),
+		errors.Is(err, models.ErrOrderEmpty),
+		errors.Is(err, models.ErrOrderMixedCurrency),
+		errors.Is(err, services.ErrPromoCodeNotFound),
+		errors.Is(err, models.ErrPromoCodeRequired),
+		errors.Is(err, models.ErrPromoCodeNotValid),
+		errors.Is(err, models.ErrPromoCodeNotApplicable),
+		errors.Is(err, models.ErrPromoCodeNotStackable),
+		errors.Is(err, models.ErrPromoCodeDuplicate),
+		errors.Is(err, models.ErrAttendeeAnswerInvalid):
+		respondWithCode(c, http.StatusBadRequest, codes.BoxOfficeInvalidRequest, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.BoxOfficeInternalError, fallback)
+	}
+}
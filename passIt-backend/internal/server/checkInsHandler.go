@@ -0,0 +1,189 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CheckInRequestBody struct {
+	Token string `json:"token" binding:"required"`
+	Gate  string `json:"gate"`
+}
+
+type OfflineScanRequestBody struct {
+	ClientScanID string    `json:"client_scan_id" binding:"required"`
+	Token        string    `json:"token" binding:"required"`
+	ScannedAt    time.Time `json:"scanned_at"`
+	// Accepted is what the scanner decided while offline
+	Accepted bool `json:"accepted"`
+}
+
+type ScanSyncRequestBody struct {
+	DeviceID string                   `json:"device_id" binding:"required"`
+	Gate     string                   `json:"gate"`
+	Scans    []OfflineScanRequestBody `json:"scans" binding:"required,dive"`
+}
+
+// CheckInHandler godoc
+// @Summary      Check in a ticket (Admin or organization member)
+// @Description  Verify a scanned QR code and admit its ticket to the event. Rejected scans are recorded too and return the reason
+// @Tags         check-in
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        scan body CheckInRequestBody true "Scanned QR code"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/check-in [post]
+func (s *Server) CheckInHandler(c *gin.Context) {
+	eventID, ok := checkInEventParam(c)
+	if !ok {
+		return
+	}
+
+	var input CheckInRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.CheckInInvalidRequest, err.Error())
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	result, err := s.checkInService.Scan(c, eventID, services.ScanRequest{
+		Token:   input.Token,
+		Gate:    input.Gate,
+		StaffID: user.ID,
+	})
+	if err != nil {
+		respondCheckInError(c, err, "Failed to check in ticket")
+		return
+	}
+
+	code := codes.CheckInAccepted
+	if !result.Accepted {
+		code = codes.CheckInRejected
+	}
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: code,
+		Data: result,
+	})
+}
+
+// ExportCheckInHandler godoc
+// @Summary      Export tickets for offline scanning (Admin or organization member)
+// @Description  Get the public key, the valid tickets and the revoked QR codes of an event so a scanner can check in without connectivity
+// @Tags         check-in
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/check-in/export [get]
+func (s *Server) ExportCheckInHandler(c *gin.Context) {
+	eventID, ok := checkInEventParam(c)
+	if !ok {
+		return
+	}
+
+	export, err := s.checkInService.ExportEvent(c, eventID)
+	if err != nil {
+		respondCheckInError(c, err, "Failed to export tickets")
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.CheckInExportRetrieved,
+		Data: export,
+	})
+}
+
+// SyncScansHandler godoc
+// @Summary      Upload an offline scan log (Admin or organization member)
+// @Description  Replay the scans of an offline scanner in scan order and report where the server disagrees with the scanner. Uploading the same scans again is safe
+// @Tags         check-in
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        scans body ScanSyncRequestBody true "Scan log"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/check-in/sync [post]
+func (s *Server) SyncScansHandler(c *gin.Context) {
+	eventID, ok := checkInEventParam(c)
+	if !ok {
+		return
+	}
+
+	var input ScanSyncRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.CheckInInvalidRequest, err.Error())
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	request := services.ScanSyncRequest{
+		DeviceID: input.DeviceID,
+		Gate:     input.Gate,
+		StaffID:  user.ID,
+		Scans:    make([]services.OfflineScan, 0, len(input.Scans)),
+	}
+	for _, scan := range input.Scans {
+		request.Scans = append(request.Scans, services.OfflineScan{
+			ClientScanID:   scan.ClientScanID,
+			Token:          scan.Token,
+			ScannedAt:      scan.ScannedAt,
+			DeviceAccepted: scan.Accepted,
+		})
+	}
+
+	reconciled, err := s.checkInService.SyncScans(c, eventID, request)
+	if err != nil {
+		respondCheckInError(c, err, "Failed to sync scans")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.CheckInScansSynced,
+		Data: reconciled,
+	})
+}
+
+func checkInEventParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.CheckInInvalidRequest, "invalid UUID format")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondCheckInError maps check-in service errors onto coded HTTP responses
+func respondCheckInError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrEventNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.CheckInEventNotFound, "Event not found")
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.CheckInInternalError, fallback)
+	}
+}
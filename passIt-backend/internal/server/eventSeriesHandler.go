@@ -0,0 +1,325 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SeriesRequestBody struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	Venue       string `json:"venue"`
+	TimeZone    string `json:"time_zone" binding:"required"`
+	// FirstStartsAt is the start of the first occurrence, later ones start at the same local time
+	FirstStartsAt   time.Time `json:"first_starts_at" binding:"required"`
+	DurationMinutes int       `json:"duration_minutes" binding:"required"`
+	// Recurrence is an RRULE, e.g. FREQ=WEEKLY;BYDAY=FR;COUNT=10. It must end with COUNT or UNTIL
+	Recurrence  string                    `json:"recurrence" binding:"required"`
+	Capacity    int                       `json:"capacity"`
+	TicketTypes []models.SeriesTicketType `json:"ticket_types"`
+}
+
+type SeriesPassRequestBody struct {
+	// EventIDs are the occurrences the pass admits to, at least two
+	EventIDs    []uuid.UUID `json:"event_ids" binding:"required"`
+	Name        string      `json:"name" binding:"required"`
+	Description string      `json:"description"`
+	Price       int64       `json:"price"` // in minor units, e.g. cents
+	Currency    string      `json:"currency" binding:"required"`
+	Quantity    int         `json:"quantity" binding:"required"`
+	SalesStart  *time.Time  `json:"sales_start,omitempty"`
+	SalesEnd    *time.Time  `json:"sales_end,omitempty"`
+	MinPerOrder int         `json:"min_per_order"`
+	MaxPerOrder int         `json:"max_per_order"`
+	Position    int         `json:"position"`
+}
+
+func (r SeriesRequestBody) toModel(createdByID uuid.UUID) models.EventSeries {
+	return models.EventSeries{
+		Title:           r.Title,
+		Description:     r.Description,
+		Venue:           r.Venue,
+		TimeZone:        r.TimeZone,
+		FirstStartsAt:   r.FirstStartsAt,
+		DurationMinutes: r.DurationMinutes,
+		Recurrence:      r.Recurrence,
+		Capacity:        r.Capacity,
+		TicketTypes:     r.TicketTypes,
+		CreatedByID:     createdByID,
+	}
+}
+
+// CreateSeriesHandler godoc
+// @Summary      Create a recurring event series (events:write)
+// @Description  Create a series and a draft event per date of its recurrence rule, each with the ticket types of the series and its own inventory
+// @Tags         series
+// @Accept       json
+// @Produce      json
+// @Param        series body SeriesRequestBody true "Series data"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/series [post]
+func (s *Server) CreateSeriesHandler(c *gin.Context) {
+	s.createSeries(c, nil)
+}
+
+// CreateOrgSeriesHandler godoc
+// @Summary      Create an organization event series
+// @Description  Create a series owned by the organization and a draft event per date of its recurrence rule. Requires a role that may manage events
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Param        orgId path string true "Organization ID"
+// @Param        series body SeriesRequestBody true "Series data"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orgs/{orgId}/series [post]
+func (s *Server) CreateOrgSeriesHandler(c *gin.Context) {
+	orgID, ok := parseOrgIDParam(c)
+	if !ok {
+		return
+	}
+	// Admins pass the middleware for organizations that do not exist
+	if _, err := s.organizationService.GetOrganization(c, orgID); err != nil {
+		respondOrganizationError(c, err, "Failed to retrieve organization")
+		return
+	}
+	s.createSeries(c, &orgID)
+}
+
+func (s *Server) createSeries(c *gin.Context, orgID *uuid.UUID) {
+	var input SeriesRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.SeriesInvalidRequest, err.Error())
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	series := input.toModel(user.ID)
+	series.OrganizationID = orgID
+	if err := s.eventSeriesService.CreateSeries(c, &series); err != nil {
+		respondSeriesError(c, err, "Failed to create event series")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.SeriesCreated,
+		Data: series,
+	})
+}
+
+// GetSeriesHandler godoc
+// @Summary      Get an event series
+// @Description  Retrieve a series with its published occurrences and the passes sold on them. Staff with events:read also see drafts and cancelled occurrences
+// @Tags         series
+// @Produce      json
+// @Param        id path string true "Series ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/series/{id} [get]
+func (s *Server) GetSeriesHandler(c *gin.Context) {
+	id, ok := seriesIDParam(c)
+	if !ok {
+		return
+	}
+
+	var (
+		series models.EventSeries
+		err    error
+	)
+	if hasPermission(c, models.PermEventsRead) {
+		series, err = s.eventSeriesService.GetSeries(c, id)
+	} else {
+		series, err = s.eventSeriesService.GetPublishedSeries(c, id)
+	}
+	if err != nil {
+		respondSeriesError(c, err, "Failed to retrieve event series")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.SeriesRetrieved,
+		Data: series,
+	})
+}
+
+// UpdateSeriesHandler godoc
+// @Summary      Update every occurrence of a series (Admin or organization member)
+// @Description  Replace the details and recurrence rule of a series. Upcoming occurrences take the new details and start time unless they were edited on their own through the event endpoints; new dates get a draft occurrence and drafts whose date was dropped are removed. Published occurrences whose date was dropped are kept and listed, cancel them on their own. Changed ticket types apply to new occurrences only
+// @Tags         series
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Series ID"
+// @Param        series body SeriesRequestBody true "Series data"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/series/{id} [put]
+func (s *Server) UpdateSeriesHandler(c *gin.Context) {
+	id, ok := seriesIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input SeriesRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.SeriesInvalidRequest, err.Error())
+		return
+	}
+
+	series := input.toModel(uuid.Nil)
+	series.ID = id
+	changes, err := s.eventSeriesService.UpdateSeries(c, &series)
+	if err != nil {
+		respondSeriesError(c, err, "Failed to update event series")
+		return
+	}
+
+	series.Occurrences = nil
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.SeriesUpdated,
+		Data: gin.H{
+			"series":  series,
+			"changes": changes,
+		},
+	})
+}
+
+// PublishSeriesHandler godoc
+// @Summary      Publish a series (Admin or organization member)
+// @Description  Publish every draft occurrence of a series, occurrences can also be published one by one through the event endpoints
+// @Tags         series
+// @Produce      json
+// @Param        id path string true "Series ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/series/{id}/publish [post]
+func (s *Server) PublishSeriesHandler(c *gin.Context) {
+	id, ok := seriesIDParam(c)
+	if !ok {
+		return
+	}
+
+	series, err := s.eventSeriesService.PublishSeries(c, id)
+	if err != nil {
+		respondSeriesError(c, err, "Failed to publish event series")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.SeriesPublished,
+		Data: series,
+	})
+}
+
+// CreateSeriesPassHandler godoc
+// @Summary      Create a multi-day pass (Admin or organization member)
+// @Description  Create a ticket type admitting to several occurrences of a series. It is sold on the first of them and takes a seat of the capacity of each; its tickets are checked in once per occurrence
+// @Tags         series
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Series ID"
+// @Param        pass body SeriesPassRequestBody true "Pass data"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/series/{id}/passes [post]
+func (s *Server) CreateSeriesPassHandler(c *gin.Context) {
+	id, ok := seriesIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input SeriesPassRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.SeriesInvalidRequest, err.Error())
+		return
+	}
+
+	pass := models.TicketType{
+		Name:        input.Name,
+		Description: input.Description,
+		Price:       input.Price,
+		Currency:    input.Currency,
+		Quantity:    input.Quantity,
+		SalesStart:  input.SalesStart,
+		SalesEnd:    input.SalesEnd,
+		MinPerOrder: input.MinPerOrder,
+		MaxPerOrder: input.MaxPerOrder,
+		Position:    input.Position,
+	}
+	if err := s.eventSeriesService.CreatePass(c, id, &pass, input.EventIDs); err != nil {
+		respondSeriesError(c, err, "Failed to create pass")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.SeriesPassCreated,
+		Data: pass,
+	})
+}
+
+func seriesIDParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.SeriesInvalidRequest, "invalid UUID format")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondSeriesError maps event series errors onto coded HTTP responses
+func respondSeriesError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrSeriesNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.SeriesNotFound, "Event series not found")
+	case errors.Is(err, services.ErrEventNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.SeriesNotFound, "Event not found")
+	case errors.Is(err, services.ErrEventNotEditable),
+		errors.Is(err, models.ErrEventInvalidStatus),
+		errors.Is(err, models.ErrTicketCapacityExceeded) && !errors.Is(err, models.ErrSeriesInvalid):
+		respondWithCode(c, http.StatusConflict, codes.SeriesConflict, err.Error())
+	case errors.Is(err, models.ErrSeriesInvalid),
+		errors.Is(err, models.ErrSeriesMismatch),
+		errors.Is(err, models.ErrPassInvalidEvents),
+		errors.Is(err, models.ErrTicketTypeNameRequired),
+		errors.Is(err, models.ErrTicketTypeInvalidPrice),
+		errors.Is(err, models.ErrTicketTypeInvalidCurrency),
+		errors.Is(err, models.ErrTicketTypeInvalidQuantity),
+		errors.Is(err, models.ErrTicketTypeInvalidLimits),
+		errors.Is(err, models.ErrTicketTypeInvalidWindow):
+		respondWithCode(c, http.StatusBadRequest, codes.SeriesInvalidRequest, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.SeriesInternalError, fallback)
+	}
+}
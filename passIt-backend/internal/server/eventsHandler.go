@@ -0,0 +1,379 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+	"passIt/internal/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateEventRequestBody struct {
+	Title       string    `json:"title" binding:"required"`
+	Description string    `json:"description"`
+	Venue       string    `json:"venue"`
+	StartsAt    time.Time `json:"starts_at" binding:"required"`
+	EndsAt      time.Time `json:"ends_at" binding:"required"`
+	TimeZone    string    `json:"time_zone" binding:"required"`
+	Capacity    int       `json:"capacity"`
+	// TransferPolicy is allowed (default) or forbidden
+	TransferPolicy      string `json:"transfer_policy"`
+	TransferCutoffHours int    `json:"transfer_cutoff_hours"`
+	// ResaleMarkupPercent caps resale prices at the face value plus this percentage
+	ResaleEnabled       bool `json:"resale_enabled"`
+	ResaleMarkupPercent int  `json:"resale_markup_percent"`
+	// MaxTicketsPerUser and MaxTicketsPerPaymentMethod cap the tickets one account and one
+	// card may buy, 0 means no limit
+	MaxTicketsPerUser          int `json:"max_tickets_per_user"`
+	MaxTicketsPerPaymentMethod int `json:"max_tickets_per_payment_method"`
+}
+
+func (b CreateEventRequestBody) toModel(createdByID uuid.UUID) models.Event {
+	event := models.Event{
+		Title:       b.Title,
+		Description: b.Description,
+		Venue:       b.Venue,
+		StartsAt:    b.StartsAt,
+		EndsAt:      b.EndsAt,
+		TimeZone:    b.TimeZone,
+		Capacity:    b.Capacity,
+		CreatedByID: createdByID,
+
+		TransferPolicy:      models.TransferPolicy(b.TransferPolicy),
+		TransferCutoffHours: b.TransferCutoffHours,
+		ResaleEnabled:       b.ResaleEnabled,
+		ResaleMarkupPercent: b.ResaleMarkupPercent,
+
+		MaxTicketsPerUser:          b.MaxTicketsPerUser,
+		MaxTicketsPerPaymentMethod: b.MaxTicketsPerPaymentMethod,
+	}
+	if event.TransferPolicy == "" {
+		event.TransferPolicy = models.TransferPolicyAllowed
+	}
+	return event
+}
+
+type UpdateEventRequestBody struct {
+	Title       string     `json:"title,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Venue       *string    `json:"venue,omitempty"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	TimeZone    string     `json:"time_zone,omitempty"`
+	Capacity    *int       `json:"capacity,omitempty"`
+
+	TransferPolicy      string `json:"transfer_policy,omitempty"`
+	TransferCutoffHours *int   `json:"transfer_cutoff_hours,omitempty"`
+	ResaleEnabled       *bool  `json:"resale_enabled,omitempty"`
+	ResaleMarkupPercent *int   `json:"resale_markup_percent,omitempty"`
+
+	MaxTicketsPerUser          *int `json:"max_tickets_per_user,omitempty"`
+	MaxTicketsPerPaymentMethod *int `json:"max_tickets_per_payment_method,omitempty"`
+}
+
+// CreateEventHandler godoc
+// @Summary      Create a new event (events:write)
+// @Description  Create a draft event with schedule, venue and capacity
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        event body CreateEventRequestBody true "Event creation data"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events [post]
+func (s *Server) CreateEventHandler(c *gin.Context) {
+	var input CreateEventRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	event := input.toModel(user.ID)
+	if err := s.eventService.CreateEvent(c, &event); err != nil {
+		respondEventError(c, err, "Failed to create event")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.EventCreatedSuccessfully,
+		Data: event,
+	})
+}
+
+// ListEventsHandler godoc
+// @Summary      List events
+// @Description  List published events. Staff with events:read may filter by status (draft, published, cancelled or all)
+// @Tags         events
+// @Produce      json
+// @Param        status query string false "Status filter (events:read only)"
+// @Success      200 {array} models.Event
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events [get]
+func (s *Server) ListEventsHandler(c *gin.Context) {
+	statuses := []models.EventStatus{models.EventStatusPublished}
+
+	if status := c.Query("status"); status != "" {
+		if !hasPermission(c, models.PermEventsRead) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden - missing permission " + string(models.PermEventsRead)})
+			return
+		}
+		switch models.EventStatus(status) {
+		case models.EventStatusDraft, models.EventStatusPublished, models.EventStatusCancelled:
+			statuses = []models.EventStatus{models.EventStatus(status)}
+		case "all":
+			statuses = nil
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status filter"})
+			return
+		}
+	}
+
+	events, err := s.eventService.ListEvents(c, statuses...)
+	if err != nil {
+		log.Println(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// GetEventHandler godoc
+// @Summary      Get event by ID
+// @Description  Retrieve a published event with its ticket types currently on sale and the attendee questions asked at checkout. Staff with events:read can also retrieve drafts and cancelled events and see every ticket type
+// @Tags         events
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Success      200 {object} models.Event
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events/{id} [get]
+func (s *Server) GetEventHandler(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var (
+		event models.Event
+		err   error
+	)
+	isStaff := hasPermission(c, models.PermEventsRead)
+	if isStaff {
+		event, err = s.eventService.GetEventByID(c, id)
+	} else {
+		event, err = s.eventService.GetPublishedEvent(c, id)
+	}
+	if err != nil {
+		respondEventError(c, err, "Failed to retrieve event")
+		return
+	}
+
+	if isStaff {
+		event.TicketTypes, err = s.ticketTypeService.ListTicketTypes(c, id)
+	} else {
+		event.TicketTypes, err = s.ticketTypeService.ListOnSaleTicketTypes(c, id)
+	}
+	if err != nil {
+		log.Println(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ticket types"})
+		return
+	}
+	if event.AttendeeForms, err = s.attendeeFormService.ListForms(c, id); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve attendee forms"})
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// UpdateEventHandler godoc
+// @Summary      Update event by ID (Admin or organization member)
+// @Description  Edit the details of a draft or published event. An occurrence of a series edited here no longer follows changes to the series
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        event body UpdateEventRequestBody true "Event update data"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events/{id} [put]
+func (s *Server) UpdateEventHandler(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var updateReq UpdateEventRequestBody
+	if !utils.DecodeServerInput(c, &updateReq) {
+		return
+	}
+
+	event, err := s.eventService.GetEventByID(c, id)
+	if err != nil {
+		respondEventError(c, err, "Failed to retrieve event")
+		return
+	}
+
+	applyEventUpdates(&event, &updateReq)
+
+	if err := s.eventService.UpdateEvent(c, &event); err != nil {
+		respondEventError(c, err, "Failed to update event")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.EventUpdatedSuccessfully,
+		Data: event,
+	})
+}
+
+// applyEventUpdates applies provided fields from update request to existing event
+func applyEventUpdates(event *models.Event, update *UpdateEventRequestBody) {
+	if update.Title != "" {
+		event.Title = update.Title
+	}
+	if update.Description != nil {
+		event.Description = *update.Description
+	}
+	if update.Venue != nil {
+		event.Venue = *update.Venue
+	}
+	if update.StartsAt != nil {
+		event.StartsAt = *update.StartsAt
+	}
+	if update.EndsAt != nil {
+		event.EndsAt = *update.EndsAt
+	}
+	if update.TimeZone != "" {
+		event.TimeZone = update.TimeZone
+	}
+	if update.Capacity != nil {
+		event.Capacity = *update.Capacity
+	}
+	if update.TransferPolicy != "" {
+		event.TransferPolicy = models.TransferPolicy(update.TransferPolicy)
+	}
+	if update.TransferCutoffHours != nil {
+		event.TransferCutoffHours = *update.TransferCutoffHours
+	}
+	if update.ResaleEnabled != nil {
+		event.ResaleEnabled = *update.ResaleEnabled
+	}
+	if update.ResaleMarkupPercent != nil {
+		event.ResaleMarkupPercent = *update.ResaleMarkupPercent
+	}
+	if update.MaxTicketsPerUser != nil {
+		event.MaxTicketsPerUser = *update.MaxTicketsPerUser
+	}
+	if update.MaxTicketsPerPaymentMethod != nil {
+		event.MaxTicketsPerPaymentMethod = *update.MaxTicketsPerPaymentMethod
+	}
+}
+
+// PublishEventHandler godoc
+// @Summary      Publish event (Admin or organization member)
+// @Description  Make a draft event visible to all authenticated users
+// @Tags         events
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events/{id}/publish [post]
+func (s *Server) PublishEventHandler(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	event, err := s.eventService.PublishEvent(c, id)
+	if err != nil {
+		respondEventError(c, err, "Failed to publish event")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.EventPublishedSuccessfully,
+		Data: event,
+	})
+}
+
+// CancelEventHandler godoc
+// @Summary      Cancel event (Admin or organization member)
+// @Description  Cancel a draft or published event. Paid orders are refunded in the background and unpaid orders are cancelled
+// @Tags         events
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events/{id}/cancel [post]
+func (s *Server) CancelEventHandler(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	event, err := s.eventService.CancelEvent(c, id)
+	if err != nil {
+		respondEventError(c, err, "Failed to cancel event")
+		return
+	}
+	s.refundCancelledEvent(event.ID)
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.EventCancelledSuccessfully,
+		Data: event,
+	})
+}
+
+// respondEventError maps event service errors onto HTTP responses
+func respondEventError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+	case errors.Is(err, services.ErrEventNotEditable),
+		errors.Is(err, models.ErrEventInvalidStatus),
+		errors.Is(err, models.ErrTicketCapacityExceeded):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrEventTitleRequired),
+		errors.Is(err, models.ErrEventInvalidWindow),
+		errors.Is(err, models.ErrEventInvalidZone),
+		errors.Is(err, models.ErrEventNegativeSeats),
+		errors.Is(err, models.ErrEventInvalidTransferPolicy),
+		errors.Is(err, models.ErrEventNegativeTransferCutoff),
+		errors.Is(err, models.ErrEventNegativeResaleMarkup),
+		errors.Is(err, models.ErrEventNegativePurchaseLimit):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", fallback, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
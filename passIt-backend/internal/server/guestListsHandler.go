@@ -0,0 +1,540 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+	"passIt/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxGuestListCSVSize caps the size of guest list CSV uploads
+const maxGuestListCSVSize = 1 << 20
+
+type GuestListRequestBody struct {
+	Name string `json:"name" binding:"required"` // category, e.g. Artists, Press or Sponsors
+	// Quota caps the guests of the list, plus-ones included; 0 means no limit
+	Quota int `json:"quota" binding:"min=0"`
+	// TicketTypeID is the ticket type comp tickets of the list are issued as
+	TicketTypeID *uuid.UUID `json:"ticket_type_id,omitempty"`
+}
+
+type GuestRequestBody struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"omitempty,email"` // required to issue comp tickets
+	Company  string `json:"company"`
+	PlusOnes int    `json:"plus_ones" binding:"min=0"`
+	Note     string `json:"note"`
+}
+
+type AddGuestsRequestBody struct {
+	Guests []GuestRequestBody `json:"guests" binding:"required,dive"`
+}
+
+type GuestCheckInRequestBody struct {
+	// Guests is how many of the guest's party arrive, everyone left when omitted
+	Guests int    `json:"guests" binding:"min=0"`
+	Gate   string `json:"gate"`
+}
+
+func (b GuestListRequestBody) toModel(eventID uuid.UUID) models.GuestList {
+	return models.GuestList{
+		EventID:      eventID,
+		Name:         b.Name,
+		Quota:        b.Quota,
+		TicketTypeID: b.TicketTypeID,
+	}
+}
+
+// ListGuestListsHandler godoc
+// @Summary      List the guest lists of an event (Admin or organization member)
+// @Description  Retrieve the guest lists of the event with the number of guests on each, plus-ones included
+// @Tags         guest-lists
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/guest-lists [get]
+func (s *Server) ListGuestListsHandler(c *gin.Context) {
+	eventID, ok := guestListParam(c, "id")
+	if !ok {
+		return
+	}
+
+	lists, err := s.guestListService.ListGuestLists(c, eventID)
+	if err != nil {
+		respondGuestListError(c, err, "Failed to retrieve guest lists")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.GuestListsRetrieved,
+		Data: lists,
+	})
+}
+
+// CreateGuestListHandler godoc
+// @Summary      Create a guest list (Admin or organization member)
+// @Description  Create a category of guests of the event, such as artists, press or sponsors, with an optional quota and the ticket type comp tickets are issued as
+// @Tags         guest-lists
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        list body GuestListRequestBody true "Guest list"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/guest-lists [post]
+func (s *Server) CreateGuestListHandler(c *gin.Context) {
+	eventID, ok := guestListParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input GuestListRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.GuestListInvalidRequest, err.Error())
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	list := input.toModel(eventID)
+	list.CreatedByID = user.ID
+	if err := s.guestListService.CreateGuestList(c, &list); err != nil {
+		respondGuestListError(c, err, "Failed to create guest list")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.GuestListCreated,
+		Data: list,
+	})
+}
+
+// GetGuestListHandler godoc
+// @Summary      Get a guest list (Admin or organization member)
+// @Description  Retrieve a guest list of the event with its guests sorted by name
+// @Tags         guest-lists
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        listId path string true "Guest list ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/guest-lists/{listId} [get]
+func (s *Server) GetGuestListHandler(c *gin.Context) {
+	eventID, listID, ok := guestListParams(c)
+	if !ok {
+		return
+	}
+
+	list, err := s.guestListService.GetGuestList(c, eventID, listID)
+	if err != nil {
+		respondGuestListError(c, err, "Failed to retrieve guest list")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.GuestListRetrieved,
+		Data: list,
+	})
+}
+
+// UpdateGuestListHandler godoc
+// @Summary      Update a guest list (Admin or organization member)
+// @Description  Change the name, quota and comp ticket type of a guest list. The quota cannot be lowered below the guests already on the list
+// @Tags         guest-lists
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        listId path string true "Guest list ID"
+// @Param        list body GuestListRequestBody true "Guest list"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/guest-lists/{listId} [put]
+func (s *Server) UpdateGuestListHandler(c *gin.Context) {
+	eventID, listID, ok := guestListParams(c)
+	if !ok {
+		return
+	}
+
+	var input GuestListRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.GuestListInvalidRequest, err.Error())
+		return
+	}
+
+	list := input.toModel(eventID)
+	list.ID = listID
+	if err := s.guestListService.UpdateGuestList(c, &list); err != nil {
+		respondGuestListError(c, err, "Failed to update guest list")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.GuestListUpdated,
+		Data: list,
+	})
+}
+
+// DeleteGuestListHandler godoc
+// @Summary      Delete a guest list (Admin or organization member)
+// @Description  Remove a guest list and its guests. Lists with guests already checked in or issued tickets cannot be deleted
+// @Tags         guest-lists
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        listId path string true "Guest list ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/guest-lists/{listId} [delete]
+func (s *Server) DeleteGuestListHandler(c *gin.Context) {
+	eventID, listID, ok := guestListParams(c)
+	if !ok {
+		return
+	}
+
+	if err := s.guestListService.DeleteGuestList(c, eventID, listID); err != nil {
+		respondGuestListError(c, err, "Failed to delete guest list")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.GuestListDeleted,
+		Data: gin.H{"id": listID},
+	})
+}
+
+// AddGuestsHandler godoc
+// @Summary      Add guests to a guest list (Admin or organization member)
+// @Description  Add guests with their plus-ones. Either all guests are added or none when the quota of the list would be exceeded
+// @Tags         guest-lists
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        listId path string true "Guest list ID"
+// @Param        guests body AddGuestsRequestBody true "Guests"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/guest-lists/{listId}/guests [post]
+func (s *Server) AddGuestsHandler(c *gin.Context) {
+	eventID, listID, ok := guestListParams(c)
+	if !ok {
+		return
+	}
+
+	var input AddGuestsRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.GuestListInvalidRequest, err.Error())
+		return
+	}
+
+	entries := make([]models.GuestListEntry, len(input.Guests))
+	for i, guest := range input.Guests {
+		entries[i] = models.GuestListEntry{
+			Name:     guest.Name,
+			Email:    guest.Email,
+			Company:  guest.Company,
+			PlusOnes: guest.PlusOnes,
+			Note:     guest.Note,
+		}
+	}
+	s.addGuests(c, eventID, listID, entries)
+}
+
+// ImportGuestsHandler godoc
+// @Summary      Import guests from CSV (Admin or organization member)
+// @Description  Add the guests of a CSV file to a guest list. The header names the columns: name is required, email, company, plus_ones and note are optional. Either all guests are added or none when a line is invalid or the quota of the list would be exceeded
+// @Tags         guest-lists
+// @Accept       text/csv
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        listId path string true "Guest list ID"
+// @Param        guests body string true "CSV file"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/guest-lists/{listId}/guests/import [post]
+func (s *Server) ImportGuestsHandler(c *gin.Context) {
+	eventID, listID, ok := guestListParams(c)
+	if !ok {
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxGuestListCSVSize)
+	defer body.Close()
+	entries, err := models.ParseGuestListCSV(body)
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.GuestListInvalidRequest, err.Error())
+		return
+	}
+	s.addGuests(c, eventID, listID, entries)
+}
+
+func (s *Server) addGuests(c *gin.Context, eventID, listID uuid.UUID, entries []models.GuestListEntry) {
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	added, err := s.guestListService.AddGuests(c, eventID, listID, user.ID, entries)
+	if err != nil {
+		respondGuestListError(c, err, "Failed to add guests")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.GuestsAdded,
+		Data: added,
+	})
+}
+
+// RemoveGuestHandler godoc
+// @Summary      Remove a guest from a guest list (Admin or organization member)
+// @Description  Take a guest off the list. Guests already checked in or issued tickets cannot be removed
+// @Tags         guest-lists
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        listId path string true "Guest list ID"
+// @Param        guestId path string true "Guest ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/guest-lists/{listId}/guests/{guestId} [delete]
+func (s *Server) RemoveGuestHandler(c *gin.Context) {
+	eventID, listID, ok := guestListParams(c)
+	if !ok {
+		return
+	}
+	guestID, ok := guestListParam(c, "guestId")
+	if !ok {
+		return
+	}
+
+	if err := s.guestListService.RemoveGuest(c, eventID, listID, guestID); err != nil {
+		respondGuestListError(c, err, "Failed to remove guest")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.GuestRemoved,
+		Data: gin.H{"id": guestID},
+	})
+}
+
+// IssueGuestTicketsHandler godoc
+// @Summary      Issue comp tickets to a guest (Admin or organization member)
+// @Description  Issue free tickets of the ticket type of the guest list for the guest and their plus-ones. The tickets take inventory like any other, skip the payment provider and are attributed to you in the box office report. The guest needs an email address, an account is created for guests without one
+// @Tags         guest-lists
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        listId path string true "Guest list ID"
+// @Param        guestId path string true "Guest ID"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/guest-lists/{listId}/guests/{guestId}/tickets [post]
+func (s *Server) IssueGuestTicketsHandler(c *gin.Context) {
+	eventID, listID, ok := guestListParams(c)
+	if !ok {
+		return
+	}
+	guestID, ok := guestListParam(c, "guestId")
+	if !ok {
+		return
+	}
+
+	staff, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	receipt, err := s.guestListService.IssueTickets(c, eventID, listID, guestID, staff.ID)
+	if err != nil {
+		respondGuestListError(c, err, "Failed to issue comp tickets")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.GuestTicketsIssued,
+		Data: receipt,
+	})
+}
+
+// SearchGuestsHandler godoc
+// @Summary      Search the guests of an event (Admin or organization member)
+// @Description  Find guests on any guest list of the event by name, email or company for check-in at the door
+// @Tags         guest-lists
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        q query string true "At least 2 characters of the name, email or company"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/guests [get]
+func (s *Server) SearchGuestsHandler(c *gin.Context) {
+	eventID, ok := guestListParam(c, "id")
+	if !ok {
+		return
+	}
+
+	entries, err := s.guestListService.SearchGuests(c, eventID, c.Query("q"))
+	if err != nil {
+		respondGuestListError(c, err, "Failed to search guests")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.GuestsRetrieved,
+		Data: entries,
+	})
+}
+
+// CheckInGuestHandler godoc
+// @Summary      Check in a guest by name (Admin or organization member)
+// @Description  Admit a guest and their plus-ones without a QR code, all at once or as they arrive. Comp tickets issued to the guest are marked used so they cannot be scanned afterwards
+// @Tags         guest-lists
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        guestId path string true "Guest ID"
+// @Param        check_in body GuestCheckInRequestBody false "Guests arriving"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/guests/{guestId}/check-in [post]
+func (s *Server) CheckInGuestHandler(c *gin.Context) {
+	eventID, ok := guestListParam(c, "id")
+	if !ok {
+		return
+	}
+	guestID, ok := guestListParam(c, "guestId")
+	if !ok {
+		return
+	}
+
+	var input GuestCheckInRequestBody
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			respondWithCode(c, http.StatusBadRequest, codes.GuestListInvalidRequest, err.Error())
+			return
+		}
+	}
+
+	staff, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	entry, err := s.guestListService.CheckIn(c, eventID, guestID, services.GuestCheckInRequest{
+		Guests:  input.Guests,
+		Gate:    input.Gate,
+		StaffID: staff.ID,
+	})
+	if err != nil {
+		respondGuestListError(c, err, "Failed to check in guest")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.GuestCheckedIn,
+		Data: entry,
+	})
+}
+
+// guestListParam reads a UUID from the URL path and writes a coded 400 response if it is malformed
+func guestListParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.GuestListInvalidRequest, "invalid UUID format")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func guestListParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	eventID, ok := guestListParam(c, "id")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	listID, ok := guestListParam(c, "listId")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return eventID, listID, true
+}
+
+// respondGuestListError maps guest list errors, and the box office errors of comp ticket
+// issuance, onto coded HTTP responses
+func respondGuestListError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrGuestListNotFound),
+		errors.Is(err, services.ErrGuestNotFound),
+		errors.Is(err, services.ErrEventNotFound),
+		errors.Is(err, services.ErrTicketTypeNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.GuestListNotFound, err.Error())
+	case errors.Is(err, models.ErrGuestListQuotaExceeded):
+		respondWithCode(c, http.StatusConflict, codes.GuestListQuotaExceeded, err.Error())
+	case errors.Is(err, models.ErrGuestAlreadyCheckedIn),
+		errors.Is(err, models.ErrGuestCheckInOutOfBounds):
+		respondWithCode(c, http.StatusConflict, codes.GuestAlreadyCheckedIn, err.Error())
+	case errors.Is(err, services.ErrGuestListInUse),
+		errors.Is(err, services.ErrGuestTicketsIssued),
+		errors.Is(err, store.ErrSoldOut),
+		errors.Is(err, services.ErrTicketTypeNotOnSale):
+		respondWithCode(c, http.StatusConflict, codes.GuestListConflict, err.Error())
+	case errors.Is(err, models.ErrGuestListNameRequired),
+		errors.Is(err, models.ErrGuestListInvalidQuota),
+		errors.Is(err, models.ErrGuestNameRequired),
+		errors.Is(err, models.ErrGuestInvalidEmail),
+		errors.Is(err, models.ErrGuestInvalidPlusOnes),
+		errors.Is(err, services.ErrGuestListNoTicketType),
+		errors.Is(err, services.ErrGuestEmailRequired),
+		errors.Is(err, services.ErrGuestSearchTooShort):
+		respondWithCode(c, http.StatusBadRequest, codes.GuestListInvalidRequest, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.GuestListInternalError, fallback)
+	}
+}
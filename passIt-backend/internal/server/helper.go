@@ -1,6 +1,70 @@
 package server
 
+import (
+	"log"
+	"net/http"
+	"passIt/internal/middleware"
+	"passIt/internal/models"
+	"passIt/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
 type PassItResponseBody struct {
 	Code int `json:"code"`
 	Data any `json:"data"`
 }
+
+// PassItErrorBody is an error response carrying a code from passit-codes
+type PassItErrorBody struct {
+	Code  int    `json:"code"`
+	Error string `json:"error"`
+}
+
+// respondWithCode writes an error response with a passit-codes code
+func respondWithCode(c *gin.Context, status int, code int, message string) {
+	c.JSON(status, PassItErrorBody{Code: code, Error: message})
+}
+
+// parseUUIDParam reads a UUID from the URL path and writes a 400 response if it is malformed
+func parseUUIDParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID format"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// sessionFromContext returns the session data set by the RequireAuth middleware
+func sessionFromContext(c *gin.Context) (*store.SessionData, bool) {
+	sessionData, exists := c.Get("user_session")
+	if !exists {
+		return nil, false
+	}
+	session, ok := sessionData.(*store.SessionData)
+	return session, ok
+}
+
+// hasPermission reports whether the roles of the authenticated caller grant the permission
+func hasPermission(c *gin.Context, permission models.Permission) bool {
+	return middleware.Permissions(c).Has(permission)
+}
+
+// currentUser loads the authenticated user from the database and writes an error response if it cannot
+func (s *Server) currentUser(c *gin.Context) (models.User, bool) {
+	session, ok := sessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "No session found"})
+		return models.User{}, false
+	}
+
+	user, err := s.userService.GetUserByEmail(c, session.UserInfo.Email)
+	if err != nil {
+		log.Printf("Failed to get current user: %v", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return models.User{}, false
+	}
+	return user, true
+}
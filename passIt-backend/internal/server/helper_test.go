@@ -32,6 +32,22 @@ func TestPassItResponseBody_JSON(t *testing.T) {
 	assert.Contains(t, string(jsonData), "data")
 }
 
+func TestRespondWithCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	respondWithCode(c, http.StatusGone, 1454, "hold has expired")
+
+	assert.Equal(t, http.StatusGone, w.Code)
+
+	var body PassItErrorBody
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 1454, body.Code)
+	assert.Equal(t, "hold has expired", body.Error)
+}
+
 func TestHelperRoutes(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+	"passIt/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type HoldItemRequestBody struct {
+	TicketTypeID uuid.UUID   `json:"ticket_type_id" binding:"required"`
+	Quantity     int         `json:"quantity" binding:"required"`
+	SeatIDs      []uuid.UUID `json:"seat_ids,omitempty"`
+}
+
+type CreateHoldRequestBody struct {
+	Items []HoldItemRequestBody `json:"items" binding:"required,dive"`
+}
+
+// CreateHoldHandler godoc
+// @Summary      Hold tickets
+// @Description  Reserve tickets, and optionally specific seats, of a published event for a few minutes so they cannot be bought by anyone else. While the event is behind an open waiting room the admission token from the queue is required
+// @Tags         holds
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        hold body CreateHoldRequestBody true "Tickets to hold"
+// @Param        X-Admission-Token header string false "Admission token from the waiting room of the event"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events/{id}/holds [post]
+func (s *Server) CreateHoldHandler(c *gin.Context) {
+	eventID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input CreateHoldRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+	if !s.requireAdmission(c, eventID, user.ID) {
+		return
+	}
+
+	items := make([]store.HoldItem, len(input.Items))
+	for i, item := range input.Items {
+		items[i] = store.HoldItem{
+			TicketTypeID: item.TicketTypeID,
+			Quantity:     item.Quantity,
+			SeatIDs:      item.SeatIDs,
+		}
+	}
+
+	hold, err := s.holdService.CreateHold(c, eventID, user.ID, items)
+	if err != nil {
+		respondHoldError(c, err, "Failed to hold tickets")
+		return
+	}
+	if err := s.riskService.RecordHold(c, hold, c.ClientIP()); err != nil {
+		log.Printf("Failed to record hold %s for velocity checks: %v", hold.ID, err)
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.HoldCreatedSuccessfully,
+		Data: hold,
+	})
+}
+
+// GetHoldHandler godoc
+// @Summary      Get hold by ID
+// @Description  Retrieve one of your active holds
+// @Tags         holds
+// @Produce      json
+// @Param        id path string true "Hold ID"
+// @Success      200 {object} store.Hold
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      410 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/holds/{id} [get]
+func (s *Server) GetHoldHandler(c *gin.Context) {
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	hold, err := s.holdService.GetHold(c, c.Param("id"), user.ID)
+	if err != nil {
+		respondHoldError(c, err, "Failed to retrieve hold")
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+// ReleaseHoldHandler godoc
+// @Summary      Release hold
+// @Description  Give the tickets of one of your holds back to sale before it expires
+// @Tags         holds
+// @Produce      json
+// @Param        id path string true "Hold ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      410 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/holds/{id} [delete]
+func (s *Server) ReleaseHoldHandler(c *gin.Context) {
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	holdID := c.Param("id")
+	if err := s.holdService.ReleaseHold(c, holdID, user.ID); err != nil {
+		respondHoldError(c, err, "Failed to release hold")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.HoldReleasedSuccessfully,
+		Data: gin.H{
+			"message": "Hold released successfully",
+			"hold_id": holdID,
+		},
+	})
+}
+
+// respondHoldError maps hold service errors onto HTTP responses
+func respondHoldError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, store.ErrHoldNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Hold not found"})
+	case errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+	case errors.Is(err, services.ErrTicketTypeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ticket type not found"})
+	case errors.Is(err, services.ErrHoldForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, store.ErrHoldExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case errors.Is(err, store.ErrSoldOut),
+		errors.Is(err, store.ErrSeatUnavailable),
+		errors.Is(err, store.ErrPurchaseLimitExceeded),
+		errors.Is(err, services.ErrTicketTypeNotOnSale):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrHoldEmpty),
+		errors.Is(err, services.ErrSeatNotInEvent),
+		errors.Is(err, services.ErrSeatNotInSection),
+		errors.Is(err, services.ErrSeatSelectionMismatch),
+		errors.Is(err, models.ErrTicketTypeOrderOutOfBounds):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", fallback, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
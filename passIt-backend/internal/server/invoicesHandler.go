@@ -0,0 +1,65 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetOrderInvoiceHandler godoc
+// @Summary      Download order invoice
+// @Description  Get the invoice of one of your paid orders as a PDF, with the billing details entered at checkout, the itemized tickets, fees and taxes. Staff with orders:read can get the invoice of any order
+// @Tags         orders
+// @Produce      application/pdf
+// @Param        id path string true "Order ID"
+// @Success      200 {file} binary
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orders/{id}/invoice [get]
+func (s *Server) GetOrderInvoiceHandler(c *gin.Context) {
+	orderID, ok := orderIDParam(c)
+	if !ok {
+		return
+	}
+
+	var (
+		invoice models.Invoice
+		err     error
+	)
+	if hasPermission(c, models.PermOrdersRead) {
+		invoice, err = s.invoiceService.IssueInvoice(c, orderID)
+	} else {
+		user, ok := s.currentUser(c)
+		if !ok {
+			return
+		}
+		invoice, err = s.invoiceService.GetUserInvoice(c, orderID, user.ID)
+	}
+	if err != nil {
+		respondInvoiceError(c, err, "Failed to retrieve invoice")
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, invoice.Number))
+	c.Data(http.StatusOK, "application/pdf", invoice.PDF)
+}
+
+// respondInvoiceError maps invoice errors onto coded HTTP responses,
+// falling back to the order errors for everything else
+func respondInvoiceError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrInvoiceNotAvailable):
+		respondWithCode(c, http.StatusConflict, codes.InvoiceNotAvailable, err.Error())
+	default:
+		respondOrderError(c, err, fallback)
+	}
+}
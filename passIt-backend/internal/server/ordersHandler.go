@@ -0,0 +1,261 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+	"passIt/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CheckoutRequestBody struct {
+	HoldID          string     `json:"hold_id" binding:"required_without=ResaleListingID,excluded_with=ResaleListingID"`
+	ResaleListingID *uuid.UUID `json:"resale_listing_id" binding:"required_without=HoldID"`
+	// PromoCodes are applied in the given order. Only stackable codes can be combined and
+	// resale listings cannot be discounted.
+	PromoCodes []string `json:"promo_codes" binding:"excluded_with=ResaleListingID"`
+	// Billing is who the invoice is made out to, your profile when omitted
+	Billing *BillingDetailsRequestBody `json:"billing,omitempty"`
+	// Attendees answer the attendee questions of the event, one entry per ticket in the
+	// order of the tickets of each ticket type. Resale purchases are not asked the questions.
+	Attendees []AttendeeRequestBody `json:"attendees" binding:"excluded_with=ResaleListingID,dive"`
+}
+
+type BillingDetailsRequestBody struct {
+	Name    string `json:"name" binding:"required_without=Company"`
+	Company string `json:"company"`
+	Address string `json:"address"`
+	TaxID   string `json:"tax_id"` // VAT number of corporate buyers
+	Email   string `json:"email" binding:"omitempty,email"`
+}
+
+// CheckoutHandler godoc
+// @Summary      Check out a hold or a resale listing
+// @Description  Convert one of your active holds, or a ticket offered on the resale marketplace, into a pending order. Promo codes discount the tickets they apply to and the answers to the attendee questions of the event are stored with the order. While the event is behind an open waiting room the admission token from the queue is required. Orders that look automated are held for review by an admin before they can be paid. Fails if the hold has expired, the listing was taken, a promo code cannot be redeemed or a required question is unanswered
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        checkout body CheckoutRequestBody true "Hold or resale listing to check out"
+// @Param        X-Admission-Token header string false "Admission token from the waiting room of the event"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      410 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/checkout [post]
+func (s *Server) CheckoutHandler(c *gin.Context) {
+	var input CheckoutRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.OrderInvalidRequest, err.Error())
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	if input.ResaleListingID != nil {
+		order, err := s.resaleService.Checkout(c, user.ID, *input.ResaleListingID, c.ClientIP())
+		if err != nil {
+			respondResaleError(c, err, "Failed to check out")
+			return
+		}
+		c.JSON(http.StatusOK, PassItResponseBody{
+			Code: codes.OrderCreatedSuccessfully,
+			Data: order,
+		})
+		return
+	}
+
+	// Unknown or expired holds fall through to the checkout errors
+	if hold, err := s.holdService.GetHold(c, input.HoldID, user.ID); err == nil {
+		if err := s.waitingRoomService.CheckHoldAdmission(c, hold, c.GetHeader(admissionTokenHeader)); err != nil {
+			respondWaitingRoomError(c, err, "Failed to check admission")
+			return
+		}
+	}
+
+	opts := services.CheckoutOptions{
+		PromoCodes: input.PromoCodes,
+		Attendees:  attendeeAnswersByTicketType(input.Attendees),
+		IP:         c.ClientIP(),
+	}
+	if input.Billing != nil {
+		opts.Billing = &models.BillingDetails{
+			Name:    input.Billing.Name,
+			Company: input.Billing.Company,
+			Address: input.Billing.Address,
+			TaxID:   input.Billing.TaxID,
+			Email:   input.Billing.Email,
+		}
+	}
+	order, err := s.orderService.Checkout(c, user.ID, input.HoldID, opts)
+	if err != nil {
+		respondOrderError(c, err, "Failed to check out")
+		return
+	}
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.OrderCreatedSuccessfully,
+		Data: order,
+	})
+}
+
+// ListMyOrdersHandler godoc
+// @Summary      List my orders
+// @Description  Retrieve the orders of the authenticated user, newest first
+// @Tags         orders
+// @Produce      json
+// @Success      200 {array} models.Order
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orders [get]
+func (s *Server) ListMyOrdersHandler(c *gin.Context) {
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	orders, err := s.orderService.ListUserOrders(c, user.ID)
+	if err != nil {
+		respondOrderError(c, err, "Failed to retrieve orders")
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// GetOrderHandler godoc
+// @Summary      Get order by ID
+// @Description  Retrieve one of your orders with its line items. Staff with orders:read can retrieve any order
+// @Tags         orders
+// @Produce      json
+// @Param        id path string true "Order ID"
+// @Success      200 {object} models.Order
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orders/{id} [get]
+func (s *Server) GetOrderHandler(c *gin.Context) {
+	orderID, ok := orderIDParam(c)
+	if !ok {
+		return
+	}
+
+	var (
+		order models.Order
+		err   error
+	)
+	if hasPermission(c, models.PermOrdersRead) {
+		order, err = s.orderService.GetOrder(c, orderID)
+	} else {
+		user, ok := s.currentUser(c)
+		if !ok {
+			return
+		}
+		order, err = s.orderService.GetUserOrder(c, orderID, user.ID)
+	}
+	if err != nil {
+		respondOrderError(c, err, "Failed to retrieve order")
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// CancelOrderHandler godoc
+// @Summary      Cancel order
+// @Description  Cancel one of your unpaid orders and give its tickets back to sale
+// @Tags         orders
+// @Produce      json
+// @Param        id path string true "Order ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orders/{id}/cancel [post]
+func (s *Server) CancelOrderHandler(c *gin.Context) {
+	orderID, ok := orderIDParam(c)
+	if !ok {
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	order, err := s.orderService.CancelOrder(c, orderID, user.ID)
+	if err != nil {
+		respondOrderError(c, err, "Failed to cancel order")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.OrderCancelledSuccessfully,
+		Data: order,
+	})
+}
+
+// orderIDParam reads the order ID from the URL path and writes a coded 400 response if it is malformed
+func orderIDParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.OrderInvalidRequest, "invalid UUID format")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondOrderError maps order service errors onto coded HTTP responses
+func respondOrderError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrOrderNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.OrderNotFound, "Order not found")
+	case errors.Is(err, store.ErrHoldNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.OrderHoldNotFound, "Hold not found")
+	case errors.Is(err, store.ErrHoldExpired):
+		respondWithCode(c, http.StatusGone, codes.OrderHoldExpired, err.Error())
+	case errors.Is(err, services.ErrOrderForbidden),
+		errors.Is(err, services.ErrHoldForbidden):
+		respondWithCode(c, http.StatusForbidden, codes.OrderForbidden, err.Error())
+	case errors.Is(err, models.ErrOrderInvalidStatus),
+		errors.Is(err, services.ErrOrderConflict):
+		respondWithCode(c, http.StatusConflict, codes.OrderInvalidTransition, err.Error())
+	case errors.Is(err, services.ErrTicketTypeNotFound),
+		errors.Is(err, models.ErrOrderEmpty),
+		errors.Is(err, models.ErrOrderMixedCurrency),
+		errors.Is(err, models.ErrOrderInvalidLineItems):
+		respondWithCode(c, http.StatusBadRequest, codes.OrderInvalidItems, err.Error())
+	case errors.Is(err, services.ErrPromoCodeNotFound),
+		errors.Is(err, models.ErrPromoCodeRequired),
+		errors.Is(err, models.ErrPromoCodeNotValid),
+		errors.Is(err, models.ErrPromoCodeNotApplicable),
+		errors.Is(err, models.ErrPromoCodeNotStackable),
+		errors.Is(err, models.ErrPromoCodeDuplicate):
+		respondWithCode(c, http.StatusBadRequest, codes.PromoCodeNotApplicable, err.Error())
+	case errors.Is(err, models.ErrPromoCodeExhausted),
+		errors.Is(err, models.ErrPromoCodeUserLimit):
+		respondWithCode(c, http.StatusConflict, codes.PromoCodeExhausted, err.Error())
+	case errors.Is(err, models.ErrAttendeeAnswerInvalid):
+		respondWithCode(c, http.StatusBadRequest, codes.AttendeeAnswersInvalid, err.Error())
+	case errors.Is(err, models.ErrOrderUnderReview):
+		respondWithCode(c, http.StatusConflict, codes.OrderUnderReview, err.Error())
+	case errors.Is(err, store.ErrPurchaseLimitExceeded):
+		respondWithCode(c, http.StatusConflict, codes.PurchaseLimitExceeded, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.OrderInternalError, fallback)
+	}
+}
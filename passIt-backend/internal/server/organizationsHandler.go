@@ -0,0 +1,463 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateOrganizationRequestBody struct {
+	Name string `json:"name" binding:"required"`
+	// Slug is derived from the name when empty
+	Slug string `json:"slug"`
+	// OwnerEmail is the user who becomes the first owner of the organization
+	OwnerEmail string `json:"owner_email" binding:"required,email"`
+}
+
+type AddOrgMemberRequestBody struct {
+	Email string `json:"email" binding:"required,email"`
+	// Role is owner, manager, box_office or scanner
+	Role string `json:"role" binding:"required"`
+}
+
+type UpdateOrgMemberRequestBody struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// CreateOrganizationHandler godoc
+// @Summary      Create an organization (organizations:write)
+// @Description  Register an organizer and make an existing user its first owner
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Param        organization body CreateOrganizationRequestBody true "Organization data"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/organizations [post]
+func (s *Server) CreateOrganizationHandler(c *gin.Context) {
+	var input CreateOrganizationRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.OrganizationInvalidRequest, err.Error())
+		return
+	}
+
+	org := models.Organization{Name: input.Name, Slug: input.Slug}
+	if err := s.organizationService.CreateOrganization(c, &org, input.OwnerEmail); err != nil {
+		respondOrganizationError(c, err, "Failed to create organization")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.OrganizationCreated,
+		Data: org,
+	})
+}
+
+// ListOrganizationsHandler godoc
+// @Summary      List organizations (organizations:write)
+// @Description  Retrieve every organization by name
+// @Tags         organizations
+// @Produce      json
+// @Success      200 {object} PassItResponseBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/organizations [get]
+func (s *Server) ListOrganizationsHandler(c *gin.Context) {
+	orgs, err := s.organizationService.ListOrganizations(c)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to retrieve organizations")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.OrganizationsRetrieved,
+		Data: orgs,
+	})
+}
+
+// ListMyOrganizationsHandler godoc
+// @Summary      List my organizations
+// @Description  Retrieve the organizations the current user is a member of, with their role in each
+// @Tags         organizations
+// @Produce      json
+// @Success      200 {object} PassItResponseBody
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/users/me/organizations [get]
+func (s *Server) ListMyOrganizationsHandler(c *gin.Context) {
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	memberships, err := s.organizationService.ListUserMemberships(c, user.ID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to retrieve organizations")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.OrganizationsRetrieved,
+		Data: memberships,
+	})
+}
+
+// ListOrgMembersHandler godoc
+// @Summary      List organization members
+// @Description  Retrieve the members of an organization with their roles. Requires a role that may manage members
+// @Tags         organizations
+// @Produce      json
+// @Param        orgId path string true "Organization ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} map[string]string
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orgs/{orgId}/members [get]
+func (s *Server) ListOrgMembersHandler(c *gin.Context) {
+	orgID, ok := parseOrgIDParam(c)
+	if !ok {
+		return
+	}
+
+	members, err := s.organizationService.ListMembers(c, orgID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to retrieve members")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.OrganizationMembersRetrieved,
+		Data: members,
+	})
+}
+
+// AddOrgMemberHandler godoc
+// @Summary      Add an organization member
+// @Description  Give an existing user a role in the organization. Requires a role that may manage members
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Param        orgId path string true "Organization ID"
+// @Param        member body AddOrgMemberRequestBody true "Member data"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orgs/{orgId}/members [post]
+func (s *Server) AddOrgMemberHandler(c *gin.Context) {
+	orgID, ok := parseOrgIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input AddOrgMemberRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.OrganizationInvalidRequest, err.Error())
+		return
+	}
+
+	membership, err := s.organizationService.AddMember(c, orgID, input.Email, models.OrgRole(input.Role))
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to add member")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.OrganizationMemberAdded,
+		Data: membership,
+	})
+}
+
+// UpdateOrgMemberHandler godoc
+// @Summary      Change the role of an organization member
+// @Description  Requires a role that may manage members. The last owner cannot be demoted
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Param        orgId path string true "Organization ID"
+// @Param        userId path string true "User ID"
+// @Param        member body UpdateOrgMemberRequestBody true "New role"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orgs/{orgId}/members/{userId} [put]
+func (s *Server) UpdateOrgMemberHandler(c *gin.Context) {
+	orgID, ok := parseOrgIDParam(c)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.OrganizationInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	var input UpdateOrgMemberRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.OrganizationInvalidRequest, err.Error())
+		return
+	}
+
+	membership, err := s.organizationService.UpdateMemberRole(c, orgID, userID, models.OrgRole(input.Role))
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to update member")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.OrganizationMemberUpdated,
+		Data: membership,
+	})
+}
+
+// RemoveOrgMemberHandler godoc
+// @Summary      Remove an organization member
+// @Description  Requires a role that may manage members. The last owner cannot be removed
+// @Tags         organizations
+// @Produce      json
+// @Param        orgId path string true "Organization ID"
+// @Param        userId path string true "User ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orgs/{orgId}/members/{userId} [delete]
+func (s *Server) RemoveOrgMemberHandler(c *gin.Context) {
+	orgID, ok := parseOrgIDParam(c)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.OrganizationInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	if err := s.organizationService.RemoveMember(c, orgID, userID); err != nil {
+		respondOrganizationError(c, err, "Failed to remove member")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.OrganizationMemberRemoved,
+		Data: gin.H{"organization_id": orgID, "user_id": userID},
+	})
+}
+
+// ListOrgEventsHandler godoc
+// @Summary      List organization events
+// @Description  Retrieve the events of an organization, drafts and cancelled events included. Requires a role that may manage events
+// @Tags         organizations
+// @Produce      json
+// @Param        orgId path string true "Organization ID"
+// @Param        status query string false "Status filter (draft, published or cancelled)"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} map[string]string
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orgs/{orgId}/events [get]
+func (s *Server) ListOrgEventsHandler(c *gin.Context) {
+	orgID, ok := parseOrgIDParam(c)
+	if !ok {
+		return
+	}
+
+	var statuses []models.EventStatus
+	if status := c.Query("status"); status != "" {
+		switch models.EventStatus(status) {
+		case models.EventStatusDraft, models.EventStatusPublished, models.EventStatusCancelled:
+			statuses = []models.EventStatus{models.EventStatus(status)}
+		default:
+			respondWithCode(c, http.StatusBadRequest, codes.OrganizationInvalidRequest, "invalid status filter")
+			return
+		}
+	}
+
+	events, err := s.organizationService.ListEvents(c, orgID, statuses...)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to retrieve events")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.OrganizationEventsRetrieved,
+		Data: events,
+	})
+}
+
+// CreateOrgEventHandler godoc
+// @Summary      Create an organization event
+// @Description  Create a draft event owned by the organization. Requires a role that may manage events
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Param        orgId path string true "Organization ID"
+// @Param        event body CreateEventRequestBody true "Event data"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/orgs/{orgId}/events [post]
+func (s *Server) CreateOrgEventHandler(c *gin.Context) {
+	orgID, ok := parseOrgIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input CreateEventRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+	// Admins pass the middleware for organizations that do not exist
+	if _, err := s.organizationService.GetOrganization(c, orgID); err != nil {
+		respondOrganizationError(c, err, "Failed to retrieve organization")
+		return
+	}
+
+	event := input.toModel(user.ID)
+	event.OrganizationID = &orgID
+	if err := s.eventService.CreateEvent(c, &event); err != nil {
+		respondEventError(c, err, "Failed to create event")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.OrganizationEventCreated,
+		Data: event,
+	})
+}
+
+// ListOrgOrdersHandler godoc
+// @Summary      List organization orders
+// @Description  Retrieve the orders of the events of an organization, newest first. Requires a role that may view orders
+// @Tags         organizations
+// @Produce      json
+// @Param        orgId path string true "Organization ID"
+// @Param        event_id query string false "Only orders of this event"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} map[string]string
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orgs/{orgId}/orders [get]
+func (s *Server) ListOrgOrdersHandler(c *gin.Context) {
+	orgID, ok := parseOrgIDParam(c)
+	if !ok {
+		return
+	}
+
+	var eventID *uuid.UUID
+	if raw := c.Query("event_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			respondWithCode(c, http.StatusBadRequest, codes.OrganizationInvalidRequest, "invalid event_id")
+			return
+		}
+		eventID = &id
+	}
+
+	orders, err := s.organizationService.ListOrders(c, orgID, eventID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to retrieve orders")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.OrganizationOrdersRetrieved,
+		Data: orders,
+	})
+}
+
+// GetOrgSalesReportHandler godoc
+// @Summary      Organization sales report
+// @Description  Sum up the paid orders, tickets sold, gross revenue and refunds of every event of an organization, per currency. Requires a role that may view reports
+// @Tags         organizations
+// @Produce      json
+// @Param        orgId path string true "Organization ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} map[string]string
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orgs/{orgId}/reports/sales [get]
+func (s *Server) GetOrgSalesReportHandler(c *gin.Context) {
+	orgID, ok := parseOrgIDParam(c)
+	if !ok {
+		return
+	}
+
+	report, err := s.organizationService.SalesReport(c, orgID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to build sales report")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.OrganizationReportRetrieved,
+		Data: report,
+	})
+}
+
+// parseOrgIDParam reads the organization ID from the URL path and writes a 400 response if it is malformed
+func parseOrgIDParam(c *gin.Context) (uuid.UUID, bool) {
+	orgID, err := uuid.Parse(c.Param("orgId"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.OrganizationInvalidRequest, "invalid UUID format")
+		return uuid.Nil, false
+	}
+	return orgID, true
+}
+
+// respondOrganizationError maps organization errors onto coded HTTP responses
+func respondOrganizationError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrOrganizationNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.OrganizationNotFound, "Organization not found")
+	case errors.Is(err, services.ErrOrgUserNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.OrganizationInvalidRequest, err.Error())
+	case errors.Is(err, services.ErrOrgMemberNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.OrganizationMemberNotFound, err.Error())
+	case errors.Is(err, services.ErrOrgSlugTaken),
+		errors.Is(err, services.ErrOrgMemberExists):
+		respondWithCode(c, http.StatusConflict, codes.OrganizationConflict, err.Error())
+	case errors.Is(err, models.ErrOrgLastOwner):
+		respondWithCode(c, http.StatusConflict, codes.OrganizationLastOwner, err.Error())
+	case errors.Is(err, models.ErrOrgNameRequired),
+		errors.Is(err, models.ErrOrgInvalidSlug),
+		errors.Is(err, models.ErrOrgInvalidRole):
+		respondWithCode(c, http.StatusBadRequest, codes.OrganizationInvalidRequest, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.OrganizationInternalError, fallback)
+	}
+}
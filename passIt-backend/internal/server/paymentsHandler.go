@@ -0,0 +1,113 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/payments"
+	"passIt/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PayOrderRequestBody struct {
+	PaymentMethod string `json:"payment_method"`
+}
+
+// PayOrderHandler godoc
+// @Summary      Pay for an order
+// @Description  Start or retry the payment of one of your unpaid orders. The order becomes paid once the payment provider confirms it through the webhook. Orders held for review cannot be paid until an admin approves them, and the event may limit how many tickets one payment method buys
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Order ID"
+// @Param        payment body PayOrderRequestBody false "Payment method"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      502 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orders/{id}/pay [post]
+func (s *Server) PayOrderHandler(c *gin.Context) {
+	orderID, ok := orderIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input PayOrderRequestBody
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			respondWithCode(c, http.StatusBadRequest, codes.PaymentInvalidRequest, err.Error())
+			return
+		}
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	payment, err := s.paymentService.PayOrder(c, orderID, user.ID, input.PaymentMethod)
+	if err != nil {
+		respondPaymentError(c, err, "Failed to pay for order")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.PaymentStartedSuccessfully,
+		Data: payment,
+	})
+}
+
+// PaymentWebhookHandler godoc
+// @Summary      Payment provider webhook
+// @Description  Receive signed payment notifications from the payment provider
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Param        PassIt-Signature header string true "Webhook signature"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Router       /webhooks/payments [post]
+func (s *Server) PaymentWebhookHandler(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.PaymentInvalidRequest, "failed to read request body")
+		return
+	}
+
+	if err := s.paymentService.HandleWebhook(c, payload, c.GetHeader(payments.SignatureHeader)); err != nil {
+		respondPaymentError(c, err, "Failed to process webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.WebhookProcessedSuccessfully,
+		Data: gin.H{"received": true},
+	})
+}
+
+// respondPaymentError maps payment service errors onto coded HTTP responses,
+// falling back to the order errors for everything else
+func respondPaymentError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, payments.ErrInvalidSignature):
+		respondWithCode(c, http.StatusBadRequest, codes.PaymentInvalidSignature, err.Error())
+	case errors.Is(err, services.ErrPaymentNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.PaymentNotFound, "Payment not found")
+	case errors.Is(err, services.ErrPaymentInProgress):
+		respondWithCode(c, http.StatusConflict, codes.PaymentInProgress, err.Error())
+	case errors.Is(err, services.ErrPaymentAmountMismatch):
+		respondWithCode(c, http.StatusBadRequest, codes.PaymentInvalidRequest, err.Error())
+	case errors.Is(err, services.ErrPaymentProvider):
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusBadGateway, codes.PaymentProviderError, "Payment provider error")
+	default:
+		respondOrderError(c, err, fallback)
+	}
+}
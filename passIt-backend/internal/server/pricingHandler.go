@@ -0,0 +1,225 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateFeeRuleRequestBody struct {
+	// EventID limits the fee to one event, whose fees then replace the global ones
+	EventID        *uuid.UUID `json:"event_id,omitempty"`
+	Name           string     `json:"name" binding:"required"`
+	Currency       string     `json:"currency" binding:"required"`
+	FixedPerTicket int64      `json:"fixed_per_ticket"` // in minor units
+	BasisPoints    int64      `json:"basis_points"`     // 250 is 2.5% of the ticket price
+}
+
+type CreateTaxRateRequestBody struct {
+	Jurisdiction string `json:"jurisdiction" binding:"required"` // matches the tax_jurisdiction of venues
+	Name         string `json:"name" binding:"required"`
+	BasisPoints  int64  `json:"basis_points"` // 1900 is 19%
+	OnFees       bool   `json:"on_fees"`      // also tax the service fees
+}
+
+// ListFeeRulesHandler godoc
+// @Summary      List service fees (pricing:write)
+// @Description  Retrieve the global service fees followed by the fees of single events
+// @Tags         pricing
+// @Produce      json
+// @Success      200 {object} PassItResponseBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/fees [get]
+func (s *Server) ListFeeRulesHandler(c *gin.Context) {
+	fees, err := s.pricingService.ListFeeRules(c)
+	if err != nil {
+		respondPricingError(c, err, "Failed to retrieve fee rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.FeeRulesRetrieved,
+		Data: fees,
+	})
+}
+
+// CreateFeeRuleHandler godoc
+// @Summary      Create a service fee (pricing:write)
+// @Description  Charge a fixed amount and/or a percentage per ticket on orders in a currency, for every event or for one event only. Free tickets carry no fees
+// @Tags         pricing
+// @Accept       json
+// @Produce      json
+// @Param        fee body CreateFeeRuleRequestBody true "Fee data"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/fees [post]
+func (s *Server) CreateFeeRuleHandler(c *gin.Context) {
+	var input CreateFeeRuleRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.PricingInvalidRequest, err.Error())
+		return
+	}
+
+	fee := models.FeeRule{
+		EventID:        input.EventID,
+		Name:           input.Name,
+		Currency:       input.Currency,
+		FixedPerTicket: input.FixedPerTicket,
+		BasisPoints:    input.BasisPoints,
+	}
+	if err := s.pricingService.CreateFeeRule(c, &fee); err != nil {
+		respondPricingError(c, err, "Failed to create fee rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.FeeRuleCreated,
+		Data: fee,
+	})
+}
+
+// DeleteFeeRuleHandler godoc
+// @Summary      Delete a service fee (pricing:write)
+// @Description  Stop charging a service fee. Orders already placed keep their fees
+// @Tags         pricing
+// @Produce      json
+// @Param        id path string true "Fee rule ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/fees/{id} [delete]
+func (s *Server) DeleteFeeRuleHandler(c *gin.Context) {
+	feeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.PricingInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	if err := s.pricingService.DeleteFeeRule(c, feeID); err != nil {
+		respondPricingError(c, err, "Failed to delete fee rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.FeeRuleDeleted,
+		Data: gin.H{"id": feeID},
+	})
+}
+
+// ListTaxRatesHandler godoc
+// @Summary      List tax rates (pricing:write)
+// @Description  Retrieve the tax rates of every jurisdiction
+// @Tags         pricing
+// @Produce      json
+// @Success      200 {object} PassItResponseBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/tax-rates [get]
+func (s *Server) ListTaxRatesHandler(c *gin.Context) {
+	taxes, err := s.pricingService.ListTaxRates(c)
+	if err != nil {
+		respondPricingError(c, err, "Failed to retrieve tax rates")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.TaxRatesRetrieved,
+		Data: taxes,
+	})
+}
+
+// CreateTaxRateHandler godoc
+// @Summary      Create a tax rate (pricing:write)
+// @Description  Charge a tax on tickets of events at venues in a jurisdiction. A jurisdiction can have several rates, e.g. a state and a city tax
+// @Tags         pricing
+// @Accept       json
+// @Produce      json
+// @Param        tax body CreateTaxRateRequestBody true "Tax rate data"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/tax-rates [post]
+func (s *Server) CreateTaxRateHandler(c *gin.Context) {
+	var input CreateTaxRateRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.PricingInvalidRequest, err.Error())
+		return
+	}
+
+	tax := models.TaxRate{
+		Jurisdiction: input.Jurisdiction,
+		Name:         input.Name,
+		BasisPoints:  input.BasisPoints,
+		OnFees:       input.OnFees,
+	}
+	if err := s.pricingService.CreateTaxRate(c, &tax); err != nil {
+		respondPricingError(c, err, "Failed to create tax rate")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.TaxRateCreated,
+		Data: tax,
+	})
+}
+
+// DeleteTaxRateHandler godoc
+// @Summary      Delete a tax rate (pricing:write)
+// @Description  Stop charging a tax. Orders already placed keep their taxes
+// @Tags         pricing
+// @Produce      json
+// @Param        id path string true "Tax rate ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/tax-rates/{id} [delete]
+func (s *Server) DeleteTaxRateHandler(c *gin.Context) {
+	taxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.PricingInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	if err := s.pricingService.DeleteTaxRate(c, taxID); err != nil {
+		respondPricingError(c, err, "Failed to delete tax rate")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.TaxRateDeleted,
+		Data: gin.H{"id": taxID},
+	})
+}
+
+// respondPricingError maps fee and tax errors onto coded HTTP responses
+func respondPricingError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrFeeRuleNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.FeeRuleNotFound, "Fee rule not found")
+	case errors.Is(err, services.ErrTaxRateNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.TaxRateNotFound, "Tax rate not found")
+	case errors.Is(err, services.ErrEventNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.PricingInvalidRequest, "Event not found")
+	case errors.Is(err, models.ErrFeeRuleNameRequired),
+		errors.Is(err, models.ErrFeeRuleInvalid),
+		errors.Is(err, models.ErrFeeRuleCurrency),
+		errors.Is(err, models.ErrTaxRateInvalid):
+		respondWithCode(c, http.StatusBadRequest, codes.PricingInvalidRequest, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.PricingInternalError, fallback)
+	}
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PromoCodeRequestBody struct {
+	Code         string              `json:"code" binding:"required"`
+	Description  string              `json:"description"`
+	DiscountType models.DiscountType `json:"discount_type" binding:"required,oneof=percentage fixed"`
+	PercentOff   int                 `json:"percent_off"` // 1-100 for percentage codes
+	AmountOff    int64               `json:"amount_off"`  // in minor units, for fixed codes
+	Currency     string              `json:"currency"`    // required for fixed codes
+	// EventIDs and TicketTypeIDs limit the code to some events or ticket types. Leave empty for all.
+	EventIDs       []uuid.UUID `json:"event_ids"`
+	TicketTypeIDs  []uuid.UUID `json:"ticket_type_ids"`
+	MaxRedemptions int         `json:"max_redemptions"` // 0 means unlimited
+	MaxPerUser     int         `json:"max_per_user"`    // 0 means unlimited
+	StartsAt       *time.Time  `json:"starts_at,omitempty"`
+	EndsAt         *time.Time  `json:"ends_at,omitempty"`
+	Stackable      bool        `json:"stackable"`
+	// Active defaults to true
+	Active *bool `json:"active,omitempty"`
+}
+
+func (input PromoCodeRequestBody) toModel() models.PromoCode {
+	return models.PromoCode{
+		Code:           input.Code,
+		Description:    input.Description,
+		DiscountType:   input.DiscountType,
+		PercentOff:     input.PercentOff,
+		AmountOff:      input.AmountOff,
+		Currency:       input.Currency,
+		EventIDs:       input.EventIDs,
+		TicketTypeIDs:  input.TicketTypeIDs,
+		MaxRedemptions: input.MaxRedemptions,
+		MaxPerUser:     input.MaxPerUser,
+		StartsAt:       input.StartsAt,
+		EndsAt:         input.EndsAt,
+		Stackable:      input.Stackable,
+		Active:         input.Active == nil || *input.Active,
+	}
+}
+
+// ListPromoCodesHandler godoc
+// @Summary      List promo codes (promo_codes:write)
+// @Description  Retrieve every promo code with its redemption count, newest first
+// @Tags         promo-codes
+// @Produce      json
+// @Success      200 {object} PassItResponseBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/promo-codes [get]
+func (s *Server) ListPromoCodesHandler(c *gin.Context) {
+	promoCodes, err := s.promoCodeService.ListPromoCodes(c)
+	if err != nil {
+		respondPromoCodeError(c, err, "Failed to retrieve promo codes")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.PromoCodesRetrieved,
+		Data: promoCodes,
+	})
+}
+
+// CreatePromoCodeHandler godoc
+// @Summary      Create a promo code (promo_codes:write)
+// @Description  Add a percentage or fixed amount discount, optionally limited to some events or ticket types, a validity window and a number of uses overall and per user. Only stackable codes can be combined at checkout
+// @Tags         promo-codes
+// @Accept       json
+// @Produce      json
+// @Param        promoCode body PromoCodeRequestBody true "Promo code data"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/promo-codes [post]
+func (s *Server) CreatePromoCodeHandler(c *gin.Context) {
+	var input PromoCodeRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.PromoCodeInvalidRequest, err.Error())
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	promoCode := input.toModel()
+	promoCode.CreatedByID = user.ID
+	if err := s.promoCodeService.CreatePromoCode(c, &promoCode); err != nil {
+		respondPromoCodeError(c, err, "Failed to create promo code")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.PromoCodeCreated,
+		Data: promoCode,
+	})
+}
+
+// GetPromoCodeHandler godoc
+// @Summary      Get a promo code (promo_codes:write)
+// @Description  Retrieve a promo code with its redemption count
+// @Tags         promo-codes
+// @Produce      json
+// @Param        id path string true "Promo code ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/promo-codes/{id} [get]
+func (s *Server) GetPromoCodeHandler(c *gin.Context) {
+	promoCodeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.PromoCodeInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	promoCode, err := s.promoCodeService.GetPromoCode(c, promoCodeID)
+	if err != nil {
+		respondPromoCodeError(c, err, "Failed to retrieve promo code")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.PromoCodeRetrieved,
+		Data: promoCode,
+	})
+}
+
+// UpdatePromoCodeHandler godoc
+// @Summary      Update a promo code (promo_codes:write)
+// @Description  Replace the settings of a promo code. Orders that already used it keep their discount, and lowering a limit below the current redemptions only stops new ones
+// @Tags         promo-codes
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Promo code ID"
+// @Param        promoCode body PromoCodeRequestBody true "Promo code data"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/promo-codes/{id} [put]
+func (s *Server) UpdatePromoCodeHandler(c *gin.Context) {
+	promoCodeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.PromoCodeInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	var input PromoCodeRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.PromoCodeInvalidRequest, err.Error())
+		return
+	}
+
+	promoCode := input.toModel()
+	promoCode.ID = promoCodeID
+	if err := s.promoCodeService.UpdatePromoCode(c, &promoCode); err != nil {
+		respondPromoCodeError(c, err, "Failed to update promo code")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.PromoCodeUpdated,
+		Data: promoCode,
+	})
+}
+
+// DeletePromoCodeHandler godoc
+// @Summary      Delete a promo code (promo_codes:write)
+// @Description  Remove a promo code so it can no longer be redeemed. Orders that already used it keep their discount
+// @Tags         promo-codes
+// @Produce      json
+// @Param        id path string true "Promo code ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/promo-codes/{id} [delete]
+func (s *Server) DeletePromoCodeHandler(c *gin.Context) {
+	promoCodeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.PromoCodeInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	if err := s.promoCodeService.DeletePromoCode(c, promoCodeID); err != nil {
+		respondPromoCodeError(c, err, "Failed to delete promo code")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.PromoCodeDeleted,
+		Data: gin.H{"id": promoCodeID},
+	})
+}
+
+// respondPromoCodeError maps promo code errors onto coded HTTP responses
+func respondPromoCodeError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrPromoCodeNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.PromoCodeNotFound, "Promo code not found")
+	case errors.Is(err, services.ErrPromoCodeExists):
+		respondWithCode(c, http.StatusConflict, codes.PromoCodeConflict, err.Error())
+	case errors.Is(err, models.ErrPromoCodeRequired),
+		errors.Is(err, models.ErrPromoCodeInvalidType),
+		errors.Is(err, models.ErrPromoCodeInvalidPercent),
+		errors.Is(err, models.ErrPromoCodeInvalidAmount),
+		errors.Is(err, models.ErrPromoCodeInvalidLimits),
+		errors.Is(err, models.ErrPromoCodeInvalidWindow):
+		respondWithCode(c, http.StatusBadRequest, codes.PromoCodeInvalidRequest, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.PromoCodeInternalError, fallback)
+	}
+}
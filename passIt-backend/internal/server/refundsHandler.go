@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RefundItemRequestBody struct {
+	OrderItemID string `json:"order_item_id" binding:"required"`
+	Quantity    int    `json:"quantity" binding:"required,min=1"`
+}
+
+type RefundOrderRequestBody struct {
+	// Items to refund; leave empty to refund every ticket left on the order
+	Items  []RefundItemRequestBody `json:"items" binding:"dive"`
+	Reason string                  `json:"reason"`
+	// Restock gives the refunded tickets back to sale, defaults to true
+	Restock *bool `json:"restock"`
+}
+
+type EventRefundsRequestBody struct {
+	Reason string `json:"reason"`
+}
+
+// RefundOrderHandler godoc
+// @Summary      Refund an order (Admin or organization member)
+// @Description  Refund some or all tickets of a paid order through the payment provider
+// @Tags         refunds
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Order ID"
+// @Param        refund body RefundOrderRequestBody false "Tickets to refund"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      502 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orders/{id}/refunds [post]
+func (s *Server) RefundOrderHandler(c *gin.Context) {
+	orderID, ok := orderIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input RefundOrderRequestBody
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			respondWithCode(c, http.StatusBadRequest, codes.RefundInvalidRequest, err.Error())
+			return
+		}
+	}
+
+	request := services.RefundRequest{
+		Quantities: make(map[uuid.UUID]int, len(input.Items)),
+		Reason:     input.Reason,
+		Restock:    input.Restock == nil || *input.Restock,
+	}
+	for _, item := range input.Items {
+		itemID, err := uuid.Parse(item.OrderItemID)
+		if err != nil {
+			respondWithCode(c, http.StatusBadRequest, codes.RefundInvalidRequest, "Invalid order item ID")
+			return
+		}
+		request.Quantities[itemID] += item.Quantity
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+	request.RequestedByID = &user.ID
+
+	refund, err := s.refundService.RefundOrder(c, orderID, request)
+	if err != nil {
+		respondRefundError(c, err, "Failed to refund order")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.RefundCreatedSuccessfully,
+		Data: refund,
+	})
+}
+
+// ListOrderRefundsHandler godoc
+// @Summary      List order refunds (Admin or organization member)
+// @Description  Get the refund transactions of an order
+// @Tags         refunds
+// @Produce      json
+// @Param        id path string true "Order ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orders/{id}/refunds [get]
+func (s *Server) ListOrderRefundsHandler(c *gin.Context) {
+	orderID, ok := orderIDParam(c)
+	if !ok {
+		return
+	}
+
+	refunds, err := s.refundService.ListOrderRefunds(c, orderID)
+	if err != nil {
+		respondRefundError(c, err, "Failed to retrieve refunds")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.RefundsRetrievedSuccessfully,
+		Data: refunds,
+	})
+}
+
+// RefundEventOrdersHandler godoc
+// @Summary      Refund a cancelled event (Admin or organization member)
+// @Description  Refund every paid order of a cancelled event. Orders refunded before are skipped, so this can be used to retry failed refunds
+// @Tags         refunds
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        refund body EventRefundsRequestBody false "Refund reason"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/refunds [post]
+func (s *Server) RefundEventOrdersHandler(c *gin.Context) {
+	eventID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input EventRefundsRequestBody
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			respondWithCode(c, http.StatusBadRequest, codes.RefundInvalidRequest, err.Error())
+			return
+		}
+	}
+	if input.Reason == "" {
+		input.Reason = eventCancelledRefundReason
+	}
+
+	summary, err := s.refundService.RefundEventOrders(c, eventID, input.Reason)
+	if err != nil {
+		respondRefundError(c, err, "Failed to refund event orders")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.EventRefundsProcessed,
+		Data: summary,
+	})
+}
+
+const eventCancelledRefundReason = "event cancelled"
+
+// refundCancelledEvent refunds the orders of a cancelled event in the background,
+// since it calls the payment provider once per order
+func (s *Server) refundCancelledEvent(eventID uuid.UUID) {
+	go func() {
+		summary, err := s.refundService.RefundEventOrders(context.Background(), eventID, eventCancelledRefundReason)
+		if err != nil {
+			log.Printf("Failed to refund orders of cancelled event %s: %v", eventID, err)
+			return
+		}
+		log.Printf("Refunded orders of cancelled event %s: %d refunded, %d cancelled, %d failed",
+			eventID, summary.Refunded, summary.Cancelled, summary.Failed)
+	}()
+}
+
+// respondRefundError maps refund service errors onto coded HTTP responses,
+// falling back to the payment and order errors for everything else
+func respondRefundError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrEventNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.RefundEventNotFound, "Event not found")
+	case errors.Is(err, services.ErrRefundEventNotCancelled):
+		respondWithCode(c, http.StatusConflict, codes.RefundEventNotCancelled, err.Error())
+	case errors.Is(err, models.ErrRefundNothingToRefund):
+		respondWithCode(c, http.StatusConflict, codes.RefundNothingToRefund, err.Error())
+	case errors.Is(err, models.ErrRefundExceedsTickets),
+		errors.Is(err, models.ErrRefundUnknownItem):
+		respondWithCode(c, http.StatusBadRequest, codes.RefundExceedsTickets, err.Error())
+	case errors.Is(err, services.ErrRefundPaymentNotCaptured):
+		respondWithCode(c, http.StatusConflict, codes.RefundPaymentNotCaptured, err.Error())
+	default:
+		respondPaymentError(c, err, fallback)
+	}
+}
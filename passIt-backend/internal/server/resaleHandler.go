@@ -0,0 +1,212 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ListTicketForResaleRequestBody struct {
+	Price int64 `json:"price" binding:"required,gt=0"` // in minor units
+}
+
+// ListTicketForResaleHandler godoc
+// @Summary      Sell a ticket
+// @Description  Offer one of your tickets on the resale marketplace. The price cannot exceed the face value plus the event's resale markup, and the platform fee is deducted from your payout. Your QR code stops working while the ticket is listed
+// @Tags         resale
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Ticket ID"
+// @Param        listing body ListTicketForResaleRequestBody true "Asking price"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/tickets/{id}/resale [post]
+func (s *Server) ListTicketForResaleHandler(c *gin.Context) {
+	ticketID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.ResaleInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	var input ListTicketForResaleRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.ResaleInvalidRequest, err.Error())
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	listing, err := s.resaleService.ListTicket(c, ticketID, user.ID, input.Price)
+	if err != nil {
+		respondResaleError(c, err, "Failed to list ticket for resale")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.ResaleListingCreated,
+		Data: listing,
+	})
+}
+
+// CancelResaleListingHandler godoc
+// @Summary      Withdraw a resale listing
+// @Description  Take one of your tickets off the resale marketplace. A new QR code is issued to you
+// @Tags         resale
+// @Produce      json
+// @Param        id path string true "Listing ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/resale/{id} [delete]
+func (s *Server) CancelResaleListingHandler(c *gin.Context) {
+	listingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.ResaleInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	listing, err := s.resaleService.CancelListing(c, listingID, user.ID)
+	if err != nil {
+		respondResaleError(c, err, "Failed to cancel resale listing")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.ResaleListingCancelled,
+		Data: listing,
+	})
+}
+
+// ListEventResaleListingsHandler godoc
+// @Summary      List resale tickets of an event
+// @Description  Get the tickets other fans offer for an event, cheapest first. Buy one by checking out its listing ID
+// @Tags         resale
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/resale [get]
+func (s *Server) ListEventResaleListingsHandler(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.ResaleInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	listings, err := s.resaleService.ListEventListings(c, eventID)
+	if err != nil {
+		respondResaleError(c, err, "Failed to retrieve resale listings")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.ResaleListingsRetrieved,
+		Data: listings,
+	})
+}
+
+// ListMyResaleListingsHandler godoc
+// @Summary      List my resale listings
+// @Description  Get the tickets you offered for resale with their sale and payout status, newest first
+// @Tags         resale
+// @Produce      json
+// @Success      200 {object} PassItResponseBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/users/me/resale [get]
+func (s *Server) ListMyResaleListingsHandler(c *gin.Context) {
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	listings, err := s.resaleService.ListUserListings(c, user.ID)
+	if err != nil {
+		respondResaleError(c, err, "Failed to retrieve resale listings")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.ResaleListingsRetrieved,
+		Data: listings,
+	})
+}
+
+// RetryResalePayoutHandler godoc
+// @Summary      Retry a resale payout
+// @Description  Pay the seller of a sold listing whose payout failed (payouts:write)
+// @Tags         resale
+// @Produce      json
+// @Param        id path string true "Listing ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      502 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/resale/{id}/payout [post]
+func (s *Server) RetryResalePayoutHandler(c *gin.Context) {
+	listingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.ResaleInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	listing, err := s.resaleService.RetryPayout(c, listingID)
+	if err != nil {
+		respondResaleError(c, err, "Failed to pay out resale listing")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.ResalePayoutSent,
+		Data: listing,
+	})
+}
+
+// respondResaleError maps resale service errors onto coded HTTP responses,
+// falling back to the ticket errors for everything else
+func respondResaleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrResaleListingNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.ResaleListingNotFound, "Resale listing not found")
+	case errors.Is(err, services.ErrResaleListingForbidden):
+		respondWithCode(c, http.StatusForbidden, codes.ResaleForbidden, err.Error())
+	case errors.Is(err, models.ErrResalePriceAboveCap),
+		errors.Is(err, models.ErrResaleInvalidPrice),
+		errors.Is(err, models.ErrResaleOwnListing):
+		respondWithCode(c, http.StatusBadRequest, codes.ResaleInvalidRequest, err.Error())
+	case errors.Is(err, models.ErrResaleDisabled):
+		respondWithCode(c, http.StatusConflict, codes.ResaleNotAllowed, err.Error())
+	case errors.Is(err, models.ErrResaleTicketNotAllowed),
+		errors.Is(err, models.ErrResaleListingNotActive),
+		errors.Is(err, services.ErrResalePayoutNotDue):
+		respondWithCode(c, http.StatusConflict, codes.ResaleConflict, err.Error())
+	case errors.Is(err, services.ErrPaymentProvider):
+		respondWithCode(c, http.StatusBadGateway, codes.ResalePayoutFailed, err.Error())
+	default:
+		respondTicketError(c, err, fallback)
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReviewOrderRequestBody struct {
+	// Approve lets the buyer pay the order, otherwise it is cancelled and its tickets go back on sale
+	Approve *bool `json:"approve" binding:"required"`
+}
+
+// ListOrdersForReviewHandler godoc
+// @Summary      List orders awaiting review (Admin)
+// @Description  Retrieve the unpaid orders whose risk score routed them to manual review, oldest first, with the reasons for their score
+// @Tags         orders
+// @Produce      json
+// @Success      200 {object} PassItResponseBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orders/review [get]
+func (s *Server) ListOrdersForReviewHandler(c *gin.Context) {
+	orders, err := s.riskService.ListOrdersForReview(c)
+	if err != nil {
+		respondOrderReviewError(c, err, "Failed to list orders for review")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.OrdersForReviewRetrieved,
+		Data: orders,
+	})
+}
+
+// ReviewOrderHandler godoc
+// @Summary      Review a suspicious order (Admin)
+// @Description  Approve an order awaiting review so the buyer can pay it within a new payment window, or reject it to cancel the order and put its tickets back on sale
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Order ID"
+// @Param        review body ReviewOrderRequestBody true "Decision"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/orders/{id}/review [post]
+func (s *Server) ReviewOrderHandler(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.OrderReviewInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	var input ReviewOrderRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.OrderReviewInvalidRequest, err.Error())
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	order, err := s.riskService.ReviewOrder(c, orderID, user.ID, *input.Approve)
+	if err != nil {
+		respondOrderReviewError(c, err, "Failed to review order")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.OrderReviewed,
+		Data: order,
+	})
+}
+
+// respondOrderReviewError maps order review errors onto coded HTTP responses
+func respondOrderReviewError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrOrderNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.OrderReviewNotFound, "Order not found")
+	case errors.Is(err, models.ErrOrderNotUnderReview),
+		errors.Is(err, models.ErrOrderInvalidStatus),
+		errors.Is(err, services.ErrOrderConflict):
+		respondWithCode(c, http.StatusConflict, codes.OrderNotUnderReview, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.OrderReviewInternalError, fallback)
+	}
+}
@@ -0,0 +1,321 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/middleware"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RoleRequestBody struct {
+	// Name may contain lower case letters, digits, dashes and underscores
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	// Permissions such as users:read or orders:refund, "*" grants all of them
+	Permissions []string `json:"permissions"`
+}
+
+func (b RoleRequestBody) toModel() models.Role {
+	role := models.Role{
+		Name:        b.Name,
+		Description: b.Description,
+		Permissions: make([]models.Permission, 0, len(b.Permissions)),
+	}
+	for _, p := range b.Permissions {
+		role.Permissions = append(role.Permissions, models.Permission(p))
+	}
+	return role
+}
+
+type SetUserRolesRequestBody struct {
+	// Roles replaces every role of the user, an empty list removes them all
+	Roles []string `json:"roles" binding:"required"`
+}
+
+// ListPermissionsHandler godoc
+// @Summary      List permissions (roles:read)
+// @Description  Retrieve every permission a role can be made of
+// @Tags         roles
+// @Produce      json
+// @Success      200 {object} PassItResponseBody
+// @Security     BearerAuth
+// @Router       /api/permissions [get]
+func (s *Server) ListPermissionsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.PermissionsRetrieved,
+		Data: models.AllPermissions,
+	})
+}
+
+// GetMyPermissionsHandler godoc
+// @Summary      List my permissions
+// @Description  Retrieve the permissions granted to the current user by their roles
+// @Tags         roles
+// @Produce      json
+// @Success      200 {object} PassItResponseBody
+// @Security     BearerAuth
+// @Router       /api/users/me/permissions [get]
+func (s *Server) GetMyPermissionsHandler(c *gin.Context) {
+	permissions := middleware.Permissions(c)
+	if permissions == nil {
+		permissions = models.PermissionSet{}
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.PermissionsRetrieved,
+		Data: permissions,
+	})
+}
+
+// ListRolesHandler godoc
+// @Summary      List roles (roles:read)
+// @Description  Retrieve every role with its permissions
+// @Tags         roles
+// @Produce      json
+// @Success      200 {object} PassItResponseBody
+// @Failure      403 {object} map[string]string
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/roles [get]
+func (s *Server) ListRolesHandler(c *gin.Context) {
+	roles, err := s.roleService.ListRoles(c)
+	if err != nil {
+		respondRoleError(c, err, "Failed to retrieve roles")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.RolesRetrieved,
+		Data: roles,
+	})
+}
+
+// GetRoleHandler godoc
+// @Summary      Get a role (roles:read)
+// @Tags         roles
+// @Produce      json
+// @Param        id path string true "Role ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/roles/{id} [get]
+func (s *Server) GetRoleHandler(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.RoleInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	role, err := s.roleService.GetRole(c, roleID)
+	if err != nil {
+		respondRoleError(c, err, "Failed to retrieve role")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.RoleRetrieved,
+		Data: role,
+	})
+}
+
+// CreateRoleHandler godoc
+// @Summary      Create a role (roles:write)
+// @Description  Define a named set of permissions that can be assigned to users
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Param        role body RoleRequestBody true "Role data"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/roles [post]
+func (s *Server) CreateRoleHandler(c *gin.Context) {
+	var input RoleRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.RoleInvalidRequest, err.Error())
+		return
+	}
+
+	role := input.toModel()
+	if err := s.roleService.CreateRole(c, &role); err != nil {
+		respondRoleError(c, err, "Failed to create role")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.RoleCreated,
+		Data: role,
+	})
+}
+
+// UpdateRoleHandler godoc
+// @Summary      Update a role (roles:write)
+// @Description  Rename a custom role or change its permissions. Users holding the role get the new permissions on their next request
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Role ID"
+// @Param        role body RoleRequestBody true "Role data"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/roles/{id} [put]
+func (s *Server) UpdateRoleHandler(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.RoleInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	var input RoleRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.RoleInvalidRequest, err.Error())
+		return
+	}
+
+	role := input.toModel()
+	role.ID = roleID
+	if err := s.roleService.UpdateRole(c, &role); err != nil {
+		respondRoleError(c, err, "Failed to update role")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.RoleUpdated,
+		Data: role,
+	})
+}
+
+// DeleteRoleHandler godoc
+// @Summary      Delete a role (roles:write)
+// @Description  Delete a custom role and take it away from every user holding it
+// @Tags         roles
+// @Produce      json
+// @Param        id path string true "Role ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/roles/{id} [delete]
+func (s *Server) DeleteRoleHandler(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.RoleInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	if err := s.roleService.DeleteRole(c, roleID); err != nil {
+		respondRoleError(c, err, "Failed to delete role")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.RoleDeleted,
+		Data: gin.H{"id": roleID},
+	})
+}
+
+// GetUserRolesHandler godoc
+// @Summary      List the roles of a user (roles:read)
+// @Tags         roles
+// @Produce      json
+// @Param        id path string true "User ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/users/{id}/roles [get]
+func (s *Server) GetUserRolesHandler(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.RoleInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	roles, err := s.roleService.GetUserRoles(c, userID)
+	if err != nil {
+		respondRoleError(c, err, "Failed to retrieve user roles")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.UserRolesRetrieved,
+		Data: roles,
+	})
+}
+
+// SetUserRolesHandler godoc
+// @Summary      Set the roles of a user (roles:write)
+// @Description  Replace the roles of a user. The change applies to the user's next request, including running sessions
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "User ID"
+// @Param        roles body SetUserRolesRequestBody true "Role names"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/users/{id}/roles [put]
+func (s *Server) SetUserRolesHandler(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.RoleInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	var input SetUserRolesRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.RoleInvalidRequest, err.Error())
+		return
+	}
+
+	roles, err := s.roleService.SetUserRoles(c, userID, input.Roles)
+	if err != nil {
+		respondRoleError(c, err, "Failed to set user roles")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.UserRolesUpdated,
+		Data: roles,
+	})
+}
+
+// respondRoleError maps role errors onto coded HTTP responses
+func respondRoleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrRoleNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.RoleNotFound, "Role not found")
+	case errors.Is(err, services.ErrRoleUserNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.RoleInvalidRequest, "User not found")
+	case errors.Is(err, services.ErrRoleExists):
+		respondWithCode(c, http.StatusConflict, codes.RoleConflict, err.Error())
+	case errors.Is(err, models.ErrRoleBuiltIn):
+		respondWithCode(c, http.StatusConflict, codes.RoleBuiltIn, err.Error())
+	case errors.Is(err, models.ErrLastAdmin):
+		respondWithCode(c, http.StatusConflict, codes.RoleLastAdmin, err.Error())
+	case errors.Is(err, models.ErrRoleNameRequired),
+		errors.Is(err, models.ErrRoleInvalidName),
+		errors.Is(err, models.ErrRoleUnknownPermission):
+		respondWithCode(c, http.StatusBadRequest, codes.RoleInvalidRequest, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.RoleInternalError, fallback)
+	}
+}
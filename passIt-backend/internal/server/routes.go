@@ -2,12 +2,14 @@ package server
 
 import (
 	"context"
+	"log"
 	"net/http"
 
 	"passIt/internal/auth"
 	"passIt/internal/config"
 	"passIt/internal/handlers"
 	"passIt/internal/middleware"
+	"passIt/internal/models"
 	"passIt/internal/store"
 
 	"github.com/gin-contrib/cors"
@@ -27,6 +29,10 @@ func (s *Server) RegisterRoutes(ctx context.Context, cfg *config.Config, authCli
 	}
 	
 	r := gin.Default()
+	// Client IPs feed the velocity checks, so X-Forwarded-For is only believed from our own proxies
+	if err := r.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
 	r.LoadHTMLGlob("./internal/templates/*.*")
 
 	// No need for authStore - state is in cookies now (simpler!)
@@ -39,13 +45,16 @@ func (s *Server) RegisterRoutes(ctx context.Context, cfg *config.Config, authCli
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{cfg.App.FrontendURL},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type"},
+		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type", queueTokenHeader, admissionTokenHeader},
 		AllowCredentials: true, // Enable cookies/auth
 	}))
 
 	// Public routes - no authentication required
 	r.GET("/", authHandler.ShowLoginPage)
 	r.GET("/health", s.healthHandler)
+
+	// Payment provider webhooks - authenticated by their signature
+	r.POST("/webhooks/payments", s.PaymentWebhookHandler)
 	
 	// Swagger documentation - only in development
 	if cfg.App.ENV == "development" {
@@ -69,17 +78,169 @@ func (s *Server) RegisterRoutes(ctx context.Context, cfg *config.Config, authCli
 		api.GET("/users/me", s.GetCurrentUserHandler) // Get current user profile
 		api.GET("/users/find", s.FindUserByIdHandler)
 		api.GET("/users/by-email", s.FindUserByEmailHandler)
-		
-		// Admin-only endpoints
-		adminAPI := api.Group("")
-		adminAPI.Use(authMiddleware.RequireAdmin()) // Admin-only middleware
+
+		// Event catalog - published events are visible to everyone
+		api.GET("/events", s.ListEventsHandler)
+		api.GET("/events/:id", s.GetEventHandler)
+		api.GET("/events/:id/seats", s.GetEventSeatsHandler)
+		// Organizers follow the availability of their drafts as well
+		api.GET("/events/:id/availability", authMiddleware.RequireOrgAccessUnlessPublished(models.OrgCapManageEvents), s.StreamAvailabilityHandler)
+		api.GET("/series/:id", s.GetSeriesHandler)
+
+		// Waiting room - during high-demand on-sales holds and checkout need an admission from the queue
+		api.POST("/events/:id/queue", s.JoinQueueHandler)
+		api.GET("/events/:id/queue", s.GetQueueStatusHandler)
+
+		// Inventory holds - tickets reserved while the buyer checks out
+		api.POST("/events/:id/holds", s.CreateHoldHandler)
+		api.GET("/holds/:id", s.GetHoldHandler)
+		api.DELETE("/holds/:id", s.ReleaseHoldHandler)
+
+		// Checkout and orders
+		api.POST("/checkout", s.CheckoutHandler)
+		api.GET("/orders", s.ListMyOrdersHandler)
+		api.GET("/orders/:id", s.GetOrderHandler)
+		api.POST("/orders/:id/cancel", s.CancelOrderHandler)
+		api.POST("/orders/:id/pay", s.PayOrderHandler)
+		api.GET("/orders/:id/invoice", s.GetOrderInvoiceHandler)
+
+		// Issued tickets
+		api.GET("/users/me/tickets", s.ListMyTicketsHandler)
+		api.GET("/tickets/:id/qr", s.GetTicketQRCodeHandler)
+
+		// Ticket transfers between users
+		api.POST("/tickets/:id/transfers", s.StartTransferHandler)
+		api.GET("/tickets/:id/ownership", s.GetTicketOwnershipHandler)
+		api.GET("/users/me/transfers", s.ListMyTransfersHandler)
+		api.POST("/transfers/:id/accept", s.AcceptTransferHandler)
+		api.POST("/transfers/:id/decline", s.DeclineTransferHandler)
+		api.POST("/transfers/:id/cancel", s.CancelTransferHandler)
+
+		// Waitlists for sold-out ticket types, offers are checked out through /checkout
+		api.POST("/events/:id/waitlist", s.JoinWaitlistHandler)
+		api.GET("/users/me/waitlist", s.ListMyWaitlistsHandler)
+		api.GET("/waitlist/:id", s.GetWaitlistPlaceHandler)
+		api.DELETE("/waitlist/:id", s.LeaveWaitlistHandler)
+
+		// Resale marketplace, listings are bought through /checkout
+		api.POST("/tickets/:id/resale", s.ListTicketForResaleHandler)
+		api.GET("/events/:id/resale", s.ListEventResaleListingsHandler)
+		api.GET("/users/me/resale", s.ListMyResaleListingsHandler)
+		api.DELETE("/resale/:id", s.CancelResaleListingHandler)
+
+		// Organizations - members act on their own organization within the limits of their role
+		api.GET("/users/me/organizations", s.ListMyOrganizationsHandler)
+		orgAPI := api.Group("/orgs/:orgId")
 		{
-			adminAPI.GET("/users", s.GetAllUsersHandler)
-			adminAPI.GET("/users/inactive", s.GetInactiveUsersHandler)
-			adminAPI.POST("/users", s.CreateUserHandler)
-			adminAPI.PUT("/users/:id", s.UpdateUserByIdHandler)
-			adminAPI.DELETE("/users/:id", s.DeleteUserByIdHandler)
+			manageMembers := authMiddleware.RequireOrgAccess(models.OrgCapManageMembers, middleware.ScopeOrganization)
+			orgAPI.GET("/members", manageMembers, s.ListOrgMembersHandler)
+			orgAPI.POST("/members", manageMembers, s.AddOrgMemberHandler)
+			orgAPI.PUT("/members/:userId", manageMembers, s.UpdateOrgMemberHandler)
+			orgAPI.DELETE("/members/:userId", manageMembers, s.RemoveOrgMemberHandler)
+
+			manageOrgEvents := authMiddleware.RequireOrgAccess(models.OrgCapManageEvents, middleware.ScopeOrganization)
+			orgAPI.GET("/events", manageOrgEvents, s.ListOrgEventsHandler)
+			orgAPI.POST("/events", manageOrgEvents, s.CreateOrgEventHandler)
+			orgAPI.POST("/series", manageOrgEvents, s.CreateOrgSeriesHandler)
+			orgAPI.GET("/orders", authMiddleware.RequireOrgAccess(models.OrgCapViewOrders, middleware.ScopeOrganization), s.ListOrgOrdersHandler)
+			orgAPI.GET("/reports/sales", authMiddleware.RequireOrgAccess(models.OrgCapViewReports, middleware.ScopeOrganization), s.GetOrgSalesReportHandler)
+			orgAPI.GET("/reports/box-office", authMiddleware.RequireOrgAccess(models.OrgCapViewReports, middleware.ScopeOrganization), s.GetOrgBoxOfficeReportHandler)
 		}
+
+		// Event management - allowed to staff with the matching permission and to members of the organization of the event
+		manageEvents := authMiddleware.RequireOrgAccess(models.OrgCapManageEvents, middleware.ScopeEvent)
+		api.PUT("/events/:id", manageEvents, s.UpdateEventHandler)
+		publishEvents := authMiddleware.RequireOrgAccess(models.OrgCapPublishEvents, middleware.ScopeEvent)
+		api.POST("/events/:id/publish", publishEvents, s.PublishEventHandler)
+		api.POST("/events/:id/cancel", publishEvents, s.CancelEventHandler)
+		api.POST("/events/:id/venue", manageEvents, s.AttachVenueToEventHandler)
+		api.GET("/events/:id/ticket-types", manageEvents, s.ListTicketTypesHandler)
+		api.POST("/events/:id/ticket-types", manageEvents, s.CreateTicketTypeHandler)
+		api.PUT("/events/:id/ticket-types/:ticketTypeId", manageEvents, s.UpdateTicketTypeHandler)
+		api.DELETE("/events/:id/ticket-types/:ticketTypeId", manageEvents, s.DeleteTicketTypeHandler)
+		api.GET("/events/:id/attendee-forms", manageEvents, s.ListAttendeeFormsHandler)
+		api.PUT("/events/:id/attendee-forms", manageEvents, s.SaveAttendeeFormHandler)
+		api.DELETE("/events/:id/attendee-forms/:formId", manageEvents, s.DeleteAttendeeFormHandler)
+		api.GET("/events/:id/waiting-room", manageEvents, s.GetWaitingRoomHandler)
+		api.PUT("/events/:id/waiting-room", manageEvents, s.SaveWaitingRoomHandler)
+		api.DELETE("/events/:id/waiting-room", manageEvents, s.DeleteWaitingRoomHandler)
+		api.GET("/events/:id/attendee-answers", authMiddleware.RequireOrgAccess(models.OrgCapViewOrders, middleware.ScopeEvent), s.ExportAttendeeAnswersHandler)
+		api.POST("/events/:id/refunds", authMiddleware.RequireOrgAccess(models.OrgCapRefundOrders, middleware.ScopeEvent), s.RefundEventOrdersHandler)
+
+		// Box office - staff sell on behalf of customers, with or without an account
+		api.POST("/events/:id/box-office/orders", authMiddleware.RequireOrgAccess(models.OrgCapSellTickets, middleware.ScopeEvent), s.CreateBoxOfficeOrderHandler)
+
+		// Guest lists - comp tickets are issued through the box office
+		api.GET("/events/:id/guest-lists", manageEvents, s.ListGuestListsHandler)
+		api.POST("/events/:id/guest-lists", manageEvents, s.CreateGuestListHandler)
+		api.GET("/events/:id/guest-lists/:listId", manageEvents, s.GetGuestListHandler)
+		api.PUT("/events/:id/guest-lists/:listId", manageEvents, s.UpdateGuestListHandler)
+		api.DELETE("/events/:id/guest-lists/:listId", manageEvents, s.DeleteGuestListHandler)
+		api.POST("/events/:id/guest-lists/:listId/guests", manageEvents, s.AddGuestsHandler)
+		api.POST("/events/:id/guest-lists/:listId/guests/import", manageEvents, s.ImportGuestsHandler)
+		api.DELETE("/events/:id/guest-lists/:listId/guests/:guestId", manageEvents, s.RemoveGuestHandler)
+		api.POST("/events/:id/guest-lists/:listId/guests/:guestId/tickets", manageEvents, s.IssueGuestTicketsHandler)
+
+		// Event series - occurrences are events, edited one by one through the routes above
+		api.PUT("/series/:id", authMiddleware.RequireOrgAccess(models.OrgCapManageEvents, middleware.ScopeSeries), s.UpdateSeriesHandler)
+		api.POST("/series/:id/publish", authMiddleware.RequireOrgAccess(models.OrgCapPublishEvents, middleware.ScopeSeries), s.PublishSeriesHandler)
+		api.POST("/series/:id/passes", authMiddleware.RequireOrgAccess(models.OrgCapManageEvents, middleware.ScopeSeries), s.CreateSeriesPassHandler)
+
+		checkIn := authMiddleware.RequireOrgAccess(models.OrgCapCheckIn, middleware.ScopeEvent)
+		api.POST("/events/:id/check-in", checkIn, s.CheckInHandler)
+		api.GET("/events/:id/check-in/export", checkIn, s.ExportCheckInHandler)
+		api.POST("/events/:id/check-in/sync", checkIn, s.SyncScansHandler)
+		api.GET("/events/:id/guests", checkIn, s.SearchGuestsHandler)
+		api.POST("/events/:id/guests/:guestId/check-in", checkIn, s.CheckInGuestHandler)
+
+		api.POST("/orders/:id/refunds", authMiddleware.RequireOrgAccess(models.OrgCapRefundOrders, middleware.ScopeOrder), s.RefundOrderHandler)
+		api.GET("/orders/:id/refunds", authMiddleware.RequireOrgAccess(models.OrgCapViewOrders, middleware.ScopeOrder), s.ListOrderRefundsHandler)
+
+		// Staff endpoints, each guarded by the permission its roles must grant
+		perm := authMiddleware.RequirePermission
+		api.GET("/users/me/permissions", s.GetMyPermissionsHandler)
+		api.GET("/users", perm(models.PermUsersRead), s.GetAllUsersHandler)
+		api.GET("/users/inactive", perm(models.PermUsersRead), s.GetInactiveUsersHandler)
+		api.POST("/users", perm(models.PermUsersWrite), s.CreateUserHandler)
+		api.PUT("/users/:id", perm(models.PermUsersWrite), s.UpdateUserByIdHandler)
+		api.DELETE("/users/:id", perm(models.PermUsersWrite), s.DeleteUserByIdHandler)
+
+		api.GET("/permissions", perm(models.PermRolesRead), s.ListPermissionsHandler)
+		api.GET("/roles", perm(models.PermRolesRead), s.ListRolesHandler)
+		api.POST("/roles", perm(models.PermRolesWrite), s.CreateRoleHandler)
+		api.GET("/roles/:id", perm(models.PermRolesRead), s.GetRoleHandler)
+		api.PUT("/roles/:id", perm(models.PermRolesWrite), s.UpdateRoleHandler)
+		api.DELETE("/roles/:id", perm(models.PermRolesWrite), s.DeleteRoleHandler)
+		api.GET("/users/:id/roles", perm(models.PermRolesRead), s.GetUserRolesHandler)
+		api.PUT("/users/:id/roles", perm(models.PermRolesWrite), s.SetUserRolesHandler)
+
+		api.POST("/events", perm(models.PermEventsWrite), s.CreateEventHandler)
+		api.POST("/series", perm(models.PermEventsWrite), s.CreateSeriesHandler)
+		api.GET("/organizations", perm(models.PermOrganizationsWrite), s.ListOrganizationsHandler)
+		api.POST("/organizations", perm(models.PermOrganizationsWrite), s.CreateOrganizationHandler)
+		api.POST("/resale/:id/payout", perm(models.PermPayoutsWrite), s.RetryResalePayoutHandler)
+		api.GET("/reports/box-office", perm(models.PermReportsRead), s.GetBoxOfficeReportHandler)
+		api.GET("/orders/review", perm(models.PermOrdersReview), s.ListOrdersForReviewHandler)
+		api.POST("/orders/:id/review", perm(models.PermOrdersReview), s.ReviewOrderHandler)
+
+		api.GET("/promo-codes", perm(models.PermPromoCodesWrite), s.ListPromoCodesHandler)
+		api.POST("/promo-codes", perm(models.PermPromoCodesWrite), s.CreatePromoCodeHandler)
+		api.GET("/promo-codes/:id", perm(models.PermPromoCodesWrite), s.GetPromoCodeHandler)
+		api.PUT("/promo-codes/:id", perm(models.PermPromoCodesWrite), s.UpdatePromoCodeHandler)
+		api.DELETE("/promo-codes/:id", perm(models.PermPromoCodesWrite), s.DeletePromoCodeHandler)
+
+		api.GET("/fees", perm(models.PermPricingWrite), s.ListFeeRulesHandler)
+		api.POST("/fees", perm(models.PermPricingWrite), s.CreateFeeRuleHandler)
+		api.DELETE("/fees/:id", perm(models.PermPricingWrite), s.DeleteFeeRuleHandler)
+		api.GET("/tax-rates", perm(models.PermPricingWrite), s.ListTaxRatesHandler)
+		api.POST("/tax-rates", perm(models.PermPricingWrite), s.CreateTaxRateHandler)
+		api.DELETE("/tax-rates/:id", perm(models.PermPricingWrite), s.DeleteTaxRateHandler)
+
+		api.GET("/venues", perm(models.PermVenuesRead), s.ListVenuesHandler)
+		api.POST("/venues", perm(models.PermVenuesWrite), s.CreateVenueHandler)
+		api.GET("/venues/:id", perm(models.PermVenuesRead), s.GetVenueHandler)
+		api.PUT("/venues/:id", perm(models.PermVenuesWrite), s.UpdateVenueHandler)
+		api.PUT("/venues/:id/layout", perm(models.PermVenuesWrite), s.ImportVenueLayoutHandler)
 	}
 
 	return r
@@ -12,11 +12,14 @@ import (
 
 	"passIt/internal/auth"
 	"passIt/internal/config"
+	"passIt/internal/constant"
 	"passIt/internal/database"
 	"passIt/internal/models"
+	"passIt/internal/payments"
 	"passIt/internal/services"
-
-	// "passIt/internal/store"
+	"passIt/internal/store"
+	"passIt/internal/tickets"
+	"passIt/internal/waitingroom"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -28,6 +31,34 @@ type Server struct {
 	db          database.Service
 	gormDB      *gorm.DB
 	userService services.UserService
+
+	eventService services.EventService
+	venueService services.VenueService
+
+	ticketTypeService services.TicketTypeService
+	holdService       services.HoldService
+	orderService      services.OrderService
+	paymentService    services.PaymentService
+	refundService     services.RefundService
+	ticketService     services.TicketService
+	checkInService    services.CheckInService
+	transferService   services.TransferService
+	resaleService     services.ResaleService
+	waitlistService   services.WaitlistService
+	promoCodeService  services.PromoCodeService
+	pricingService    services.PricingService
+	invoiceService    services.InvoiceService
+
+	organizationService services.OrganizationService
+	roleService         services.RoleService
+
+	attendeeFormService services.AttendeeFormService
+	eventSeriesService  services.EventSeriesService
+	boxOfficeService    services.BoxOfficeService
+	guestListService    services.GuestListService
+	waitingRoomService  services.WaitingRoomService
+	riskService         services.RiskService
+	availabilityService services.AvailabilityService
 }
 
 func NewServer(ctx context.Context, cfg *config.Config, authClient *auth.Client, redisClient *redis.Client) *http.Server {
@@ -36,6 +67,45 @@ func NewServer(ctx context.Context, cfg *config.Config, authClient *auth.Client,
 	
 	// Create user service with business logic
 	userService := services.NewUserService(dbService, authClient)
+	eventService := services.NewEventService(dbService)
+	venueService := services.NewVenueService(dbService)
+	ticketTypeService := services.NewTicketTypeService(dbService)
+	holdStore := store.NewHoldRedisManager(redisClient)
+	holdService := services.NewHoldService(dbService, holdStore)
+	promoCodeService := services.NewPromoCodeService(dbService)
+	pricingService := services.NewPricingService(dbService)
+	riskAssessor := services.NewRiskAssessor(dbService, store.NewVelocityRedisManager(redisClient))
+	orderService := services.NewOrderService(dbService, holdStore, promoCodeService, pricingService, riskAssessor)
+	waitlistService := services.NewWaitlistService(dbService, holdStore)
+
+	paymentProvider, err := payments.New(cfg.Payments)
+	if err != nil {
+		log.Fatalf("failed to initialize payment provider : %v", err)
+	}
+	ticketSigner, err := tickets.New(cfg.Tickets)
+	if err != nil {
+		log.Fatalf("failed to initialize ticket signer : %v", err)
+	}
+	refundService := services.NewRefundService(dbService, paymentProvider, orderService, holdStore)
+	resaleService := services.NewResaleService(dbService, paymentProvider, orderService, refundService, riskAssessor)
+	ticketService := services.NewTicketService(dbService, ticketSigner, orderService, resaleService)
+	checkInService := services.NewCheckInService(dbService, ticketSigner)
+	transferService := services.NewTransferService(dbService, ticketService)
+	invoiceService := services.NewInvoiceService(dbService, orderService, cfg.Invoices)
+	paymentService := services.NewPaymentService(dbService, paymentProvider, orderService, ticketService, invoiceService, holdStore)
+	organizationService := services.NewOrganizationService(dbService)
+	roleService := services.NewRoleService(dbService, authClient)
+	attendeeFormService := services.NewAttendeeFormService(dbService)
+	eventSeriesService := services.NewEventSeriesService(dbService, eventService, ticketTypeService)
+	boxOfficeService := services.NewBoxOfficeService(dbService, holdService, orderService, paymentService)
+	guestListService := services.NewGuestListService(dbService, boxOfficeService)
+	queueSigner, err := waitingroom.New(cfg.WaitingRoom)
+	if err != nil {
+		log.Fatalf("failed to initialize queue signer : %v", err)
+	}
+	waitingRoomService := services.NewWaitingRoomService(dbService, store.NewQueueRedisManager(redisClient), queueSigner)
+	riskService := services.NewRiskService(dbService, riskAssessor, orderService)
+	availabilityService := services.NewAvailabilityService(dbService, holdStore)
 	
 	NewServer := &Server{
 		port: cfg.App.Port,
@@ -44,10 +114,50 @@ func NewServer(ctx context.Context, cfg *config.Config, authClient *auth.Client,
 		db:          dbService,
 		gormDB:      dbService.GetGormDB(),
 		userService: userService,
+
+		eventService: eventService,
+		venueService: venueService,
+
+		ticketTypeService: ticketTypeService,
+		holdService:       holdService,
+		orderService:      orderService,
+		paymentService:    paymentService,
+		refundService:     refundService,
+		ticketService:     ticketService,
+		checkInService:    checkInService,
+		transferService:   transferService,
+		resaleService:     resaleService,
+		waitlistService:   waitlistService,
+		promoCodeService:  promoCodeService,
+		pricingService:    pricingService,
+		invoiceService:    invoiceService,
+
+		organizationService: organizationService,
+		roleService:         roleService,
+
+		attendeeFormService: attendeeFormService,
+		eventSeriesService:  eventSeriesService,
+		boxOfficeService:    boxOfficeService,
+		guestListService:    guestListService,
+		waitingRoomService:  waitingRoomService,
+		riskService:         riskService,
+		availabilityService: availabilityService,
 	}
 
+	// Return the inventory of expired holds and unpaid orders to sale in the background
+	go holdService.RunReaper(ctx, constant.HoldReaperInterval)
+	go orderService.RunExpiryWorker(ctx, constant.OrderExpiryInterval)
+	// Offer freed tickets to the next users on the waitlists
+	go waitlistService.RunOfferWorker(ctx, constant.WaitlistInterval)
+	// Retry issuing tickets for paid orders whose fulfilment failed
+	go ticketService.RunIssuer(ctx, constant.TicketIssueInterval)
+
 	// Initialize first admin user if none exists
 	NewServer.initializeAdminUser(ctx, cfg)
+	// Permissions come from roles, admins get the built-in admin role
+	if err := roleService.EnsureBuiltInRoles(ctx); err != nil {
+		log.Printf("Warning: Could not set up built-in roles: %v", err)
+	}
 
 	// Declare Server config
 	server := &http.Server{
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+	"passIt/internal/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateTicketTypeRequestBody struct {
+	Name        string     `json:"name" binding:"required"`
+	Description string     `json:"description"`
+	SectionID   *uuid.UUID `json:"section_id,omitempty"`
+	Price       int64      `json:"price"` // in minor units, e.g. cents
+	Currency    string     `json:"currency" binding:"required"`
+	Quantity    int        `json:"quantity" binding:"required"`
+	SalesStart  *time.Time `json:"sales_start,omitempty"`
+	SalesEnd    *time.Time `json:"sales_end,omitempty"`
+	MinPerOrder int        `json:"min_per_order"`
+	MaxPerOrder int        `json:"max_per_order"`
+	Position    int        `json:"position"`
+}
+
+type UpdateTicketTypeRequestBody struct {
+	Name        string     `json:"name,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	SectionID   *uuid.UUID `json:"section_id,omitempty"`
+	Price       *int64     `json:"price,omitempty"`
+	Currency    string     `json:"currency,omitempty"`
+	Quantity    *int       `json:"quantity,omitempty"`
+	SalesStart  *time.Time `json:"sales_start,omitempty"`
+	SalesEnd    *time.Time `json:"sales_end,omitempty"`
+	MinPerOrder *int       `json:"min_per_order,omitempty"`
+	MaxPerOrder *int       `json:"max_per_order,omitempty"`
+	Position    *int       `json:"position,omitempty"`
+}
+
+// ListTicketTypesHandler godoc
+// @Summary      List ticket types of an event (Admin or organization member)
+// @Description  Retrieve every ticket type of an event, including those not on sale
+// @Tags         ticket-types
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Success      200 {array} models.TicketType
+// @Failure      400 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events/{id}/ticket-types [get]
+func (s *Server) ListTicketTypesHandler(c *gin.Context) {
+	eventID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	ticketTypes, err := s.ticketTypeService.ListTicketTypes(c, eventID)
+	if err != nil {
+		log.Println(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ticket types"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ticketTypes)
+}
+
+// CreateTicketTypeHandler godoc
+// @Summary      Create a ticket type (Admin or organization member)
+// @Description  Add a priced ticket type to an event. The sum of all quantities cannot exceed the event capacity
+// @Tags         ticket-types
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        ticketType body CreateTicketTypeRequestBody true "Ticket type data"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events/{id}/ticket-types [post]
+func (s *Server) CreateTicketTypeHandler(c *gin.Context) {
+	eventID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input CreateTicketTypeRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ticketType := models.TicketType{
+		EventID:     eventID,
+		SectionID:   input.SectionID,
+		Name:        input.Name,
+		Description: input.Description,
+		Price:       input.Price,
+		Currency:    input.Currency,
+		Quantity:    input.Quantity,
+		SalesStart:  input.SalesStart,
+		SalesEnd:    input.SalesEnd,
+		MinPerOrder: input.MinPerOrder,
+		MaxPerOrder: input.MaxPerOrder,
+		Position:    input.Position,
+	}
+
+	if err := s.ticketTypeService.CreateTicketType(c, &ticketType); err != nil {
+		respondTicketTypeError(c, err, "Failed to create ticket type")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.TicketTypeCreatedSuccessfully,
+		Data: ticketType,
+	})
+}
+
+// UpdateTicketTypeHandler godoc
+// @Summary      Update a ticket type (Admin or organization member)
+// @Description  Change the price, quantity, sales window or limits of a ticket type
+// @Tags         ticket-types
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        ticketTypeId path string true "Ticket type ID"
+// @Param        ticketType body UpdateTicketTypeRequestBody true "Ticket type update data"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events/{id}/ticket-types/{ticketTypeId} [put]
+func (s *Server) UpdateTicketTypeHandler(c *gin.Context) {
+	eventID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	ticketTypeID, ok := parseUUIDParam(c, "ticketTypeId")
+	if !ok {
+		return
+	}
+
+	var updateReq UpdateTicketTypeRequestBody
+	if !utils.DecodeServerInput(c, &updateReq) {
+		return
+	}
+
+	ticketType, err := s.ticketTypeService.GetTicketType(c, eventID, ticketTypeID)
+	if err != nil {
+		respondTicketTypeError(c, err, "Failed to retrieve ticket type")
+		return
+	}
+
+	applyTicketTypeUpdates(&ticketType, &updateReq)
+
+	if err := s.ticketTypeService.UpdateTicketType(c, &ticketType); err != nil {
+		respondTicketTypeError(c, err, "Failed to update ticket type")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.TicketTypeUpdatedSuccessfully,
+		Data: ticketType,
+	})
+}
+
+// applyTicketTypeUpdates applies provided fields from update request to existing ticket type
+func applyTicketTypeUpdates(ticketType *models.TicketType, update *UpdateTicketTypeRequestBody) {
+	if update.Name != "" {
+		ticketType.Name = update.Name
+	}
+	if update.Description != nil {
+		ticketType.Description = *update.Description
+	}
+	if update.SectionID != nil {
+		ticketType.SectionID = update.SectionID
+	}
+	if update.Price != nil {
+		ticketType.Price = *update.Price
+	}
+	if update.Currency != "" {
+		ticketType.Currency = update.Currency
+	}
+	if update.Quantity != nil {
+		ticketType.Quantity = *update.Quantity
+	}
+	if update.SalesStart != nil {
+		ticketType.SalesStart = update.SalesStart
+	}
+	if update.SalesEnd != nil {
+		ticketType.SalesEnd = update.SalesEnd
+	}
+	if update.MinPerOrder != nil {
+		ticketType.MinPerOrder = *update.MinPerOrder
+	}
+	if update.MaxPerOrder != nil {
+		ticketType.MaxPerOrder = *update.MaxPerOrder
+	}
+	if update.Position != nil {
+		ticketType.Position = *update.Position
+	}
+}
+
+// DeleteTicketTypeHandler godoc
+// @Summary      Delete a ticket type (Admin or organization member)
+// @Description  Remove a ticket type from an event
+// @Tags         ticket-types
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        ticketTypeId path string true "Ticket type ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events/{id}/ticket-types/{ticketTypeId} [delete]
+func (s *Server) DeleteTicketTypeHandler(c *gin.Context) {
+	eventID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	ticketTypeID, ok := parseUUIDParam(c, "ticketTypeId")
+	if !ok {
+		return
+	}
+
+	if err := s.ticketTypeService.DeleteTicketType(c, eventID, ticketTypeID); err != nil {
+		respondTicketTypeError(c, err, "Failed to delete ticket type")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.TicketTypeDeletedSuccessfully,
+		Data: gin.H{
+			"message":        "Ticket type deleted successfully",
+			"ticket_type_id": ticketTypeID,
+		},
+	})
+}
+
+// respondTicketTypeError maps ticket type service errors onto HTTP responses
+func respondTicketTypeError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrTicketTypeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ticket type not found"})
+	case errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+	case errors.Is(err, services.ErrEventNotEditable),
+		errors.Is(err, models.ErrTicketCapacityExceeded):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSectionNotInVenue),
+		errors.Is(err, models.ErrTicketTypeNameRequired),
+		errors.Is(err, models.ErrTicketTypeInvalidPrice),
+		errors.Is(err, models.ErrTicketTypeInvalidCurrency),
+		errors.Is(err, models.ErrTicketTypeInvalidQuantity),
+		errors.Is(err, models.ErrTicketTypeInvalidLimits),
+		errors.Is(err, models.ErrTicketTypeInvalidWindow):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", fallback, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
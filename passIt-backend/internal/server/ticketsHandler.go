@@ -0,0 +1,114 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListMyTicketsHandler godoc
+// @Summary      List my tickets
+// @Description  Get the tickets owned by the current user with their event, ticket type and seat
+// @Tags         tickets
+// @Produce      json
+// @Success      200 {object} PassItResponseBody
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/users/me/tickets [get]
+func (s *Server) ListMyTicketsHandler(c *gin.Context) {
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	owned, err := s.ticketService.ListUserTickets(c, user.ID)
+	if err != nil {
+		respondTicketError(c, err, "Failed to retrieve tickets")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.TicketsRetrievedSuccessfully,
+		Data: owned,
+	})
+}
+
+// GetTicketQRCodeHandler godoc
+// @Summary      Ticket QR code
+// @Description  Get the signed QR code of one of your tickets as a PNG image. Staff with orders:read can get the QR code of any ticket
+// @Tags         tickets
+// @Produce      png
+// @Param        id path string true "Ticket ID"
+// @Param        size query int false "Image size in pixels (128-1024, default 256)"
+// @Success      200 {file} binary
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      410 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/tickets/{id}/qr [get]
+func (s *Server) GetTicketQRCodeHandler(c *gin.Context) {
+	ticket, ok := s.requestedTicket(c)
+	if !ok {
+		return
+	}
+
+	size, _ := strconv.Atoi(c.Query("size"))
+	png, err := s.ticketService.TicketQRCode(c, ticket, size)
+	if err != nil {
+		respondTicketError(c, err, "Failed to render ticket QR code")
+		return
+	}
+
+	// The QR code is the admission itself, keep it out of shared caches
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// requestedTicket loads the ticket in the :id parameter if the caller owns it or may read every order
+func (s *Server) requestedTicket(c *gin.Context) (models.Ticket, bool) {
+	ticketID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.TicketInvalidRequest, "invalid UUID format")
+		return models.Ticket{}, false
+	}
+
+	var ticket models.Ticket
+	if hasPermission(c, models.PermOrdersRead) {
+		ticket, err = s.ticketService.GetTicket(c, ticketID)
+	} else {
+		user, ok := s.currentUser(c)
+		if !ok {
+			return models.Ticket{}, false
+		}
+		ticket, err = s.ticketService.GetUserTicket(c, ticketID, user.ID)
+	}
+	if err != nil {
+		respondTicketError(c, err, "Failed to retrieve ticket")
+		return models.Ticket{}, false
+	}
+	return ticket, true
+}
+
+// respondTicketError maps ticket service errors onto coded HTTP responses
+func respondTicketError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrTicketNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.TicketNotFound, "Ticket not found")
+	case errors.Is(err, services.ErrTicketForbidden):
+		respondWithCode(c, http.StatusForbidden, codes.TicketForbidden, err.Error())
+	case errors.Is(err, models.ErrTicketNotValid):
+		respondWithCode(c, http.StatusGone, codes.TicketNotValid, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.TicketInternalError, fallback)
+	}
+}
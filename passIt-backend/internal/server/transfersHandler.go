@@ -0,0 +1,225 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type StartTransferRequestBody struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// StartTransferHandler godoc
+// @Summary      Transfer a ticket
+// @Description  Offer one of your tickets to another user by email. The ticket keeps working for you until the recipient accepts
+// @Tags         transfers
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Ticket ID"
+// @Param        transfer body StartTransferRequestBody true "Recipient"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/tickets/{id}/transfers [post]
+func (s *Server) StartTransferHandler(c *gin.Context) {
+	ticketID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.TransferInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	var input StartTransferRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.TransferInvalidRequest, err.Error())
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	transfer, err := s.transferService.StartTransfer(c, ticketID, user.ID, input.Email)
+	if err != nil {
+		respondTransferError(c, err, "Failed to start transfer")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.TransferStartedSuccessfully,
+		Data: transfer,
+	})
+}
+
+// ListMyTransfersHandler godoc
+// @Summary      List my transfers
+// @Description  Get the ticket transfers you sent or received, newest first
+// @Tags         transfers
+// @Produce      json
+// @Success      200 {object} PassItResponseBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/users/me/transfers [get]
+func (s *Server) ListMyTransfersHandler(c *gin.Context) {
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	transfers, err := s.transferService.ListUserTransfers(c, user.ID)
+	if err != nil {
+		respondTransferError(c, err, "Failed to retrieve transfers")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.TransfersRetrievedSuccessfully,
+		Data: transfers,
+	})
+}
+
+// AcceptTransferHandler godoc
+// @Summary      Accept a transfer
+// @Description  Accept a ticket offered to you. A new QR code is issued to you and the sender's QR code stops working
+// @Tags         transfers
+// @Produce      json
+// @Param        id path string true "Transfer ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/transfers/{id}/accept [post]
+func (s *Server) AcceptTransferHandler(c *gin.Context) {
+	s.respondToTransfer(c, s.transferService.AcceptTransfer, codes.TransferAcceptedSuccessfully, "Failed to accept transfer")
+}
+
+// DeclineTransferHandler godoc
+// @Summary      Decline a transfer
+// @Description  Decline a ticket offered to you, the sender keeps the ticket
+// @Tags         transfers
+// @Produce      json
+// @Param        id path string true "Transfer ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/transfers/{id}/decline [post]
+func (s *Server) DeclineTransferHandler(c *gin.Context) {
+	s.respondToTransfer(c, s.transferService.DeclineTransfer, codes.TransferDeclinedSuccessfully, "Failed to decline transfer")
+}
+
+// CancelTransferHandler godoc
+// @Summary      Cancel a transfer
+// @Description  Withdraw a pending transfer you started
+// @Tags         transfers
+// @Produce      json
+// @Param        id path string true "Transfer ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/transfers/{id}/cancel [post]
+func (s *Server) CancelTransferHandler(c *gin.Context) {
+	s.respondToTransfer(c, s.transferService.CancelTransfer, codes.TransferCancelledSuccessfully, "Failed to cancel transfer")
+}
+
+// respondToTransfer applies an action of the current user to the transfer in the :id parameter
+func (s *Server) respondToTransfer(
+	c *gin.Context,
+	action func(ctx context.Context, transferID, userID uuid.UUID) (models.TicketTransfer, error),
+	code int,
+	fallback string,
+) {
+	transferID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.TransferInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	transfer, err := action(c, transferID, user.ID)
+	if err != nil {
+		respondTransferError(c, err, fallback)
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: code,
+		Data: transfer,
+	})
+}
+
+// GetTicketOwnershipHandler godoc
+// @Summary      Ticket ownership chain
+// @Description  Get every owner of one of your tickets from the original buyer to you. Admins can get the chain of any ticket
+// @Tags         transfers
+// @Produce      json
+// @Param        id path string true "Ticket ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/tickets/{id}/ownership [get]
+func (s *Server) GetTicketOwnershipHandler(c *gin.Context) {
+	ticket, ok := s.requestedTicket(c)
+	if !ok {
+		return
+	}
+
+	chain, err := s.transferService.OwnershipChain(c, ticket)
+	if err != nil {
+		respondTransferError(c, err, "Failed to retrieve ownership chain")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.TicketOwnershipRetrieved,
+		Data: chain,
+	})
+}
+
+// respondTransferError maps transfer service errors onto coded HTTP responses,
+// falling back to the ticket errors for everything else
+func respondTransferError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrTransferNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.TransferNotFound, "Transfer not found")
+	case errors.Is(err, services.ErrTransferRecipientNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.TransferRecipientNotFound, err.Error())
+	case errors.Is(err, services.ErrTransferForbidden):
+		respondWithCode(c, http.StatusForbidden, codes.TransferForbidden, err.Error())
+	case errors.Is(err, models.ErrTransfersForbidden),
+		errors.Is(err, models.ErrTransferDeadlinePassed):
+		respondWithCode(c, http.StatusConflict, codes.TransferNotAllowed, err.Error())
+	case errors.Is(err, models.ErrTransferToSelf):
+		respondWithCode(c, http.StatusBadRequest, codes.TransferInvalidRequest, err.Error())
+	case errors.Is(err, models.ErrTransferInvalidStatus),
+		errors.Is(err, models.ErrTransferTicketNotAllowed),
+		errors.Is(err, services.ErrTransferPending),
+		errors.Is(err, services.ErrTransferStale):
+		respondWithCode(c, http.StatusConflict, codes.TransferConflict, err.Error())
+	default:
+		respondTicketError(c, err, fallback)
+	}
+}
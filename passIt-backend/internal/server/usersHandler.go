@@ -6,6 +6,7 @@ import (
 	"passIt/internal/models"
 	codes "passIt/internal/passit-codes"
 	"passIt/internal/store"
+	"slices"
 
 	"passIt/internal/utils"
 
@@ -42,6 +43,9 @@ type UpdateUserRequestBody struct {
 	FirstName string `json:"first_name,omitempty"`
 	LastName  string `json:"last_name,omitempty"`
 	IsAdmin   *bool  `json:"is_admin,omitempty"` // Pointer to distinguish between false and not provided
+
+	// Roles replaces the roles of the user, requires roles:write. is_admin only grants or revokes the admin role.
+	Roles *[]string `json:"roles,omitempty"`
 }
 
 type CreateUserReturnBody struct {
@@ -50,8 +54,8 @@ type CreateUserReturnBody struct {
 }
 
 // CreateUserHandler godoc
-// @Summary      Create a new user (Admin only)
-// @Description  Create a new user with username, email, password and admin status
+// @Summary      Create a new user (users:write)
+// @Description  Create a new user with username, email, password and admin status. Creating an admin requires roles:write
 // @Tags         users
 // @Accept       json
 // @Produce      json
@@ -67,6 +71,10 @@ func (s *Server) CreateUserHandler(c *gin.Context) {
 	if !utils.DecodeServerInput(c, &input) {
 		return // Stop processing if decode fails
 	}
+	if input.IsAdmin && !hasPermission(c, models.PermRolesWrite) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden - missing permission " + string(models.PermRolesWrite)})
+		return
+	}
 
 	// Build user model from flat request
 	user := models.User{
@@ -87,6 +95,12 @@ func (s *Server) CreateUserHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
 		return
 	}
+	if input.IsAdmin {
+		if _, err := s.roleService.SetAdmin(c, user.ID, true); err != nil {
+			respondRoleError(c, err, "Failed to grant admin role")
+			return
+		}
+	}
 
 	defer c.Request.Body.Close()
 
@@ -140,8 +154,8 @@ func (s *Server) FindUserByEmailHandler(c *gin.Context) {
 }
 
 // UpdateUserByIdHandler godoc
-// @Summary      Update user by ID (Admin only)
-// @Description  Update user information including email, name, password, and admin status
+// @Summary      Update user by ID (users:write)
+// @Description  Update user information including email, name and password. Changing roles or admin status requires roles:write and is mirrored onto the mapped Keycloak roles
 // @Tags         users
 // @Accept       json
 // @Produce      json
@@ -167,6 +181,10 @@ func (s *Server) UpdateUserByIdHandler(c *gin.Context) {
 	if !utils.DecodeServerInput(c, &updateReq) {
 		return // Stop processing if decode fails
 	}
+	if (updateReq.Roles != nil || updateReq.IsAdmin != nil) && !hasPermission(c, models.PermRolesWrite) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden - missing permission " + string(models.PermRolesWrite)})
+		return
+	}
 
 	// Get existing user
 	existingUser, err := s.userService.GetUserByID(c, id)
@@ -208,6 +226,12 @@ func (s *Server) UpdateUserByIdHandler(c *gin.Context) {
 		return
 	}
 
+	// Roles change last, permissions apply from the user's next request
+	if err := s.applyUserRoleUpdates(c, &existingUser, &updateReq); err != nil {
+		respondRoleError(c, err, "Failed to update user roles")
+		return
+	}
+
 	c.JSON(http.StatusOK, existingUser)
 }
 
@@ -225,13 +249,31 @@ func applyUserUpdates(user *models.User, update *UpdateUserRequestBody) {
 	if update.LastName != "" {
 		user.LastName = update.LastName
 	}
-	if update.IsAdmin != nil {
-		user.IsAdmin = *update.IsAdmin
+}
+
+// applyUserRoleUpdates replaces the roles of the user, or only grants or revokes the admin role
+func (s *Server) applyUserRoleUpdates(c *gin.Context, user *models.User, update *UpdateUserRequestBody) error {
+	var (
+		roles []models.Role
+		err   error
+	)
+	switch {
+	case update.Roles != nil:
+		roles, err = s.roleService.SetUserRoles(c, user.ID, *update.Roles)
+	case update.IsAdmin != nil:
+		roles, err = s.roleService.SetAdmin(c, user.ID, *update.IsAdmin)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
 	}
+	user.IsAdmin = slices.ContainsFunc(roles, func(role models.Role) bool { return role.Name == models.AdminRoleName })
+	return nil
 }
 
 // DeleteUserByIdHandler godoc
-// @Summary      Soft delete user by ID (Admin only)
+// @Summary      Soft delete user by ID (users:write)
 // @Description  Deactivate a user by setting isActive to false and disabling in Keycloak
 // @Tags         users
 // @Produce      json
@@ -300,7 +342,7 @@ func (s *Server) DeleteUserByIdHandler(c *gin.Context) {
 }
 
 // GetAllUsersHandler godoc
-// @Summary      Get all users (Admin only)
+// @Summary      Get all users (users:read)
 // @Description  Retrieve a list of all users in the system
 // @Tags         users
 // @Produce      json
@@ -322,7 +364,7 @@ func (s *Server) GetAllUsersHandler(c *gin.Context) {
 }
 
 // GetInactiveUsersHandler godoc
-// @Summary      Get all inactive users (Admin only)
+// @Summary      Get all inactive users (users:read)
 // @Description  Retrieve a list of all deactivated/deleted users in the system
 // @Tags         users
 // @Produce      json
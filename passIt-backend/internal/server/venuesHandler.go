@@ -0,0 +1,334 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+	"passIt/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateVenueRequestBody struct {
+	Name     string                `json:"name" binding:"required"`
+	Address  string                `json:"address"`
+	City     string                `json:"city"`
+	Country  string                `json:"country"`
+	TimeZone string                `json:"time_zone"`
+	Sections []models.VenueSection `json:"sections" binding:"required"`
+	// TaxJurisdiction selects the tax rates of the venue, e.g. "US-CA"
+	TaxJurisdiction string `json:"tax_jurisdiction"`
+}
+
+type UpdateVenueRequestBody struct {
+	Name     string  `json:"name,omitempty"`
+	Address  *string `json:"address,omitempty"`
+	City     *string `json:"city,omitempty"`
+	Country  *string `json:"country,omitempty"`
+	TimeZone *string `json:"time_zone,omitempty"`
+
+	TaxJurisdiction *string `json:"tax_jurisdiction,omitempty"`
+}
+
+// VenueLayoutRequestBody is the JSON document used to import a seating layout
+type VenueLayoutRequestBody struct {
+	Sections []models.VenueSection `json:"sections" binding:"required"`
+}
+
+type AttachVenueRequestBody struct {
+	VenueID uuid.UUID `json:"venue_id" binding:"required"`
+}
+
+// CreateVenueHandler godoc
+// @Summary      Create a venue (venues:write)
+// @Description  Create a venue together with its seating layout (sections, rows, seats and general-admission areas)
+// @Tags         venues
+// @Accept       json
+// @Produce      json
+// @Param        venue body CreateVenueRequestBody true "Venue with layout"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/venues [post]
+func (s *Server) CreateVenueHandler(c *gin.Context) {
+	var input CreateVenueRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	venue := models.Venue{
+		Name:     input.Name,
+		Address:  input.Address,
+		City:     input.City,
+		Country:  input.Country,
+		TimeZone: input.TimeZone,
+		Sections: input.Sections,
+
+		TaxJurisdiction: input.TaxJurisdiction,
+	}
+
+	if err := s.venueService.CreateVenue(c, &venue); err != nil {
+		respondVenueError(c, err, "Failed to create venue")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.VenueCreatedSuccessfully,
+		Data: venue,
+	})
+}
+
+// ListVenuesHandler godoc
+// @Summary      List venues (venues:read)
+// @Description  Retrieve all venues without their seating layouts
+// @Tags         venues
+// @Produce      json
+// @Success      200 {array} models.Venue
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/venues [get]
+func (s *Server) ListVenuesHandler(c *gin.Context) {
+	venues, err := s.venueService.ListVenues(c)
+	if err != nil {
+		log.Println(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve venues"})
+		return
+	}
+
+	c.JSON(http.StatusOK, venues)
+}
+
+// GetVenueHandler godoc
+// @Summary      Get venue by ID (venues:read)
+// @Description  Retrieve a venue with its full seating layout
+// @Tags         venues
+// @Produce      json
+// @Param        id path string true "Venue ID"
+// @Success      200 {object} models.Venue
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/venues/{id} [get]
+func (s *Server) GetVenueHandler(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	venue, err := s.venueService.GetVenueByID(c, id)
+	if err != nil {
+		respondVenueError(c, err, "Failed to retrieve venue")
+		return
+	}
+
+	c.JSON(http.StatusOK, venue)
+}
+
+// UpdateVenueHandler godoc
+// @Summary      Update venue details (venues:write)
+// @Description  Update the name and address of a venue. Use the layout endpoint to change seating
+// @Tags         venues
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Venue ID"
+// @Param        venue body UpdateVenueRequestBody true "Venue update data"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/venues/{id} [put]
+func (s *Server) UpdateVenueHandler(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var updateReq UpdateVenueRequestBody
+	if !utils.DecodeServerInput(c, &updateReq) {
+		return
+	}
+
+	venue, err := s.venueService.GetVenueByID(c, id)
+	if err != nil {
+		respondVenueError(c, err, "Failed to retrieve venue")
+		return
+	}
+
+	applyVenueUpdates(&venue, &updateReq)
+
+	if err := s.venueService.UpdateVenueDetails(c, &venue); err != nil {
+		respondVenueError(c, err, "Failed to update venue")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.VenueUpdatedSuccessfully,
+		Data: venue,
+	})
+}
+
+// applyVenueUpdates applies provided fields from update request to existing venue
+func applyVenueUpdates(venue *models.Venue, update *UpdateVenueRequestBody) {
+	if update.Name != "" {
+		venue.Name = update.Name
+	}
+	if update.Address != nil {
+		venue.Address = *update.Address
+	}
+	if update.City != nil {
+		venue.City = *update.City
+	}
+	if update.Country != nil {
+		venue.Country = *update.Country
+	}
+	if update.TimeZone != nil {
+		venue.TimeZone = *update.TimeZone
+	}
+	if update.TaxJurisdiction != nil {
+		venue.TaxJurisdiction = *update.TaxJurisdiction
+	}
+}
+
+// ImportVenueLayoutHandler godoc
+// @Summary      Import venue seating layout (venues:write)
+// @Description  Replace the seating layout of a venue with the given JSON document
+// @Tags         venues
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Venue ID"
+// @Param        layout body VenueLayoutRequestBody true "Seating layout"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/venues/{id}/layout [put]
+func (s *Server) ImportVenueLayoutHandler(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input VenueLayoutRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	venue, err := s.venueService.ImportLayout(c, id, input.Sections)
+	if err != nil {
+		respondVenueError(c, err, "Failed to import venue layout")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.VenueLayoutImportedSuccessfully,
+		Data: venue,
+	})
+}
+
+// AttachVenueToEventHandler godoc
+// @Summary      Attach venue to event (Admin or organization member)
+// @Description  Link a venue layout to an event and generate its per-seat inventory
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        venue body AttachVenueRequestBody true "Venue to attach"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events/{id}/venue [post]
+func (s *Server) AttachVenueToEventHandler(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input AttachVenueRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event, err := s.venueService.AttachVenueToEvent(c, id, input.VenueID)
+	if err != nil {
+		respondVenueError(c, err, "Failed to attach venue to event")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.VenueAttachedSuccessfully,
+		Data: event,
+	})
+}
+
+// GetEventSeatsHandler godoc
+// @Summary      Get event seat map
+// @Description  Retrieve the per-seat inventory of an event
+// @Tags         events
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Success      200 {array} models.EventSeat
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/events/{id}/seats [get]
+func (s *Server) GetEventSeatsHandler(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	// Regular users only see the seat map of published events
+	if !hasPermission(c, models.PermEventsRead) {
+		if _, err := s.eventService.GetPublishedEvent(c, id); err != nil {
+			respondEventError(c, err, "Failed to retrieve event")
+			return
+		}
+	}
+
+	seats, err := s.venueService.GetEventSeats(c, id)
+	if err != nil {
+		log.Println(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve event seats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, seats)
+}
+
+// respondVenueError maps venue service errors onto HTTP responses
+func respondVenueError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrVenueNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Venue not found"})
+	case errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+	case errors.Is(err, services.ErrVenueLayoutInUse),
+		errors.Is(err, services.ErrEventSeatsSold),
+		errors.Is(err, services.ErrEventNotEditable),
+		errors.Is(err, models.ErrTicketCapacityExceeded):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrVenueNameRequired),
+		errors.Is(err, models.ErrVenueInvalidLayout),
+		errors.Is(err, models.ErrVenueLayoutEmpty),
+		errors.Is(err, models.ErrVenueInvalidZone):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", fallback, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,261 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// queueTokenHeader carries the token proving a buyer's place in a waiting room
+	queueTokenHeader = "X-Queue-Token"
+	// admissionTokenHeader carries the token letting an admitted buyer hold and check out tickets
+	admissionTokenHeader = "X-Admission-Token"
+)
+
+type WaitingRoomRequestBody struct {
+	OpensAt  time.Time `json:"opens_at" binding:"required"`
+	ClosesAt time.Time `json:"closes_at" binding:"required"`
+	// AdmitPerMinute is how many buyers are let into checkout every minute
+	AdmitPerMinute int `json:"admit_per_minute" binding:"required,min=1"`
+	// AdmissionMinutes is how long an admitted buyer may hold and check out tickets, 15 when omitted
+	AdmissionMinutes int `json:"admission_minutes" binding:"min=0"`
+}
+
+// GetWaitingRoomHandler godoc
+// @Summary      Get the waiting room of an event (Admin or organization member)
+// @Description  Retrieve the on-sale window and admission rate of the waiting room protecting the event
+// @Tags         waiting-room
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/waiting-room [get]
+func (s *Server) GetWaitingRoomHandler(c *gin.Context) {
+	eventID, ok := waitingRoomParam(c)
+	if !ok {
+		return
+	}
+
+	room, err := s.waitingRoomService.GetWaitingRoom(c, eventID)
+	if err != nil {
+		respondWaitingRoomError(c, err, "Failed to retrieve waiting room")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.WaitingRoomRetrieved,
+		Data: room,
+	})
+}
+
+// SaveWaitingRoomHandler godoc
+// @Summary      Set up the waiting room of an event (Admin or organization member)
+// @Description  Create or replace the waiting room of a high-demand on-sale. Between opens_at and closes_at buyers queue in order of arrival and only those admitted at admit_per_minute may hold and check out tickets
+// @Tags         waiting-room
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        room body WaitingRoomRequestBody true "Waiting room"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/waiting-room [put]
+func (s *Server) SaveWaitingRoomHandler(c *gin.Context) {
+	eventID, ok := waitingRoomParam(c)
+	if !ok {
+		return
+	}
+
+	var input WaitingRoomRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.WaitingRoomInvalidRequest, err.Error())
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	room := models.WaitingRoom{
+		EventID:          eventID,
+		OpensAt:          input.OpensAt,
+		ClosesAt:         input.ClosesAt,
+		AdmitPerMinute:   input.AdmitPerMinute,
+		AdmissionMinutes: input.AdmissionMinutes,
+		CreatedByID:      &user.ID,
+	}
+	if err := s.waitingRoomService.SaveWaitingRoom(c, &room); err != nil {
+		respondWaitingRoomError(c, err, "Failed to save waiting room")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.WaitingRoomSaved,
+		Data: room,
+	})
+}
+
+// DeleteWaitingRoomHandler godoc
+// @Summary      Remove the waiting room of an event (Admin or organization member)
+// @Description  Remove the waiting room and its queue, buyers can hold tickets right away
+// @Tags         waiting-room
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/waiting-room [delete]
+func (s *Server) DeleteWaitingRoomHandler(c *gin.Context) {
+	eventID, ok := waitingRoomParam(c)
+	if !ok {
+		return
+	}
+
+	if err := s.waitingRoomService.DeleteWaitingRoom(c, eventID); err != nil {
+		respondWaitingRoomError(c, err, "Failed to delete waiting room")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.WaitingRoomDeleted,
+		Data: gin.H{
+			"message":  "Waiting room deleted successfully",
+			"event_id": eventID,
+		},
+	})
+}
+
+// JoinQueueHandler godoc
+// @Summary      Join the queue of an event
+// @Description  Take a place in the waiting room of a high-demand on-sale. Joining again keeps your place. The queue token is polled for admission, the admission token is sent in the X-Admission-Token header to hold and check out tickets
+// @Tags         waiting-room
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/queue [post]
+func (s *Server) JoinQueueHandler(c *gin.Context) {
+	eventID, ok := waitingRoomParam(c)
+	if !ok {
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	status, err := s.waitingRoomService.Join(c, eventID, user.ID)
+	if err != nil {
+		respondWaitingRoomError(c, err, "Failed to join queue")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.QueueJoined,
+		Data: status,
+	})
+}
+
+// GetQueueStatusHandler godoc
+// @Summary      Get your place in the queue of an event
+// @Description  Retrieve your position, the number of buyers ahead and the estimated wait. Once your turn has come the response carries an admission token valid for a limited time
+// @Tags         waiting-room
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        X-Queue-Token header string true "Queue token returned when joining"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Failure      410 {object} PassItErrorBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/queue [get]
+func (s *Server) GetQueueStatusHandler(c *gin.Context) {
+	eventID, ok := waitingRoomParam(c)
+	if !ok {
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	status, err := s.waitingRoomService.Status(c, eventID, user.ID, c.GetHeader(queueTokenHeader))
+	if err != nil {
+		respondWaitingRoomError(c, err, "Failed to retrieve queue status")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.QueueStatusRetrieved,
+		Data: status,
+	})
+}
+
+// requireAdmission writes an error response and returns false when the event is behind
+// an open waiting room and the request carries no admission of the user
+func (s *Server) requireAdmission(c *gin.Context, eventID, userID uuid.UUID) bool {
+	err := s.waitingRoomService.CheckAdmission(c, eventID, userID, c.GetHeader(admissionTokenHeader))
+	if err != nil {
+		respondWaitingRoomError(c, err, "Failed to check admission")
+		return false
+	}
+	return true
+}
+
+func waitingRoomParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.WaitingRoomInvalidRequest, "invalid UUID format")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondWaitingRoomError maps waiting room errors onto coded HTTP responses
+func respondWaitingRoomError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrWaitingRoomNotFound),
+		errors.Is(err, services.ErrEventNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.WaitingRoomNotFound, err.Error())
+	case errors.Is(err, services.ErrQueueNotOpen):
+		respondWithCode(c, http.StatusConflict, codes.QueueNotOpen, err.Error())
+	case errors.Is(err, services.ErrQueueClosed):
+		respondWithCode(c, http.StatusConflict, codes.QueueClosed, err.Error())
+	case errors.Is(err, services.ErrAdmissionRequired):
+		respondWithCode(c, http.StatusForbidden, codes.AdmissionRequired, err.Error())
+	case errors.Is(err, services.ErrAdmissionExpired):
+		respondWithCode(c, http.StatusGone, codes.AdmissionExpired, err.Error())
+	case errors.Is(err, services.ErrInvalidQueueToken),
+		errors.Is(err, models.ErrWaitingRoomInvalidWindow),
+		errors.Is(err, models.ErrWaitingRoomInvalidRate),
+		errors.Is(err, models.ErrWaitingRoomInvalidAdmission):
+		respondWithCode(c, http.StatusBadRequest, codes.WaitingRoomInvalidRequest, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.WaitingRoomInternalError, fallback)
+	}
+}
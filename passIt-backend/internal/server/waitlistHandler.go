@@ -0,0 +1,191 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"passIt/internal/models"
+	codes "passIt/internal/passit-codes"
+	"passIt/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type JoinWaitlistRequestBody struct {
+	TicketTypeID uuid.UUID `json:"ticket_type_id" binding:"required"`
+	// Quantity defaults to one ticket
+	Quantity int `json:"quantity" binding:"omitempty,min=1"`
+}
+
+// JoinWaitlistHandler godoc
+// @Summary      Join a waitlist
+// @Description  Queue for a sold-out ticket type. When tickets free up the next user in line gets them reserved for a limited time and can check out the hold of the offer
+// @Tags         waitlist
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Event ID"
+// @Param        waitlist body JoinWaitlistRequestBody true "Ticket type and quantity"
+// @Success      201 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/events/{id}/waitlist [post]
+func (s *Server) JoinWaitlistHandler(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.WaitlistInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	var input JoinWaitlistRequestBody
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.WaitlistInvalidRequest, err.Error())
+		return
+	}
+	if input.Quantity == 0 {
+		input.Quantity = 1
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	place, err := s.waitlistService.Join(c, eventID, input.TicketTypeID, user.ID, input.Quantity)
+	if err != nil {
+		respondWaitlistError(c, err, "Failed to join waitlist")
+		return
+	}
+
+	c.JSON(http.StatusCreated, PassItResponseBody{
+		Code: codes.WaitlistJoined,
+		Data: place,
+	})
+}
+
+// ListMyWaitlistsHandler godoc
+// @Summary      List my waitlists
+// @Description  Get your waitlist entries with your place in each queue and any outstanding offer, newest first
+// @Tags         waitlist
+// @Produce      json
+// @Success      200 {object} PassItResponseBody
+// @Failure      500 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/users/me/waitlist [get]
+func (s *Server) ListMyWaitlistsHandler(c *gin.Context) {
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	places, err := s.waitlistService.ListUserPlaces(c, user.ID)
+	if err != nil {
+		respondWaitlistError(c, err, "Failed to retrieve waitlists")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.WaitlistPlacesRetrieved,
+		Data: places,
+	})
+}
+
+// GetWaitlistPlaceHandler godoc
+// @Summary      Check my waitlist position
+// @Description  Get one of your waitlist entries with your place in the queue. Offered entries carry the hold to check out before the offer expires
+// @Tags         waitlist
+// @Produce      json
+// @Param        id path string true "Waitlist entry ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/waitlist/{id} [get]
+func (s *Server) GetWaitlistPlaceHandler(c *gin.Context) {
+	entryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.WaitlistInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	place, err := s.waitlistService.GetPlace(c, entryID, user.ID)
+	if err != nil {
+		respondWaitlistError(c, err, "Failed to retrieve waitlist entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.WaitlistPlaceRetrieved,
+		Data: place,
+	})
+}
+
+// LeaveWaitlistHandler godoc
+// @Summary      Leave a waitlist
+// @Description  Give up your place in the queue. An outstanding offer is passed on to the next user
+// @Tags         waitlist
+// @Produce      json
+// @Param        id path string true "Waitlist entry ID"
+// @Success      200 {object} PassItResponseBody
+// @Failure      400 {object} PassItErrorBody
+// @Failure      403 {object} PassItErrorBody
+// @Failure      404 {object} PassItErrorBody
+// @Failure      409 {object} PassItErrorBody
+// @Security     BearerAuth
+// @Router       /api/waitlist/{id} [delete]
+func (s *Server) LeaveWaitlistHandler(c *gin.Context) {
+	entryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithCode(c, http.StatusBadRequest, codes.WaitlistInvalidRequest, "invalid UUID format")
+		return
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	entry, err := s.waitlistService.Leave(c, entryID, user.ID)
+	if err != nil {
+		respondWaitlistError(c, err, "Failed to leave waitlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, PassItResponseBody{
+		Code: codes.WaitlistLeft,
+		Data: entry,
+	})
+}
+
+// respondWaitlistError maps waitlist service errors onto coded HTTP responses
+func respondWaitlistError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrWaitlistEntryNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.WaitlistEntryNotFound, "Waitlist entry not found")
+	case errors.Is(err, services.ErrEventNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.WaitlistEntryNotFound, "Event not found")
+	case errors.Is(err, services.ErrTicketTypeNotFound):
+		respondWithCode(c, http.StatusNotFound, codes.WaitlistEntryNotFound, "Ticket type not found")
+	case errors.Is(err, services.ErrWaitlistForbidden):
+		respondWithCode(c, http.StatusForbidden, codes.WaitlistForbidden, err.Error())
+	case errors.Is(err, models.ErrWaitlistNotSoldOut):
+		respondWithCode(c, http.StatusConflict, codes.WaitlistNotSoldOut, err.Error())
+	case errors.Is(err, services.ErrWaitlistAlreadyJoined),
+		errors.Is(err, models.ErrWaitlistInvalidStatus),
+		errors.Is(err, services.ErrTicketTypeNotOnSale):
+		respondWithCode(c, http.StatusConflict, codes.WaitlistConflict, err.Error())
+	case errors.Is(err, models.ErrTicketTypeOrderOutOfBounds):
+		respondWithCode(c, http.StatusBadRequest, codes.WaitlistInvalidRequest, err.Error())
+	default:
+		log.Printf("%s: %v", fallback, err)
+		respondWithCode(c, http.StatusInternalServerError, codes.WaitlistInternalError, fallback)
+	}
+}
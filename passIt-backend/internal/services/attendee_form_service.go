@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"slices"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAttendeeFormNotFound = errors.New("attendee form not found")
+)
+
+// AttendeeFormService manages the questions organizers ask attendees at checkout and
+// the answers they collected. Answers are validated and stored by the order service.
+type AttendeeFormService interface {
+	// ListForms returns the event-wide form of an event first, then the forms of its ticket types
+	ListForms(ctx context.Context, eventID uuid.UUID) ([]models.AttendeeForm, error)
+	// SaveForm creates the form of an event or of one of its ticket types, or replaces its questions
+	SaveForm(ctx context.Context, form *models.AttendeeForm) error
+	DeleteForm(ctx context.Context, eventID, id uuid.UUID) error
+	// ExportAnswers lists the answers given for the tickets of the paid orders of an event
+	ExportAnswers(ctx context.Context, eventID uuid.UUID) (AttendeeAnswerExport, error)
+}
+
+// AttendeeAnswerExport holds every question of an event and the answers per ticket
+type AttendeeAnswerExport struct {
+	Fields []models.FormField         `json:"fields"`
+	Rows   []models.AttendeeAnswerRow `json:"rows"`
+}
+
+type attendeeFormService struct {
+	db database.Service
+}
+
+// NewAttendeeFormService creates a new attendee form service
+func NewAttendeeFormService(db database.Service) AttendeeFormService {
+	return &attendeeFormService{
+		db: db,
+	}
+}
+
+func (s *attendeeFormService) ListForms(ctx context.Context, eventID uuid.UUID) ([]models.AttendeeForm, error) {
+	if err := s.ensureEvent(eventID); err != nil {
+		return nil, err
+	}
+	forms, err := s.db.ListAttendeeFormsByEvent(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attendee forms: %w", err)
+	}
+	return forms, nil
+}
+
+// SaveForm keeps one form per event and ticket type, saving a form again replaces its
+// questions. Answers already given are kept as they were.
+func (s *attendeeFormService) SaveForm(ctx context.Context, form *models.AttendeeForm) error {
+	if err := form.Validate(); err != nil {
+		return err
+	}
+	if err := s.ensureEvent(form.EventID); err != nil {
+		return err
+	}
+	if form.TicketTypeID != nil {
+		ticketType, err := s.db.FindTicketTypeById(*form.TicketTypeID)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && ticketType.EventID != form.EventID) {
+			return ErrTicketTypeNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to retrieve ticket type: %w", err)
+		}
+	}
+
+	existing, err := s.db.FindAttendeeForm(form.EventID, form.TicketTypeID)
+	switch {
+	case err == nil:
+		form.ID = existing.ID
+		form.CreatedAt = existing.CreatedAt
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		form.ID = uuid.Nil
+	default:
+		return fmt.Errorf("failed to retrieve attendee form: %w", err)
+	}
+
+	if err := s.db.SaveAttendeeForm(form); err != nil {
+		return fmt.Errorf("failed to save attendee form: %w", err)
+	}
+	return nil
+}
+
+// DeleteForm removes a form of the given event, its questions are no longer asked
+func (s *attendeeFormService) DeleteForm(ctx context.Context, eventID, id uuid.UUID) error {
+	form, err := s.db.FindAttendeeFormById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAttendeeFormNotFound
+		}
+		return fmt.Errorf("failed to retrieve attendee form: %w", err)
+	}
+	if form.EventID != eventID {
+		return ErrAttendeeFormNotFound
+	}
+
+	if err := s.db.DeleteAttendeeForm(id); err != nil {
+		return fmt.Errorf("failed to delete attendee form: %w", err)
+	}
+	return nil
+}
+
+// ExportAnswers lists the answers with the questions of the current forms. Questions
+// that were removed since are added at the end so no answer is lost.
+func (s *attendeeFormService) ExportAnswers(ctx context.Context, eventID uuid.UUID) (AttendeeAnswerExport, error) {
+	forms, err := s.ListForms(ctx, eventID)
+	if err != nil {
+		return AttendeeAnswerExport{}, err
+	}
+	rows, err := s.db.ListAttendeeAnswersByEvent(eventID)
+	if err != nil {
+		return AttendeeAnswerExport{}, fmt.Errorf("failed to retrieve attendee answers: %w", err)
+	}
+
+	export := AttendeeAnswerExport{
+		Fields: []models.FormField{},
+		Rows:   rows,
+	}
+	known := func(key string) bool {
+		return slices.ContainsFunc(export.Fields, func(f models.FormField) bool { return f.Key == key })
+	}
+	for _, form := range forms {
+		for _, field := range form.Fields {
+			if !known(field.Key) {
+				export.Fields = append(export.Fields, field)
+			}
+		}
+	}
+	for _, row := range rows {
+		for _, key := range slices.Sorted(maps.Keys(row.Answers)) {
+			if !known(key) {
+				export.Fields = append(export.Fields, models.FormField{Key: key, Label: key})
+			}
+		}
+	}
+	if export.Rows == nil {
+		export.Rows = []models.AttendeeAnswerRow{}
+	}
+	return export, nil
+}
+
+func (s *attendeeFormService) ensureEvent(eventID uuid.UUID) error {
+	if _, err := s.db.FindEventById(eventID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrEventNotFound
+		}
+		return fmt.Errorf("failed to retrieve event: %w", err)
+	}
+	return nil
+}
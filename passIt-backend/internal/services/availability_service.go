@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/store"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AvailabilitySnapshot is the reason of the first change of a stream, it lists every ticket type
+const AvailabilitySnapshot store.AvailabilityReason = "snapshot"
+
+// AvailabilityChange is what buyers browsing an event see change in its inventory
+type AvailabilityChange struct {
+	EventID     uuid.UUID                `json:"event_id"`
+	Reason      store.AvailabilityReason `json:"reason"`
+	TicketTypes []TicketTypeAvailability `json:"ticket_types"`
+	// Seats are only listed when seats were held, released or sold, the seat map has the rest
+	Seats []SeatAvailability `json:"seats,omitempty"`
+	At    time.Time          `json:"at"`
+}
+
+// TicketTypeAvailability is how many tickets of a type can still be held
+type TicketTypeAvailability struct {
+	TicketTypeID uuid.UUID `json:"ticket_type_id"`
+	Available    int       `json:"available"`
+}
+
+// SeatAvailability tells whether a seat can still be held
+type SeatAvailability struct {
+	SeatID    uuid.UUID `json:"seat_id"`
+	Available bool      `json:"available"`
+}
+
+// AvailabilityService follows the inventory of events as holds are made and released and
+// orders are paid, on every API instance
+type AvailabilityService interface {
+	// Watch returns the availability of every ticket type of the event followed by each
+	// change, until the context is done. The channel closes early when the watcher falls
+	// behind, watching again starts from a fresh snapshot.
+	Watch(ctx context.Context, eventID uuid.UUID) (<-chan AvailabilityChange, error)
+}
+
+type availabilityService struct {
+	db    database.Service
+	holds store.HoldStore
+}
+
+// NewAvailabilityService creates a new availability service
+func NewAvailabilityService(db database.Service, holds store.HoldStore) AvailabilityService {
+	return &availabilityService{
+		db:    db,
+		holds: holds,
+	}
+}
+
+func (s *availabilityService) Watch(ctx context.Context, eventID uuid.UUID) (<-chan AvailabilityChange, error) {
+	ticketTypes, err := s.db.ListTicketTypesByEvent(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve ticket types: %w", err)
+	}
+	capacities := make(map[uuid.UUID]int, len(ticketTypes))
+	for _, ticketType := range ticketTypes {
+		capacities[ticketType.ID] = ticketType.Quantity
+	}
+
+	// Subscribe before reading the snapshot so no change in between is lost
+	updates, err := s.holds.SubscribeAvailability(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.snapshot(ctx, eventID, ticketTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(chan AvailabilityChange, 1)
+	changes <- snapshot
+	go func() {
+		defer close(changes)
+		for update := range updates {
+			select {
+			case changes <- s.change(update, capacities):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// snapshot is the availability of every ticket type of the event
+func (s *availabilityService) snapshot(ctx context.Context, eventID uuid.UUID, ticketTypes []models.TicketType) (AvailabilityChange, error) {
+	ids := make([]uuid.UUID, len(ticketTypes))
+	for i, ticketType := range ticketTypes {
+		ids[i] = ticketType.ID
+	}
+	// Counters not loaded into Redis yet have no holds, the database has the sold tickets
+	sold, err := s.db.CountTakenTickets(ids)
+	if err != nil {
+		return AvailabilityChange{}, fmt.Errorf("failed to count sold tickets: %w", err)
+	}
+
+	snapshot := AvailabilityChange{
+		EventID:     eventID,
+		Reason:      AvailabilitySnapshot,
+		TicketTypes: make([]TicketTypeAvailability, 0, len(ticketTypes)),
+		At:          time.Now(),
+	}
+	for _, ticketType := range ticketTypes {
+		held, err := s.holds.Taken(ctx, ticketType.ID)
+		if err != nil {
+			return AvailabilityChange{}, err
+		}
+		snapshot.TicketTypes = append(snapshot.TicketTypes, TicketTypeAvailability{
+			TicketTypeID: ticketType.ID,
+			Available:    max(ticketType.Quantity-max(held, sold[ticketType.ID]), 0),
+		})
+	}
+	return snapshot, nil
+}
+
+// change turns the counts published by the store into what is left to buy. Ticket types
+// created after the stream started are looked up once.
+func (s *availabilityService) change(update store.AvailabilityUpdate, capacities map[uuid.UUID]int) AvailabilityChange {
+	change := AvailabilityChange{
+		EventID:     update.EventID,
+		Reason:      update.Reason,
+		TicketTypes: make([]TicketTypeAvailability, 0, len(update.TicketTypes)),
+		At:          update.At,
+	}
+	for _, ticketType := range update.TicketTypes {
+		capacity, ok := capacities[ticketType.TicketTypeID]
+		if !ok {
+			found, err := s.db.FindTicketTypeById(ticketType.TicketTypeID)
+			if err != nil {
+				log.Printf("Failed to retrieve ticket type %s: %v", ticketType.TicketTypeID, err)
+				continue
+			}
+			capacity = found.Quantity
+			capacities[found.ID] = capacity
+		}
+		change.TicketTypes = append(change.TicketTypes, TicketTypeAvailability{
+			TicketTypeID: ticketType.TicketTypeID,
+			Available:    max(capacity-ticketType.Taken, 0),
+		})
+	}
+	for _, seat := range update.Seats {
+		change.Seats = append(change.Seats, SeatAvailability{SeatID: seat.SeatID, Available: !seat.Taken})
+	}
+	return change
+}
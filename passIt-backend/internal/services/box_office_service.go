@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/store"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BoxOfficeService sells tickets on behalf of customers over the phone and at the door.
+// Every sale is attributed to the staff member who made it for the end-of-day reconciliation.
+type BoxOfficeService interface {
+	// Sell reserves, checks out and records the payment of an order for a customer in one step
+	Sell(ctx context.Context, sale BoxOfficeSale) (BoxOfficeReceipt, error)
+	// Report sums up the box office payments captured in [from, to)
+	Report(ctx context.Context, from, to time.Time, filter BoxOfficeReportFilter) (models.BoxOfficeReport, error)
+}
+
+// BoxOfficeSale is an order staff take for a customer
+type BoxOfficeSale struct {
+	EventID  uuid.UUID
+	StaffID  uuid.UUID
+	Customer BoxOfficeCustomer
+	Items    []store.HoldItem
+	Payment  BoxOfficePayment
+	// Checkout holds the promo codes, billing details and attendee answers of the order
+	Checkout CheckoutOptions
+}
+
+// BoxOfficeCustomer identifies who the tickets are for. Customers with an account are
+// found by email, others get a guest record.
+type BoxOfficeCustomer struct {
+	Email       string
+	FirstName   string
+	LastName    string
+	PhoneNumber string
+}
+
+// BoxOfficeReceipt is what the box office hands over once an order is paid
+type BoxOfficeReceipt struct {
+	Customer models.User     `json:"customer"`
+	Order    models.Order    `json:"order"`
+	Payment  models.Payment  `json:"payment"`
+	Tickets  []models.Ticket `json:"tickets"`
+}
+
+// BoxOfficeReportFilter narrows a report down to the events of an organization or the
+// sales of one staff member
+type BoxOfficeReportFilter struct {
+	OrganizationID *uuid.UUID
+	StaffID        *uuid.UUID
+}
+
+type boxOfficeService struct {
+	db       database.Service
+	holds    HoldService
+	orders   OrderService
+	payments PaymentService
+}
+
+// NewBoxOfficeService creates a new box office service
+func NewBoxOfficeService(db database.Service, holds HoldService, orders OrderService, payments PaymentService) BoxOfficeService {
+	return &boxOfficeService{
+		db:       db,
+		holds:    holds,
+		orders:   orders,
+		payments: payments,
+	}
+}
+
+// Sell goes through the same hold and checkout steps as an online purchase, so inventory,
+// waitlists, promo codes and attendee questions apply to box office orders as well. If
+// the payment cannot be recorded the order is cancelled and its tickets go back on sale.
+func (s *boxOfficeService) Sell(ctx context.Context, sale BoxOfficeSale) (BoxOfficeReceipt, error) {
+	if err := sale.Payment.Method.Validate(); err != nil {
+		return BoxOfficeReceipt{}, err
+	}
+	customer, err := s.customer(sale.Customer)
+	if err != nil {
+		return BoxOfficeReceipt{}, err
+	}
+
+	hold, err := s.holds.CreateBoxOfficeHold(ctx, sale.EventID, customer.ID, sale.Items)
+	if err != nil {
+		return BoxOfficeReceipt{}, err
+	}
+
+	opts := sale.Checkout
+	opts.SoldByID = &sale.StaffID
+	opts.Complimentary = sale.Payment.Method == models.PaymentMethodComp
+	order, err := s.orders.Checkout(ctx, customer.ID, hold.ID, opts)
+	if err != nil {
+		if releaseErr := s.holds.ReleaseHold(ctx, hold.ID, customer.ID); releaseErr != nil {
+			log.Printf("Failed to release box office hold %s: %v", hold.ID, releaseErr)
+		}
+		return BoxOfficeReceipt{}, err
+	}
+
+	sale.Payment.StaffID = sale.StaffID
+	payment, err := s.payments.RecordBoxOfficePayment(ctx, order.ID, sale.Payment)
+	if err != nil {
+		if _, cancelErr := s.orders.Transition(ctx, order.ID, models.OrderStatusCancelled); cancelErr != nil {
+			log.Printf("Failed to cancel box office order %s: %v", order.ID, cancelErr)
+		}
+		return BoxOfficeReceipt{}, err
+	}
+
+	receipt := BoxOfficeReceipt{Customer: customer, Payment: payment}
+	if receipt.Order, err = s.orders.GetOrder(ctx, order.ID); err != nil {
+		return BoxOfficeReceipt{}, err
+	}
+	// Tickets that failed to issue are retried by the ticket issuer
+	if receipt.Tickets, err = s.db.ListTicketsByOrder(order.ID); err != nil {
+		return BoxOfficeReceipt{}, fmt.Errorf("failed to retrieve tickets: %w", err)
+	}
+	return receipt, nil
+}
+
+// customer finds the user with the email of the customer or creates a guest record
+func (s *boxOfficeService) customer(details BoxOfficeCustomer) (models.User, error) {
+	email := strings.ToLower(strings.TrimSpace(details.Email))
+	user, err := s.db.FindUserByEmail(email)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.User{}, fmt.Errorf("failed to retrieve customer: %w", err)
+	}
+
+	guest := models.NewGuestUser(email, details.FirstName, details.LastName, details.PhoneNumber)
+	if err := s.db.CreateUser(&guest); err != nil {
+		return models.User{}, fmt.Errorf("failed to create guest customer: %w", err)
+	}
+	return guest, nil
+}
+
+func (s *boxOfficeService) Report(ctx context.Context, from, to time.Time, filter BoxOfficeReportFilter) (models.BoxOfficeReport, error) {
+	lines, err := s.db.BoxOfficeReport(from, to, filter.OrganizationID, filter.StaffID)
+	if err != nil {
+		return models.BoxOfficeReport{}, fmt.Errorf("failed to build box office report: %w", err)
+	}
+	return models.NewBoxOfficeReport(from, to, lines), nil
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/tickets"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ScanRequest is one QR code scanned at the door
+type ScanRequest struct {
+	Token   string
+	Gate    string
+	StaffID uuid.UUID
+}
+
+// ScanResult tells door staff whether to admit the ticket holder
+type ScanResult struct {
+	Accepted bool                 `json:"accepted"`
+	Reason   models.CheckInReason `json:"reason,omitempty"`
+	Message  string               `json:"message"`
+	Ticket   *models.Ticket       `json:"ticket,omitempty"`
+	CheckIn  models.CheckIn       `json:"check_in"`
+}
+
+// OfflineScan is one entry of a scan log recorded by a scanner without connectivity
+type OfflineScan struct {
+	ClientScanID   string
+	Token          string
+	ScannedAt      time.Time
+	DeviceAccepted bool
+}
+
+// ScanSyncRequest is the scan log uploaded by a scanner device
+type ScanSyncRequest struct {
+	DeviceID string
+	Gate     string
+	StaffID  uuid.UUID
+	Scans    []OfflineScan
+}
+
+// ReconciledScan compares the decision of an offline scanner with the server's
+type ReconciledScan struct {
+	ClientScanID   string               `json:"client_scan_id"`
+	Accepted       bool                 `json:"accepted"`
+	DeviceAccepted bool                 `json:"device_accepted"`
+	Reason         models.CheckInReason `json:"reason,omitempty"`
+	// Conflict is set when the scanner admitted a ticket the server rejects, e.g. a
+	// ticket scanned at two gates while they were offline
+	Conflict bool `json:"conflict"`
+}
+
+// OfflineTicket is an entry of the ticket list scanners validate against
+type OfflineTicket struct {
+	TicketID uuid.UUID           `json:"ticket_id"`
+	Version  int                 `json:"version"`
+	Status   models.TicketStatus `json:"status"`
+	Token    string              `json:"token,omitempty"`
+}
+
+// RevokedTicket marks QR codes of a ticket that must be rejected. Versions up to
+// and including RevokedVersion are revoked.
+type RevokedTicket struct {
+	TicketID       uuid.UUID `json:"ticket_id"`
+	RevokedVersion int       `json:"revoked_version"`
+}
+
+// OfflineExport is everything a scanner needs to check in an event without connectivity
+type OfflineExport struct {
+	EventID     uuid.UUID       `json:"event_id"`
+	Algorithm   string          `json:"algorithm"`
+	KeyID       string          `json:"key_id"`
+	PublicKey   string          `json:"public_key"` // base64 encoded
+	GeneratedAt time.Time       `json:"generated_at"`
+	Tickets     []OfflineTicket `json:"tickets"`
+	Revoked     []RevokedTicket `json:"revoked"`
+}
+
+// CheckInService verifies tickets at the door
+type CheckInService interface {
+	// Scan verifies a QR code and admits its ticket to the event
+	Scan(ctx context.Context, eventID uuid.UUID, request ScanRequest) (ScanResult, error)
+	// ExportEvent builds the ticket and revocation lists for offline scanners
+	ExportEvent(ctx context.Context, eventID uuid.UUID) (OfflineExport, error)
+	// SyncScans replays the scan log of an offline scanner in scan order.
+	// Scans uploaded before are returned as they were reconciled the first time.
+	SyncScans(ctx context.Context, eventID uuid.UUID, request ScanSyncRequest) ([]ReconciledScan, error)
+}
+
+type checkInService struct {
+	db     database.Service
+	signer *tickets.Signer
+}
+
+// NewCheckInService creates a new check-in service
+func NewCheckInService(db database.Service, signer *tickets.Signer) CheckInService {
+	return &checkInService{
+		db:     db,
+		signer: signer,
+	}
+}
+
+func (s *checkInService) Scan(ctx context.Context, eventID uuid.UUID, request ScanRequest) (ScanResult, error) {
+	if err := s.requireEvent(eventID); err != nil {
+		return ScanResult{}, err
+	}
+
+	checkIn := models.CheckIn{
+		EventID:   eventID,
+		Gate:      request.Gate,
+		StaffID:   request.StaffID,
+		ScannedAt: time.Now(),
+		Source:    models.CheckInSourceOnline,
+	}
+	ticket, err := s.admit(&checkIn, request.Token)
+	if err != nil {
+		return ScanResult{}, err
+	}
+	if err := s.db.CreateCheckIn(&checkIn); err != nil {
+		return ScanResult{}, fmt.Errorf("failed to record check-in: %w", err)
+	}
+
+	return ScanResult{
+		Accepted: checkIn.Accepted,
+		Reason:   checkIn.Reason,
+		Message:  checkIn.Reason.Message(),
+		Ticket:   ticket,
+		CheckIn:  checkIn,
+	}, nil
+}
+
+// admit verifies the token and marks its ticket used, filling in the outcome on the check-in
+func (s *checkInService) admit(checkIn *models.CheckIn, token string) (*models.Ticket, error) {
+	claims, err := s.signer.Verify(token)
+	if err != nil {
+		checkIn.Reason = models.CheckInReasonInvalidSignature
+		return nil, nil
+	}
+	checkIn.TicketID = &claims.TicketID
+	checkIn.Version = claims.Version
+
+	ticket, err := s.db.FindTicketById(claims.TicketID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			checkIn.Reason = models.CheckInReasonUnknownTicket
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to retrieve ticket: %w", err)
+	}
+
+	if reason := ticket.CheckInReason(checkIn.EventID, claims.Version); reason != models.CheckInReasonNone {
+		checkIn.Reason = reason
+		return &ticket, nil
+	}
+
+	if ticket.TicketType != nil && ticket.TicketType.IsPass() {
+		return s.admitPass(checkIn, ticket, claims.Version)
+	}
+
+	admitted, err := s.db.MarkTicketUsed(ticket.ID, claims.Version, checkIn.ScannedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check in ticket: %w", err)
+	}
+	if !admitted {
+		// Another gate admitted the ticket in the meantime
+		checkIn.Reason = models.CheckInReasonAlreadyUsed
+		return &ticket, nil
+	}
+
+	ticket.Status = models.TicketStatusUsed
+	ticket.UsedAt = &checkIn.ScannedAt
+	checkIn.Accepted = true
+	return &ticket, nil
+}
+
+// admitPass admits a pass to the event of the check-in, the pass stays valid for the
+// other occurrences it admits to
+func (s *checkInService) admitPass(checkIn *models.CheckIn, ticket models.Ticket, version int) (*models.Ticket, error) {
+	admitted, err := s.db.MarkPassUsed(ticket.ID, checkIn.EventID, version, checkIn.ScannedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check in pass: %w", err)
+	}
+	if !admitted {
+		checkIn.Reason = models.CheckInReasonAlreadyUsed
+		return &ticket, nil
+	}
+	checkIn.Accepted = true
+	return &ticket, nil
+}
+
+func (s *checkInService) requireEvent(eventID uuid.UUID) error {
+	if _, err := s.db.FindEventById(eventID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrEventNotFound
+		}
+		return fmt.Errorf("failed to retrieve event: %w", err)
+	}
+	return nil
+}
+
+// ExportEvent lists the valid and used tickets with their current version and revokes
+// every older QR code version, so a scanner can reject refunded and reissued tickets.
+// Passes admitting to the event are listed as used once they were admitted to it.
+func (s *checkInService) ExportEvent(ctx context.Context, eventID uuid.UUID) (OfflineExport, error) {
+	if err := s.requireEvent(eventID); err != nil {
+		return OfflineExport{}, err
+	}
+
+	issued, err := s.db.ListTicketsByEvent(eventID)
+	if err != nil {
+		return OfflineExport{}, fmt.Errorf("failed to retrieve tickets: %w", err)
+	}
+	issued, err = s.withPasses(eventID, issued)
+	if err != nil {
+		return OfflineExport{}, err
+	}
+
+	export := OfflineExport{
+		EventID:     eventID,
+		Algorithm:   "Ed25519",
+		KeyID:       s.signer.KeyID(),
+		PublicKey:   base64.StdEncoding.EncodeToString(s.signer.PublicKey()),
+		GeneratedAt: time.Now(),
+		Tickets:     []OfflineTicket{},
+		Revoked:     []RevokedTicket{},
+	}
+	for _, ticket := range issued {
+		if ticket.Status == models.TicketStatusVoid {
+			export.Revoked = append(export.Revoked, RevokedTicket{TicketID: ticket.ID, RevokedVersion: ticket.Version})
+			continue
+		}
+		if ticket.Version > 1 {
+			export.Revoked = append(export.Revoked, RevokedTicket{TicketID: ticket.ID, RevokedVersion: ticket.Version - 1})
+		}
+
+		entry := OfflineTicket{TicketID: ticket.ID, Version: ticket.Version, Status: ticket.Status}
+		if ticket.Status == models.TicketStatusValid {
+			if entry.Token, err = s.signer.Sign(tickets.NewClaims(ticket.ID, ticket.EventID, ticket.Version)); err != nil {
+				return OfflineExport{}, err
+			}
+		}
+		export.Tickets = append(export.Tickets, entry)
+	}
+	return export, nil
+}
+
+// withPasses replaces the pass tickets among the tickets of an event by the tickets of
+// every pass admitting to it, with the status they have at this event
+func (s *checkInService) withPasses(eventID uuid.UUID, issued []models.Ticket) ([]models.Ticket, error) {
+	passes, err := s.db.ListPassTicketsByEvent(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pass tickets: %w", err)
+	}
+	if len(passes) == 0 {
+		return issued, nil
+	}
+	admissions, err := s.db.ListPassAdmissionsByEvent(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pass admissions: %w", err)
+	}
+
+	isPass := make(map[uuid.UUID]bool, len(passes))
+	for _, ticket := range passes {
+		isPass[ticket.ID] = true
+	}
+	tickets := make([]models.Ticket, 0, len(issued)+len(passes))
+	for _, ticket := range issued {
+		if !isPass[ticket.ID] {
+			tickets = append(tickets, ticket)
+		}
+	}
+	for _, ticket := range passes {
+		for _, admission := range admissions {
+			if admission.TicketID == ticket.ID && ticket.Status == models.TicketStatusValid {
+				ticket.Status = models.TicketStatusUsed
+				ticket.UsedAt = &admission.UsedAt
+			}
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, nil
+}
+
+func (s *checkInService) SyncScans(ctx context.Context, eventID uuid.UUID, request ScanSyncRequest) ([]ReconciledScan, error) {
+	if err := s.requireEvent(eventID); err != nil {
+		return nil, err
+	}
+
+	scans := make([]OfflineScan, len(request.Scans))
+	copy(scans, request.Scans)
+	sort.SliceStable(scans, func(i, j int) bool { return scans[i].ScannedAt.Before(scans[j].ScannedAt) })
+
+	reconciled := make([]ReconciledScan, 0, len(scans))
+	for _, scan := range scans {
+		checkIn, err := s.db.FindCheckInByClientScan(request.DeviceID, scan.ClientScanID)
+		switch {
+		case err == nil:
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if checkIn, err = s.replay(eventID, request, scan); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("failed to retrieve scan: %w", err)
+		}
+
+		reconciled = append(reconciled, ReconciledScan{
+			ClientScanID:   scan.ClientScanID,
+			Accepted:       checkIn.Accepted,
+			DeviceAccepted: scan.DeviceAccepted,
+			Reason:         checkIn.Reason,
+			Conflict:       scan.DeviceAccepted && !checkIn.Accepted,
+		})
+	}
+	return reconciled, nil
+}
+
+// replay applies one offline scan as if it had been checked at the time it was scanned
+func (s *checkInService) replay(eventID uuid.UUID, request ScanSyncRequest, scan OfflineScan) (models.CheckIn, error) {
+	clientScanID := scan.ClientScanID
+	deviceAccepted := scan.DeviceAccepted
+	checkIn := models.CheckIn{
+		EventID:        eventID,
+		Gate:           request.Gate,
+		StaffID:        request.StaffID,
+		ScannedAt:      scan.ScannedAt,
+		Source:         models.CheckInSourceOffline,
+		DeviceID:       request.DeviceID,
+		ClientScanID:   &clientScanID,
+		DeviceAccepted: &deviceAccepted,
+	}
+	if checkIn.ScannedAt.IsZero() {
+		checkIn.ScannedAt = time.Now()
+	}
+
+	if _, err := s.admit(&checkIn, scan.Token); err != nil {
+		return models.CheckIn{}, err
+	}
+	if err := s.db.CreateCheckIn(&checkIn); err != nil {
+		return models.CheckIn{}, fmt.Errorf("failed to record check-in: %w", err)
+	}
+	if deviceAccepted && !checkIn.Accepted {
+		log.Printf("Offline scan %s of device %s admitted a ticket the server rejects: %s", clientScanID, request.DeviceID, checkIn.Reason)
+	}
+	return checkIn, nil
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSeriesNotFound = errors.New("event series not found")
+)
+
+// EventSeriesService manages recurring events. Every occurrence of a series is an event
+// with its own ticket types and inventory, edited through the event endpoints to change
+// one occurrence or through the series to change all of them.
+type EventSeriesService interface {
+	// CreateSeries stores a series and a draft event per date of its schedule
+	CreateSeries(ctx context.Context, series *models.EventSeries) error
+	// GetSeries returns a series with every occurrence and the passes sold on them
+	GetSeries(ctx context.Context, id uuid.UUID) (models.EventSeries, error)
+	// GetPublishedSeries returns a series with its published occurrences only
+	GetPublishedSeries(ctx context.Context, id uuid.UUID) (models.EventSeries, error)
+	// UpdateSeries stores the edited series and applies it to its occurrences
+	UpdateSeries(ctx context.Context, series *models.EventSeries) (models.SeriesChanges, error)
+	// PublishSeries publishes every draft occurrence of a series
+	PublishSeries(ctx context.Context, id uuid.UUID) (models.EventSeries, error)
+	// CreatePass creates a ticket type admitting to several occurrences of a series
+	CreatePass(ctx context.Context, seriesID uuid.UUID, pass *models.TicketType, eventIDs []uuid.UUID) error
+}
+
+type eventSeriesService struct {
+	db          database.Service
+	events      EventService
+	ticketTypes TicketTypeService
+}
+
+// NewEventSeriesService creates a new event series service
+func NewEventSeriesService(db database.Service, events EventService, ticketTypes TicketTypeService) EventSeriesService {
+	return &eventSeriesService{
+		db:          db,
+		events:      events,
+		ticketTypes: ticketTypes,
+	}
+}
+
+func (s *eventSeriesService) CreateSeries(ctx context.Context, series *models.EventSeries) error {
+	series.ID = uuid.New()
+	if err := series.Validate(); err != nil {
+		return err
+	}
+	starts, err := series.Schedule()
+	if err != nil {
+		return err
+	}
+
+	occurrences := make([]models.Event, 0, len(starts))
+	for _, start := range starts {
+		occurrences = append(occurrences, series.NewOccurrence(start))
+	}
+	if err := s.db.CreateSeries(series, occurrences); err != nil {
+		return fmt.Errorf("failed to create event series: %w", err)
+	}
+	series.Occurrences = occurrences
+	return nil
+}
+
+func (s *eventSeriesService) GetSeries(ctx context.Context, id uuid.UUID) (models.EventSeries, error) {
+	series, err := s.db.FindSeriesById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.EventSeries{}, ErrSeriesNotFound
+		}
+		return models.EventSeries{}, fmt.Errorf("failed to retrieve event series: %w", err)
+	}
+	if series.Passes, err = s.db.ListPassesBySeries(id); err != nil {
+		return models.EventSeries{}, fmt.Errorf("failed to retrieve passes: %w", err)
+	}
+	return series, nil
+}
+
+// GetPublishedSeries hides drafts and cancelled occurrences and the passes sold on them,
+// a series without published occurrences is not found
+func (s *eventSeriesService) GetPublishedSeries(ctx context.Context, id uuid.UUID) (models.EventSeries, error) {
+	series, err := s.GetSeries(ctx, id)
+	if err != nil {
+		return models.EventSeries{}, err
+	}
+
+	published := map[uuid.UUID]bool{}
+	occurrences := series.Occurrences[:0]
+	for _, occurrence := range series.Occurrences {
+		if occurrence.Status == models.EventStatusPublished {
+			published[occurrence.ID] = true
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+	if len(occurrences) == 0 {
+		return models.EventSeries{}, ErrSeriesNotFound
+	}
+	passes := series.Passes[:0]
+	for _, pass := range series.Passes {
+		if published[pass.EventID] {
+			passes = append(passes, pass)
+		}
+	}
+	series.Occurrences, series.Passes = occurrences, passes
+	return series, nil
+}
+
+// UpdateSeries applies the series to the occurrences that still follow it. Occurrences
+// edited on their own, cancelled or already started keep their details, and published
+// occurrences whose date left the schedule are kept for the organizer to cancel.
+func (s *eventSeriesService) UpdateSeries(ctx context.Context, series *models.EventSeries) (models.SeriesChanges, error) {
+	existing, err := s.GetSeries(ctx, series.ID)
+	if err != nil {
+		return models.SeriesChanges{}, err
+	}
+	series.CreatedAt = existing.CreatedAt
+	series.CreatedByID = existing.CreatedByID
+	series.OrganizationID = existing.OrganizationID
+	series.Occurrences = existing.Occurrences
+	if err := series.Validate(); err != nil {
+		return models.SeriesChanges{}, err
+	}
+
+	changes, err := series.PlanChanges(time.Now())
+	if err != nil {
+		return models.SeriesChanges{}, err
+	}
+	// The capacity of an occurrence cannot shrink below what its ticket types and passes hold
+	for _, occurrence := range changes.Updated {
+		allocated, err := s.db.SumTicketTypeQuantities(occurrence.ID)
+		if err != nil {
+			return models.SeriesChanges{}, fmt.Errorf("failed to check ticket allocation: %w", err)
+		}
+		if allocated > occurrence.Capacity {
+			return models.SeriesChanges{}, models.ErrTicketCapacityExceeded
+		}
+	}
+
+	if err := s.db.UpdateSeries(series, changes); err != nil {
+		return models.SeriesChanges{}, fmt.Errorf("failed to update event series: %w", err)
+	}
+	return changes, nil
+}
+
+// PublishSeries publishes the draft occurrences one by one, occurrences published or
+// cancelled before are left as they are
+func (s *eventSeriesService) PublishSeries(ctx context.Context, id uuid.UUID) (models.EventSeries, error) {
+	series, err := s.GetSeries(ctx, id)
+	if err != nil {
+		return models.EventSeries{}, err
+	}
+	for i, occurrence := range series.Occurrences {
+		if occurrence.Status != models.EventStatusDraft {
+			continue
+		}
+		if series.Occurrences[i], err = s.events.PublishEvent(ctx, occurrence.ID); err != nil {
+			return models.EventSeries{}, err
+		}
+	}
+	return series, nil
+}
+
+// CreatePass sells the pass on the first occurrence it admits to, it takes a seat of
+// every occurrence it admits to
+func (s *eventSeriesService) CreatePass(ctx context.Context, seriesID uuid.UUID, pass *models.TicketType, eventIDs []uuid.UUID) error {
+	series, err := s.GetSeries(ctx, seriesID)
+	if err != nil {
+		return err
+	}
+	anchor, others, err := models.PassEvents(series.Occurrences, eventIDs)
+	if err != nil {
+		return err
+	}
+	for _, occurrence := range series.Occurrences {
+		if slices.Contains(eventIDs, occurrence.ID) && !occurrence.Status.IsEditable() {
+			return ErrEventNotEditable
+		}
+	}
+
+	pass.EventID = anchor.ID
+	pass.PassEventIDs = others
+	pass.SectionID = nil
+	return s.ticketTypes.CreateTicketType(ctx, pass)
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEventNotFound    = errors.New("event not found")
+	ErrEventNotEditable = errors.New("event can no longer be edited")
+)
+
+// EventService handles the event catalog business logic
+type EventService interface {
+	CreateEvent(ctx context.Context, event *models.Event) error
+	GetEventByID(ctx context.Context, id uuid.UUID) (models.Event, error)
+	GetPublishedEvent(ctx context.Context, id uuid.UUID) (models.Event, error)
+	ListEvents(ctx context.Context, statuses ...models.EventStatus) ([]models.Event, error)
+	UpdateEvent(ctx context.Context, event *models.Event) error
+	PublishEvent(ctx context.Context, id uuid.UUID) (models.Event, error)
+	CancelEvent(ctx context.Context, id uuid.UUID) (models.Event, error)
+}
+
+type eventService struct {
+	db database.Service
+}
+
+// NewEventService creates a new event service
+func NewEventService(db database.Service) EventService {
+	return &eventService{
+		db: db,
+	}
+}
+
+// CreateEvent validates and stores a new event, always starting as a draft
+func (s *eventService) CreateEvent(ctx context.Context, event *models.Event) error {
+	event.Status = models.EventStatusDraft
+	if err := event.Validate(); err != nil {
+		return err
+	}
+	if err := s.db.CreateEvent(event); err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+	return nil
+}
+
+// GetEventByID retrieves an event regardless of its status
+func (s *eventService) GetEventByID(ctx context.Context, id uuid.UUID) (models.Event, error) {
+	event, err := s.db.FindEventById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Event{}, ErrEventNotFound
+		}
+		return models.Event{}, fmt.Errorf("failed to retrieve event: %w", err)
+	}
+	return event, nil
+}
+
+// GetPublishedEvent retrieves an event only if it is visible to regular users
+func (s *eventService) GetPublishedEvent(ctx context.Context, id uuid.UUID) (models.Event, error) {
+	event, err := s.GetEventByID(ctx, id)
+	if err != nil {
+		return models.Event{}, err
+	}
+	if event.Status != models.EventStatusPublished {
+		// Hide drafts and cancelled events from non-admins
+		return models.Event{}, ErrEventNotFound
+	}
+	return event, nil
+}
+
+// ListEvents retrieves events filtered by status
+func (s *eventService) ListEvents(ctx context.Context, statuses ...models.EventStatus) ([]models.Event, error) {
+	events, err := s.db.ListEvents(statuses...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve events: %w", err)
+	}
+	return events, nil
+}
+
+// UpdateEvent validates and persists changes to an editable event. An occurrence of a
+// series edited on its own no longer follows changes made to the series.
+func (s *eventService) UpdateEvent(ctx context.Context, event *models.Event) error {
+	if !event.Status.IsEditable() {
+		return ErrEventNotEditable
+	}
+	if err := event.Validate(); err != nil {
+		return err
+	}
+	if event.SeriesID != nil {
+		event.SeriesDetached = true
+	}
+
+	// The capacity cannot shrink below what has already been allocated to ticket types
+	allocated, err := s.db.SumTicketTypeQuantities(event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check ticket allocation: %w", err)
+	}
+	if allocated > event.Capacity {
+		return models.ErrTicketCapacityExceeded
+	}
+
+	if err := s.db.UpdateEvent(event); err != nil {
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+	return nil
+}
+
+// PublishEvent makes a draft event visible to all users
+func (s *eventService) PublishEvent(ctx context.Context, id uuid.UUID) (models.Event, error) {
+	return s.transition(ctx, id, models.EventStatusPublished)
+}
+
+// CancelEvent cancels a draft or published event
+func (s *eventService) CancelEvent(ctx context.Context, id uuid.UUID) (models.Event, error) {
+	return s.transition(ctx, id, models.EventStatusCancelled)
+}
+
+func (s *eventService) transition(ctx context.Context, id uuid.UUID, next models.EventStatus) (models.Event, error) {
+	event, err := s.GetEventByID(ctx, id)
+	if err != nil {
+		return models.Event{}, err
+	}
+	if !event.Status.CanTransitionTo(next) {
+		return models.Event{}, models.ErrEventInvalidStatus
+	}
+
+	now := time.Now()
+	switch next {
+	case models.EventStatusPublished:
+		event.PublishedAt = &now
+	case models.EventStatusCancelled:
+		event.CancelledAt = &now
+	}
+	event.Status = next
+
+	if err := s.db.UpdateEvent(&event); err != nil {
+		return models.Event{}, fmt.Errorf("failed to update event status: %w", err)
+	}
+	return event, nil
+}
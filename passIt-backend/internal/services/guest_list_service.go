@@ -0,0 +1,390 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/store"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrGuestListNotFound     = errors.New("guest list not found")
+	ErrGuestNotFound         = errors.New("guest not found")
+	ErrGuestListInUse        = errors.New("guests were already checked in or issued tickets")
+	ErrGuestListNoTicketType = errors.New("guest list has no ticket type to issue comp tickets as")
+	ErrGuestEmailRequired    = errors.New("guest needs an email address to be issued tickets")
+	ErrGuestTicketsIssued    = errors.New("guest was already issued tickets")
+	ErrGuestSearchTooShort   = errors.New("search needs at least 2 characters")
+)
+
+// GuestListService manages the guest lists of events, issues comp tickets to their guests
+// and checks guests in by name
+type GuestListService interface {
+	ListGuestLists(ctx context.Context, eventID uuid.UUID) ([]models.GuestList, error)
+	CreateGuestList(ctx context.Context, list *models.GuestList) error
+	// GetGuestList returns a guest list of the event with its guests
+	GetGuestList(ctx context.Context, eventID, id uuid.UUID) (models.GuestList, error)
+	UpdateGuestList(ctx context.Context, list *models.GuestList) error
+	DeleteGuestList(ctx context.Context, eventID, id uuid.UUID) error
+	// AddGuests adds guests to a list, all of them or none when the quota would be exceeded
+	AddGuests(ctx context.Context, eventID, listID, addedByID uuid.UUID, entries []models.GuestListEntry) ([]models.GuestListEntry, error)
+	RemoveGuest(ctx context.Context, eventID, listID, entryID uuid.UUID) error
+	// IssueTickets issues free tickets for a guest and their plus-ones without payment
+	IssueTickets(ctx context.Context, eventID, listID, entryID, staffID uuid.UUID) (BoxOfficeReceipt, error)
+	// SearchGuests finds the guests of an event by name, email or company for door staff
+	SearchGuests(ctx context.Context, eventID uuid.UUID, query string) ([]models.GuestListEntry, error)
+	// CheckIn admits guests of an entry by name, without a QR code
+	CheckIn(ctx context.Context, eventID, entryID uuid.UUID, request GuestCheckInRequest) (models.GuestListEntry, error)
+}
+
+// GuestCheckInRequest admits some or all of the guests of an entry
+type GuestCheckInRequest struct {
+	Guests  int // 0 admits everyone left
+	Gate    string
+	StaffID uuid.UUID
+}
+
+type guestListService struct {
+	db        database.Service
+	boxOffice BoxOfficeService
+}
+
+// NewGuestListService creates a new guest list service
+func NewGuestListService(db database.Service, boxOffice BoxOfficeService) GuestListService {
+	return &guestListService{
+		db:        db,
+		boxOffice: boxOffice,
+	}
+}
+
+func (s *guestListService) ListGuestLists(ctx context.Context, eventID uuid.UUID) ([]models.GuestList, error) {
+	if err := s.ensureEvent(eventID); err != nil {
+		return nil, err
+	}
+	lists, err := s.db.ListGuestListsByEvent(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve guest lists: %w", err)
+	}
+	return lists, nil
+}
+
+func (s *guestListService) CreateGuestList(ctx context.Context, list *models.GuestList) error {
+	if err := list.Validate(); err != nil {
+		return err
+	}
+	if err := s.ensureEvent(list.EventID); err != nil {
+		return err
+	}
+	if err := s.ensureTicketType(list); err != nil {
+		return err
+	}
+
+	list.ID = uuid.New()
+	if err := s.db.CreateGuestList(list); err != nil {
+		return fmt.Errorf("failed to create guest list: %w", err)
+	}
+	return nil
+}
+
+func (s *guestListService) GetGuestList(ctx context.Context, eventID, id uuid.UUID) (models.GuestList, error) {
+	list, err := s.db.FindGuestListById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.GuestList{}, ErrGuestListNotFound
+		}
+		return models.GuestList{}, fmt.Errorf("failed to retrieve guest list: %w", err)
+	}
+	if list.EventID != eventID {
+		return models.GuestList{}, ErrGuestListNotFound
+	}
+	if list.Entries == nil {
+		list.Entries = []models.GuestListEntry{}
+	}
+	return list, nil
+}
+
+// UpdateGuestList changes the name, quota and ticket type of a list. The quota cannot be
+// lowered below the guests already on the list.
+func (s *guestListService) UpdateGuestList(ctx context.Context, list *models.GuestList) error {
+	existing, err := s.GetGuestList(ctx, list.EventID, list.ID)
+	if err != nil {
+		return err
+	}
+	if err := list.Validate(); err != nil {
+		return err
+	}
+	if err := s.ensureTicketType(list); err != nil {
+		return err
+	}
+
+	list.CreatedAt = existing.CreatedAt
+	list.CreatedByID = existing.CreatedByID
+	if err := s.db.UpdateGuestList(list); err != nil {
+		if errors.Is(err, models.ErrGuestListQuotaExceeded) {
+			return err
+		}
+		return fmt.Errorf("failed to update guest list: %w", err)
+	}
+	list.Entries = existing.Entries
+	list.Guests = existing.Guests
+	return nil
+}
+
+// DeleteGuestList removes a list as long as none of its guests was checked in or issued tickets
+func (s *guestListService) DeleteGuestList(ctx context.Context, eventID, id uuid.UUID) error {
+	list, err := s.GetGuestList(ctx, eventID, id)
+	if err != nil {
+		return err
+	}
+	for _, entry := range list.Entries {
+		if entry.CheckedIn > 0 || entry.OrderID != nil {
+			return ErrGuestListInUse
+		}
+	}
+
+	if err := s.db.DeleteGuestList(id); err != nil {
+		return fmt.Errorf("failed to delete guest list: %w", err)
+	}
+	return nil
+}
+
+func (s *guestListService) AddGuests(ctx context.Context, eventID, listID, addedByID uuid.UUID, entries []models.GuestListEntry) ([]models.GuestListEntry, error) {
+	if _, err := s.GetGuestList(ctx, eventID, listID); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return []models.GuestListEntry{}, nil
+	}
+	for i := range entries {
+		if err := entries[i].Validate(); err != nil {
+			return nil, err
+		}
+		entries[i].ID = uuid.New()
+		entries[i].GuestListID = listID
+		entries[i].EventID = eventID
+		entries[i].AddedByID = addedByID
+		entries[i].OrderID = nil
+		entries[i].CheckedIn = 0
+	}
+
+	if err := s.db.AddGuestListEntries(listID, entries); err != nil {
+		if errors.Is(err, models.ErrGuestListQuotaExceeded) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to add guests: %w", err)
+	}
+	return entries, nil
+}
+
+// RemoveGuest takes a guest off a list as long as they were not checked in or issued tickets
+func (s *guestListService) RemoveGuest(ctx context.Context, eventID, listID, entryID uuid.UUID) error {
+	entry, err := s.entry(eventID, entryID)
+	if err != nil {
+		return err
+	}
+	if entry.GuestListID != listID {
+		return ErrGuestNotFound
+	}
+	if entry.CheckedIn > 0 || entry.OrderID != nil {
+		return ErrGuestListInUse
+	}
+
+	if err := s.db.DeleteGuestListEntry(entryID); err != nil {
+		return fmt.Errorf("failed to remove guest: %w", err)
+	}
+	return nil
+}
+
+// IssueTickets sells the guest a complimentary order of the ticket type of the list
+// through the box office, so the tickets take inventory like any other and are issued
+// right away without going through the payment provider.
+func (s *guestListService) IssueTickets(ctx context.Context, eventID, listID, entryID, staffID uuid.UUID) (BoxOfficeReceipt, error) {
+	list, err := s.GetGuestList(ctx, eventID, listID)
+	if err != nil {
+		return BoxOfficeReceipt{}, err
+	}
+	entry, err := s.entry(eventID, entryID)
+	if err != nil {
+		return BoxOfficeReceipt{}, err
+	}
+	switch {
+	case entry.GuestListID != listID:
+		return BoxOfficeReceipt{}, ErrGuestNotFound
+	case list.TicketTypeID == nil:
+		return BoxOfficeReceipt{}, ErrGuestListNoTicketType
+	case entry.Email == "":
+		return BoxOfficeReceipt{}, ErrGuestEmailRequired
+	case entry.OrderID != nil:
+		return BoxOfficeReceipt{}, ErrGuestTicketsIssued
+	}
+
+	firstName, lastName := entry.FirstAndLastName()
+	receipt, err := s.boxOffice.Sell(ctx, BoxOfficeSale{
+		EventID: eventID,
+		StaffID: staffID,
+		Customer: BoxOfficeCustomer{
+			Email:     entry.Email,
+			FirstName: firstName,
+			LastName:  lastName,
+		},
+		Items:   []store.HoldItem{{TicketTypeID: *list.TicketTypeID, Quantity: entry.Admissions()}},
+		Payment: BoxOfficePayment{Method: models.PaymentMethodComp, Reference: "guest list: " + list.Name},
+	})
+	if err != nil {
+		return BoxOfficeReceipt{}, err
+	}
+
+	recorded, err := s.db.SetGuestListEntryOrder(entryID, receipt.Order.ID)
+	if err != nil {
+		return BoxOfficeReceipt{}, fmt.Errorf("failed to record comp order: %w", err)
+	}
+	if !recorded {
+		// Tickets were issued twice concurrently, both orders stay valid and the second
+		// one shows up in the box office report for staff to void
+		log.Printf("Guest %s was issued a second comp order %s", entryID, receipt.Order.ID)
+	}
+	return receipt, nil
+}
+
+func (s *guestListService) SearchGuests(ctx context.Context, eventID uuid.UUID, query string) ([]models.GuestListEntry, error) {
+	query = strings.TrimSpace(query)
+	if len([]rune(query)) < 2 {
+		return nil, ErrGuestSearchTooShort
+	}
+	if err := s.ensureEvent(eventID); err != nil {
+		return nil, err
+	}
+	entries, err := s.db.SearchGuestListEntries(eventID, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search guests: %w", err)
+	}
+	return entries, nil
+}
+
+// CheckIn admits guests of an entry by name. Guests who were issued tickets are admitted
+// with them: their tickets are marked used so the QR codes cannot be used afterwards.
+func (s *guestListService) CheckIn(ctx context.Context, eventID, entryID uuid.UUID, request GuestCheckInRequest) (models.GuestListEntry, error) {
+	entry, err := s.entry(eventID, entryID)
+	if err != nil {
+		return models.GuestListEntry{}, err
+	}
+	guests := request.Guests
+	if guests == 0 {
+		guests = entry.Remaining()
+	}
+	switch {
+	case entry.Remaining() == 0:
+		return models.GuestListEntry{}, models.ErrGuestAlreadyCheckedIn
+	case guests < 0 || guests > entry.Remaining():
+		return models.GuestListEntry{}, models.ErrGuestCheckInOutOfBounds
+	}
+
+	now := time.Now()
+	if entry.OrderID != nil {
+		if guests, err = s.admitTickets(*entry.OrderID, eventID, guests, request, now); err != nil {
+			return models.GuestListEntry{}, err
+		}
+	}
+
+	admitted, err := s.db.CheckInGuests(entryID, guests, request.StaffID, now)
+	if err != nil {
+		return models.GuestListEntry{}, fmt.Errorf("failed to check in guests: %w", err)
+	}
+	if !admitted {
+		return models.GuestListEntry{}, models.ErrGuestCheckInOutOfBounds
+	}
+	return s.entry(eventID, entryID)
+}
+
+// admitTickets marks up to guests valid tickets of the comp order of a guest used and
+// records their check-ins, it returns how many were admitted
+func (s *guestListService) admitTickets(orderID, eventID uuid.UUID, guests int, request GuestCheckInRequest, now time.Time) (int, error) {
+	tickets, err := s.db.ListTicketsByOrder(orderID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve tickets: %w", err)
+	}
+
+	admitted := 0
+	for _, ticket := range tickets {
+		if admitted == guests {
+			break
+		}
+		if ticket.Status != models.TicketStatusValid {
+			continue
+		}
+		marked, err := s.db.MarkTicketUsed(ticket.ID, ticket.Version, now)
+		if err != nil {
+			return 0, fmt.Errorf("failed to mark ticket used: %w", err)
+		}
+		if !marked {
+			continue
+		}
+		checkIn := models.CheckIn{
+			EventID:   eventID,
+			TicketID:  &ticket.ID,
+			Version:   ticket.Version,
+			Gate:      request.Gate,
+			StaffID:   request.StaffID,
+			ScannedAt: now,
+			Accepted:  true,
+			Source:    models.CheckInSourceOnline,
+		}
+		if err := s.db.CreateCheckIn(&checkIn); err != nil {
+			log.Printf("Failed to record check-in of ticket %s: %v", ticket.ID, err)
+		}
+		admitted++
+	}
+	// The remaining tickets were scanned at the door already
+	if admitted == 0 {
+		return 0, models.ErrGuestAlreadyCheckedIn
+	}
+	return admitted, nil
+}
+
+// entry returns a guest of the event
+func (s *guestListService) entry(eventID, entryID uuid.UUID) (models.GuestListEntry, error) {
+	entry, err := s.db.FindGuestListEntryById(entryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.GuestListEntry{}, ErrGuestNotFound
+		}
+		return models.GuestListEntry{}, fmt.Errorf("failed to retrieve guest: %w", err)
+	}
+	if entry.EventID != eventID {
+		return models.GuestListEntry{}, ErrGuestNotFound
+	}
+	return entry, nil
+}
+
+// ensureTicketType checks that comp tickets of the list are issued as a ticket type of its event
+func (s *guestListService) ensureTicketType(list *models.GuestList) error {
+	if list.TicketTypeID == nil {
+		return nil
+	}
+	ticketType, err := s.db.FindTicketTypeById(*list.TicketTypeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && ticketType.EventID != list.EventID) {
+		return ErrTicketTypeNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to retrieve ticket type: %w", err)
+	}
+	return nil
+}
+
+func (s *guestListService) ensureEvent(eventID uuid.UUID) error {
+	if _, err := s.db.FindEventById(eventID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrEventNotFound
+		}
+		return fmt.Errorf("failed to retrieve event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/store"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrHoldEmpty             = errors.New("hold must contain at least one ticket")
+	ErrTicketTypeNotOnSale   = errors.New("ticket type is not on sale")
+	ErrSeatNotInEvent        = errors.New("seat does not belong to the event")
+	ErrSeatNotInSection      = errors.New("seat is outside the section of the ticket type")
+	ErrSeatSelectionMismatch = errors.New("number of seats does not match the ticket quantity")
+	ErrHoldForbidden         = errors.New("hold belongs to another user")
+)
+
+// HoldService reserves event inventory for a buyer for a limited time
+type HoldService interface {
+	CreateHold(ctx context.Context, eventID, userID uuid.UUID, items []store.HoldItem) (*store.Hold, error)
+	// CreateBoxOfficeHold reserves tickets staff sell on behalf of a customer. Group bookings
+	// may go past the per-order maximum and the box office keeps selling after online sales end.
+	CreateBoxOfficeHold(ctx context.Context, eventID, userID uuid.UUID, items []store.HoldItem) (*store.Hold, error)
+	GetHold(ctx context.Context, holdID string, userID uuid.UUID) (*store.Hold, error)
+	ReleaseHold(ctx context.Context, holdID string, userID uuid.UUID) error
+	ReleaseExpiredHolds(ctx context.Context) (int, error)
+	// RunReaper releases expired holds every interval until the context is cancelled
+	RunReaper(ctx context.Context, interval time.Duration)
+}
+
+type holdService struct {
+	db    database.Service
+	holds store.HoldStore
+}
+
+// NewHoldService creates a new hold service
+func NewHoldService(db database.Service, holds store.HoldStore) HoldService {
+	return &holdService{
+		db:    db,
+		holds: holds,
+	}
+}
+
+// CreateHold validates the requested tickets against the event and reserves them atomically
+func (s *holdService) CreateHold(ctx context.Context, eventID, userID uuid.UUID, items []store.HoldItem) (*store.Hold, error) {
+	return s.createHold(ctx, eventID, userID, items, false)
+}
+
+func (s *holdService) CreateBoxOfficeHold(ctx context.Context, eventID, userID uuid.UUID, items []store.HoldItem) (*store.Hold, error) {
+	return s.createHold(ctx, eventID, userID, items, true)
+}
+
+func (s *holdService) createHold(ctx context.Context, eventID, userID uuid.UUID, items []store.HoldItem, boxOffice bool) (*store.Hold, error) {
+	if len(items) == 0 {
+		return nil, ErrHoldEmpty
+	}
+
+	event, err := s.db.FindEventById(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEventNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve event: %w", err)
+	}
+	if event.Status != models.EventStatusPublished {
+		return nil, ErrEventNotFound
+	}
+
+	ticketTypes, err := s.db.ListTicketTypesByEvent(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve ticket types: %w", err)
+	}
+	byID := make(map[uuid.UUID]models.TicketType, len(ticketTypes))
+	for _, ticketType := range ticketTypes {
+		byID[ticketType.ID] = ticketType
+	}
+
+	var seatsByID map[uuid.UUID]models.EventSeat
+	quantities := make(map[uuid.UUID]int)
+	limits := make(map[uuid.UUID]store.InventoryLimit)
+	now := time.Now()
+
+	for _, item := range items {
+		ticketType, ok := byID[item.TicketTypeID]
+		if !ok {
+			return nil, ErrTicketTypeNotFound
+		}
+		onSale := ticketType.IsOnSale(now)
+		if boxOffice {
+			onSale = ticketType.IsOnSaleAtBoxOffice(now)
+		}
+		if !onSale {
+			return nil, ErrTicketTypeNotOnSale
+		}
+		if item.Quantity <= 0 {
+			return nil, models.ErrTicketTypeOrderOutOfBounds
+		}
+
+		if len(item.SeatIDs) > 0 {
+			if len(item.SeatIDs) != item.Quantity {
+				return nil, ErrSeatSelectionMismatch
+			}
+			if seatsByID == nil {
+				if seatsByID, err = s.eventSeats(eventID); err != nil {
+					return nil, err
+				}
+			}
+			if err := checkSeats(item, ticketType, seatsByID); err != nil {
+				return nil, err
+			}
+		}
+
+		quantities[ticketType.ID] += item.Quantity
+		limits[ticketType.ID] = store.InventoryLimit{Capacity: ticketType.Quantity}
+	}
+
+	ticketTypeIDs := make([]uuid.UUID, 0, len(quantities))
+	for id, quantity := range quantities {
+		ticketType := byID[id]
+		if boxOffice {
+			ticketType.MaxPerOrder = 0
+		}
+		if err := ticketType.CheckOrderQuantity(quantity); err != nil {
+			return nil, err
+		}
+		ticketTypeIDs = append(ticketTypeIDs, id)
+	}
+
+	// Tickets freed while users are waiting belong to the waitlist
+	waiting, err := s.db.CountWaitingEntries(ticketTypeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count waiting users: %w", err)
+	}
+	for id, count := range waiting {
+		if count > 0 {
+			return nil, &store.SoldOutError{TicketTypeID: id}
+		}
+	}
+
+	// Tickets already in orders seed the Redis counters the first time they are used
+	taken, err := s.db.CountTakenTickets(ticketTypeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count sold tickets: %w", err)
+	}
+	for id, limit := range limits {
+		limit.Taken = taken[id]
+		limits[id] = limit
+	}
+
+	// Give back expired inventory before checking availability so buyers are not
+	// turned away while the reaper has not caught up yet
+	if _, err := s.holds.ReleaseExpired(ctx, now); err != nil {
+		log.Printf("Failed to release expired holds: %v", err)
+	}
+
+	hold := &store.Hold{
+		EventID: eventID,
+		UserID:  userID,
+		Items:   items,
+	}
+	// Staff selling at the box office are not bound by the limits of online buyers
+	if !boxOffice {
+		limit, err := userPurchaseLimit(s.db, event, userID)
+		if err != nil {
+			return nil, err
+		}
+		hold.Limits = []store.PurchaseLimit{limit}
+	}
+	if err := s.holds.Create(ctx, hold, limits); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// userPurchaseLimit returns the limit on the tickets of the event the user may buy online,
+// counting the tickets the user already bought
+func userPurchaseLimit(db database.Service, event models.Event, userID uuid.UUID) (store.PurchaseLimit, error) {
+	taken, err := db.CountUserTickets(event.ID, userID)
+	if err != nil {
+		return store.PurchaseLimit{}, fmt.Errorf("failed to count tickets bought by user: %w", err)
+	}
+	return store.PurchaseLimit{
+		Key:   store.UserLimitKey(event.ID, userID),
+		Max:   event.MaxTicketsPerUser,
+		Taken: taken,
+	}, nil
+}
+
+func (s *holdService) eventSeats(eventID uuid.UUID) (map[uuid.UUID]models.EventSeat, error) {
+	seats, err := s.db.ListEventSeats(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve event seats: %w", err)
+	}
+	byID := make(map[uuid.UUID]models.EventSeat, len(seats))
+	for _, seat := range seats {
+		byID[seat.ID] = seat
+	}
+	return byID, nil
+}
+
+// checkSeats makes sure every selected seat exists, can be sold and lies in the ticket type's section
+func checkSeats(item store.HoldItem, ticketType models.TicketType, seats map[uuid.UUID]models.EventSeat) error {
+	seen := make(map[uuid.UUID]bool, len(item.SeatIDs))
+	for _, seatID := range item.SeatIDs {
+		seat, ok := seats[seatID]
+		if !ok {
+			return ErrSeatNotInEvent
+		}
+		if seen[seatID] {
+			return ErrSeatSelectionMismatch
+		}
+		seen[seatID] = true
+		if seat.Status != models.EventSeatAvailable {
+			return &store.SeatUnavailableError{SeatID: seatID}
+		}
+		if ticketType.SectionID != nil && seat.SectionID != *ticketType.SectionID {
+			return ErrSeatNotInSection
+		}
+	}
+	return nil
+}
+
+// GetHold retrieves an active hold owned by the given user
+func (s *holdService) GetHold(ctx context.Context, holdID string, userID uuid.UUID) (*store.Hold, error) {
+	hold, err := s.holds.Get(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	if hold.UserID != userID {
+		return nil, ErrHoldForbidden
+	}
+	return hold, nil
+}
+
+// ReleaseHold gives the inventory of a user's hold back to sale
+func (s *holdService) ReleaseHold(ctx context.Context, holdID string, userID uuid.UUID) error {
+	if _, err := s.GetHold(ctx, holdID, userID); err != nil {
+		return err
+	}
+	if _, err := s.holds.Release(ctx, holdID); err != nil {
+		return fmt.Errorf("failed to release hold: %w", err)
+	}
+	return nil
+}
+
+// ReleaseExpiredHolds returns the inventory of every expired hold
+func (s *holdService) ReleaseExpiredHolds(ctx context.Context) (int, error) {
+	return s.holds.ReleaseExpired(ctx, time.Now())
+}
+
+func (s *holdService) RunReaper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := s.ReleaseExpiredHolds(ctx)
+			if err != nil {
+				log.Printf("Failed to release expired holds: %v", err)
+				continue
+			}
+			if released > 0 {
+				log.Printf("Released %d expired holds", released)
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"passIt/internal/database"
+	"passIt/internal/invoices"
+	"passIt/internal/models"
+	"passIt/internal/pricing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrInvoiceNotAvailable is returned for orders that were never paid
+var ErrInvoiceNotAvailable = errors.New("invoices are only available for paid orders")
+
+// InvoiceService issues the invoices of paid orders
+type InvoiceService interface {
+	// IssueInvoice creates the invoice of a paid order, or returns the existing one
+	IssueInvoice(ctx context.Context, orderID uuid.UUID) (models.Invoice, error)
+	// GetUserInvoice returns the invoice of an order of the user, issuing it if needed
+	GetUserInvoice(ctx context.Context, orderID, userID uuid.UUID) (models.Invoice, error)
+}
+
+type invoiceService struct {
+	db     database.Service
+	orders OrderService
+	cfg    *invoices.Config
+}
+
+// NewInvoiceService creates a new invoice service
+func NewInvoiceService(db database.Service, orders OrderService, cfg *invoices.Config) InvoiceService {
+	return &invoiceService{
+		db:     db,
+		orders: orders,
+		cfg:    cfg,
+	}
+}
+
+func (s *invoiceService) IssueInvoice(ctx context.Context, orderID uuid.UUID) (models.Invoice, error) {
+	order, err := s.orders.GetOrder(ctx, orderID)
+	if err != nil {
+		return models.Invoice{}, err
+	}
+	return s.issue(&order)
+}
+
+func (s *invoiceService) GetUserInvoice(ctx context.Context, orderID, userID uuid.UUID) (models.Invoice, error) {
+	order, err := s.orders.GetUserOrder(ctx, orderID, userID)
+	if err != nil {
+		return models.Invoice{}, err
+	}
+	return s.issue(&order)
+}
+
+// issue returns the invoice of the order, creating it on first use. Invoices are
+// normally issued on payment; orders whose invoice failed then get it on download.
+func (s *invoiceService) issue(order *models.Order) (models.Invoice, error) {
+	if order.PaidAt == nil {
+		return models.Invoice{}, ErrInvoiceNotAvailable
+	}
+
+	invoice, err := s.db.FindInvoiceByOrder(order.ID)
+	if err == nil {
+		return invoice, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Invoice{}, fmt.Errorf("failed to retrieve invoice: %w", err)
+	}
+
+	event, err := s.db.FindEventById(order.EventID)
+	if err != nil {
+		return models.Invoice{}, fmt.Errorf("failed to retrieve event: %w", err)
+	}
+	user, err := s.db.FindUserById(order.UserID)
+	if err != nil {
+		return models.Invoice{}, fmt.Errorf("failed to retrieve buyer: %w", err)
+	}
+
+	invoice = models.Invoice{
+		OrderID:  order.ID,
+		UserID:   order.UserID,
+		IssuedAt: time.Now().UTC(),
+		Billing:  order.BillingDetailsOf(user),
+		Currency: order.Currency,
+		Total:    order.Total,
+	}
+	render := func(invoice *models.Invoice) ([]byte, error) {
+		return invoices.Render(s.document(invoice, order, event))
+	}
+	if err := s.db.CreateInvoice(&invoice, render); err != nil {
+		// Another request issued the invoice of this order at the same time
+		if existing, findErr := s.db.FindInvoiceByOrder(order.ID); findErr == nil {
+			return existing, nil
+		}
+		return models.Invoice{}, fmt.Errorf("failed to create invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+func (s *invoiceService) document(invoice *models.Invoice, order *models.Order, event models.Event) invoices.Document {
+	doc := invoices.Document{
+		Number:    invoice.Number,
+		IssuedAt:  invoice.IssuedAt,
+		PaidAt:    *order.PaidAt,
+		Reference: order.ID.String(),
+		Event:     event.Title + ", " + event.StartsAt.Format("2006-01-02"),
+		Seller: invoices.Party{
+			Name:    s.cfg.IssuerName,
+			Address: s.cfg.IssuerAddress,
+			TaxID:   s.cfg.IssuerTaxID,
+		},
+		Buyer: invoices.Party{
+			Name:    invoice.Billing.Name,
+			Company: invoice.Billing.Company,
+			Address: invoice.Billing.Address,
+			TaxID:   invoice.Billing.TaxID,
+			Email:   invoice.Billing.Email,
+		},
+		Currency: order.Currency,
+		Subtotal: order.Subtotal,
+		Discount: order.Discount,
+		Total:    order.Total,
+	}
+	for _, item := range order.Items {
+		doc.Lines = append(doc.Lines, invoices.Line{
+			Description: item.Name,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Discount:    item.Discount,
+			Total:       item.UnitPrice*int64(item.Quantity) - item.Discount,
+		})
+	}
+
+	if order.Breakdown != nil {
+		doc.Fees = order.Breakdown.Fees
+		doc.Taxes = order.Breakdown.Taxes
+	} else {
+		if order.ServiceFee > 0 {
+			doc.Fees = []pricing.Charge{{Name: "Service fees", Amount: order.ServiceFee}}
+		}
+		if order.Tax > 0 {
+			doc.Taxes = []pricing.Charge{{Name: "Taxes", Amount: order.Tax}}
+		}
+	}
+	return doc
+}
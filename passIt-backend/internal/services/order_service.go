@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"passIt/internal/constant"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/store"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrderNotFound  = errors.New("order not found")
+	ErrOrderForbidden = errors.New("order belongs to another user")
+	// ErrOrderConflict is returned when the order changed status while it was being updated
+	ErrOrderConflict = errors.New("order was modified concurrently")
+)
+
+// OrderService turns holds into orders and moves orders through their lifecycle
+type OrderService interface {
+	// Checkout converts an active hold of the user into a pending order
+	Checkout(ctx context.Context, userID uuid.UUID, holdID string, opts CheckoutOptions) (models.Order, error)
+	GetOrder(ctx context.Context, orderID uuid.UUID) (models.Order, error)
+	GetUserOrder(ctx context.Context, orderID, userID uuid.UUID) (models.Order, error)
+	ListUserOrders(ctx context.Context, userID uuid.UUID) ([]models.Order, error)
+	CancelOrder(ctx context.Context, orderID, userID uuid.UUID) (models.Order, error)
+	// Transition moves an order to the next status, enforcing the order state machine
+	Transition(ctx context.Context, orderID uuid.UUID, next models.OrderStatus) (models.Order, error)
+	ExpireOrders(ctx context.Context) (int, error)
+	// RunExpiryWorker expires unpaid orders every interval until the context is cancelled
+	RunExpiryWorker(ctx context.Context, interval time.Duration)
+}
+
+// CheckoutOptions are the optional choices of the buyer at checkout
+type CheckoutOptions struct {
+	PromoCodes []string               // applied in the given order
+	Billing    *models.BillingDetails // who the invoice is made out to, the buyer's profile when nil
+	// Attendees are the answers to the attendee questions of the event per ticket type,
+	// the n-th answer belongs to the n-th ticket of the type
+	Attendees map[uuid.UUID][]map[string]any
+	// SoldByID is the box office staff member selling the order on behalf of the buyer
+	SoldByID *uuid.UUID
+	// Complimentary gives the tickets away, promo codes, fees and taxes are not applied
+	Complimentary bool
+	// IP is the address the buyer checks out from, empty for box office sales
+	IP string
+}
+
+type orderService struct {
+	db         database.Service
+	holds      store.HoldStore
+	promoCodes PromoCodeService
+	pricing    PricingService
+	risk       RiskAssessor
+}
+
+// NewOrderService creates a new order service
+func NewOrderService(db database.Service, holds store.HoldStore, promoCodes PromoCodeService, pricing PricingService, risk RiskAssessor) OrderService {
+	return &orderService{
+		db:         db,
+		holds:      holds,
+		promoCodes: promoCodes,
+		pricing:    pricing,
+		risk:       risk,
+	}
+}
+
+// Checkout claims the hold and stores the order. The hold is claimed first so it cannot
+// expire or be checked out twice; if the order cannot be stored its inventory is given back.
+// Promo codes are redeemed together with the order, so a code that runs out in the
+// meantime fails the checkout rather than going over its limits. The order is scored for
+// risk before it is stored, so it is never payable before its review flag is set.
+func (s *orderService) Checkout(ctx context.Context, userID uuid.UUID, holdID string, opts CheckoutOptions) (models.Order, error) {
+	hold, err := s.holds.Get(ctx, holdID)
+	if err != nil {
+		return models.Order{}, err
+	}
+	if hold.UserID != userID {
+		return models.Order{}, ErrHoldForbidden
+	}
+
+	order, err := s.buildOrder(hold)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	order.Billing = opts.Billing
+	order.SoldByID = opts.SoldByID
+
+	// Answers are stored with the order, so a checkout missing required answers fails as a whole
+	forms, err := s.db.ListAttendeeFormsByEvent(order.EventID)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("failed to retrieve attendee forms: %w", err)
+	}
+	if err := order.AttachAnswers(forms, opts.Attendees); err != nil {
+		return models.Order{}, err
+	}
+
+	var redemptions []models.PromoRedemption
+	if opts.Complimentary {
+		if err := order.MakeComplimentary(); err != nil {
+			return models.Order{}, err
+		}
+	} else {
+		if len(opts.PromoCodes) > 0 {
+			codes, err := s.promoCodes.ResolveCodes(ctx, opts.PromoCodes)
+			if err != nil {
+				return models.Order{}, err
+			}
+			if redemptions, err = order.ApplyPromoCodes(codes, time.Now()); err != nil {
+				return models.Order{}, err
+			}
+		}
+		// Fees and taxes are charged on the discounted price
+		if err := s.pricing.PriceOrder(ctx, &order); err != nil {
+			return models.Order{}, err
+		}
+	}
+
+	order.ExpiresAt = time.Now().Add(constant.OrderPaymentWindow)
+	s.risk.AssessOrder(ctx, &order, opts.IP)
+	if opts.SoldByID != nil && order.AwaitsReview() {
+		// Staff selling in person vouch for the buyer, the score is kept for the record
+		now := time.Now()
+		if err := order.Review(true, *opts.SoldByID, now); err != nil {
+			return models.Order{}, err
+		}
+		order.ExpiresAt = now.Add(constant.OrderPaymentWindow)
+	}
+
+	claimed, err := s.holds.Claim(ctx, holdID)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	if len(redemptions) > 0 {
+		err = s.db.CreateOrderWithRedemptions(&order, redemptions)
+	} else {
+		err = s.db.CreateOrder(&order)
+	}
+	if err != nil {
+		if returnErr := s.holds.ReturnInventory(ctx, claimed); returnErr != nil {
+			log.Printf("Failed to return inventory of hold %s: %v", holdID, returnErr)
+		}
+		if errors.Is(err, models.ErrPromoCodeExhausted) || errors.Is(err, models.ErrPromoCodeUserLimit) {
+			return models.Order{}, err
+		}
+		return models.Order{}, fmt.Errorf("failed to create order: %w", err)
+	}
+	return order, nil
+}
+
+// buildOrder prices the items of a hold with the current ticket type prices.
+// Seated tickets get one line per seat so every seat can be tracked on its own.
+func (s *orderService) buildOrder(hold *store.Hold) (models.Order, error) {
+	ticketTypes, err := s.db.ListTicketTypesByEvent(hold.EventID)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("failed to retrieve ticket types: %w", err)
+	}
+	byID := make(map[uuid.UUID]models.TicketType, len(ticketTypes))
+	for _, ticketType := range ticketTypes {
+		byID[ticketType.ID] = ticketType
+	}
+
+	order := models.Order{
+		UserID:  hold.UserID,
+		EventID: hold.EventID,
+		HoldID:  hold.ID,
+		Status:  models.OrderStatusPending,
+	}
+	for _, item := range hold.Items {
+		ticketType, ok := byID[item.TicketTypeID]
+		if !ok {
+			return models.Order{}, ErrTicketTypeNotFound
+		}
+		if order.Currency == "" {
+			order.Currency = ticketType.Currency
+		} else if order.Currency != ticketType.Currency {
+			return models.Order{}, models.ErrOrderMixedCurrency
+		}
+
+		if len(item.SeatIDs) == 0 {
+			order.Items = append(order.Items, models.OrderItem{
+				TicketTypeID: ticketType.ID,
+				Name:         ticketType.Name,
+				UnitPrice:    ticketType.Price,
+				Quantity:     item.Quantity,
+			})
+			continue
+		}
+		for _, seatID := range item.SeatIDs {
+			seatID := seatID
+			order.Items = append(order.Items, models.OrderItem{
+				TicketTypeID: ticketType.ID,
+				EventSeatID:  &seatID,
+				Name:         ticketType.Name,
+				UnitPrice:    ticketType.Price,
+				Quantity:     1,
+			})
+		}
+	}
+
+	if err := order.CalculateTotals(); err != nil {
+		return models.Order{}, err
+	}
+	return order, nil
+}
+
+// GetOrder retrieves an order with its items
+func (s *orderService) GetOrder(ctx context.Context, orderID uuid.UUID) (models.Order, error) {
+	order, err := s.db.FindOrderById(orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Order{}, ErrOrderNotFound
+		}
+		return models.Order{}, fmt.Errorf("failed to retrieve order: %w", err)
+	}
+	return order, nil
+}
+
+// GetUserOrder retrieves an order, making sure it belongs to the given user
+func (s *orderService) GetUserOrder(ctx context.Context, orderID, userID uuid.UUID) (models.Order, error) {
+	order, err := s.GetOrder(ctx, orderID)
+	if err != nil {
+		return models.Order{}, err
+	}
+	if order.UserID != userID {
+		return models.Order{}, ErrOrderForbidden
+	}
+	return order, nil
+}
+
+// ListUserOrders retrieves the orders of a user, newest first
+func (s *orderService) ListUserOrders(ctx context.Context, userID uuid.UUID) ([]models.Order, error) {
+	orders, err := s.db.ListOrdersByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve orders: %w", err)
+	}
+	return orders, nil
+}
+
+// CancelOrder cancels an unpaid order of the user and gives its tickets back to sale
+func (s *orderService) CancelOrder(ctx context.Context, orderID, userID uuid.UUID) (models.Order, error) {
+	if _, err := s.GetUserOrder(ctx, orderID, userID); err != nil {
+		return models.Order{}, err
+	}
+	return s.Transition(ctx, orderID, models.OrderStatusCancelled)
+}
+
+func (s *orderService) Transition(ctx context.Context, orderID uuid.UUID, next models.OrderStatus) (models.Order, error) {
+	order, err := s.GetOrder(ctx, orderID)
+	if err != nil {
+		return models.Order{}, err
+	}
+	if err := s.transition(ctx, &order, next); err != nil {
+		return models.Order{}, err
+	}
+	return order, nil
+}
+
+// transition applies a status change guarded by the current status, so concurrent
+// updates (e.g. a payment arriving while the order expires) cannot both succeed
+func (s *orderService) transition(ctx context.Context, order *models.Order, next models.OrderStatus) error {
+	if !order.Status.CanTransitionTo(next) {
+		return models.ErrOrderInvalidStatus
+	}
+
+	from := order.Status
+	now := time.Now()
+	order.Status = next
+	switch next {
+	case models.OrderStatusPaid:
+		order.PaidAt = &now
+	case models.OrderStatusFulfilled:
+		order.FulfilledAt = &now
+	case models.OrderStatusCancelled:
+		order.CancelledAt = &now
+	}
+
+	updated, err := s.db.TransitionOrder(order, from)
+	if err != nil {
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+	if !updated {
+		return ErrOrderConflict
+	}
+
+	if next.ReleasesInventory() && order.IsResale() {
+		// The ticket was never taken from inventory, it goes back on the resale marketplace
+		if err := s.db.ReleaseResaleListing(order.ID); err != nil {
+			log.Printf("Failed to release resale listing of order %s: %v", order.ID, err)
+		}
+	} else if next.ReleasesInventory() {
+		if err := s.holds.ReturnInventory(ctx, holdFromOrder(order)); err != nil {
+			log.Printf("Failed to return inventory of order %s: %v", order.ID, err)
+		}
+	} else if next == models.OrderStatusPaid && !order.IsResale() {
+		// Seats go from held to sold on the seat maps buyers are browsing
+		if err := s.holds.PublishAvailability(ctx, store.AvailabilitySold, holdFromOrder(order)); err != nil {
+			log.Printf("Failed to publish availability of order %s: %v", order.ID, err)
+		}
+	}
+	return nil
+}
+
+// holdFromOrder rebuilds the inventory reserved by an order so it can be given back
+func holdFromOrder(order *models.Order) *store.Hold {
+	hold := &store.Hold{
+		ID:      order.HoldID,
+		EventID: order.EventID,
+		UserID:  order.UserID,
+	}
+	for _, item := range order.Items {
+		holdItem := store.HoldItem{
+			TicketTypeID: item.TicketTypeID,
+			Quantity:     item.Quantity,
+		}
+		if item.EventSeatID != nil {
+			holdItem.SeatIDs = []uuid.UUID{*item.EventSeatID}
+		}
+		hold.Items = append(hold.Items, holdItem)
+	}
+	// Tickets bought online count against the purchase limit of the buyer
+	if order.SoldByID == nil && order.ResaleListingID == nil {
+		hold.Limits = []store.PurchaseLimit{{Key: store.UserLimitKey(order.EventID, order.UserID)}}
+	}
+	return hold
+}
+
+// ExpireOrders expires every unpaid order past its payment window and returns how many expired
+func (s *orderService) ExpireOrders(ctx context.Context) (int, error) {
+	orders, err := s.db.ListExpiredOrders(time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired orders: %w", err)
+	}
+
+	expired := 0
+	for i := range orders {
+		if err := s.transition(ctx, &orders[i], models.OrderStatusExpired); err != nil {
+			if !errors.Is(err, ErrOrderConflict) {
+				log.Printf("Failed to expire order %s: %v", orders[i].ID, err)
+			}
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+func (s *orderService) RunExpiryWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := s.ExpireOrders(ctx)
+			if err != nil {
+				log.Printf("Failed to expire orders: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("Expired %d unpaid orders", expired)
+			}
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"passIt/internal/database"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrOrgSlugTaken         = errors.New("an organization with this slug already exists")
+	ErrOrgMemberNotFound    = errors.New("user is not a member of the organization")
+	ErrOrgMemberExists      = errors.New("user is already a member of the organization")
+	ErrOrgUserNotFound      = errors.New("no user with this email")
+)
+
+// OrganizationService manages organizers, their members and the data of their events
+type OrganizationService interface {
+	// CreateOrganization stores an organization with the user of the given email as its owner
+	CreateOrganization(ctx context.Context, org *models.Organization, ownerEmail string) error
+	GetOrganization(ctx context.Context, id uuid.UUID) (models.Organization, error)
+	ListOrganizations(ctx context.Context) ([]models.Organization, error)
+	ListUserMemberships(ctx context.Context, userID uuid.UUID) ([]models.OrgMembership, error)
+
+	ListMembers(ctx context.Context, orgID uuid.UUID) ([]models.OrgMembership, error)
+	AddMember(ctx context.Context, orgID uuid.UUID, email string, role models.OrgRole) (models.OrgMembership, error)
+	UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role models.OrgRole) (models.OrgMembership, error)
+	RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error
+
+	ListEvents(ctx context.Context, orgID uuid.UUID, statuses ...models.EventStatus) ([]models.Event, error)
+	ListOrders(ctx context.Context, orgID uuid.UUID, eventID *uuid.UUID) ([]models.Order, error)
+	SalesReport(ctx context.Context, orgID uuid.UUID) ([]models.EventSalesReport, error)
+}
+
+type organizationService struct {
+	db database.Service
+}
+
+// NewOrganizationService creates a new organization service
+func NewOrganizationService(db database.Service) OrganizationService {
+	return &organizationService{
+		db: db,
+	}
+}
+
+func (s *organizationService) CreateOrganization(ctx context.Context, org *models.Organization, ownerEmail string) error {
+	org.ID = uuid.Nil
+	if err := org.Validate(); err != nil {
+		return err
+	}
+	owner, err := s.userByEmail(ownerEmail)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.db.FindOrganizationBySlug(org.Slug); err == nil {
+		return ErrOrgSlugTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to check organization slug: %w", err)
+	}
+
+	if err := s.db.CreateOrganization(org, owner.ID); err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+	return nil
+}
+
+// GetOrganization retrieves an organization by ID
+func (s *organizationService) GetOrganization(ctx context.Context, id uuid.UUID) (models.Organization, error) {
+	org, err := s.db.FindOrganizationById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Organization{}, ErrOrganizationNotFound
+		}
+		return models.Organization{}, fmt.Errorf("failed to retrieve organization: %w", err)
+	}
+	return org, nil
+}
+
+// ListOrganizations retrieves every organization by name
+func (s *organizationService) ListOrganizations(ctx context.Context) ([]models.Organization, error) {
+	orgs, err := s.db.ListOrganizations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve organizations: %w", err)
+	}
+	return orgs, nil
+}
+
+// ListUserMemberships retrieves the organizations a user belongs to with their role
+func (s *organizationService) ListUserMemberships(ctx context.Context, userID uuid.UUID) ([]models.OrgMembership, error) {
+	memberships, err := s.db.ListMembershipsByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve memberships: %w", err)
+	}
+	return memberships, nil
+}
+
+// ListMembers retrieves the members of an organization with their user profiles
+func (s *organizationService) ListMembers(ctx context.Context, orgID uuid.UUID) ([]models.OrgMembership, error) {
+	memberships, err := s.db.ListMemberships(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve members: %w", err)
+	}
+	return memberships, nil
+}
+
+// AddMember gives the user of the given email a role in the organization
+func (s *organizationService) AddMember(ctx context.Context, orgID uuid.UUID, email string, role models.OrgRole) (models.OrgMembership, error) {
+	if !role.IsValid() {
+		return models.OrgMembership{}, models.ErrOrgInvalidRole
+	}
+	if _, err := s.GetOrganization(ctx, orgID); err != nil {
+		return models.OrgMembership{}, err
+	}
+	user, err := s.userByEmail(email)
+	if err != nil {
+		return models.OrgMembership{}, err
+	}
+
+	if _, err := s.db.FindMembership(orgID, user.ID); err == nil {
+		return models.OrgMembership{}, ErrOrgMemberExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.OrgMembership{}, fmt.Errorf("failed to retrieve membership: %w", err)
+	}
+
+	membership := models.OrgMembership{
+		OrganizationID: orgID,
+		UserID:         user.ID,
+		Role:           role,
+	}
+	if err := s.db.CreateMembership(&membership); err != nil {
+		return models.OrgMembership{}, fmt.Errorf("failed to create membership: %w", err)
+	}
+	membership.User = &user
+	return membership, nil
+}
+
+// UpdateMemberRole changes the role of a member. The last owner cannot be demoted.
+func (s *organizationService) UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role models.OrgRole) (models.OrgMembership, error) {
+	if !role.IsValid() {
+		return models.OrgMembership{}, models.ErrOrgInvalidRole
+	}
+	if err := s.db.UpdateMembershipRole(orgID, userID, role); err != nil {
+		return models.OrgMembership{}, s.membershipError(err, "failed to update membership")
+	}
+
+	membership, err := s.db.FindMembership(orgID, userID)
+	if err != nil {
+		return models.OrgMembership{}, s.membershipError(err, "failed to retrieve membership")
+	}
+	return membership, nil
+}
+
+// RemoveMember takes a user out of the organization. The last owner cannot be removed.
+func (s *organizationService) RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error {
+	if err := s.db.DeleteMembership(orgID, userID); err != nil {
+		return s.membershipError(err, "failed to delete membership")
+	}
+	return nil
+}
+
+func (s *organizationService) membershipError(err error, message string) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrOrgMemberNotFound
+	case errors.Is(err, models.ErrOrgLastOwner):
+		return err
+	default:
+		return fmt.Errorf("%s: %w", message, err)
+	}
+}
+
+// ListEvents retrieves the events of an organization, drafts included
+func (s *organizationService) ListEvents(ctx context.Context, orgID uuid.UUID, statuses ...models.EventStatus) ([]models.Event, error) {
+	events, err := s.db.ListEventsByOrganization(orgID, statuses...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve events: %w", err)
+	}
+	return events, nil
+}
+
+// ListOrders retrieves the orders of the events of an organization, newest first
+func (s *organizationService) ListOrders(ctx context.Context, orgID uuid.UUID, eventID *uuid.UUID) ([]models.Order, error) {
+	orders, err := s.db.ListOrdersByOrganization(orgID, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve orders: %w", err)
+	}
+	return orders, nil
+}
+
+// SalesReport sums up the paid orders of every event of an organization
+func (s *organizationService) SalesReport(ctx context.Context, orgID uuid.UUID) ([]models.EventSalesReport, error) {
+	report, err := s.db.SalesReportByOrganization(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sales report: %w", err)
+	}
+	return report, nil
+}
+
+func (s *organizationService) userByEmail(email string) (models.User, error) {
+	user, err := s.db.FindUserByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.User{}, ErrOrgUserNotFound
+		}
+		return models.User{}, fmt.Errorf("failed to retrieve user: %w", err)
+	}
+	return user, nil
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/payments"
+	"passIt/internal/store"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPaymentNotFound       = errors.New("payment not found")
+	ErrPaymentInProgress     = errors.New("a payment for this order is already in progress")
+	ErrPaymentAmountMismatch = errors.New("webhook amount does not match the payment")
+	ErrPaymentProvider       = errors.New("payment provider error")
+)
+
+// PaymentService collects payments for orders through the configured payment provider
+type PaymentService interface {
+	// PayOrder starts or retries the payment of an unpaid order of the user
+	PayOrder(ctx context.Context, orderID, userID uuid.UUID, paymentMethod string) (models.Payment, error)
+	// HandleWebhook verifies and applies a provider notification
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+	// RecordBoxOfficePayment marks an unpaid order paid with a payment staff collected at
+	// the box office, and issues its tickets
+	RecordBoxOfficePayment(ctx context.Context, orderID uuid.UUID, payment BoxOfficePayment) (models.Payment, error)
+}
+
+// BoxOfficePayment is a payment collected by staff outside the payment provider
+type BoxOfficePayment struct {
+	Method    models.PaymentMethod
+	StaffID   uuid.UUID
+	Reference string // e.g. the receipt number of the card terminal
+}
+
+type paymentService struct {
+	db       database.Service
+	provider payments.PaymentProvider
+	orders   OrderService
+	tickets  TicketService
+	invoices InvoiceService
+	holds    store.HoldStore
+}
+
+// NewPaymentService creates a new payment service
+func NewPaymentService(db database.Service, provider payments.PaymentProvider, orders OrderService, tickets TicketService, invoices InvoiceService, holds store.HoldStore) PaymentService {
+	return &paymentService{
+		db:       db,
+		provider: provider,
+		orders:   orders,
+		tickets:  tickets,
+		invoices: invoices,
+		holds:    holds,
+	}
+}
+
+// PayOrder creates a payment intent for the order total and confirms it with the
+// given payment method. The order is only marked paid once the provider's webhook arrives.
+func (s *paymentService) PayOrder(ctx context.Context, orderID, userID uuid.UUID, paymentMethod string) (models.Payment, error) {
+	order, err := s.orders.GetUserOrder(ctx, orderID, userID)
+	if err != nil {
+		return models.Payment{}, err
+	}
+	if !order.Status.IsUnpaid() {
+		return models.Payment{}, models.ErrOrderInvalidStatus
+	}
+	if order.AwaitsReview() {
+		return models.Payment{}, models.ErrOrderUnderReview
+	}
+	if order.Total == 0 {
+		return s.completeFreeOrder(ctx, order)
+	}
+
+	payment, err := s.db.FindLatestPaymentByOrder(order.ID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if payment, err = s.createPayment(ctx, order); err != nil {
+			return models.Payment{}, err
+		}
+	case err != nil:
+		return models.Payment{}, fmt.Errorf("failed to retrieve payment: %w", err)
+	case payment.Status != models.PaymentStatusPending && payment.Status != models.PaymentStatusFailed:
+		return models.Payment{}, ErrPaymentInProgress
+	}
+
+	if order.Status == models.OrderStatusPending {
+		if _, err := s.orders.Transition(ctx, order.ID, models.OrderStatusAwaitingPayment); err != nil {
+			return models.Payment{}, err
+		}
+	}
+
+	if err := s.reservePaymentMethod(ctx, order, &payment, paymentMethod); err != nil {
+		return models.Payment{}, err
+	}
+
+	intent, err := s.provider.ConfirmIntent(ctx, payment.IntentID, paymentMethod)
+	if err != nil {
+		s.returnPaymentMethod(ctx, payment)
+		return models.Payment{}, fmt.Errorf("%w: %v", ErrPaymentProvider, err)
+	}
+
+	// The webhook may already have been handled, so only move forward from the states we saw
+	if intent.Status == payments.IntentFailed {
+		payment.Status = models.PaymentStatusFailed
+		payment.FailureReason = intent.FailureReason
+		s.returnPaymentMethod(ctx, payment)
+	} else {
+		payment.Status = models.PaymentStatusProcessing
+		payment.FailureReason = ""
+	}
+	if _, err := s.db.TransitionPayment(&payment, models.PaymentStatusPending, models.PaymentStatusFailed); err != nil {
+		return models.Payment{}, fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	return s.paymentByIntent(payment.IntentID)
+}
+
+// reservePaymentMethod counts the tickets of the order against the purchase limit of the
+// payment method and records the method on the payment, so a payment that fails gives
+// them back
+func (s *paymentService) reservePaymentMethod(ctx context.Context, order models.Order, payment *models.Payment, paymentMethod string) error {
+	event, err := s.db.FindEventById(order.EventID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve event: %w", err)
+	}
+	// Tokens are issued per use, the card behind them is what the limit applies to
+	fingerprint, err := s.provider.PaymentMethodFingerprint(ctx, paymentMethod)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPaymentProvider, err)
+	}
+	taken, err := s.db.CountPaymentMethodTickets(order.EventID, fingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to count tickets bought with payment method: %w", err)
+	}
+	limit := store.PurchaseLimit{
+		Key:   store.PaymentMethodLimitKey(order.EventID, fingerprint),
+		Max:   event.MaxTicketsPerPaymentMethod,
+		Taken: taken,
+	}
+	if err := s.holds.ReserveLimit(ctx, limit, order.TicketCount()); err != nil {
+		return err
+	}
+
+	payment.MethodFingerprint = fingerprint
+	payment.LimitedTickets = order.TicketCount()
+	updated, err := s.db.SetPaymentMethod(payment)
+	if err != nil || !updated {
+		s.returnPaymentMethod(ctx, *payment)
+		if err != nil {
+			return fmt.Errorf("failed to update payment: %w", err)
+		}
+		return ErrPaymentInProgress
+	}
+	payment.Status = models.PaymentStatusPending
+	payment.FailureReason = ""
+	return nil
+}
+
+// returnPaymentMethod gives the tickets of a payment that did not go through back to
+// the purchase limit of its payment method
+func (s *paymentService) returnPaymentMethod(ctx context.Context, payment models.Payment) {
+	if payment.MethodFingerprint == "" || payment.LimitedTickets == 0 {
+		return
+	}
+	order, err := s.orders.GetOrder(ctx, payment.OrderID)
+	if err != nil {
+		log.Printf("Failed to return payment method limit of payment %s: %v", payment.IntentID, err)
+		return
+	}
+	key := store.PaymentMethodLimitKey(order.EventID, payment.MethodFingerprint)
+	if err := s.holds.ReturnLimit(ctx, key, payment.LimitedTickets); err != nil {
+		log.Printf("Failed to return payment method limit of payment %s: %v", payment.IntentID, err)
+	}
+}
+
+func (s *paymentService) createPayment(ctx context.Context, order models.Order) (models.Payment, error) {
+	intent, err := s.provider.CreateIntent(ctx, payments.IntentRequest{
+		OrderID:  order.ID.String(),
+		Amount:   order.Total,
+		Currency: order.Currency,
+	})
+	if err != nil {
+		return models.Payment{}, fmt.Errorf("%w: %v", ErrPaymentProvider, err)
+	}
+
+	payment := models.Payment{
+		OrderID:      order.ID,
+		Provider:     s.provider.Name(),
+		IntentID:     intent.ID,
+		ClientSecret: intent.ClientSecret,
+		Amount:       order.Total,
+		Currency:     order.Currency,
+		Status:       models.PaymentStatusPending,
+	}
+	if err := s.db.CreatePayment(&payment); err != nil {
+		return models.Payment{}, fmt.Errorf("failed to create payment: %w", err)
+	}
+	return payment, nil
+}
+
+// completeFreeOrder marks an order without anything to pay as paid without involving the provider
+func (s *paymentService) completeFreeOrder(ctx context.Context, order models.Order) (models.Payment, error) {
+	return s.completeOutsideProvider(ctx, order, models.Payment{
+		Provider: "none",
+		IntentID: "free_" + order.ID.String(),
+	})
+}
+
+// RecordBoxOfficePayment records the payment for the order total, a comp payment is
+// only accepted for orders without anything to pay
+func (s *paymentService) RecordBoxOfficePayment(ctx context.Context, orderID uuid.UUID, collected BoxOfficePayment) (models.Payment, error) {
+	if err := collected.Method.Validate(); err != nil {
+		return models.Payment{}, err
+	}
+	order, err := s.orders.GetOrder(ctx, orderID)
+	if err != nil {
+		return models.Payment{}, err
+	}
+	if !order.Status.IsUnpaid() {
+		return models.Payment{}, models.ErrOrderInvalidStatus
+	}
+	if collected.Method == models.PaymentMethodComp && order.Total != 0 {
+		return models.Payment{}, models.ErrPaymentInvalidMethod
+	}
+
+	return s.completeOutsideProvider(ctx, order, models.Payment{
+		Provider:      models.BoxOfficeProvider,
+		IntentID:      "box_office_" + order.ID.String(),
+		Method:        collected.Method,
+		CollectedByID: &collected.StaffID,
+		Reference:     collected.Reference,
+	})
+}
+
+// completeOutsideProvider marks the order paid with a payment that was settled without
+// the payment provider and fulfils it
+func (s *paymentService) completeOutsideProvider(ctx context.Context, order models.Order, payment models.Payment) (models.Payment, error) {
+	if order.Status == models.OrderStatusPending {
+		if _, err := s.orders.Transition(ctx, order.ID, models.OrderStatusAwaitingPayment); err != nil {
+			return models.Payment{}, err
+		}
+	}
+	if _, err := s.orders.Transition(ctx, order.ID, models.OrderStatusPaid); err != nil {
+		return models.Payment{}, err
+	}
+
+	now := time.Now()
+	payment.OrderID = order.ID
+	payment.Amount = order.Total
+	payment.Currency = order.Currency
+	payment.Status = models.PaymentStatusCaptured
+	payment.CapturedAt = &now
+	if err := s.db.CreatePayment(&payment); err != nil {
+		return models.Payment{}, fmt.Errorf("failed to create payment: %w", err)
+	}
+	s.fulfil(ctx, order.ID)
+	return payment, nil
+}
+
+// fulfil issues the tickets and the invoice of a paid order. Failed tickets are retried
+// by the ticket issuer, a failed invoice is issued when it is first downloaded.
+func (s *paymentService) fulfil(ctx context.Context, orderID uuid.UUID) {
+	if _, err := s.tickets.IssueOrderTickets(ctx, orderID); err != nil {
+		log.Printf("Failed to issue tickets of order %s: %v", orderID, err)
+	}
+	if _, err := s.invoices.IssueInvoice(ctx, orderID); err != nil {
+		log.Printf("Failed to issue invoice of order %s: %v", orderID, err)
+	}
+}
+
+func (s *paymentService) paymentByIntent(intentID string) (models.Payment, error) {
+	payment, err := s.db.FindPaymentByIntentID(intentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Payment{}, ErrPaymentNotFound
+		}
+		return models.Payment{}, fmt.Errorf("failed to retrieve payment: %w", err)
+	}
+	return payment, nil
+}
+
+// HandleWebhook applies a signed provider event. Events may be delivered more than
+// once, so every step is guarded by the current payment and order status.
+func (s *paymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.provider.VerifyWebhookSignature(payload, signature)
+	if err != nil {
+		return err
+	}
+
+	payment, err := s.paymentByIntent(event.IntentID)
+	if err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case payments.EventPaymentAuthorized:
+		return s.capture(ctx, payment, event)
+	case payments.EventPaymentFailed:
+		payment.Status = models.PaymentStatusFailed
+		payment.FailureReason = event.FailureReason
+		updated, err := s.db.TransitionPayment(&payment, models.PaymentStatusPending, models.PaymentStatusProcessing)
+		if err != nil {
+			return fmt.Errorf("failed to update payment: %w", err)
+		}
+		if updated {
+			s.returnPaymentMethod(ctx, payment)
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// capture collects an authorized payment and marks its order paid. If the order
+// expired or was cancelled while the customer was paying, the money is refunded.
+func (s *paymentService) capture(ctx context.Context, payment models.Payment, event *payments.WebhookEvent) error {
+	if payment.Status == models.PaymentStatusCaptured || payment.Status == models.PaymentStatusRefunded {
+		return nil
+	}
+	if event.Amount != payment.Amount || event.Currency != payment.Currency {
+		return ErrPaymentAmountMismatch
+	}
+
+	if _, err := s.provider.CaptureIntent(ctx, payment.IntentID); err != nil {
+		if errors.Is(err, payments.ErrInvalidIntentState) {
+			// Captured by a concurrent delivery of the same event
+			return nil
+		}
+		return fmt.Errorf("%w: %v", ErrPaymentProvider, err)
+	}
+
+	now := time.Now()
+	payment.Status = models.PaymentStatusCaptured
+	payment.FailureReason = ""
+	payment.CapturedAt = &now
+	if _, err := s.db.TransitionPayment(&payment, models.PaymentStatusPending, models.PaymentStatusProcessing, models.PaymentStatusFailed); err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	_, err := s.orders.Transition(ctx, payment.OrderID, models.OrderStatusPaid)
+	if err == nil {
+		s.fulfil(ctx, payment.OrderID)
+		return nil
+	}
+	if !errors.Is(err, models.ErrOrderInvalidStatus) && !errors.Is(err, ErrOrderConflict) {
+		return err
+	}
+
+	log.Printf("Order %s can no longer be paid, refunding payment %s", payment.OrderID, payment.IntentID)
+	if _, err := s.provider.Refund(ctx, payment.IntentID, payment.Amount); err != nil {
+		return fmt.Errorf("%w: %v", ErrPaymentProvider, err)
+	}
+	payment.Status = models.PaymentStatusRefunded
+	updated, err := s.db.TransitionPayment(&payment, models.PaymentStatusCaptured)
+	if err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+	if updated {
+		// The order never got its tickets
+		s.returnPaymentMethod(ctx, payment)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"passIt/internal/database"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrFeeRuleNotFound = errors.New("fee rule not found")
+	ErrTaxRateNotFound = errors.New("tax rate not found")
+)
+
+// PricingService manages service fees and tax rates and prices orders with them
+type PricingService interface {
+	CreateFeeRule(ctx context.Context, fee *models.FeeRule) error
+	ListFeeRules(ctx context.Context) ([]models.FeeRule, error)
+	DeleteFeeRule(ctx context.Context, id uuid.UUID) error
+	CreateTaxRate(ctx context.Context, tax *models.TaxRate) error
+	ListTaxRates(ctx context.Context) ([]models.TaxRate, error)
+	DeleteTaxRate(ctx context.Context, id uuid.UUID) error
+	// PriceOrder adds the fees of the event and the taxes of its venue to the order
+	PriceOrder(ctx context.Context, order *models.Order) error
+}
+
+type pricingService struct {
+	db database.Service
+}
+
+// NewPricingService creates a new pricing service
+func NewPricingService(db database.Service) PricingService {
+	return &pricingService{
+		db: db,
+	}
+}
+
+// CreateFeeRule validates and stores a fee rule, global or for one event
+func (s *pricingService) CreateFeeRule(ctx context.Context, fee *models.FeeRule) error {
+	fee.ID = uuid.Nil
+	if err := fee.Validate(); err != nil {
+		return err
+	}
+	if fee.EventID != nil {
+		if _, err := s.db.FindEventById(*fee.EventID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEventNotFound
+			}
+			return fmt.Errorf("failed to retrieve event: %w", err)
+		}
+	}
+
+	if err := s.db.CreateFeeRule(fee); err != nil {
+		return fmt.Errorf("failed to create fee rule: %w", err)
+	}
+	return nil
+}
+
+// ListFeeRules retrieves the global fee rules followed by the event ones
+func (s *pricingService) ListFeeRules(ctx context.Context) ([]models.FeeRule, error) {
+	fees, err := s.db.ListFeeRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve fee rules: %w", err)
+	}
+	return fees, nil
+}
+
+// DeleteFeeRule removes a fee rule. Orders already placed keep their fees.
+func (s *pricingService) DeleteFeeRule(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.db.FindFeeRuleById(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrFeeRuleNotFound
+		}
+		return fmt.Errorf("failed to retrieve fee rule: %w", err)
+	}
+	if err := s.db.DeleteFeeRule(id); err != nil {
+		return fmt.Errorf("failed to delete fee rule: %w", err)
+	}
+	return nil
+}
+
+// CreateTaxRate validates and stores a tax rate of a jurisdiction
+func (s *pricingService) CreateTaxRate(ctx context.Context, tax *models.TaxRate) error {
+	tax.ID = uuid.Nil
+	tax.Jurisdiction = models.NormalizeJurisdiction(tax.Jurisdiction)
+	if err := tax.Validate(); err != nil {
+		return err
+	}
+	if err := s.db.CreateTaxRate(tax); err != nil {
+		return fmt.Errorf("failed to create tax rate: %w", err)
+	}
+	return nil
+}
+
+// ListTaxRates retrieves every tax rate grouped by jurisdiction
+func (s *pricingService) ListTaxRates(ctx context.Context) ([]models.TaxRate, error) {
+	taxes, err := s.db.ListTaxRates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tax rates: %w", err)
+	}
+	return taxes, nil
+}
+
+// DeleteTaxRate removes a tax rate. Orders already placed keep their taxes.
+func (s *pricingService) DeleteTaxRate(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.db.FindTaxRateById(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTaxRateNotFound
+		}
+		return fmt.Errorf("failed to retrieve tax rate: %w", err)
+	}
+	if err := s.db.DeleteTaxRate(id); err != nil {
+		return fmt.Errorf("failed to delete tax rate: %w", err)
+	}
+	return nil
+}
+
+func (s *pricingService) PriceOrder(ctx context.Context, order *models.Order) error {
+	fees, err := s.db.ListFeeRulesForOrder(order.EventID, order.Currency)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve fee rules: %w", err)
+	}
+	taxes, err := s.db.ListTaxRatesForEvent(order.EventID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve tax rates: %w", err)
+	}
+	return order.ApplyPricing(fees, taxes)
+}
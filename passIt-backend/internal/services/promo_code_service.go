@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"passIt/internal/database"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPromoCodeNotFound = errors.New("promo code not found")
+	ErrPromoCodeExists   = errors.New("a promo code with this code already exists")
+)
+
+// PromoCodeService manages promo codes and resolves the codes buyers enter at checkout
+type PromoCodeService interface {
+	CreatePromoCode(ctx context.Context, code *models.PromoCode) error
+	UpdatePromoCode(ctx context.Context, code *models.PromoCode) error
+	DeletePromoCode(ctx context.Context, id uuid.UUID) error
+	GetPromoCode(ctx context.Context, id uuid.UUID) (models.PromoCode, error)
+	ListPromoCodes(ctx context.Context) ([]models.PromoCode, error)
+	// ResolveCodes looks up the codes entered by a buyer, keeping the order they were entered in
+	ResolveCodes(ctx context.Context, codes []string) ([]models.PromoCode, error)
+}
+
+type promoCodeService struct {
+	db database.Service
+}
+
+// NewPromoCodeService creates a new promo code service
+func NewPromoCodeService(db database.Service) PromoCodeService {
+	return &promoCodeService{
+		db: db,
+	}
+}
+
+// CreatePromoCode validates a promo code and stores it with a fresh redemption counter
+func (s *promoCodeService) CreatePromoCode(ctx context.Context, code *models.PromoCode) error {
+	code.ID = uuid.Nil
+	code.Redemptions = 0
+	code.Code = models.NormalizePromoCode(code.Code)
+	if err := code.Validate(); err != nil {
+		return err
+	}
+	if err := s.checkCodeFree(code); err != nil {
+		return err
+	}
+
+	if err := s.db.CreatePromoCode(code); err != nil {
+		return fmt.Errorf("failed to create promo code: %w", err)
+	}
+	return nil
+}
+
+// UpdatePromoCode validates and stores changes to an existing promo code. Redemptions
+// already made are kept, even if the new limits are lower.
+func (s *promoCodeService) UpdatePromoCode(ctx context.Context, code *models.PromoCode) error {
+	existing, err := s.GetPromoCode(ctx, code.ID)
+	if err != nil {
+		return err
+	}
+
+	code.Code = models.NormalizePromoCode(code.Code)
+	if err := code.Validate(); err != nil {
+		return err
+	}
+	if code.Code != existing.Code {
+		if err := s.checkCodeFree(code); err != nil {
+			return err
+		}
+	}
+
+	if err := s.db.UpdatePromoCode(code); err != nil {
+		return fmt.Errorf("failed to update promo code: %w", err)
+	}
+	code.CreatedAt = existing.CreatedAt
+	code.CreatedByID = existing.CreatedByID
+	code.Redemptions = existing.Redemptions
+	return nil
+}
+
+func (s *promoCodeService) checkCodeFree(code *models.PromoCode) error {
+	taken, err := s.db.FindPromoCodesByCode([]string{code.Code})
+	if err != nil {
+		return fmt.Errorf("failed to check promo code: %w", err)
+	}
+	for _, other := range taken {
+		if other.ID != code.ID {
+			return ErrPromoCodeExists
+		}
+	}
+	return nil
+}
+
+// DeletePromoCode removes a promo code. Orders that already used it keep their discount.
+func (s *promoCodeService) DeletePromoCode(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetPromoCode(ctx, id); err != nil {
+		return err
+	}
+	if err := s.db.DeletePromoCode(id); err != nil {
+		return fmt.Errorf("failed to delete promo code: %w", err)
+	}
+	return nil
+}
+
+// GetPromoCode retrieves a promo code by ID
+func (s *promoCodeService) GetPromoCode(ctx context.Context, id uuid.UUID) (models.PromoCode, error) {
+	code, err := s.db.FindPromoCodeById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.PromoCode{}, ErrPromoCodeNotFound
+		}
+		return models.PromoCode{}, fmt.Errorf("failed to retrieve promo code: %w", err)
+	}
+	return code, nil
+}
+
+// ListPromoCodes retrieves every promo code, newest first
+func (s *promoCodeService) ListPromoCodes(ctx context.Context) ([]models.PromoCode, error) {
+	codes, err := s.db.ListPromoCodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve promo codes: %w", err)
+	}
+	return codes, nil
+}
+
+func (s *promoCodeService) ResolveCodes(ctx context.Context, codes []string) ([]models.PromoCode, error) {
+	normalized := make([]string, len(codes))
+	for i, code := range codes {
+		normalized[i] = models.NormalizePromoCode(code)
+		if normalized[i] == "" {
+			return nil, models.ErrPromoCodeRequired
+		}
+	}
+
+	found, err := s.db.FindPromoCodesByCode(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve promo codes: %w", err)
+	}
+	byCode := make(map[string]models.PromoCode, len(found))
+	for _, code := range found {
+		byCode[code.Code] = code
+	}
+
+	resolved := make([]models.PromoCode, 0, len(normalized))
+	for _, code := range normalized {
+		promoCode, ok := byCode[code]
+		if !ok {
+			return nil, ErrPromoCodeNotFound
+		}
+		resolved = append(resolved, promoCode)
+	}
+	return resolved, nil
+}
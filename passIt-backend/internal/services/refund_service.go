@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/payments"
+	"passIt/internal/store"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRefundPaymentNotCaptured = errors.New("order has no captured payment to refund")
+	ErrRefundEventNotCancelled  = errors.New("event is not cancelled")
+)
+
+// RefundRequest describes which tickets of an order to refund
+type RefundRequest struct {
+	// Quantities maps order item IDs to the number of tickets to refund.
+	// An empty map refunds every ticket that has not been refunded yet.
+	Quantities map[uuid.UUID]int
+	Reason     string
+	// Restock gives the refunded tickets back to sale
+	Restock       bool
+	RequestedByID *uuid.UUID
+}
+
+// EventRefundSummary reports the outcome of refunding every order of an event
+type EventRefundSummary struct {
+	Refunded     int         `json:"refunded"`
+	Cancelled    int         `json:"cancelled"`
+	Failed       int         `json:"failed"`
+	FailedOrders []uuid.UUID `json:"failed_orders,omitempty"`
+}
+
+// RefundService returns money for paid orders through the payment provider
+type RefundService interface {
+	// RefundOrder refunds some or all tickets of a paid order
+	RefundOrder(ctx context.Context, orderID uuid.UUID, request RefundRequest) (models.Refund, error)
+	ListOrderRefunds(ctx context.Context, orderID uuid.UUID) ([]models.Refund, error)
+	// RefundEventOrders refunds every paid order of a cancelled event and cancels its unpaid orders
+	RefundEventOrders(ctx context.Context, eventID uuid.UUID, reason string) (EventRefundSummary, error)
+}
+
+type refundService struct {
+	db       database.Service
+	provider payments.PaymentProvider
+	orders   OrderService
+	holds    store.HoldStore
+}
+
+// NewRefundService creates a new refund service
+func NewRefundService(db database.Service, provider payments.PaymentProvider, orders OrderService, holds store.HoldStore) RefundService {
+	return &refundService{
+		db:       db,
+		provider: provider,
+		orders:   orders,
+		holds:    holds,
+	}
+}
+
+// RefundOrder books the refunded tickets on the order before calling the provider, so
+// concurrent refunds cannot return the same tickets twice. If the provider rejects the
+// refund the booking is undone.
+func (s *refundService) RefundOrder(ctx context.Context, orderID uuid.UUID, request RefundRequest) (models.Refund, error) {
+	order, err := s.orders.GetOrder(ctx, orderID)
+	if err != nil {
+		return models.Refund{}, err
+	}
+	if order.Status != models.OrderStatusPaid && order.Status != models.OrderStatusFulfilled {
+		return models.Refund{}, models.ErrOrderInvalidStatus
+	}
+
+	payment, err := s.db.FindLatestPaymentByOrder(order.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Refund{}, ErrRefundPaymentNotCaptured
+		}
+		return models.Refund{}, fmt.Errorf("failed to retrieve payment: %w", err)
+	}
+	if payment.Status != models.PaymentStatusCaptured {
+		return models.Refund{}, ErrRefundPaymentNotCaptured
+	}
+
+	// Resold tickets were never taken from inventory, so they cannot go back to sale
+	if order.IsResale() {
+		request.Restock = false
+	}
+
+	items, amount, err := order.PlanRefund(request.Quantities)
+	if err != nil {
+		return models.Refund{}, err
+	}
+
+	refund := models.Refund{
+		OrderID:     order.ID,
+		PaymentID:   payment.ID,
+		Amount:      amount,
+		Currency:    order.Currency,
+		Reason:      request.Reason,
+		Restock:     request.Restock,
+		Status:      models.RefundStatusPending,
+		CreatedByID: request.RequestedByID,
+		Items:       items,
+	}
+	if err := s.db.CreateRefund(&refund); err != nil {
+		if errors.Is(err, models.ErrRefundExceedsTickets) {
+			return models.Refund{}, err
+		}
+		return models.Refund{}, fmt.Errorf("failed to create refund: %w", err)
+	}
+
+	if amount > 0 && payment.Provider == s.provider.Name() {
+		providerRefund, err := s.provider.Refund(ctx, payment.IntentID, amount)
+		if err != nil {
+			if failErr := s.db.FailRefund(&refund); failErr != nil {
+				log.Printf("Failed to roll back refund %s: %v", refund.ID, failErr)
+			}
+			return models.Refund{}, fmt.Errorf("%w: %v", ErrPaymentProvider, err)
+		}
+		refund.ProviderRefundID = providerRefund.ID
+	}
+
+	restocked := refundedItems(&order, items)
+	var seatIDs []uuid.UUID
+	if refund.Restock {
+		for _, item := range restocked {
+			if item.EventSeatID != nil {
+				seatIDs = append(seatIDs, *item.EventSeatID)
+			}
+		}
+	}
+	if err := s.db.CompleteRefund(&refund, seatIDs); err != nil {
+		return models.Refund{}, fmt.Errorf("failed to complete refund: %w", err)
+	}
+
+	if refund.Restock {
+		hold := holdFromOrder(&models.Order{
+			EventID: order.EventID,
+			UserID:  order.UserID,
+			HoldID:  order.HoldID,
+			Items:   restocked,
+			// Needed to give the tickets back to the purchase limit of the buyer
+			SoldByID:        order.SoldByID,
+			ResaleListingID: order.ResaleListingID,
+		})
+		if err := s.holds.ReturnInventory(ctx, hold); err != nil {
+			log.Printf("Failed to return inventory of refund %s: %v", refund.ID, err)
+		}
+	}
+
+	s.closeFullyRefunded(ctx, order.ID, payment)
+	return refund, nil
+}
+
+// refundedItems returns the order lines of a refund with the refunded quantity
+func refundedItems(order *models.Order, items []models.RefundItem) []models.OrderItem {
+	byID := make(map[uuid.UUID]models.OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		byID[item.ID] = item
+	}
+
+	lines := make([]models.OrderItem, 0, len(items))
+	for _, item := range items {
+		line := byID[item.OrderItemID]
+		line.Quantity = item.Quantity
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// closeFullyRefunded marks the order and its payment refunded once no ticket is left
+func (s *refundService) closeFullyRefunded(ctx context.Context, orderID uuid.UUID, payment models.Payment) {
+	order, err := s.orders.GetOrder(ctx, orderID)
+	if err != nil {
+		log.Printf("Failed to reload refunded order %s: %v", orderID, err)
+		return
+	}
+	if !order.IsFullyRefunded() {
+		return
+	}
+
+	if _, err := s.orders.Transition(ctx, order.ID, models.OrderStatusRefunded); err != nil &&
+		!errors.Is(err, models.ErrOrderInvalidStatus) && !errors.Is(err, ErrOrderConflict) {
+		log.Printf("Failed to mark order %s refunded: %v", order.ID, err)
+	}
+
+	payment.Status = models.PaymentStatusRefunded
+	if _, err := s.db.TransitionPayment(&payment, models.PaymentStatusCaptured); err != nil {
+		log.Printf("Failed to mark payment %s refunded: %v", payment.IntentID, err)
+	}
+}
+
+// ListOrderRefunds retrieves the refunds of an order, oldest first
+func (s *refundService) ListOrderRefunds(ctx context.Context, orderID uuid.UUID) ([]models.Refund, error) {
+	if _, err := s.orders.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	refunds, err := s.db.ListRefundsByOrder(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve refunds: %w", err)
+	}
+	return refunds, nil
+}
+
+// RefundEventOrders refunds every paid order of a cancelled event without restocking,
+// since nothing can be sold anymore. Orders that fail are reported so the refund can be
+// retried; already refunded tickets are skipped on a retry.
+func (s *refundService) RefundEventOrders(ctx context.Context, eventID uuid.UUID, reason string) (EventRefundSummary, error) {
+	event, err := s.db.FindEventById(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return EventRefundSummary{}, ErrEventNotFound
+		}
+		return EventRefundSummary{}, fmt.Errorf("failed to retrieve event: %w", err)
+	}
+	if event.Status != models.EventStatusCancelled {
+		return EventRefundSummary{}, ErrRefundEventNotCancelled
+	}
+
+	orders, err := s.db.ListOrdersByEvent(eventID,
+		models.OrderStatusPending,
+		models.OrderStatusAwaitingPayment,
+		models.OrderStatusPaid,
+		models.OrderStatusFulfilled,
+	)
+	if err != nil {
+		return EventRefundSummary{}, fmt.Errorf("failed to list event orders: %w", err)
+	}
+
+	var summary EventRefundSummary
+	for _, order := range orders {
+		if order.Status.IsUnpaid() {
+			// A payment completing later is refunded when it is captured
+			if _, err := s.orders.Transition(ctx, order.ID, models.OrderStatusCancelled); err != nil {
+				log.Printf("Failed to cancel order %s of cancelled event %s: %v", order.ID, eventID, err)
+				summary.Failed++
+				summary.FailedOrders = append(summary.FailedOrders, order.ID)
+				continue
+			}
+			summary.Cancelled++
+			continue
+		}
+
+		_, err := s.RefundOrder(ctx, order.ID, RefundRequest{Reason: reason})
+		if err != nil && !errors.Is(err, models.ErrRefundNothingToRefund) {
+			log.Printf("Failed to refund order %s of cancelled event %s: %v", order.ID, eventID, err)
+			summary.Failed++
+			summary.FailedOrders = append(summary.FailedOrders, order.ID)
+			continue
+		}
+		summary.Refunded++
+	}
+	return summary, nil
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"passIt/internal/constant"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/payments"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrResaleListingNotFound  = errors.New("resale listing not found")
+	ErrResaleListingForbidden = errors.New("resale listing belongs to another user")
+	ErrResalePayoutNotDue     = errors.New("resale listing has no outstanding payout")
+)
+
+// ResaleService lets fans sell tickets to each other at a capped price
+type ResaleService interface {
+	// ListTicket offers a ticket of the seller for resale. The seller's QR code stops
+	// working until the listing is cancelled.
+	ListTicket(ctx context.Context, ticketID, sellerID uuid.UUID, price int64) (models.ResaleListing, error)
+	CancelListing(ctx context.Context, listingID, sellerID uuid.UUID) (models.ResaleListing, error)
+	ListEventListings(ctx context.Context, eventID uuid.UUID) ([]models.ResaleListing, error)
+	ListUserListings(ctx context.Context, sellerID uuid.UUID) ([]models.ResaleListing, error)
+	// Checkout reserves a listing for the buyer checking out from the IP address and
+	// creates the order paying for it
+	Checkout(ctx context.Context, buyerID, listingID uuid.UUID, ip string) (models.Order, error)
+	// FulfilResaleOrder reissues the ticket of a paid resale order to the buyer and pays
+	// the seller. If the ticket was refunded meanwhile the buyer is refunded instead.
+	FulfilResaleOrder(ctx context.Context, order *models.Order) error
+	// RetryPayout pays the seller of a sold listing whose payout failed
+	RetryPayout(ctx context.Context, listingID uuid.UUID) (models.ResaleListing, error)
+}
+
+type resaleService struct {
+	db       database.Service
+	provider payments.PaymentProvider
+	orders   OrderService
+	refunds  RefundService
+	risk     RiskAssessor
+}
+
+// NewResaleService creates a new resale service
+func NewResaleService(db database.Service, provider payments.PaymentProvider, orders OrderService, refunds RefundService, risk RiskAssessor) ResaleService {
+	return &resaleService{
+		db:       db,
+		provider: provider,
+		orders:   orders,
+		refunds:  refunds,
+		risk:     risk,
+	}
+}
+
+func (s *resaleService) ListTicket(ctx context.Context, ticketID, sellerID uuid.UUID, price int64) (models.ResaleListing, error) {
+	ticket, err := s.db.FindTicketById(ticketID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ResaleListing{}, ErrTicketNotFound
+		}
+		return models.ResaleListing{}, fmt.Errorf("failed to retrieve ticket: %w", err)
+	}
+	if ticket.OwnerID != sellerID {
+		return models.ResaleListing{}, ErrTicketForbidden
+	}
+	if ticket.Event == nil {
+		return models.ResaleListing{}, ErrEventNotFound
+	}
+	if err := ticket.Event.CheckResaleAllowed(time.Now()); err != nil {
+		return models.ResaleListing{}, err
+	}
+
+	// The face value is what the original buyer paid for the ticket
+	order, err := s.orders.GetOrder(ctx, ticket.OrderID)
+	if err != nil {
+		return models.ResaleListing{}, err
+	}
+	var faceValue int64
+	for _, item := range order.Items {
+		if item.ID == ticket.OrderItemID {
+			faceValue = item.UnitPrice
+		}
+	}
+
+	listing, err := models.NewResaleListing(&ticket, ticket.Event, faceValue, price, constant.ResaleFeeBasisPoints, order.Currency)
+	if err != nil {
+		return models.ResaleListing{}, err
+	}
+	if err := s.db.ListTicketForResale(&listing); err != nil {
+		if errors.Is(err, models.ErrResaleTicketNotAllowed) {
+			return models.ResaleListing{}, err
+		}
+		return models.ResaleListing{}, fmt.Errorf("failed to create resale listing: %w", err)
+	}
+	return listing, nil
+}
+
+func (s *resaleService) getListing(listingID uuid.UUID) (models.ResaleListing, error) {
+	listing, err := s.db.FindResaleListingById(listingID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ResaleListing{}, ErrResaleListingNotFound
+		}
+		return models.ResaleListing{}, fmt.Errorf("failed to retrieve resale listing: %w", err)
+	}
+	return listing, nil
+}
+
+func (s *resaleService) CancelListing(ctx context.Context, listingID, sellerID uuid.UUID) (models.ResaleListing, error) {
+	listing, err := s.getListing(listingID)
+	if err != nil {
+		return models.ResaleListing{}, err
+	}
+	if listing.SellerID != sellerID {
+		return models.ResaleListing{}, ErrResaleListingForbidden
+	}
+
+	cancelled, err := s.db.CancelResaleListing(&listing)
+	if err != nil {
+		return models.ResaleListing{}, fmt.Errorf("failed to cancel resale listing: %w", err)
+	}
+	if !cancelled {
+		return models.ResaleListing{}, models.ErrResaleListingNotActive
+	}
+	return listing, nil
+}
+
+// ListEventListings retrieves the listings of an event buyers can purchase, cheapest first
+func (s *resaleService) ListEventListings(ctx context.Context, eventID uuid.UUID) ([]models.ResaleListing, error) {
+	listings, err := s.db.ListActiveResaleListingsByEvent(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve resale listings: %w", err)
+	}
+	return listings, nil
+}
+
+func (s *resaleService) ListUserListings(ctx context.Context, sellerID uuid.UUID) ([]models.ResaleListing, error) {
+	listings, err := s.db.ListResaleListingsBySeller(sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve resale listings: %w", err)
+	}
+	return listings, nil
+}
+
+// Checkout creates an order with a single line at the listing price. The listing stays
+// reserved while the order waits for payment and goes back on sale if it expires.
+func (s *resaleService) Checkout(ctx context.Context, buyerID, listingID uuid.UUID, ip string) (models.Order, error) {
+	listing, err := s.getListing(listingID)
+	if err != nil {
+		return models.Order{}, err
+	}
+	if listing.SellerID == buyerID {
+		return models.Order{}, models.ErrResaleOwnListing
+	}
+	if listing.Status != models.ResaleListingActive {
+		return models.Order{}, models.ErrResaleListingNotActive
+	}
+	if listing.Event == nil {
+		return models.Order{}, ErrEventNotFound
+	}
+	if err := listing.Event.CheckResaleAllowed(time.Now()); err != nil {
+		return models.Order{}, err
+	}
+
+	name := "Resale ticket"
+	if listing.TicketType != nil {
+		name = listing.TicketType.Name
+	}
+	order := models.Order{
+		UserID:          buyerID,
+		EventID:         listing.EventID,
+		HoldID:          "resale_" + uuid.NewString(),
+		Status:          models.OrderStatusPending,
+		Currency:        listing.Currency,
+		ResaleListingID: &listing.ID,
+		Items: []models.OrderItem{{
+			TicketTypeID: listing.TicketTypeID,
+			EventSeatID:  listing.EventSeatID,
+			Name:         name,
+			UnitPrice:    listing.Price,
+			Quantity:     1,
+		}},
+	}
+	if err := order.CalculateTotals(); err != nil {
+		return models.Order{}, err
+	}
+	order.ExpiresAt = time.Now().Add(constant.OrderPaymentWindow)
+	s.risk.AssessOrder(ctx, &order, ip)
+
+	reserved, err := s.db.ReserveResaleListing(&listing, &order)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("failed to create order: %w", err)
+	}
+	if !reserved {
+		return models.Order{}, models.ErrResaleListingNotActive
+	}
+	return order, nil
+}
+
+func (s *resaleService) FulfilResaleOrder(ctx context.Context, order *models.Order) error {
+	if !order.IsResale() {
+		return models.ErrResaleListingNotOnOrder
+	}
+	listing, err := s.getListing(*order.ResaleListingID)
+	if err != nil {
+		return err
+	}
+
+	// A retry after the ticket was handed over only needs to pay the seller
+	if listing.Status != models.ResaleListingSold || listing.OrderID == nil || *listing.OrderID != order.ID {
+		completed, err := s.db.CompleteResale(&listing, order)
+		if err != nil {
+			return fmt.Errorf("failed to complete resale: %w", err)
+		}
+		if !completed {
+			// The seller's ticket was refunded while the buyer was paying
+			if _, err := s.refunds.RefundOrder(ctx, order.ID, RefundRequest{Reason: "resale ticket no longer available"}); err != nil {
+				return fmt.Errorf("failed to refund resale order: %w", err)
+			}
+			return models.ErrResaleListingNotActive
+		}
+	}
+
+	if listing.PayoutStatus != models.PayoutStatusPaid {
+		s.payout(ctx, &listing)
+	}
+	return nil
+}
+
+// payout sends the seller their share, recording a failure so it can be retried
+func (s *resaleService) payout(ctx context.Context, listing *models.ResaleListing) {
+	payout, err := s.provider.Payout(ctx, payments.PayoutRequest{
+		Reference:   listing.ID.String(),
+		Destination: listing.SellerID.String(),
+		Amount:      listing.Payout,
+		Currency:    listing.Currency,
+	})
+	if err != nil {
+		log.Printf("Failed to pay out resale listing %s: %v", listing.ID, err)
+		listing.PayoutStatus = models.PayoutStatusFailed
+	} else {
+		listing.PayoutStatus = models.PayoutStatusPaid
+		listing.PayoutID = payout.ID
+		listing.PaidOutAt = &payout.CreatedAt
+	}
+	if err := s.db.UpdateResalePayout(listing); err != nil {
+		log.Printf("Failed to record payout of resale listing %s: %v", listing.ID, err)
+	}
+}
+
+func (s *resaleService) RetryPayout(ctx context.Context, listingID uuid.UUID) (models.ResaleListing, error) {
+	listing, err := s.getListing(listingID)
+	if err != nil {
+		return models.ResaleListing{}, err
+	}
+	if listing.Status != models.ResaleListingSold || listing.PayoutStatus == models.PayoutStatusPaid {
+		return models.ResaleListing{}, ErrResalePayoutNotDue
+	}
+
+	s.payout(ctx, &listing)
+	if listing.PayoutStatus != models.PayoutStatusPaid {
+		return models.ResaleListing{}, fmt.Errorf("%w: payout failed", ErrPaymentProvider)
+	}
+	return listing, nil
+}
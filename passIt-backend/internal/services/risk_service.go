@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"passIt/internal/constant"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/store"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RiskAssessor watches buying activity for bots and scores new orders from it
+type RiskAssessor interface {
+	// RecordHold counts a new hold towards the velocity of its user and IP address
+	RecordHold(ctx context.Context, hold *store.Hold, ip string) error
+	// AssessOrder scores an order before it is stored. Orders scoring models.RiskReviewScore
+	// or more cannot be paid until an admin approves them, and so cannot orders whose
+	// buyer activity could not be read.
+	AssessOrder(ctx context.Context, order *models.Order, ip string)
+}
+
+// RiskService routes suspicious orders to manual review
+type RiskService interface {
+	RiskAssessor
+	ListOrdersForReview(ctx context.Context) ([]models.Order, error)
+	// ReviewOrder approves an order awaiting review, giving the buyer a fresh payment
+	// window, or rejects it and cancels it
+	ReviewOrder(ctx context.Context, orderID, reviewerID uuid.UUID, approve bool) (models.Order, error)
+}
+
+type riskAssessor struct {
+	db       database.Service
+	velocity store.VelocityStore
+}
+
+// NewRiskAssessor creates a new risk assessor
+func NewRiskAssessor(db database.Service, velocity store.VelocityStore) RiskAssessor {
+	return &riskAssessor{
+		db:       db,
+		velocity: velocity,
+	}
+}
+
+type riskService struct {
+	RiskAssessor
+	db     database.Service
+	orders OrderService
+}
+
+// NewRiskService creates a new risk service
+func NewRiskService(db database.Service, assessor RiskAssessor, orders OrderService) RiskService {
+	return &riskService{
+		RiskAssessor: assessor,
+		db:           db,
+		orders:       orders,
+	}
+}
+
+func userHoldsKey(userID uuid.UUID) string { return "holds:user:" + userID.String() }
+func ipHoldsKey(ip string) string          { return "holds:ip:" + ip }
+func ipAccountsKey(ip string) string       { return "accounts:ip:" + ip }
+
+func (s *riskAssessor) RecordHold(ctx context.Context, hold *store.Hold, ip string) error {
+	now := time.Now()
+	if _, err := s.velocity.Record(ctx, userHoldsKey(hold.UserID), hold.ID, now, models.VelocityWindow); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+	if _, err := s.velocity.Record(ctx, ipHoldsKey(ip), hold.ID, now, models.VelocityWindow); err != nil {
+		return err
+	}
+	_, err := s.velocity.Record(ctx, ipAccountsKey(ip), hold.UserID.String(), now, models.VelocityWindow)
+	return err
+}
+
+func (s *riskAssessor) AssessOrder(ctx context.Context, order *models.Order, ip string) {
+	signals, err := s.signals(ctx, order, ip)
+	if err != nil {
+		// An order that cannot be scored waits for an admin rather than slipping through
+		log.Printf("Failed to assess risk of order of user %s: %v", order.UserID, err)
+		order.ApplyRisk(models.RiskReviewScore, []models.RiskReason{models.RiskUnscored})
+	} else {
+		order.ApplyRisk(models.AssessRisk(signals))
+	}
+
+	if order.AwaitsReview() {
+		// Keep the tickets reserved while an admin looks at the order
+		order.ExpiresAt = time.Now().Add(constant.OrderReviewWindow)
+	}
+}
+
+// signals gathers what is known about the buyer of the order
+func (s *riskAssessor) signals(ctx context.Context, order *models.Order, ip string) (models.RiskSignals, error) {
+	now := time.Now()
+	user, err := s.db.FindUserById(order.UserID)
+	if err != nil {
+		return models.RiskSignals{}, fmt.Errorf("failed to retrieve user: %w", err)
+	}
+	event, err := s.db.FindEventById(order.EventID)
+	if err != nil {
+		return models.RiskSignals{}, fmt.Errorf("failed to retrieve event: %w", err)
+	}
+
+	signals := models.RiskSignals{
+		AccountAge:        now.Sub(user.CreatedAt),
+		Tickets:           order.TicketCount(),
+		MaxTicketsPerUser: event.MaxTicketsPerUser,
+	}
+	if signals.UserHolds, err = s.velocity.Count(ctx, userHoldsKey(order.UserID), now, models.VelocityWindow); err != nil {
+		return models.RiskSignals{}, err
+	}
+	if ip == "" {
+		return signals, nil
+	}
+	if signals.IPHolds, err = s.velocity.Count(ctx, ipHoldsKey(ip), now, models.VelocityWindow); err != nil {
+		return models.RiskSignals{}, err
+	}
+	if signals.IPAccounts, err = s.velocity.Count(ctx, ipAccountsKey(ip), now, models.VelocityWindow); err != nil {
+		return models.RiskSignals{}, err
+	}
+	return signals, nil
+}
+
+func (s *riskService) ListOrdersForReview(ctx context.Context) ([]models.Order, error) {
+	orders, err := s.db.ListOrdersForReview()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders for review: %w", err)
+	}
+	return orders, nil
+}
+
+func (s *riskService) ReviewOrder(ctx context.Context, orderID, reviewerID uuid.UUID, approve bool) (models.Order, error) {
+	order, err := s.orders.GetOrder(ctx, orderID)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	now := time.Now()
+	if err := order.Review(approve, reviewerID, now); err != nil {
+		return models.Order{}, err
+	}
+	if approve {
+		order.ExpiresAt = now.Add(constant.OrderPaymentWindow)
+	}
+	updated, err := s.db.ReviewOrder(&order)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("failed to review order: %w", err)
+	}
+	if !updated {
+		// Another admin reviewed the order first
+		return models.Order{}, models.ErrOrderNotUnderReview
+	}
+	if approve {
+		return order, nil
+	}
+
+	// Cancelling gives the tickets back to sale
+	return s.orders.Transition(ctx, order.ID, models.OrderStatusCancelled)
+}
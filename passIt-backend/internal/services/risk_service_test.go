@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/store"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkoutDB serves the ticket types of one event and records the orders created,
+// other database calls are not expected
+type checkoutDB struct {
+	database.Service
+	ticketType models.TicketType
+	created    []models.Order
+	userErr    error
+}
+
+func (db *checkoutDB) ListTicketTypesByEvent(eventID uuid.UUID) ([]models.TicketType, error) {
+	return []models.TicketType{db.ticketType}, nil
+}
+
+func (db *checkoutDB) ListAttendeeFormsByEvent(eventID uuid.UUID) ([]models.AttendeeForm, error) {
+	return nil, nil
+}
+
+func (db *checkoutDB) CreateOrder(order *models.Order) error {
+	db.created = append(db.created, *order)
+	return nil
+}
+
+func (db *checkoutDB) FindUserById(id uuid.UUID) (models.User, error) {
+	return models.User{ID: id, CreatedAt: time.Now().Add(-30 * 24 * time.Hour)}, db.userErr
+}
+
+func (db *checkoutDB) FindEventById(id uuid.UUID) (models.Event, error) {
+	return models.Event{ID: id}, nil
+}
+
+// freePricing charges no fees or taxes
+type freePricing struct{ PricingService }
+
+func (freePricing) PriceOrder(ctx context.Context, order *models.Order) error { return nil }
+
+// flagAll routes every order to review
+type flagAll struct{}
+
+func (flagAll) RecordHold(ctx context.Context, hold *store.Hold, ip string) error { return nil }
+
+func (flagAll) AssessOrder(ctx context.Context, order *models.Order, ip string) {
+	order.ApplyRisk(models.RiskMaxScore, []models.RiskReason{models.RiskUserVelocity})
+}
+
+func TestRiskAssessor_UnscoredOrderAwaitsReview(t *testing.T) {
+	ctx := context.Background()
+	db := &checkoutDB{userErr: errors.New("connection refused")}
+	assessor := NewRiskAssessor(db, store.NewVelocityRedisManager(newTestRedisClient(t)))
+
+	order := models.Order{UserID: uuid.New(), EventID: uuid.New(), ExpiresAt: time.Now()}
+	assessor.AssessOrder(ctx, &order, "203.0.113.7")
+	assert.True(t, order.AwaitsReview(), "an order that cannot be scored is not let through")
+	assert.Equal(t, []models.RiskReason{models.RiskUnscored}, order.RiskReasons)
+	assert.True(t, order.ExpiresAt.After(time.Now().Add(time.Hour)), "the tickets stay reserved during the review")
+
+	db.userErr = nil
+	scored := models.Order{UserID: uuid.New(), EventID: uuid.New()}
+	assessor.AssessOrder(ctx, &scored, "203.0.113.7")
+	assert.False(t, scored.AwaitsReview())
+	assert.Empty(t, scored.RiskReasons)
+}
+
+func TestOrderService_CheckoutScoresBeforeStoring(t *testing.T) {
+	ctx := context.Background()
+	ticketType := models.TicketType{ID: uuid.New(), EventID: uuid.New(), Name: "General", Price: 1000, Currency: "EUR", Quantity: 10}
+	db := &checkoutDB{ticketType: ticketType}
+	holds := newTestHoldStore(t)
+	orders := NewOrderService(db, holds, nil, freePricing{}, flagAll{})
+	limits := map[uuid.UUID]store.InventoryLimit{ticketType.ID: {Capacity: ticketType.Quantity}}
+
+	newHold := func(userID uuid.UUID) *store.Hold {
+		hold := &store.Hold{
+			EventID: ticketType.EventID,
+			UserID:  userID,
+			Items:   []store.HoldItem{{TicketTypeID: ticketType.ID, Quantity: 1}},
+		}
+		require.NoError(t, holds.Create(ctx, hold, limits))
+		return hold
+	}
+
+	buyerID := uuid.New()
+	order, err := orders.Checkout(ctx, buyerID, newHold(buyerID).ID, CheckoutOptions{IP: "203.0.113.7"})
+	require.NoError(t, err)
+	require.Len(t, db.created, 1)
+	assert.True(t, db.created[0].AwaitsReview(), "the review flag is stored with the order")
+	assert.True(t, order.AwaitsReview())
+
+	staffID, customerID := uuid.New(), uuid.New()
+	sold, err := orders.Checkout(ctx, customerID, newHold(customerID).ID, CheckoutOptions{SoldByID: &staffID})
+	require.NoError(t, err)
+	require.Len(t, db.created, 2)
+	assert.Equal(t, models.ReviewStatusApproved, db.created[1].ReviewStatus, "staff selling in person approve flagged orders")
+	assert.Equal(t, &staffID, sold.ReviewedByID)
+	assert.Equal(t, models.RiskMaxScore, sold.RiskScore)
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"passIt/internal/auth"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"slices"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleNotFound     = errors.New("role not found")
+	ErrRoleExists       = errors.New("a role with this name already exists")
+	ErrRoleUserNotFound = errors.New("user not found")
+)
+
+// RoleService manages the roles of platform staff and the permissions they grant
+type RoleService interface {
+	ListRoles(ctx context.Context) ([]models.Role, error)
+	GetRole(ctx context.Context, id uuid.UUID) (models.Role, error)
+	CreateRole(ctx context.Context, role *models.Role) error
+	UpdateRole(ctx context.Context, role *models.Role) error
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+
+	GetUserRoles(ctx context.Context, userID uuid.UUID) ([]models.Role, error)
+	// SetUserRoles replaces the roles of a user with the roles of the given names
+	SetUserRoles(ctx context.Context, userID uuid.UUID, names []string) ([]models.Role, error)
+	// SetAdmin grants or revokes the admin role, keeping the other roles of the user
+	SetAdmin(ctx context.Context, userID uuid.UUID, admin bool) ([]models.Role, error)
+
+	// EnsureBuiltInRoles creates the admin role and grants it to users flagged is_admin
+	EnsureBuiltInRoles(ctx context.Context) error
+}
+
+type roleService struct {
+	db       database.Service
+	keycloak auth.KeycloakClient
+}
+
+// NewRoleService creates a new role service. Role changes of users are also applied to the
+// Keycloak roles mapped onto them.
+func NewRoleService(db database.Service, keycloak auth.KeycloakClient) RoleService {
+	return &roleService{
+		db:       db,
+		keycloak: keycloak,
+	}
+}
+
+// ListRoles retrieves every role by name
+func (s *roleService) ListRoles(ctx context.Context) ([]models.Role, error) {
+	roles, err := s.db.ListRoles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve roles: %w", err)
+	}
+	return roles, nil
+}
+
+// GetRole retrieves a role by ID
+func (s *roleService) GetRole(ctx context.Context, id uuid.UUID) (models.Role, error) {
+	role, err := s.db.FindRoleById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Role{}, ErrRoleNotFound
+		}
+		return models.Role{}, fmt.Errorf("failed to retrieve role: %w", err)
+	}
+	return role, nil
+}
+
+func (s *roleService) CreateRole(ctx context.Context, role *models.Role) error {
+	role.ID = uuid.Nil
+	role.BuiltIn = false
+	if err := role.Validate(); err != nil {
+		return err
+	}
+	if err := s.ensureNameFree(role.Name, uuid.Nil); err != nil {
+		return err
+	}
+
+	if err := s.db.CreateRole(role); err != nil {
+		return fmt.Errorf("failed to create role: %w", err)
+	}
+	return nil
+}
+
+// UpdateRole changes the name, description and permissions of a custom role
+func (s *roleService) UpdateRole(ctx context.Context, role *models.Role) error {
+	existing, err := s.GetRole(ctx, role.ID)
+	if err != nil {
+		return err
+	}
+	if existing.BuiltIn {
+		return models.ErrRoleBuiltIn
+	}
+	if err := role.Validate(); err != nil {
+		return err
+	}
+	if err := s.ensureNameFree(role.Name, role.ID); err != nil {
+		return err
+	}
+
+	if err := s.db.UpdateRole(role); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleNotFound
+		}
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+	role.CreatedAt = existing.CreatedAt
+	role.BuiltIn = existing.BuiltIn
+	return nil
+}
+
+// DeleteRole removes a custom role from the platform and from its users
+func (s *roleService) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	role, err := s.GetRole(ctx, id)
+	if err != nil {
+		return err
+	}
+	if role.BuiltIn {
+		return models.ErrRoleBuiltIn
+	}
+
+	if err := s.db.DeleteRole(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleNotFound
+		}
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	return nil
+}
+
+// GetUserRoles retrieves the roles of a user by name
+func (s *roleService) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+	if _, err := s.user(userID); err != nil {
+		return nil, err
+	}
+	roles, err := s.db.ListRolesByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user roles: %w", err)
+	}
+	return roles, nil
+}
+
+func (s *roleService) SetUserRoles(ctx context.Context, userID uuid.UUID, names []string) ([]models.Role, error) {
+	user, err := s.user(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(names)
+	names = slices.Compact(names)
+	var roles []models.Role
+	if len(names) > 0 {
+		roles, err = s.db.FindRolesByName(names)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve roles: %w", err)
+		}
+		if len(roles) != len(names) {
+			return nil, ErrRoleNotFound
+		}
+	}
+
+	roleIDs := make([]uuid.UUID, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	previous, err := s.db.ListRolesByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user roles: %w", err)
+	}
+
+	// Update Keycloak first, like profile changes, and restore it if the database update fails
+	if err := s.keycloak.SyncKeycloakUserRoles(ctx, user.KeycloackID, names); err != nil {
+		return nil, fmt.Errorf("failed to sync roles to keycloak: %w", err)
+	}
+	if err := s.db.SetUserRoles(userID, roleIDs); err != nil {
+		if rollbackErr := s.keycloak.SyncKeycloakUserRoles(ctx, user.KeycloackID, roleNames(previous)); rollbackErr != nil {
+			log.Printf("CRITICAL: Failed to restore Keycloak roles after database error: %v", rollbackErr)
+		}
+		if errors.Is(err, models.ErrLastAdmin) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to set user roles: %w", err)
+	}
+	return roles, nil
+}
+
+func (s *roleService) SetAdmin(ctx context.Context, userID uuid.UUID, admin bool) ([]models.Role, error) {
+	roles, err := s.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(roles)+1)
+	for _, role := range roles {
+		if role.Name != models.AdminRoleName {
+			names = append(names, role.Name)
+		}
+	}
+	if admin {
+		names = append(names, models.AdminRoleName)
+	}
+	return s.SetUserRoles(ctx, userID, names)
+}
+
+func (s *roleService) EnsureBuiltInRoles(ctx context.Context) error {
+	admin, err := s.db.FindRoleByName(models.AdminRoleName)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		admin = models.Role{
+			Name:        models.AdminRoleName,
+			Description: "Full access to the platform",
+			Permissions: []models.Permission{models.PermAll},
+			BuiltIn:     true,
+		}
+		err = s.db.CreateRole(&admin)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create admin role: %w", err)
+	}
+
+	// Users made admin before roles existed keep their access
+	if err := s.db.GrantRoleToAdmins(admin.ID); err != nil {
+		return fmt.Errorf("failed to grant admin role: %w", err)
+	}
+	return nil
+}
+
+func (s *roleService) ensureNameFree(name string, id uuid.UUID) error {
+	existing, err := s.db.FindRoleByName(name)
+	if err == nil && existing.ID != id {
+		return ErrRoleExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to check role name: %w", err)
+	}
+	return nil
+}
+
+func (s *roleService) user(userID uuid.UUID) (models.User, error) {
+	user, err := s.db.FindUserById(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.User{}, ErrRoleUserNotFound
+		}
+		return models.User{}, fmt.Errorf("failed to retrieve user: %w", err)
+	}
+	return user, nil
+}
+
+func roleNames(roles []models.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return names
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/tickets"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTicketNotFound  = errors.New("ticket not found")
+	ErrTicketForbidden = errors.New("ticket belongs to another user")
+)
+
+// TicketService issues signed tickets for paid orders
+type TicketService interface {
+	// IssueOrderTickets issues the tickets of a paid order and marks it fulfilled.
+	// Issuing an order again returns its existing tickets.
+	IssueOrderTickets(ctx context.Context, orderID uuid.UUID) ([]models.Ticket, error)
+	ListUserTickets(ctx context.Context, userID uuid.UUID) ([]models.Ticket, error)
+	GetTicket(ctx context.Context, ticketID uuid.UUID) (models.Ticket, error)
+	GetUserTicket(ctx context.Context, ticketID, userID uuid.UUID) (models.Ticket, error)
+	// TicketToken returns the signed payload encoded in the QR code of a ticket
+	TicketToken(ctx context.Context, ticket models.Ticket) (string, error)
+	// TicketQRCode renders the QR code of a valid ticket as a PNG image
+	TicketQRCode(ctx context.Context, ticket models.Ticket, size int) ([]byte, error)
+	FulfilPaidOrders(ctx context.Context) (int, error)
+	// RunIssuer issues the tickets of paid orders that were not fulfilled every interval
+	// until the context is cancelled
+	RunIssuer(ctx context.Context, interval time.Duration)
+}
+
+type ticketService struct {
+	db     database.Service
+	signer *tickets.Signer
+	orders OrderService
+	resale ResaleService
+}
+
+// NewTicketService creates a new ticket service
+func NewTicketService(db database.Service, signer *tickets.Signer, orders OrderService, resale ResaleService) TicketService {
+	return &ticketService{
+		db:     db,
+		signer: signer,
+		orders: orders,
+		resale: resale,
+	}
+}
+
+func (s *ticketService) IssueOrderTickets(ctx context.Context, orderID uuid.UUID) ([]models.Ticket, error) {
+	order, err := s.orders.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, &order)
+}
+
+func (s *ticketService) issue(ctx context.Context, order *models.Order) ([]models.Ticket, error) {
+	switch order.Status {
+	case models.OrderStatusPaid:
+		if order.IsResale() {
+			// The ticket already exists, it is reissued to the buyer
+			if err := s.resale.FulfilResaleOrder(ctx, order); err != nil {
+				return nil, err
+			}
+		} else if err := s.db.IssueTickets(order.NewTickets(time.Now())); err != nil {
+			return nil, fmt.Errorf("failed to issue tickets: %w", err)
+		}
+		if _, err := s.orders.Transition(ctx, order.ID, models.OrderStatusFulfilled); err != nil &&
+			!errors.Is(err, ErrOrderConflict) && !errors.Is(err, models.ErrOrderInvalidStatus) {
+			return nil, err
+		}
+	case models.OrderStatusFulfilled:
+	default:
+		return nil, models.ErrOrderInvalidStatus
+	}
+
+	issued, err := s.db.ListTicketsByOrder(order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tickets: %w", err)
+	}
+	return issued, nil
+}
+
+// ListUserTickets retrieves the tickets owned by a user, newest first
+func (s *ticketService) ListUserTickets(ctx context.Context, userID uuid.UUID) ([]models.Ticket, error) {
+	owned, err := s.db.ListTicketsByOwner(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tickets: %w", err)
+	}
+	return owned, nil
+}
+
+func (s *ticketService) GetTicket(ctx context.Context, ticketID uuid.UUID) (models.Ticket, error) {
+	ticket, err := s.db.FindTicketById(ticketID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Ticket{}, ErrTicketNotFound
+		}
+		return models.Ticket{}, fmt.Errorf("failed to retrieve ticket: %w", err)
+	}
+	return ticket, nil
+}
+
+// GetUserTicket retrieves a ticket, making sure it belongs to the given user
+func (s *ticketService) GetUserTicket(ctx context.Context, ticketID, userID uuid.UUID) (models.Ticket, error) {
+	ticket, err := s.GetTicket(ctx, ticketID)
+	if err != nil {
+		return models.Ticket{}, err
+	}
+	if ticket.OwnerID != userID {
+		return models.Ticket{}, ErrTicketForbidden
+	}
+	return ticket, nil
+}
+
+func (s *ticketService) TicketToken(ctx context.Context, ticket models.Ticket) (string, error) {
+	if ticket.Status == models.TicketStatusVoid || ticket.Status == models.TicketStatusListed {
+		return "", models.ErrTicketNotValid
+	}
+	return s.signer.Sign(tickets.NewClaims(ticket.ID, ticket.EventID, ticket.Version))
+}
+
+func (s *ticketService) TicketQRCode(ctx context.Context, ticket models.Ticket, size int) ([]byte, error) {
+	token, err := s.TicketToken(ctx, ticket)
+	if err != nil {
+		return nil, err
+	}
+	return tickets.QRCode(token, size)
+}
+
+// FulfilPaidOrders issues the tickets of every paid order and returns how many were fulfilled
+func (s *ticketService) FulfilPaidOrders(ctx context.Context) (int, error) {
+	orders, err := s.db.ListOrdersByStatus(models.OrderStatusPaid)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list paid orders: %w", err)
+	}
+
+	fulfilled := 0
+	for i := range orders {
+		if _, err := s.issue(ctx, &orders[i]); err != nil {
+			log.Printf("Failed to issue tickets of order %s: %v", orders[i].ID, err)
+			continue
+		}
+		fulfilled++
+	}
+	return fulfilled, nil
+}
+
+func (s *ticketService) RunIssuer(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fulfilled, err := s.FulfilPaidOrders(ctx)
+			if err != nil {
+				log.Printf("Failed to fulfil paid orders: %v", err)
+				continue
+			}
+			if fulfilled > 0 {
+				log.Printf("Issued tickets for %d paid orders", fulfilled)
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTicketTypeNotFound = errors.New("ticket type not found")
+	ErrSectionNotInVenue  = errors.New("section does not belong to the event venue")
+)
+
+// TicketTypeService handles the ticket types and price tiers of events
+type TicketTypeService interface {
+	CreateTicketType(ctx context.Context, ticketType *models.TicketType) error
+	UpdateTicketType(ctx context.Context, ticketType *models.TicketType) error
+	DeleteTicketType(ctx context.Context, eventID, id uuid.UUID) error
+	GetTicketType(ctx context.Context, eventID, id uuid.UUID) (models.TicketType, error)
+	ListTicketTypes(ctx context.Context, eventID uuid.UUID) ([]models.TicketType, error)
+	ListOnSaleTicketTypes(ctx context.Context, eventID uuid.UUID) ([]models.TicketType, error)
+}
+
+type ticketTypeService struct {
+	db database.Service
+}
+
+// NewTicketTypeService creates a new ticket type service
+func NewTicketTypeService(db database.Service) TicketTypeService {
+	return &ticketTypeService{
+		db: db,
+	}
+}
+
+// CreateTicketType validates a ticket type and stores it if the event still has unallocated capacity
+func (s *ticketTypeService) CreateTicketType(ctx context.Context, ticketType *models.TicketType) error {
+	ticketType.ID = uuid.Nil
+	return s.save(ticketType)
+}
+
+// UpdateTicketType validates and stores changes to an existing ticket type
+func (s *ticketTypeService) UpdateTicketType(ctx context.Context, ticketType *models.TicketType) error {
+	if ticketType.ID == uuid.Nil {
+		return ErrTicketTypeNotFound
+	}
+	return s.save(ticketType)
+}
+
+func (s *ticketTypeService) save(ticketType *models.TicketType) error {
+	if ticketType.MinPerOrder == 0 {
+		ticketType.MinPerOrder = 1
+	}
+	if err := ticketType.Validate(); err != nil {
+		return err
+	}
+
+	event, err := s.db.FindEventById(ticketType.EventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrEventNotFound
+		}
+		return fmt.Errorf("failed to retrieve event: %w", err)
+	}
+	if !event.Status.IsEditable() {
+		return ErrEventNotEditable
+	}
+
+	if ticketType.SectionID != nil {
+		if err := s.checkSection(event, *ticketType.SectionID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.db.SaveTicketTypeWithinCapacity(ticketType); err != nil {
+		if errors.Is(err, models.ErrTicketCapacityExceeded) {
+			return err
+		}
+		return fmt.Errorf("failed to save ticket type: %w", err)
+	}
+	return nil
+}
+
+// checkSection makes sure a section restriction points into the event's venue layout
+func (s *ticketTypeService) checkSection(event models.Event, sectionID uuid.UUID) error {
+	if event.VenueID == nil {
+		return ErrSectionNotInVenue
+	}
+	venue, err := s.db.FindVenueById(*event.VenueID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve venue: %w", err)
+	}
+	for _, section := range venue.Sections {
+		if section.ID == sectionID {
+			return nil
+		}
+	}
+	return ErrSectionNotInVenue
+}
+
+// DeleteTicketType soft deletes a ticket type of the given event
+func (s *ticketTypeService) DeleteTicketType(ctx context.Context, eventID, id uuid.UUID) error {
+	if _, err := s.GetTicketType(ctx, eventID, id); err != nil {
+		return err
+	}
+	if err := s.db.DeleteTicketType(id); err != nil {
+		return fmt.Errorf("failed to delete ticket type: %w", err)
+	}
+	return nil
+}
+
+// GetTicketType retrieves a ticket type, making sure it belongs to the given event
+func (s *ticketTypeService) GetTicketType(ctx context.Context, eventID, id uuid.UUID) (models.TicketType, error) {
+	ticketType, err := s.db.FindTicketTypeById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.TicketType{}, ErrTicketTypeNotFound
+		}
+		return models.TicketType{}, fmt.Errorf("failed to retrieve ticket type: %w", err)
+	}
+	if ticketType.EventID != eventID {
+		return models.TicketType{}, ErrTicketTypeNotFound
+	}
+	return ticketType, nil
+}
+
+// ListTicketTypes retrieves every ticket type of an event
+func (s *ticketTypeService) ListTicketTypes(ctx context.Context, eventID uuid.UUID) ([]models.TicketType, error) {
+	ticketTypes, err := s.db.ListTicketTypesByEvent(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve ticket types: %w", err)
+	}
+	return ticketTypes, nil
+}
+
+// ListOnSaleTicketTypes retrieves the ticket types whose sales window is currently open
+func (s *ticketTypeService) ListOnSaleTicketTypes(ctx context.Context, eventID uuid.UUID) ([]models.TicketType, error) {
+	ticketTypes, err := s.ListTicketTypes(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	onSale := make([]models.TicketType, 0, len(ticketTypes))
+	for _, ticketType := range ticketTypes {
+		if ticketType.IsOnSale(now) {
+			onSale = append(onSale, ticketType)
+		}
+	}
+	return onSale, nil
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTransferNotFound          = errors.New("transfer not found")
+	ErrTransferForbidden         = errors.New("transfer belongs to other users")
+	ErrTransferRecipientNotFound = errors.New("no user with this email")
+	ErrTransferPending           = errors.New("ticket already has a pending transfer")
+	// ErrTransferStale is returned when the ticket was used, refunded or transferred
+	// between starting and accepting the transfer
+	ErrTransferStale = errors.New("ticket changed since the transfer was started")
+)
+
+// OwnershipRecord is one owner in the history of a ticket
+type OwnershipRecord struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	Version    int        `json:"version"`
+	Since      time.Time  `json:"since"`
+	TransferID *uuid.UUID `json:"transfer_id,omitempty"` // unset for the original buyer
+}
+
+// TransferService passes tickets between users
+type TransferService interface {
+	// StartTransfer offers a ticket of the owner to the user with the given email
+	StartTransfer(ctx context.Context, ticketID, ownerID uuid.UUID, email string) (models.TicketTransfer, error)
+	// AcceptTransfer gives the ticket to the recipient with a new QR code, revoking the old one
+	AcceptTransfer(ctx context.Context, transferID, userID uuid.UUID) (models.TicketTransfer, error)
+	DeclineTransfer(ctx context.Context, transferID, userID uuid.UUID) (models.TicketTransfer, error)
+	CancelTransfer(ctx context.Context, transferID, userID uuid.UUID) (models.TicketTransfer, error)
+	// ListUserTransfers returns the transfers a user sent or received
+	ListUserTransfers(ctx context.Context, userID uuid.UUID) ([]models.TicketTransfer, error)
+	// OwnershipChain returns every owner of a ticket from the buyer to the current holder
+	OwnershipChain(ctx context.Context, ticket models.Ticket) ([]OwnershipRecord, error)
+}
+
+type transferService struct {
+	db      database.Service
+	tickets TicketService
+}
+
+// NewTransferService creates a new transfer service
+func NewTransferService(db database.Service, tickets TicketService) TransferService {
+	return &transferService{
+		db:      db,
+		tickets: tickets,
+	}
+}
+
+func (s *transferService) StartTransfer(ctx context.Context, ticketID, ownerID uuid.UUID, email string) (models.TicketTransfer, error) {
+	ticket, err := s.tickets.GetUserTicket(ctx, ticketID, ownerID)
+	if err != nil {
+		return models.TicketTransfer{}, err
+	}
+	if err := checkTransferable(&ticket); err != nil {
+		return models.TicketTransfer{}, err
+	}
+
+	recipient, err := s.db.FindUserByEmail(strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.TicketTransfer{}, ErrTransferRecipientNotFound
+		}
+		return models.TicketTransfer{}, fmt.Errorf("failed to retrieve recipient: %w", err)
+	}
+	if !recipient.IsActive {
+		return models.TicketTransfer{}, ErrTransferRecipientNotFound
+	}
+	if recipient.ID == ownerID {
+		return models.TicketTransfer{}, models.ErrTransferToSelf
+	}
+
+	_, err = s.db.FindPendingTransferByTicket(ticket.ID)
+	switch {
+	case err == nil:
+		return models.TicketTransfer{}, ErrTransferPending
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return models.TicketTransfer{}, fmt.Errorf("failed to check pending transfers: %w", err)
+	}
+
+	transfer := models.TicketTransfer{
+		TicketID:    ticket.ID,
+		FromUserID:  ownerID,
+		ToUserID:    recipient.ID,
+		ToEmail:     recipient.Email,
+		Status:      models.TransferStatusPending,
+		FromVersion: ticket.Version,
+	}
+	if err := s.db.CreateTransfer(&transfer); err != nil {
+		return models.TicketTransfer{}, fmt.Errorf("failed to create transfer: %w", err)
+	}
+	return transfer, nil
+}
+
+// checkTransferable applies the ticket state and the event's transfer rules
+func checkTransferable(ticket *models.Ticket) error {
+	if ticket.Status != models.TicketStatusValid {
+		return models.ErrTransferTicketNotAllowed
+	}
+	if ticket.Event == nil {
+		return ErrEventNotFound
+	}
+	return ticket.Event.CheckTransferAllowed(time.Now())
+}
+
+func (s *transferService) getTransfer(transferID uuid.UUID) (models.TicketTransfer, error) {
+	transfer, err := s.db.FindTransferById(transferID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.TicketTransfer{}, ErrTransferNotFound
+		}
+		return models.TicketTransfer{}, fmt.Errorf("failed to retrieve transfer: %w", err)
+	}
+	return transfer, nil
+}
+
+// AcceptTransfer checks the event rules again, since the deadline may have passed
+// while the transfer was pending
+func (s *transferService) AcceptTransfer(ctx context.Context, transferID, userID uuid.UUID) (models.TicketTransfer, error) {
+	transfer, err := s.getTransfer(transferID)
+	if err != nil {
+		return models.TicketTransfer{}, err
+	}
+	if transfer.ToUserID != userID {
+		return models.TicketTransfer{}, ErrTransferForbidden
+	}
+	if transfer.Status != models.TransferStatusPending {
+		return models.TicketTransfer{}, models.ErrTransferInvalidStatus
+	}
+	if transfer.Ticket == nil {
+		return models.TicketTransfer{}, ErrTicketNotFound
+	}
+	if err := checkTransferable(transfer.Ticket); err != nil {
+		return models.TicketTransfer{}, err
+	}
+
+	accepted, err := s.db.AcceptTransfer(&transfer)
+	if err != nil {
+		return models.TicketTransfer{}, fmt.Errorf("failed to accept transfer: %w", err)
+	}
+	if accepted {
+		return transfer, nil
+	}
+
+	current, err := s.getTransfer(transferID)
+	if err != nil {
+		return models.TicketTransfer{}, err
+	}
+	if current.Status != models.TransferStatusPending {
+		return models.TicketTransfer{}, models.ErrTransferInvalidStatus
+	}
+	// The ticket can no longer be handed over, close the transfer so a new one can be started
+	if _, err := s.close(&current, models.TransferStatusCancelled); err != nil {
+		log.Printf("Failed to cancel stale transfer %s: %v", current.ID, err)
+	}
+	return models.TicketTransfer{}, ErrTransferStale
+}
+
+func (s *transferService) DeclineTransfer(ctx context.Context, transferID, userID uuid.UUID) (models.TicketTransfer, error) {
+	transfer, err := s.getTransfer(transferID)
+	if err != nil {
+		return models.TicketTransfer{}, err
+	}
+	if transfer.ToUserID != userID {
+		return models.TicketTransfer{}, ErrTransferForbidden
+	}
+	return s.close(&transfer, models.TransferStatusDeclined)
+}
+
+func (s *transferService) CancelTransfer(ctx context.Context, transferID, userID uuid.UUID) (models.TicketTransfer, error) {
+	transfer, err := s.getTransfer(transferID)
+	if err != nil {
+		return models.TicketTransfer{}, err
+	}
+	if transfer.FromUserID != userID {
+		return models.TicketTransfer{}, ErrTransferForbidden
+	}
+	return s.close(&transfer, models.TransferStatusCancelled)
+}
+
+func (s *transferService) close(transfer *models.TicketTransfer, status models.TransferStatus) (models.TicketTransfer, error) {
+	if transfer.Status != models.TransferStatusPending {
+		return models.TicketTransfer{}, models.ErrTransferInvalidStatus
+	}
+
+	now := time.Now()
+	transfer.Status = status
+	transfer.RespondedAt = &now
+	closed, err := s.db.CloseTransfer(transfer)
+	if err != nil {
+		return models.TicketTransfer{}, fmt.Errorf("failed to update transfer: %w", err)
+	}
+	if !closed {
+		return models.TicketTransfer{}, models.ErrTransferInvalidStatus
+	}
+	return *transfer, nil
+}
+
+func (s *transferService) ListUserTransfers(ctx context.Context, userID uuid.UUID) ([]models.TicketTransfer, error) {
+	transfers, err := s.db.ListTransfersByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transfers: %w", err)
+	}
+	return transfers, nil
+}
+
+func (s *transferService) OwnershipChain(ctx context.Context, ticket models.Ticket) ([]OwnershipRecord, error) {
+	transfers, err := s.db.ListAcceptedTransfersByTicket(ticket.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve ticket transfers: %w", err)
+	}
+
+	// The first owner is the sender of the first transfer, or the current owner
+	// if the ticket never changed hands
+	buyer := ticket.OwnerID
+	if len(transfers) > 0 {
+		buyer = transfers[0].FromUserID
+	}
+	chain := []OwnershipRecord{{UserID: buyer, Version: 1, Since: ticket.IssuedAt}}
+	for _, transfer := range transfers {
+		transferID := transfer.ID
+		since := transfer.UpdatedAt
+		if transfer.RespondedAt != nil {
+			since = *transfer.RespondedAt
+		}
+		chain = append(chain, OwnershipRecord{
+			UserID:     transfer.ToUserID,
+			Version:    transfer.ToVersion,
+			Since:      since,
+			TransferID: &transferID,
+		})
+	}
+	return chain, nil
+}
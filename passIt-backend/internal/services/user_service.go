@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"passIt/internal/auth"
@@ -11,9 +12,20 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrEmailVerificationRequired is returned when a sign-up uses the email of a box office
+	// guest record, the account only gets the guest's orders once the email is verified
+	ErrEmailVerificationRequired = errors.New("verify your email address to access the orders made with it")
+	ErrEmailNotVerified          = errors.New("email address is not verified")
+)
+
 // UserService handles all user-related business logic
 type UserService interface {
 	CreateUser(ctx context.Context, user *models.User, password string) error
+	// ClaimGuestAccount turns the box office guest record of the email into the account of
+	// the Keycloak user signing in with it. The identity provider must have verified the
+	// email, otherwise anyone could sign up with a customer's email and take their tickets.
+	ClaimGuestAccount(ctx context.Context, claim GuestClaim) (models.User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (models.User, error)
 	GetAllUsers(ctx context.Context) ([]models.User, error)
@@ -44,8 +56,18 @@ func (s *userService) CreateUser(ctx context.Context, user *models.User, passwor
 		return fmt.Errorf("failed to create user in Keycloak: %w", err)
 	}
 
-	// Step 2: Store user data in PostgreSQL (source of truth)
+	// Step 2: Store user data in PostgreSQL (source of truth). A guest record the box office
+	// created for the same email is claimed at the first sign in with the verified email.
 	user.KeycloackID = keycloakUserID
+	if guest, findErr := s.db.FindUserByEmail(user.Email); findErr == nil && guest.IsGuest {
+		if err := s.keycloak.SendVerifyEmail(ctx, keycloakUserID); err != nil {
+			if deleteErr := s.keycloak.DeleteKeycloakUser(ctx, keycloakUserID); deleteErr != nil {
+				log.Printf("Failed to rollback Keycloak user %s: %v", keycloakUserID, deleteErr)
+			}
+			return fmt.Errorf("failed to send verification email: %w", err)
+		}
+		return ErrEmailVerificationRequired
+	}
 	err = s.db.CreateUser(user)
 	if err != nil {
 		// Rollback: Delete from Keycloak since DB creation failed
@@ -58,6 +80,43 @@ func (s *userService) CreateUser(ctx context.Context, user *models.User, passwor
 	return nil
 }
 
+// GuestClaim is the identity of a user signing in, as reported by the identity provider
+type GuestClaim struct {
+	KeycloakID    string
+	Email         string
+	EmailVerified bool
+	Username      string
+	FirstName     string
+	LastName      string
+}
+
+func (s *userService) ClaimGuestAccount(ctx context.Context, claim GuestClaim) (models.User, error) {
+	user, err := s.db.FindUserByEmail(claim.Email)
+	if err != nil {
+		return models.User{}, fmt.Errorf("user not found: %w", err)
+	}
+	if !user.IsGuest {
+		return user, nil
+	}
+	if !claim.EmailVerified {
+		return models.User{}, ErrEmailNotVerified
+	}
+
+	user.KeycloackID = claim.KeycloakID
+	user.Username = claim.Username
+	if claim.FirstName != "" {
+		user.FirstName = claim.FirstName
+	}
+	if claim.LastName != "" {
+		user.LastName = claim.LastName
+	}
+	user.IsGuest = false
+	if err := s.db.UpdateUserById(&user); err != nil {
+		return models.User{}, fmt.Errorf("failed to claim guest account: %w", err)
+	}
+	return user, nil
+}
+
 // GetUserByID retrieves a user by ID from the database
 func (s *userService) GetUserByID(ctx context.Context, id uuid.UUID) (models.User, error) {
 	user, err := s.db.FindUserById(id)
@@ -131,7 +190,7 @@ func (s *userService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	}
 
 	// Delete from Keycloak first (can be recreated if DB delete fails)
-	if user.KeycloackID != "" {
+	if user.KeycloackID != "" && !user.IsGuest {
 		err = s.keycloak.DeleteKeycloakUser(ctx, user.KeycloackID)
 		if err != nil {
 			log.Printf("Warning: Failed to delete user from Keycloak: %v", err)
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"passIt/internal/auth"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// guestDB serves a single box office guest record and records the writes, other
+// database calls are not expected
+type guestDB struct {
+	database.Service
+	guest   models.User
+	updated []models.User
+	created []models.User
+}
+
+func (db *guestDB) FindUserByEmail(email string) (models.User, error) {
+	return db.guest, nil
+}
+
+func (db *guestDB) UpdateUserById(user *models.User) error {
+	db.updated = append(db.updated, *user)
+	return nil
+}
+
+func (db *guestDB) CreateUser(user *models.User) error {
+	db.created = append(db.created, *user)
+	return nil
+}
+
+// keycloakStub creates every user with the same Keycloak ID and records verification emails
+type keycloakStub struct {
+	auth.KeycloakClient
+	verifyEmails []string
+}
+
+func (k *keycloakStub) CreateKeycloakUser(ctx context.Context, user *models.User, password string) (string, error) {
+	return "kc-1", nil
+}
+
+func (k *keycloakStub) SendVerifyEmail(ctx context.Context, keycloakUserID string) error {
+	k.verifyEmails = append(k.verifyEmails, keycloakUserID)
+	return nil
+}
+
+func TestUserService_GuestClaimedOnlyWithVerifiedEmail(t *testing.T) {
+	ctx := context.Background()
+	guest := models.NewGuestUser("fan@example.com", "Box", "Office", "+3212345678")
+	db := &guestDB{guest: guest}
+	keycloak := &keycloakStub{}
+	users := NewUserService(db, keycloak)
+
+	signup := &models.User{Username: "someone", Email: guest.Email}
+	err := users.CreateUser(ctx, signup, "password123")
+	assert.ErrorIs(t, err, ErrEmailVerificationRequired)
+	assert.Equal(t, []string{"kc-1"}, keycloak.verifyEmails)
+	assert.NotEqual(t, guest.ID, signup.ID, "a sign-up does not take over the guest record")
+	assert.Empty(t, db.updated)
+	assert.Empty(t, db.created)
+
+	claim := GuestClaim{KeycloakID: "kc-1", Email: guest.Email, Username: "someone"}
+	_, err = users.ClaimGuestAccount(ctx, claim)
+	assert.ErrorIs(t, err, ErrEmailNotVerified)
+	assert.Empty(t, db.updated, "an unverified email does not get the guest's orders")
+
+	claim.EmailVerified = true
+	user, err := users.ClaimGuestAccount(ctx, claim)
+	require.NoError(t, err)
+	assert.Equal(t, guest.ID, user.ID)
+	assert.Equal(t, "kc-1", user.KeycloackID)
+	assert.Equal(t, "someone", user.Username)
+	assert.False(t, user.IsGuest)
+	require.Len(t, db.updated, 1)
+	assert.Equal(t, guest.ID, db.updated[0].ID)
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"passIt/internal/database"
+	"passIt/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrVenueNotFound    = errors.New("venue not found")
+	ErrVenueLayoutInUse = errors.New("venue layout is used by events with generated seat inventory")
+	ErrEventSeatsSold   = errors.New("event already has sold seats")
+)
+
+// VenueService handles venues, seating layouts and event seat inventory
+type VenueService interface {
+	CreateVenue(ctx context.Context, venue *models.Venue) error
+	GetVenueByID(ctx context.Context, id uuid.UUID) (models.Venue, error)
+	ListVenues(ctx context.Context) ([]models.Venue, error)
+	UpdateVenueDetails(ctx context.Context, venue *models.Venue) error
+	ImportLayout(ctx context.Context, venueID uuid.UUID, sections []models.VenueSection) (models.Venue, error)
+	AttachVenueToEvent(ctx context.Context, eventID, venueID uuid.UUID) (models.Event, error)
+	GetEventSeats(ctx context.Context, eventID uuid.UUID) ([]models.EventSeat, error)
+}
+
+type venueService struct {
+	db database.Service
+}
+
+// NewVenueService creates a new venue service
+func NewVenueService(db database.Service) VenueService {
+	return &venueService{
+		db: db,
+	}
+}
+
+// CreateVenue validates the layout and stores the venue with all its sections, rows and seats
+func (s *venueService) CreateVenue(ctx context.Context, venue *models.Venue) error {
+	models.NormalizeLayout(venue.Sections)
+	venue.TaxJurisdiction = models.NormalizeJurisdiction(venue.TaxJurisdiction)
+	if err := venue.Validate(); err != nil {
+		return err
+	}
+	if err := s.db.CreateVenue(venue); err != nil {
+		return fmt.Errorf("failed to create venue: %w", err)
+	}
+	return nil
+}
+
+// GetVenueByID retrieves a venue with its full seating layout
+func (s *venueService) GetVenueByID(ctx context.Context, id uuid.UUID) (models.Venue, error) {
+	venue, err := s.db.FindVenueById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Venue{}, ErrVenueNotFound
+		}
+		return models.Venue{}, fmt.Errorf("failed to retrieve venue: %w", err)
+	}
+	return venue, nil
+}
+
+// ListVenues retrieves all venues without their layouts
+func (s *venueService) ListVenues(ctx context.Context) ([]models.Venue, error) {
+	venues, err := s.db.ListVenues()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve venues: %w", err)
+	}
+	return venues, nil
+}
+
+// UpdateVenueDetails updates the descriptive fields of a venue, leaving the layout untouched
+func (s *venueService) UpdateVenueDetails(ctx context.Context, venue *models.Venue) error {
+	venue.TaxJurisdiction = models.NormalizeJurisdiction(venue.TaxJurisdiction)
+	if err := venue.Validate(); err != nil {
+		return err
+	}
+	if err := s.db.UpdateVenueDetails(venue); err != nil {
+		return fmt.Errorf("failed to update venue: %w", err)
+	}
+	return nil
+}
+
+// ImportLayout replaces the seating layout of a venue.
+// Layouts that already generated event inventory are locked so seat references stay valid.
+func (s *venueService) ImportLayout(ctx context.Context, venueID uuid.UUID, sections []models.VenueSection) (models.Venue, error) {
+	venue, err := s.GetVenueByID(ctx, venueID)
+	if err != nil {
+		return models.Venue{}, err
+	}
+
+	models.NormalizeLayout(sections)
+	if err := models.ValidateLayout(sections); err != nil {
+		return models.Venue{}, err
+	}
+
+	inUse, err := s.db.CountEventsWithSeatsForVenue(venue.ID)
+	if err != nil {
+		return models.Venue{}, fmt.Errorf("failed to check venue usage: %w", err)
+	}
+	if inUse > 0 {
+		return models.Venue{}, ErrVenueLayoutInUse
+	}
+
+	if err := s.db.ReplaceVenueLayout(venue.ID, sections); err != nil {
+		return models.Venue{}, fmt.Errorf("failed to import venue layout: %w", err)
+	}
+	return s.GetVenueByID(ctx, venue.ID)
+}
+
+// AttachVenueToEvent links a venue to an event and generates one inventory record per seat.
+// The event capacity is set to the capacity of the layout.
+func (s *venueService) AttachVenueToEvent(ctx context.Context, eventID, venueID uuid.UUID) (models.Event, error) {
+	event, err := s.db.FindEventById(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Event{}, ErrEventNotFound
+		}
+		return models.Event{}, fmt.Errorf("failed to retrieve event: %w", err)
+	}
+	if !event.Status.IsEditable() {
+		return models.Event{}, ErrEventNotEditable
+	}
+
+	venue, err := s.GetVenueByID(ctx, venueID)
+	if err != nil {
+		return models.Event{}, err
+	}
+
+	sold, err := s.db.CountSoldEventSeats(event.ID)
+	if err != nil {
+		return models.Event{}, fmt.Errorf("failed to check event seats: %w", err)
+	}
+	if sold > 0 {
+		return models.Event{}, ErrEventSeatsSold
+	}
+
+	allocated, err := s.db.SumTicketTypeQuantities(event.ID)
+	if err != nil {
+		return models.Event{}, fmt.Errorf("failed to check ticket allocation: %w", err)
+	}
+	if allocated > venue.Capacity() {
+		return models.Event{}, models.ErrTicketCapacityExceeded
+	}
+
+	event.VenueID = &venue.ID
+	event.Venue = venue.Name
+	event.Capacity = venue.Capacity()
+
+	seats := venue.GenerateEventSeats(event.ID)
+	if err := s.db.AttachVenueToEvent(&event, seats); err != nil {
+		return models.Event{}, fmt.Errorf("failed to attach venue to event: %w", err)
+	}
+	return event, nil
+}
+
+// GetEventSeats retrieves the seat inventory generated for an event
+func (s *venueService) GetEventSeats(ctx context.Context, eventID uuid.UUID) ([]models.EventSeat, error) {
+	seats, err := s.db.ListEventSeats(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve event seats: %w", err)
+	}
+	return seats, nil
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"passIt/internal/constant"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/store"
+	"passIt/internal/waitingroom"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWaitingRoomNotFound = errors.New("event has no waiting room")
+	ErrQueueNotOpen        = errors.New("waiting room is not open yet")
+	ErrQueueClosed         = errors.New("waiting room is closed, tickets can be bought directly")
+	ErrInvalidQueueToken   = errors.New("invalid queue token")
+	ErrAdmissionRequired   = errors.New("an admission from the waiting room is required to buy tickets for this event")
+	ErrAdmissionExpired    = errors.New("admission from the waiting room has expired, join the queue again")
+)
+
+// QueueStatus is a buyer's place in the waiting room of an event
+type QueueStatus struct {
+	EventID uuid.UUID `json:"event_id"`
+	// Position is the place in the queue, counted from 1 in order of arrival
+	Position int64 `json:"position"`
+	// Ahead is how many buyers are still waiting in front of this one
+	Ahead                int64  `json:"ahead"`
+	EstimatedWaitSeconds int64  `json:"estimated_wait_seconds"`
+	Admitted             bool   `json:"admitted"`
+	QueueToken           string `json:"queue_token"`
+	// AdmissionToken must be sent in the X-Admission-Token header to hold and check out tickets
+	AdmissionToken     string     `json:"admission_token,omitempty"`
+	AdmissionExpiresAt *time.Time `json:"admission_expires_at,omitempty"`
+	ClosesAt           time.Time  `json:"closes_at"`
+}
+
+// WaitingRoomService queues buyers of high-demand on-sales and lets them into checkout
+// at the rate the organizer configured
+type WaitingRoomService interface {
+	GetWaitingRoom(ctx context.Context, eventID uuid.UUID) (models.WaitingRoom, error)
+	// SaveWaitingRoom creates or replaces the waiting room of an event
+	SaveWaitingRoom(ctx context.Context, room *models.WaitingRoom) error
+	// DeleteWaitingRoom removes the waiting room and its queue, buyers are no longer held back
+	DeleteWaitingRoom(ctx context.Context, eventID uuid.UUID) error
+	// Join puts the user in the queue of the event and returns a signed queue token
+	Join(ctx context.Context, eventID, userID uuid.UUID) (QueueStatus, error)
+	// Status returns the position and estimated wait of a queue token, with an admission
+	// token once the user's turn has come
+	Status(ctx context.Context, eventID, userID uuid.UUID, queueToken string) (QueueStatus, error)
+	// CheckAdmission reports whether the user may hold and check out tickets of the event.
+	// Events without a waiting room, or whose waiting room is not open, need no admission.
+	CheckAdmission(ctx context.Context, eventID, userID uuid.UUID, admissionToken string) error
+	// CheckHoldAdmission reports whether the owner of a hold may check it out. Waitlist offers
+	// and holds taken out before the waiting room opened are checked out without queueing.
+	CheckHoldAdmission(ctx context.Context, hold *store.Hold, admissionToken string) error
+}
+
+type waitingRoomService struct {
+	db     database.Service
+	queues store.QueueStore
+	signer *waitingroom.Signer
+}
+
+// NewWaitingRoomService creates a new waiting room service
+func NewWaitingRoomService(db database.Service, queues store.QueueStore, signer *waitingroom.Signer) WaitingRoomService {
+	return &waitingRoomService{
+		db:     db,
+		queues: queues,
+		signer: signer,
+	}
+}
+
+func (s *waitingRoomService) GetWaitingRoom(ctx context.Context, eventID uuid.UUID) (models.WaitingRoom, error) {
+	room, err := s.db.FindWaitingRoomByEvent(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.WaitingRoom{}, ErrWaitingRoomNotFound
+		}
+		return models.WaitingRoom{}, fmt.Errorf("failed to retrieve waiting room: %w", err)
+	}
+	return room, nil
+}
+
+func (s *waitingRoomService) SaveWaitingRoom(ctx context.Context, room *models.WaitingRoom) error {
+	if err := room.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.db.FindEventById(room.EventID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrEventNotFound
+		}
+		return fmt.Errorf("failed to retrieve event: %w", err)
+	}
+	if err := s.db.SaveWaitingRoom(room); err != nil {
+		return fmt.Errorf("failed to save waiting room: %w", err)
+	}
+	return nil
+}
+
+func (s *waitingRoomService) DeleteWaitingRoom(ctx context.Context, eventID uuid.UUID) error {
+	if _, err := s.GetWaitingRoom(ctx, eventID); err != nil {
+		return err
+	}
+	if err := s.db.DeleteWaitingRoom(eventID); err != nil {
+		return fmt.Errorf("failed to delete waiting room: %w", err)
+	}
+	return s.queues.Reset(ctx, eventID)
+}
+
+func (s *waitingRoomService) Join(ctx context.Context, eventID, userID uuid.UUID) (QueueStatus, error) {
+	room, err := s.openWaitingRoom(ctx, eventID)
+	if err != nil {
+		return QueueStatus{}, err
+	}
+
+	position, err := s.queues.Join(ctx, eventID, userID, s.retention(room))
+	if err != nil {
+		return QueueStatus{}, err
+	}
+	status, err := s.status(ctx, room, userID, position)
+	if errors.Is(err, ErrAdmissionExpired) {
+		// Joining again after the admission lapsed sends the user to the back of the queue
+		if err := s.queues.Leave(ctx, eventID, userID); err != nil {
+			return QueueStatus{}, err
+		}
+		if position, err = s.queues.Join(ctx, eventID, userID, s.retention(room)); err != nil {
+			return QueueStatus{}, err
+		}
+		return s.status(ctx, room, userID, position)
+	}
+	return status, err
+}
+
+func (s *waitingRoomService) Status(ctx context.Context, eventID, userID uuid.UUID, queueToken string) (QueueStatus, error) {
+	claims, err := s.signer.Verify(queueToken, waitingroom.KindQueue, time.Now())
+	if err != nil || claims.EventID != eventID || claims.UserID != userID {
+		return QueueStatus{}, ErrInvalidQueueToken
+	}
+	room, err := s.openWaitingRoom(ctx, eventID)
+	if err != nil {
+		return QueueStatus{}, err
+	}
+	// A token from before the user was sent to the back of the queue no longer holds a place
+	position, err := s.queues.Position(ctx, eventID, userID)
+	if err != nil {
+		return QueueStatus{}, err
+	}
+	if position != claims.Position {
+		return QueueStatus{}, ErrInvalidQueueToken
+	}
+	return s.status(ctx, room, userID, position)
+}
+
+func (s *waitingRoomService) CheckAdmission(ctx context.Context, eventID, userID uuid.UUID, admissionToken string) error {
+	room, err := s.GetWaitingRoom(ctx, eventID)
+	if errors.Is(err, ErrWaitingRoomNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.checkAdmission(room, userID, admissionToken, time.Now())
+}
+
+func (s *waitingRoomService) CheckHoldAdmission(ctx context.Context, hold *store.Hold, admissionToken string) error {
+	if hold.Offer {
+		return nil
+	}
+	room, err := s.GetWaitingRoom(ctx, hold.EventID)
+	if errors.Is(err, ErrWaitingRoomNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if hold.CreatedAt.Before(room.OpensAt) {
+		return nil
+	}
+	return s.checkAdmission(room, hold.UserID, admissionToken, time.Now())
+}
+
+// checkAdmission verifies the admission token of the user while the waiting room is open
+func (s *waitingRoomService) checkAdmission(room models.WaitingRoom, userID uuid.UUID, admissionToken string, now time.Time) error {
+	if !room.IsActive(now) {
+		return nil
+	}
+	if admissionToken == "" {
+		return ErrAdmissionRequired
+	}
+	claims, err := s.signer.Verify(admissionToken, waitingroom.KindAdmission, now)
+	if errors.Is(err, waitingroom.ErrExpiredToken) {
+		return ErrAdmissionExpired
+	}
+	if err != nil || claims.EventID != room.EventID || claims.UserID != userID {
+		return ErrAdmissionRequired
+	}
+	return nil
+}
+
+// openWaitingRoom returns the waiting room of the event while buyers are queueing in it
+func (s *waitingRoomService) openWaitingRoom(ctx context.Context, eventID uuid.UUID) (models.WaitingRoom, error) {
+	room, err := s.GetWaitingRoom(ctx, eventID)
+	if err != nil {
+		return models.WaitingRoom{}, err
+	}
+	now := time.Now()
+	if now.Before(room.OpensAt) {
+		return models.WaitingRoom{}, ErrQueueNotOpen
+	}
+	if !room.IsActive(now) {
+		return models.WaitingRoom{}, ErrQueueClosed
+	}
+	return room, nil
+}
+
+// status advances the queue and describes the place of the user in it. Once the user is
+// admitted the admission lasts for the configured time from the first time it is seen.
+func (s *waitingRoomService) status(ctx context.Context, room models.WaitingRoom, userID uuid.UUID, position int64) (QueueStatus, error) {
+	now := time.Now()
+	retention := s.retention(room)
+	admitted, err := s.queues.Admitted(ctx, room.EventID, room.Interval(), now, retention)
+	if err != nil {
+		return QueueStatus{}, err
+	}
+
+	queueToken, err := s.signer.Sign(waitingroom.Claims{
+		Kind:      waitingroom.KindQueue,
+		EventID:   room.EventID,
+		UserID:    userID,
+		Position:  position,
+		ExpiresAt: room.ClosesAt.Unix(),
+	})
+	if err != nil {
+		return QueueStatus{}, err
+	}
+	status := QueueStatus{
+		EventID:    room.EventID,
+		Position:   position,
+		QueueToken: queueToken,
+		ClosesAt:   room.ClosesAt,
+	}
+	if position > admitted {
+		status.Ahead = position - admitted - 1
+		status.EstimatedWaitSeconds = int64(room.EstimatedWait(position - admitted).Seconds())
+		return status, nil
+	}
+
+	expiresAt, err := s.queues.Admission(ctx, room.EventID, userID, now.Add(room.AdmissionDuration()), retention)
+	if err != nil {
+		return QueueStatus{}, err
+	}
+	if !now.Before(expiresAt) {
+		return QueueStatus{}, ErrAdmissionExpired
+	}
+	admissionToken, err := s.signer.Sign(waitingroom.Claims{
+		Kind:      waitingroom.KindAdmission,
+		EventID:   room.EventID,
+		UserID:    userID,
+		Position:  position,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return QueueStatus{}, err
+	}
+	status.Admitted = true
+	status.AdmissionToken = admissionToken
+	status.AdmissionExpiresAt = &expiresAt
+	return status, nil
+}
+
+// retention is how long the queue of a waiting room is kept in Redis
+func (s *waitingRoomService) retention(room models.WaitingRoom) time.Duration {
+	return time.Until(room.ClosesAt) + constant.WaitingRoomRetention
+}
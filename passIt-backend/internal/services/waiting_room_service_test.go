@@ -0,0 +1,77 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/store"
+	"passIt/internal/waitingroom"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitingRoomDB serves a single waiting room, other database calls are not expected
+type waitingRoomDB struct {
+	database.Service
+	room models.WaitingRoom
+}
+
+func (db *waitingRoomDB) FindWaitingRoomByEvent(eventID uuid.UUID) (models.WaitingRoom, error) {
+	return db.room, nil
+}
+
+func newTestWaitingRoomService(t *testing.T, room models.WaitingRoom) (WaitingRoomService, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	signer, err := waitingroom.NewSigner(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	queues := store.NewQueueRedisManager(client)
+	return NewWaitingRoomService(&waitingRoomDB{room: room}, queues, signer), mr
+}
+
+func TestWaitingRoomService_RejoinAfterExpiredAdmission(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	room := models.WaitingRoom{
+		EventID:          uuid.New(),
+		OpensAt:          now.Add(-time.Hour),
+		ClosesAt:         now.Add(time.Hour),
+		AdmitPerMinute:   1,
+		AdmissionMinutes: 15,
+	}
+	service, mr := newTestWaitingRoomService(t, room)
+	userID, otherID := uuid.New(), uuid.New()
+
+	first, err := service.Join(ctx, room.EventID, userID)
+	require.NoError(t, err)
+	require.True(t, first.Admitted)
+	_, err = service.Join(ctx, room.EventID, otherID)
+	require.NoError(t, err)
+
+	// The admission lapses without the user buying
+	mr.HSet("queue:"+room.EventID.String()+":admissions", userID.String(), strconv.FormatInt(now.Add(-time.Minute).UnixMilli(), 10))
+
+	rejoined, err := service.Join(ctx, room.EventID, userID)
+	require.NoError(t, err)
+	assert.False(t, rejoined.Admitted, "an expired admission sends the user to the back of the queue")
+	assert.Greater(t, rejoined.Position, first.Position)
+
+	_, err = service.Status(ctx, room.EventID, userID, first.QueueToken)
+	assert.ErrorIs(t, err, ErrInvalidQueueToken, "the token of the earlier place cannot be used to skip the queue")
+
+	status, err := service.Status(ctx, room.EventID, userID, rejoined.QueueToken)
+	require.NoError(t, err)
+	assert.False(t, status.Admitted)
+	assert.Equal(t, rejoined.Position, status.Position)
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"passIt/internal/constant"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/store"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+	ErrWaitlistForbidden     = errors.New("waitlist entry belongs to another user")
+	ErrWaitlistAlreadyJoined = errors.New("you are already on the waitlist of this ticket type")
+)
+
+// WaitlistPlace is a waitlist entry with the user's place in the queue
+type WaitlistPlace struct {
+	models.WaitlistEntry
+	// Position is the 1-based place among waiting users, 0 once the entry left the queue
+	Position int `json:"position"`
+}
+
+// WaitlistService queues users for sold-out ticket types and offers them freed tickets in turn
+type WaitlistService interface {
+	// Join queues the user for a sold-out ticket type of the event
+	Join(ctx context.Context, eventID, ticketTypeID, userID uuid.UUID, quantity int) (WaitlistPlace, error)
+	// Leave removes the user from the queue, giving up an outstanding offer
+	Leave(ctx context.Context, entryID, userID uuid.UUID) (models.WaitlistEntry, error)
+	GetPlace(ctx context.Context, entryID, userID uuid.UUID) (WaitlistPlace, error)
+	ListUserPlaces(ctx context.Context, userID uuid.UUID) ([]WaitlistPlace, error)
+	// ProcessWaitlists closes lapsed offers and offers freed tickets to the next users in line.
+	// It returns how many offers were made.
+	ProcessWaitlists(ctx context.Context) (int, error)
+	// RunOfferWorker processes the waitlists every interval until the context is cancelled
+	RunOfferWorker(ctx context.Context, interval time.Duration)
+}
+
+type waitlistService struct {
+	db    database.Service
+	holds store.HoldStore
+}
+
+// NewWaitlistService creates a new waitlist service
+func NewWaitlistService(db database.Service, holds store.HoldStore) WaitlistService {
+	return &waitlistService{
+		db:    db,
+		holds: holds,
+	}
+}
+
+func (s *waitlistService) Join(ctx context.Context, eventID, ticketTypeID, userID uuid.UUID, quantity int) (WaitlistPlace, error) {
+	event, err := s.db.FindEventById(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return WaitlistPlace{}, ErrEventNotFound
+		}
+		return WaitlistPlace{}, fmt.Errorf("failed to retrieve event: %w", err)
+	}
+	if event.Status != models.EventStatusPublished {
+		return WaitlistPlace{}, ErrEventNotFound
+	}
+
+	ticketType, err := s.db.FindTicketTypeById(ticketTypeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return WaitlistPlace{}, ErrTicketTypeNotFound
+		}
+		return WaitlistPlace{}, fmt.Errorf("failed to retrieve ticket type: %w", err)
+	}
+	if ticketType.EventID != eventID {
+		return WaitlistPlace{}, ErrTicketTypeNotFound
+	}
+	if !ticketType.IsOnSale(time.Now()) {
+		return WaitlistPlace{}, ErrTicketTypeNotOnSale
+	}
+	if err := ticketType.CheckOrderQuantity(quantity); err != nil {
+		return WaitlistPlace{}, err
+	}
+
+	available, err := s.available(ctx, &ticketType)
+	if err != nil {
+		return WaitlistPlace{}, err
+	}
+	waiting, err := s.db.CountWaitingEntries([]uuid.UUID{ticketType.ID})
+	if err != nil {
+		return WaitlistPlace{}, fmt.Errorf("failed to count waiting users: %w", err)
+	}
+	// With nobody in line, users who could buy right away are sent to the shop instead
+	if available >= quantity && waiting[ticketType.ID] == 0 {
+		return WaitlistPlace{}, models.ErrWaitlistNotSoldOut
+	}
+
+	_, err = s.db.FindOpenWaitlistEntry(ticketType.ID, userID)
+	switch {
+	case err == nil:
+		return WaitlistPlace{}, ErrWaitlistAlreadyJoined
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return WaitlistPlace{}, fmt.Errorf("failed to check waitlist: %w", err)
+	}
+
+	entry := models.WaitlistEntry{
+		EventID:      eventID,
+		TicketTypeID: ticketType.ID,
+		UserID:       userID,
+		Quantity:     quantity,
+		Status:       models.WaitlistStatusWaiting,
+	}
+	if err := s.db.CreateWaitlistEntry(&entry); err != nil {
+		return WaitlistPlace{}, fmt.Errorf("failed to join waitlist: %w", err)
+	}
+	return s.place(entry)
+}
+
+// available returns how many tickets of the type can still be held
+func (s *waitlistService) available(ctx context.Context, ticketType *models.TicketType) (int, error) {
+	held, err := s.holds.Taken(ctx, ticketType.ID)
+	if err != nil {
+		return 0, err
+	}
+	sold, err := s.db.CountTakenTickets([]uuid.UUID{ticketType.ID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count sold tickets: %w", err)
+	}
+	// The Redis counter is only seeded from the orders once someone holds tickets
+	return ticketType.Quantity - max(held, sold[ticketType.ID]), nil
+}
+
+func (s *waitlistService) place(entry models.WaitlistEntry) (WaitlistPlace, error) {
+	place := WaitlistPlace{WaitlistEntry: entry}
+	if entry.Status != models.WaitlistStatusWaiting {
+		return place, nil
+	}
+	position, err := s.db.WaitlistPosition(&entry)
+	if err != nil {
+		return WaitlistPlace{}, fmt.Errorf("failed to compute waitlist position: %w", err)
+	}
+	place.Position = position
+	return place, nil
+}
+
+func (s *waitlistService) getUserEntry(entryID, userID uuid.UUID) (models.WaitlistEntry, error) {
+	entry, err := s.db.FindWaitlistEntryById(entryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.WaitlistEntry{}, ErrWaitlistEntryNotFound
+		}
+		return models.WaitlistEntry{}, fmt.Errorf("failed to retrieve waitlist entry: %w", err)
+	}
+	if entry.UserID != userID {
+		return models.WaitlistEntry{}, ErrWaitlistForbidden
+	}
+	return entry, nil
+}
+
+func (s *waitlistService) Leave(ctx context.Context, entryID, userID uuid.UUID) (models.WaitlistEntry, error) {
+	entry, err := s.getUserEntry(entryID, userID)
+	if err != nil {
+		return models.WaitlistEntry{}, err
+	}
+	if err := s.close(ctx, &entry, models.WaitlistStatusLeft); err != nil {
+		return models.WaitlistEntry{}, err
+	}
+	return entry, nil
+}
+
+// close moves an open entry to a final status. Offers that were not checked out
+// give their tickets back so the next user can get them.
+func (s *waitlistService) close(ctx context.Context, entry *models.WaitlistEntry, next models.WaitlistStatus) error {
+	if !entry.Status.CanTransitionTo(next) {
+		return models.ErrWaitlistInvalidStatus
+	}
+
+	from := entry.Status
+	entry.Status = next
+	updated, err := s.db.UpdateWaitlistEntry(entry, from)
+	if err != nil {
+		return fmt.Errorf("failed to update waitlist entry: %w", err)
+	}
+	if !updated {
+		return models.ErrWaitlistInvalidStatus
+	}
+
+	if from == models.WaitlistStatusOffered && next != models.WaitlistStatusAccepted {
+		if _, err := s.holds.Release(ctx, entry.HoldID); err != nil {
+			log.Printf("Failed to release hold of waitlist offer %s: %v", entry.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *waitlistService) GetPlace(ctx context.Context, entryID, userID uuid.UUID) (WaitlistPlace, error) {
+	entry, err := s.getUserEntry(entryID, userID)
+	if err != nil {
+		return WaitlistPlace{}, err
+	}
+	return s.place(entry)
+}
+
+func (s *waitlistService) ListUserPlaces(ctx context.Context, userID uuid.UUID) ([]WaitlistPlace, error) {
+	entries, err := s.db.ListWaitlistEntriesByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve waitlist entries: %w", err)
+	}
+
+	places := make([]WaitlistPlace, 0, len(entries))
+	for _, entry := range entries {
+		place, err := s.place(entry)
+		if err != nil {
+			return nil, err
+		}
+		places = append(places, place)
+	}
+	return places, nil
+}
+
+func (s *waitlistService) ProcessWaitlists(ctx context.Context) (int, error) {
+	now := time.Now()
+	if err := s.closeOffers(ctx, now); err != nil {
+		return 0, err
+	}
+
+	// Expired holds are the most common source of freed tickets
+	if _, err := s.holds.ReleaseExpired(ctx, now); err != nil {
+		log.Printf("Failed to release expired holds: %v", err)
+	}
+
+	ticketTypeIDs, err := s.db.ListWaitlistedTicketTypes()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list waitlists: %w", err)
+	}
+
+	offered := 0
+	for _, ticketTypeID := range ticketTypeIDs {
+		count, err := s.offer(ctx, ticketTypeID, now)
+		if err != nil {
+			log.Printf("Failed to process waitlist of ticket type %s: %v", ticketTypeID, err)
+		}
+		offered += count
+	}
+	return offered, nil
+}
+
+// closeOffers marks offers that were checked out as accepted and passes on the ones
+// that lapsed
+func (s *waitlistService) closeOffers(ctx context.Context, now time.Time) error {
+	entries, err := s.db.ListOfferedWaitlistEntries()
+	if err != nil {
+		return fmt.Errorf("failed to list waitlist offers: %w", err)
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		next := models.WaitlistStatusAccepted
+		_, err := s.db.FindOrderByHoldId(entry.HoldID)
+		switch {
+		case err == nil:
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			log.Printf("Failed to check order of waitlist offer %s: %v", entry.ID, err)
+			continue
+		case entry.OfferExpired(now):
+			next = models.WaitlistStatusExpired
+		default:
+			continue
+		}
+
+		if err := s.close(ctx, entry, next); err != nil && !errors.Is(err, models.ErrWaitlistInvalidStatus) {
+			log.Printf("Failed to close waitlist offer %s: %v", entry.ID, err)
+		}
+	}
+	return nil
+}
+
+// offer holds freed tickets for the users waiting on a ticket type in queue order. It
+// stops at the first user whose quantity is not available, nobody can skip the line.
+func (s *waitlistService) offer(ctx context.Context, ticketTypeID uuid.UUID, now time.Time) (int, error) {
+	ticketType, err := s.db.FindTicketTypeById(ticketTypeID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve ticket type: %w", err)
+	}
+	if !ticketType.IsOnSale(now) {
+		return 0, nil
+	}
+
+	event, err := s.db.FindEventById(ticketType.EventID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve event: %w", err)
+	}
+
+	entries, err := s.db.ListWaitingEntries(ticketTypeID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list waiting users: %w", err)
+	}
+	taken, err := s.db.CountTakenTickets([]uuid.UUID{ticketTypeID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count sold tickets: %w", err)
+	}
+	limits := map[uuid.UUID]store.InventoryLimit{
+		ticketTypeID: {Capacity: ticketType.Quantity, Taken: taken[ticketTypeID]},
+	}
+
+	offered := 0
+	for i := range entries {
+		entry := &entries[i]
+		hold := &store.Hold{
+			EventID: entry.EventID,
+			UserID:  entry.UserID,
+			Items:   []store.HoldItem{{TicketTypeID: ticketTypeID, Quantity: entry.Quantity}},
+			Offer:   true,
+		}
+		limit, err := userPurchaseLimit(s.db, event, entry.UserID)
+		if err != nil {
+			return offered, err
+		}
+		hold.Limits = []store.PurchaseLimit{limit}
+		if err := s.holds.CreateWithTTL(ctx, hold, limits, constant.WaitlistOfferWindow); err != nil {
+			if errors.Is(err, store.ErrSoldOut) {
+				return offered, nil
+			}
+			if errors.Is(err, store.ErrPurchaseLimitExceeded) {
+				// The user bought tickets elsewhere in the meantime and can never be offered
+				// these, the next in line gets the offer
+				if err := s.skip(entry, models.WaitlistSkipPurchaseLimit); err != nil {
+					return offered, err
+				}
+				continue
+			}
+			return offered, err
+		}
+
+		if err := entry.Offer(hold.ID, now, hold.ExpiresAt); err != nil {
+			return offered, err
+		}
+		updated, err := s.db.UpdateWaitlistEntry(entry, models.WaitlistStatusWaiting)
+		if err != nil || !updated {
+			// The user left while the offer was being made
+			if _, releaseErr := s.holds.Release(ctx, hold.ID); releaseErr != nil {
+				log.Printf("Failed to release hold of waitlist offer %s: %v", entry.ID, releaseErr)
+			}
+			if err != nil {
+				return offered, fmt.Errorf("failed to update waitlist entry: %w", err)
+			}
+			continue
+		}
+		offered++
+	}
+	return offered, nil
+}
+
+// skip takes a waiting entry out of the queue so it no longer holds back freed tickets
+func (s *waitlistService) skip(entry *models.WaitlistEntry, reason string) error {
+	if err := entry.Skip(reason); err != nil {
+		return err
+	}
+	if _, err := s.db.UpdateWaitlistEntry(entry, models.WaitlistStatusWaiting); err != nil {
+		return fmt.Errorf("failed to update waitlist entry: %w", err)
+	}
+	return nil
+}
+
+func (s *waitlistService) RunOfferWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			offered, err := s.ProcessWaitlists(ctx)
+			if err != nil {
+				log.Printf("Failed to process waitlists: %v", err)
+				continue
+			}
+			if offered > 0 {
+				log.Printf("Made %d waitlist offers", offered)
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"passIt/internal/database"
+	"passIt/internal/models"
+	"passIt/internal/store"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitlistDB serves one event with one ticket type and its waitlist, other database
+// calls are not expected
+type waitlistDB struct {
+	database.Service
+	event       models.Event
+	ticketType  models.TicketType
+	taken       int
+	userTickets map[uuid.UUID]int
+	entries     []models.WaitlistEntry
+}
+
+func (db *waitlistDB) FindEventById(id uuid.UUID) (models.Event, error) {
+	return db.event, nil
+}
+
+func (db *waitlistDB) FindTicketTypeById(id uuid.UUID) (models.TicketType, error) {
+	return db.ticketType, nil
+}
+
+func (db *waitlistDB) ListTicketTypesByEvent(eventID uuid.UUID) ([]models.TicketType, error) {
+	return []models.TicketType{db.ticketType}, nil
+}
+
+func (db *waitlistDB) CountTakenTickets(ticketTypeIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	return map[uuid.UUID]int{db.ticketType.ID: db.taken}, nil
+}
+
+func (db *waitlistDB) CountUserTickets(eventID, userID uuid.UUID) (int, error) {
+	return db.userTickets[userID], nil
+}
+
+func (db *waitlistDB) ListOfferedWaitlistEntries() ([]models.WaitlistEntry, error) {
+	return db.withStatus(models.WaitlistStatusOffered), nil
+}
+
+func (db *waitlistDB) ListWaitingEntries(ticketTypeID uuid.UUID) ([]models.WaitlistEntry, error) {
+	return db.withStatus(models.WaitlistStatusWaiting), nil
+}
+
+func (db *waitlistDB) ListWaitlistedTicketTypes() ([]uuid.UUID, error) {
+	if len(db.withStatus(models.WaitlistStatusWaiting)) == 0 {
+		return nil, nil
+	}
+	return []uuid.UUID{db.ticketType.ID}, nil
+}
+
+func (db *waitlistDB) CountWaitingEntries(ticketTypeIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	return map[uuid.UUID]int{db.ticketType.ID: len(db.withStatus(models.WaitlistStatusWaiting))}, nil
+}
+
+func (db *waitlistDB) UpdateWaitlistEntry(entry *models.WaitlistEntry, from models.WaitlistStatus) (bool, error) {
+	for i := range db.entries {
+		if db.entries[i].ID == entry.ID && db.entries[i].Status == from {
+			db.entries[i] = *entry
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (db *waitlistDB) withStatus(status models.WaitlistStatus) []models.WaitlistEntry {
+	var entries []models.WaitlistEntry
+	for _, entry := range db.entries {
+		if entry.Status == status {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func newTestRedisClient(t *testing.T) *redis.Client {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func newTestHoldStore(t *testing.T) store.HoldStore {
+	t.Helper()
+	return store.NewHoldRedisManager(newTestRedisClient(t))
+}
+
+func TestWaitlistService_SkipsUsersOverPurchaseLimit(t *testing.T) {
+	ctx := context.Background()
+	event := models.Event{ID: uuid.New(), Status: models.EventStatusPublished, MaxTicketsPerUser: 2}
+	ticketType := models.TicketType{ID: uuid.New(), EventID: event.ID, Quantity: 2}
+	waiterID, buyerID := uuid.New(), uuid.New()
+	db := &waitlistDB{
+		event:      event,
+		ticketType: ticketType,
+		// One of the two tickets was refunded while the waiter bought their limit elsewhere
+		taken:       1,
+		userTickets: map[uuid.UUID]int{waiterID: 2},
+		entries: []models.WaitlistEntry{{
+			ID:           uuid.New(),
+			EventID:      event.ID,
+			TicketTypeID: ticketType.ID,
+			UserID:       waiterID,
+			Quantity:     1,
+			Status:       models.WaitlistStatusWaiting,
+		}},
+	}
+	holds := newTestHoldStore(t)
+	waitlists := NewWaitlistService(db, holds)
+	holdService := NewHoldService(db, holds)
+	items := []store.HoldItem{{TicketTypeID: ticketType.ID, Quantity: 1}}
+
+	_, err := holdService.CreateHold(ctx, event.ID, buyerID, items)
+	assert.ErrorIs(t, err, store.ErrSoldOut, "freed tickets belong to the waitlist")
+
+	offered, err := waitlists.ProcessWaitlists(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, offered)
+	assert.Equal(t, models.WaitlistStatusSkipped, db.entries[0].Status)
+	assert.Equal(t, models.WaitlistSkipPurchaseLimit, db.entries[0].SkipReason)
+
+	hold, err := holdService.CreateHold(ctx, event.ID, buyerID, items)
+	require.NoError(t, err, "the ticket is back on sale once nobody who can buy it is waiting")
+	assert.Equal(t, buyerID, hold.UserID)
+}
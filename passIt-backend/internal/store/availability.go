@@ -0,0 +1,271 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// AvailabilityReason tells subscribers why the inventory of an event changed
+type AvailabilityReason string
+
+const (
+	AvailabilityHeld     AvailabilityReason = "held"
+	AvailabilityReleased AvailabilityReason = "released"
+	AvailabilitySold     AvailabilityReason = "sold"
+)
+
+// AvailabilityUpdate is broadcast to every API instance when holds or orders change the
+// inventory of an event. It carries the state of the changed inventory after the change.
+type AvailabilityUpdate struct {
+	EventID     uuid.UUID          `json:"event_id"`
+	Reason      AvailabilityReason `json:"reason"`
+	TicketTypes []TicketTypeTaken  `json:"ticket_types"`
+	Seats       []SeatTaken        `json:"seats"`
+	At          time.Time          `json:"at"`
+}
+
+// TicketTypeTaken is how many tickets of a type are held or sold
+type TicketTypeTaken struct {
+	TicketTypeID uuid.UUID `json:"ticket_type_id"`
+	Taken        int       `json:"taken"`
+}
+
+// SeatTaken tells whether a seat is held or sold
+type SeatTaken struct {
+	SeatID uuid.UUID `json:"seat_id"`
+	Taken  bool      `json:"taken"`
+}
+
+// AvailabilityStore broadcasts inventory changes of events over Redis pub/sub, so buyers
+// connected to any API instance see them
+type AvailabilityStore interface {
+	// PublishAvailability broadcasts the current state of the ticket types and seats of a hold
+	PublishAvailability(ctx context.Context, reason AvailabilityReason, hold *Hold) error
+	// SubscribeAvailability delivers the updates of an event until the context is done,
+	// then closes the channel. Subscribers of an instance share one Redis subscription, one
+	// that falls behind is closed early and should subscribe again.
+	SubscribeAvailability(ctx context.Context, eventID uuid.UUID) (<-chan AvailabilityUpdate, error)
+}
+
+// Ensure RedisHoldManager implements AvailabilityStore
+var _ AvailabilityStore = (*RedisHoldManager)(nil)
+
+// availabilityChannel is the pub/sub channel of the inventory changes of an event
+func (r *RedisHoldManager) availabilityChannel(eventID uuid.UUID) string {
+	return fmt.Sprintf("%s:availability:%s", r.PrefixState, eventID)
+}
+
+// publishAvailabilityScript reads the counters and seats and publishes them in one step,
+// so updates reach subscribers in the order the inventory changed. Counters that are not
+// loaded are left out, the database has their count.
+// KEYS: taken counters..., seats...
+// ARGV: channel, message head, counter count, ticket type ids..., seat ids...
+var publishAvailabilityScript = redis.NewScript(`
+local n = tonumber(ARGV[3])
+local ticketTypes = {}
+for i = 1, n do
+	local taken = redis.call('GET', KEYS[i])
+	if taken then
+		table.insert(ticketTypes, '{"ticket_type_id":"' .. ARGV[3 + i] .. '","taken":' .. tonumber(taken) .. '}')
+	end
+end
+local seats = {}
+for j = n + 1, #KEYS do
+	local taken = redis.call('EXISTS', KEYS[j]) == 1 and 'true' or 'false'
+	table.insert(seats, '{"seat_id":"' .. ARGV[3 + j] .. '","taken":' .. taken .. '}')
+end
+local message = ARGV[2] .. ',"ticket_types":[' .. table.concat(ticketTypes, ',') .. '],"seats":[' .. table.concat(seats, ',') .. ']}'
+return redis.call('PUBLISH', ARGV[1], message)
+`)
+
+func (r *RedisHoldManager) PublishAvailability(ctx context.Context, reason AvailabilityReason, hold *Hold) error {
+	ticketTypes, _, _, seats, seatKeys := r.inventoryKeys(hold)
+
+	// The purchase limit counters are private to each buyer and never published
+	keys := make([]string, 0, len(ticketTypes)+len(seatKeys))
+	for _, id := range ticketTypes {
+		keys = append(keys, r.takenKey(id))
+	}
+	keys = append(keys, seatKeys...)
+
+	head, err := json.Marshal(struct {
+		EventID uuid.UUID          `json:"event_id"`
+		Reason  AvailabilityReason `json:"reason"`
+		At      time.Time          `json:"at"`
+	}{hold.EventID, reason, time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal availability update: %w", err)
+	}
+
+	args := []interface{}{r.availabilityChannel(hold.EventID), string(head[:len(head)-1]), len(ticketTypes)}
+	for _, id := range ticketTypes {
+		args = append(args, id.String())
+	}
+	for _, id := range seats {
+		args = append(args, id.String())
+	}
+
+	if err := publishAvailabilityScript.Run(ctx, r.client, keys, args...).Err(); err != nil {
+		return fmt.Errorf("failed to publish availability in Redis: %w", err)
+	}
+	return nil
+}
+
+// publish broadcasts an inventory change. Subscribers only miss a refresh when it fails,
+// so the change itself is not undone.
+func (r *RedisHoldManager) publish(ctx context.Context, reason AvailabilityReason, hold *Hold) {
+	if err := r.PublishAvailability(ctx, reason, hold); err != nil {
+		log.Printf("Failed to publish availability of event %s: %v", hold.EventID, err)
+	}
+}
+
+func (r *RedisHoldManager) SubscribeAvailability(ctx context.Context, eventID uuid.UUID) (<-chan AvailabilityUpdate, error) {
+	return r.availability.subscribe(ctx, r.availabilityChannel(eventID))
+}
+
+// availabilityBuffer is how many updates a subscriber may fall behind before it is dropped
+const availabilityBuffer = 16
+
+// availabilityHub shares one Redis subscription per event between all subscribers of an
+// API instance, over a single pub/sub connection, so on-sales with thousands of buyers
+// watching do not open thousands of Redis connections
+type availabilityHub struct {
+	client *redis.Client
+
+	mu     sync.Mutex
+	pubsub *redis.PubSub
+	feeds  map[string]*availabilityFeed // by channel
+}
+
+// availabilityFeed is the subscribers of one event
+type availabilityFeed struct {
+	subscribers map[chan AvailabilityUpdate]struct{}
+	// ready is closed once Redis confirmed the subscription
+	ready chan struct{}
+}
+
+func newAvailabilityHub(client *redis.Client) *availabilityHub {
+	return &availabilityHub{
+		client: client,
+		feeds:  make(map[string]*availabilityFeed),
+	}
+}
+
+// subscribe adds a subscriber to the feed of the channel, subscribing in Redis for the
+// first one. The subscriber is removed and its channel closed when the context is done,
+// or when it falls too far behind, in which case it should subscribe again.
+func (h *availabilityHub) subscribe(ctx context.Context, channel string) (<-chan AvailabilityUpdate, error) {
+	updates := make(chan AvailabilityUpdate, availabilityBuffer)
+
+	h.mu.Lock()
+	feed, ok := h.feeds[channel]
+	if !ok {
+		if h.pubsub == nil {
+			h.pubsub = h.client.Subscribe(context.Background())
+			go h.dispatch(h.pubsub.ChannelWithSubscriptions())
+		}
+		feed = &availabilityFeed{
+			subscribers: make(map[chan AvailabilityUpdate]struct{}),
+			ready:       make(chan struct{}),
+		}
+		h.feeds[channel] = feed
+		if err := h.pubsub.Subscribe(ctx, channel); err != nil {
+			delete(h.feeds, channel)
+			h.mu.Unlock()
+			return nil, fmt.Errorf("failed to subscribe to availability in Redis: %w", err)
+		}
+	}
+	feed.subscribers[updates] = struct{}{}
+	h.mu.Unlock()
+
+	// Wait for the confirmation so no update published after this returns is missed
+	select {
+	case <-feed.ready:
+	case <-ctx.Done():
+		h.unsubscribe(channel, updates)
+		return nil, ctx.Err()
+	}
+
+	go func() {
+		<-ctx.Done()
+		h.unsubscribe(channel, updates)
+	}()
+	return updates, nil
+}
+
+// unsubscribe removes a subscriber, the Redis subscription ends with the last one
+func (h *availabilityHub) unsubscribe(channel string, updates chan AvailabilityUpdate) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	feed, ok := h.feeds[channel]
+	if !ok {
+		return
+	}
+	if _, ok := feed.subscribers[updates]; ok {
+		delete(feed.subscribers, updates)
+		close(updates)
+	}
+	if len(feed.subscribers) > 0 {
+		return
+	}
+	delete(h.feeds, channel)
+	if err := h.pubsub.Unsubscribe(context.Background(), channel); err != nil {
+		log.Printf("Failed to unsubscribe from availability in Redis: %v", err)
+	}
+}
+
+// dispatch delivers the messages of the shared connection to the subscribers of their feed
+func (h *availabilityHub) dispatch(messages <-chan interface{}) {
+	for message := range messages {
+		switch message := message.(type) {
+		case *redis.Subscription:
+			if message.Kind != "subscribe" {
+				continue
+			}
+			h.mu.Lock()
+			if feed, ok := h.feeds[message.Channel]; ok {
+				select {
+				case <-feed.ready:
+				default:
+					close(feed.ready)
+				}
+			}
+			h.mu.Unlock()
+		case *redis.Message:
+			var update AvailabilityUpdate
+			if err := json.Unmarshal([]byte(message.Payload), &update); err != nil {
+				log.Printf("Failed to decode availability update: %v", err)
+				continue
+			}
+			h.broadcast(message.Channel, update)
+		}
+	}
+}
+
+// broadcast hands the update to every subscriber of the feed without waiting on any of them
+func (h *availabilityHub) broadcast(channel string, update AvailabilityUpdate) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	feed, ok := h.feeds[channel]
+	if !ok {
+		return
+	}
+	for updates := range feed.subscribers {
+		select {
+		case updates <- update:
+		default:
+			// A slow client must not hold up the others, it catches up when it reconnects
+			delete(feed.subscribers, updates)
+			close(updates)
+		}
+	}
+}
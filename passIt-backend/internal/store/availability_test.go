@@ -0,0 +1,154 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nextUpdate(t *testing.T, updates <-chan AvailabilityUpdate) AvailabilityUpdate {
+	t.Helper()
+	select {
+	case update, ok := <-updates:
+		require.True(t, ok, "subscription closed")
+		return update
+	case <-time.After(2 * time.Second):
+		t.Fatal("no availability update received")
+		return AvailabilityUpdate{}
+	}
+}
+
+func TestRedisHoldManager_SubscribeAvailabilityShared(t *testing.T) {
+	ctx := context.Background()
+	manager, mr := newTestHoldManager(t)
+	eventID := uuid.New()
+	channel := manager.availabilityChannel(eventID)
+
+	firstCtx, cancelFirst := context.WithCancel(ctx)
+	first, err := manager.SubscribeAvailability(firstCtx, eventID)
+	require.NoError(t, err)
+	secondCtx, cancelSecond := context.WithCancel(ctx)
+	defer cancelSecond()
+	second, err := manager.SubscribeAvailability(secondCtx, eventID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, mr.PubSubNumSub(channel)[channel], "subscribers of an instance share one Redis subscription")
+
+	require.NoError(t, manager.PublishAvailability(ctx, AvailabilityHeld, &Hold{EventID: eventID}))
+	assert.Equal(t, AvailabilityHeld, nextUpdate(t, first).Reason)
+	assert.Equal(t, AvailabilityHeld, nextUpdate(t, second).Reason)
+
+	cancelFirst()
+	require.Eventually(t, func() bool {
+		_, ok := <-first
+		return !ok
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, mr.PubSubNumSub(channel)[channel], "the subscription stays while a subscriber is left")
+
+	require.NoError(t, manager.PublishAvailability(ctx, AvailabilityReleased, &Hold{EventID: eventID}))
+	assert.Equal(t, AvailabilityReleased, nextUpdate(t, second).Reason)
+
+	cancelSecond()
+	require.Eventually(t, func() bool {
+		return mr.PubSubNumSub(channel)[channel] == 0
+	}, 2*time.Second, 10*time.Millisecond, "the last subscriber ends the Redis subscription")
+}
+
+func TestRedisHoldManager_SubscribeAvailabilityDropsSlowSubscribers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	manager, _ := newTestHoldManager(t)
+	eventID := uuid.New()
+
+	slow, err := manager.SubscribeAvailability(ctx, eventID)
+	require.NoError(t, err)
+	fast, err := manager.SubscribeAvailability(ctx, eventID)
+	require.NoError(t, err)
+
+	for i := 0; i <= availabilityBuffer; i++ {
+		require.NoError(t, manager.PublishAvailability(ctx, AvailabilityHeld, &Hold{EventID: eventID}))
+		nextUpdate(t, fast)
+	}
+
+	received := 0
+	for range slow {
+		received++
+	}
+	assert.Equal(t, availabilityBuffer, received, "a subscriber that falls behind is closed so it subscribes again")
+}
+
+func TestRedisHoldManager_PublishesAvailability(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	manager, _ := newTestHoldManager(t)
+	eventID, ticketTypeID, seatID := uuid.New(), uuid.New(), uuid.New()
+
+	updates, err := manager.SubscribeAvailability(ctx, eventID)
+	require.NoError(t, err)
+
+	hold := &Hold{
+		EventID: eventID,
+		UserID:  uuid.New(),
+		Items:   []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 1, SeatIDs: []uuid.UUID{seatID}}},
+		Limits:  []PurchaseLimit{{Key: UserLimitKey(eventID, uuid.New()), Max: 4}},
+	}
+	limits := map[uuid.UUID]InventoryLimit{ticketTypeID: {Capacity: 10, Taken: 3}}
+	require.NoError(t, manager.Create(ctx, hold, limits))
+
+	update := nextUpdate(t, updates)
+	assert.Equal(t, eventID, update.EventID)
+	assert.Equal(t, AvailabilityHeld, update.Reason)
+	assert.Equal(t, []TicketTypeTaken{{TicketTypeID: ticketTypeID, Taken: 4}}, update.TicketTypes,
+		"purchase limit counters are not published")
+	assert.Equal(t, []SeatTaken{{SeatID: seatID, Taken: true}}, update.Seats)
+	assert.False(t, update.At.IsZero())
+
+	released, err := manager.Release(ctx, hold.ID)
+	require.NoError(t, err)
+	require.True(t, released)
+
+	update = nextUpdate(t, updates)
+	assert.Equal(t, AvailabilityReleased, update.Reason)
+	assert.Equal(t, []TicketTypeTaken{{TicketTypeID: ticketTypeID, Taken: 3}}, update.TicketTypes)
+	assert.Equal(t, []SeatTaken{{SeatID: seatID, Taken: false}}, update.Seats)
+
+	// Releasing again changes nothing and publishes nothing
+	_, err = manager.Release(ctx, hold.ID)
+	require.NoError(t, err)
+	require.NoError(t, manager.PublishAvailability(ctx, AvailabilitySold, &Hold{EventID: eventID}))
+	update = nextUpdate(t, updates)
+	assert.Equal(t, AvailabilitySold, update.Reason)
+	assert.Empty(t, update.TicketTypes)
+	assert.Empty(t, update.Seats)
+}
+
+func TestRedisHoldManager_SubscribeAvailabilityPerEvent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	manager, _ := newTestHoldManager(t)
+	eventID, otherEventID, ticketTypeID := uuid.New(), uuid.New(), uuid.New()
+
+	updates, err := manager.SubscribeAvailability(ctx, eventID)
+	require.NoError(t, err)
+
+	other := &Hold{EventID: otherEventID, Items: []HoldItem{{TicketTypeID: uuid.New(), Quantity: 1}}}
+	require.NoError(t, manager.PublishAvailability(ctx, AvailabilityHeld, other))
+	require.NoError(t, manager.PublishAvailability(ctx, AvailabilityReleased, &Hold{
+		EventID: eventID,
+		Items:   []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 1}},
+	}))
+
+	update := nextUpdate(t, updates)
+	assert.Equal(t, eventID, update.EventID, "updates of other events are not delivered")
+	assert.Empty(t, update.TicketTypes, "counters that were never loaded are left out")
+
+	cancel()
+	select {
+	case _, ok := <-updates:
+		assert.False(t, ok, "the channel is closed once the context is done")
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription not closed")
+	}
+}
@@ -0,0 +1,565 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"passIt/internal/constant"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrHoldNotFound    = errors.New("hold not found")
+	ErrHoldExpired     = errors.New("hold has expired")
+	ErrSoldOut         = errors.New("not enough tickets available")
+	ErrSeatUnavailable = errors.New("seat is no longer available")
+	// ErrPurchaseLimitExceeded is returned when a buyer would go over a purchase limit of the event
+	ErrPurchaseLimitExceeded = errors.New("purchase limit for this event reached")
+)
+
+// Hold is a short-lived reservation of inventory for one buyer
+type Hold struct {
+	ID        string     `json:"id"`
+	EventID   uuid.UUID  `json:"event_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Items     []HoldItem `json:"items"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	// Offer marks tickets offered to a waitlist, they are checked out without queueing again
+	Offer bool `json:"offer,omitempty"`
+	// Limits are the purchase limits the tickets of the hold count against
+	Limits []PurchaseLimit `json:"limits,omitempty"`
+}
+
+// HoldItem reserves a quantity of one ticket type, optionally on specific seats
+type HoldItem struct {
+	TicketTypeID uuid.UUID   `json:"ticket_type_id"`
+	Quantity     int         `json:"quantity"`
+	SeatIDs      []uuid.UUID `json:"seat_ids,omitempty"`
+}
+
+// InventoryLimit tells the store how many tickets of a type exist and how many
+// were already taken before the counter was first loaded into Redis
+type InventoryLimit struct {
+	Capacity int
+	Taken    int
+}
+
+// PurchaseLimit caps how many tickets of an event one buyer takes. Like ticket type
+// inventory it is counted in Redis across holds and orders, so it is checked atomically
+// with the rest of a hold.
+type PurchaseLimit struct {
+	Key string `json:"key"` // the buyer the limit applies to, see UserLimitKey
+	Max int    `json:"max"` // 0 means no limit, the tickets are still counted
+	// Taken is how many tickets the buyer had already bought when the counter was first loaded
+	Taken int `json:"-"`
+}
+
+// UserLimitKey identifies the purchase limit of a user for an event
+func UserLimitKey(eventID, userID uuid.UUID) string {
+	return fmt.Sprintf("user:%s:%s", eventID, userID)
+}
+
+// PaymentMethodLimitKey identifies the purchase limit of a payment method for an event
+func PaymentMethodLimitKey(eventID uuid.UUID, fingerprint string) string {
+	return fmt.Sprintf("method:%s:%s", eventID, fingerprint)
+}
+
+// capacity is the most tickets the counter of the limit may reach
+func (l PurchaseLimit) capacity() int {
+	if l.Max <= 0 {
+		return math.MaxInt32
+	}
+	return l.Max
+}
+
+// PurchaseLimitError reports which purchase limit a hold would go over
+type PurchaseLimitError struct {
+	Key string
+	Max int
+}
+
+func (e *PurchaseLimitError) Error() string {
+	return fmt.Sprintf("%s (%d tickets)", ErrPurchaseLimitExceeded, e.Max)
+}
+
+func (e *PurchaseLimitError) Unwrap() error { return ErrPurchaseLimitExceeded }
+
+// SoldOutError reports which ticket type could not satisfy a hold
+type SoldOutError struct {
+	TicketTypeID uuid.UUID
+}
+
+func (e *SoldOutError) Error() string {
+	return fmt.Sprintf("ticket type %s: %s", e.TicketTypeID, ErrSoldOut)
+}
+
+func (e *SoldOutError) Unwrap() error { return ErrSoldOut }
+
+// SeatUnavailableError reports which seat could not be reserved
+type SeatUnavailableError struct {
+	SeatID uuid.UUID
+}
+
+func (e *SeatUnavailableError) Error() string {
+	return fmt.Sprintf("seat %s: %s", e.SeatID, ErrSeatUnavailable)
+}
+
+func (e *SeatUnavailableError) Unwrap() error { return ErrSeatUnavailable }
+
+// HoldStore defines the contract for inventory reservations
+type HoldStore interface {
+	// Create atomically checks availability for every item and reserves it, or reserves nothing
+	Create(ctx context.Context, hold *Hold, limits map[uuid.UUID]InventoryLimit) error
+	// CreateWithTTL is Create with a custom lifetime, e.g. for waitlist offers
+	CreateWithTTL(ctx context.Context, hold *Hold, limits map[uuid.UUID]InventoryLimit, ttl time.Duration) error
+	Get(ctx context.Context, holdID string) (*Hold, error)
+	// Release returns the held inventory to sale. Releasing twice is a no-op.
+	Release(ctx context.Context, holdID string) (bool, error)
+	// ReleaseExpired releases every hold that expired before now and returns how many were released
+	ReleaseExpired(ctx context.Context, now time.Time) (int, error)
+	// Taken returns how many tickets of a type are currently held or sold
+	Taken(ctx context.Context, ticketTypeID uuid.UUID) (int, error)
+	// Claim turns a hold into a permanent reservation: the hold is removed but its
+	// inventory stays taken until ReturnInventory is called
+	Claim(ctx context.Context, holdID string) (*Hold, error)
+	// ReturnInventory gives the inventory of a claimed hold back to sale
+	ReturnInventory(ctx context.Context, hold *Hold) error
+	// ReserveLimit atomically counts tickets against a purchase limit outside of a hold,
+	// e.g. the payment method paying for an order
+	ReserveLimit(ctx context.Context, limit PurchaseLimit, quantity int) error
+	// ReturnLimit gives back tickets counted against a purchase limit
+	ReturnLimit(ctx context.Context, key string, quantity int) error
+	// Holds, releases and returned inventory are published to availability subscribers
+	AvailabilityStore
+}
+
+type RedisHoldManager struct {
+	client       *redis.Client
+	PrefixState  string
+	defaultTTL   time.Duration
+	availability *availabilityHub
+}
+
+func NewHoldRedisManager(rds *redis.Client) *RedisHoldManager {
+	return &RedisHoldManager{
+		client:       rds,
+		PrefixState:  "inventory",
+		defaultTTL:   constant.HoldDuration,
+		availability: newAvailabilityHub(rds),
+	}
+}
+
+// Ensure RedisHoldManager implements HoldStore
+var _ HoldStore = (*RedisHoldManager)(nil)
+
+func (r *RedisHoldManager) holdKey(holdID string) string {
+	return fmt.Sprintf("%s:hold:%s", r.PrefixState, holdID)
+}
+
+// expiryKey is a sorted set of hold IDs scored by their expiry time in milliseconds
+func (r *RedisHoldManager) expiryKey() string {
+	return fmt.Sprintf("%s:holds:expiry", r.PrefixState)
+}
+
+// pendingKey is a hash of hold ID to hold data, kept after the hold key itself expires
+// so the reaper still knows what to give back
+func (r *RedisHoldManager) pendingKey() string {
+	return fmt.Sprintf("%s:holds:pending", r.PrefixState)
+}
+
+func (r *RedisHoldManager) takenKey(ticketTypeID uuid.UUID) string {
+	return fmt.Sprintf("%s:taken:%s", r.PrefixState, ticketTypeID)
+}
+
+func (r *RedisHoldManager) seatKey(seatID uuid.UUID) string {
+	return fmt.Sprintf("%s:seat:%s", r.PrefixState, seatID)
+}
+
+// limitKey counts the tickets a buyer took against a purchase limit
+func (r *RedisHoldManager) limitKey(key string) string {
+	return fmt.Sprintf("%s:limit:%s", r.PrefixState, key)
+}
+
+// createHoldScript reserves all items of a hold or nothing.
+// KEYS: hold, expiry zset, pending hash, taken counters..., seats...
+// ARGV: hold id, hold json, ttl ms, expires at ms, counter count, then (quantity, capacity, initial taken) per counter
+var createHoldScript = redis.NewScript(`
+local n = tonumber(ARGV[5])
+for i = 1, n do
+	local base = 5 + (i - 1) * 3
+	redis.call('SETNX', KEYS[3 + i], ARGV[base + 3])
+	local taken = tonumber(redis.call('GET', KEYS[3 + i]))
+	if taken + tonumber(ARGV[base + 1]) > tonumber(ARGV[base + 2]) then
+		return {-1, i}
+	end
+end
+for j = 4 + n, #KEYS do
+	if redis.call('EXISTS', KEYS[j]) == 1 then
+		return {-2, j - 3 - n}
+	end
+end
+for i = 1, n do
+	redis.call('INCRBY', KEYS[3 + i], tonumber(ARGV[5 + (i - 1) * 3 + 1]))
+end
+for j = 4 + n, #KEYS do
+	redis.call('SET', KEYS[j], ARGV[1])
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+redis.call('HSET', KEYS[3], ARGV[1], ARGV[2])
+redis.call('ZADD', KEYS[2], ARGV[4], ARGV[1])
+return {1, 0}
+`)
+
+// releaseHoldScript gives the inventory of a hold back. Removing the hold from the
+// expiry set acts as the claim, so concurrent releases only decrement once.
+// KEYS: hold, expiry zset, pending hash, taken counters..., seats...
+// ARGV: hold id, counter count, quantities...
+var releaseHoldScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[2], ARGV[1]) == 0 then
+	return 0
+end
+local n = tonumber(ARGV[2])
+for i = 1, n do
+	if redis.call('EXISTS', KEYS[3 + i]) == 1 then
+		redis.call('DECRBY', KEYS[3 + i], tonumber(ARGV[2 + i]))
+	end
+end
+for j = 4 + n, #KEYS do
+	if redis.call('GET', KEYS[j]) == ARGV[1] then
+		redis.call('DEL', KEYS[j])
+	end
+end
+redis.call('DEL', KEYS[1])
+redis.call('HDEL', KEYS[3], ARGV[1])
+return 1
+`)
+
+// claimHoldScript removes an active hold without giving its inventory back.
+// Returns the hold data, -1 when the hold expired or 0 when it does not exist.
+// KEYS: hold, expiry zset, pending hash
+// ARGV: hold id
+var claimHoldScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	if redis.call('HEXISTS', KEYS[3], ARGV[1]) == 1 then
+		return -1
+	end
+	return 0
+end
+if redis.call('ZREM', KEYS[2], ARGV[1]) == 0 then
+	return 0
+end
+local data = redis.call('GET', KEYS[1])
+redis.call('DEL', KEYS[1])
+redis.call('HDEL', KEYS[3], ARGV[1])
+return data
+`)
+
+// returnInventoryScript gives back the inventory of a claimed hold. Counters that are
+// gone are left alone, they are loaded again from the database on the next hold.
+// KEYS: taken counters..., seats...
+// ARGV: hold id, counter count, quantities...
+var returnInventoryScript = redis.NewScript(`
+local n = tonumber(ARGV[2])
+for i = 1, n do
+	if redis.call('EXISTS', KEYS[i]) == 1 then
+		redis.call('DECRBY', KEYS[i], tonumber(ARGV[2 + i]))
+	end
+end
+for j = n + 1, #KEYS do
+	if redis.call('GET', KEYS[j]) == ARGV[1] then
+		redis.call('DEL', KEYS[j])
+	end
+end
+return 1
+`)
+
+// inventoryKeys builds the counter and seat keys for a hold with the per-counter quantities.
+// The ticket type counters come first, followed by the purchase limit counters.
+func (r *RedisHoldManager) inventoryKeys(hold *Hold) ([]uuid.UUID, []string, []int, []uuid.UUID, []string) {
+	quantities := make(map[uuid.UUID]int)
+	var ticketTypes []uuid.UUID
+	var seats []uuid.UUID
+	total := 0
+	for _, item := range hold.Items {
+		if _, seen := quantities[item.TicketTypeID]; !seen {
+			ticketTypes = append(ticketTypes, item.TicketTypeID)
+		}
+		quantities[item.TicketTypeID] += item.Quantity
+		total += item.Quantity
+		seats = append(seats, item.SeatIDs...)
+	}
+
+	counterKeys := make([]string, 0, len(ticketTypes)+len(hold.Limits))
+	counts := make([]int, 0, len(ticketTypes)+len(hold.Limits))
+	for _, id := range ticketTypes {
+		counterKeys = append(counterKeys, r.takenKey(id))
+		counts = append(counts, quantities[id])
+	}
+	for _, limit := range hold.Limits {
+		counterKeys = append(counterKeys, r.limitKey(limit.Key))
+		counts = append(counts, total)
+	}
+	seatKeys := make([]string, len(seats))
+	for i, id := range seats {
+		seatKeys[i] = r.seatKey(id)
+	}
+	return ticketTypes, counterKeys, counts, seats, seatKeys
+}
+
+// Create reserves the hold items and stores the hold with the default TTL.
+// The hold ID, creation and expiry times are set on the passed hold.
+func (r *RedisHoldManager) Create(ctx context.Context, hold *Hold, limits map[uuid.UUID]InventoryLimit) error {
+	return r.CreateWithTTL(ctx, hold, limits, r.defaultTTL)
+}
+
+func (r *RedisHoldManager) CreateWithTTL(ctx context.Context, hold *Hold, limits map[uuid.UUID]InventoryLimit, ttl time.Duration) error {
+	now := time.Now()
+	hold.ID = uuid.NewString()
+	hold.CreatedAt = now
+	hold.ExpiresAt = now.Add(ttl)
+
+	jsonData, err := json.Marshal(hold)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hold: %w", err)
+	}
+
+	ticketTypes, counterKeys, counts, seats, seatKeys := r.inventoryKeys(hold)
+
+	keys := []string{r.holdKey(hold.ID), r.expiryKey(), r.pendingKey()}
+	keys = append(keys, counterKeys...)
+	keys = append(keys, seatKeys...)
+
+	args := []interface{}{
+		hold.ID,
+		jsonData,
+		ttl.Milliseconds(),
+		hold.ExpiresAt.UnixMilli(),
+		len(counterKeys),
+	}
+	for i, id := range ticketTypes {
+		limit, ok := limits[id]
+		if !ok {
+			return fmt.Errorf("no inventory limit for ticket type %s", id)
+		}
+		args = append(args, counts[i], limit.Capacity, limit.Taken)
+	}
+	for i, limit := range hold.Limits {
+		args = append(args, counts[len(ticketTypes)+i], limit.capacity(), limit.Taken)
+	}
+
+	result, err := createHoldScript.Run(ctx, r.client, keys, args...).Int64Slice()
+	if err != nil {
+		return fmt.Errorf("failed to create hold in Redis: %w", err)
+	}
+
+	switch result[0] {
+	case 1:
+		r.publish(ctx, AvailabilityHeld, hold)
+		return nil
+	case -1:
+		if counter := int(result[1]) - 1; counter >= len(ticketTypes) {
+			limit := hold.Limits[counter-len(ticketTypes)]
+			return &PurchaseLimitError{Key: limit.Key, Max: limit.Max}
+		}
+		return &SoldOutError{TicketTypeID: ticketTypes[result[1]-1]}
+	case -2:
+		return &SeatUnavailableError{SeatID: seats[result[1]-1]}
+	default:
+		return fmt.Errorf("unexpected hold script result %v", result)
+	}
+}
+
+// Get retrieves an active hold. Holds past their expiry return ErrHoldExpired
+// until the reaper has released them, then ErrHoldNotFound.
+func (r *RedisHoldManager) Get(ctx context.Context, holdID string) (*Hold, error) {
+	data, err := r.client.Get(ctx, r.holdKey(holdID)).Result()
+	if err == redis.Nil {
+		if pending, _ := r.client.HExists(ctx, r.pendingKey(), holdID).Result(); pending {
+			return nil, ErrHoldExpired
+		}
+		return nil, ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+
+	var hold Hold
+	if err := json.Unmarshal([]byte(data), &hold); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal hold: %w", err)
+	}
+	return &hold, nil
+}
+
+// pending reads the hold data kept for the reaper, regardless of the hold TTL
+func (r *RedisHoldManager) pending(ctx context.Context, holdID string) (*Hold, error) {
+	data, err := r.client.HGet(ctx, r.pendingKey(), holdID).Result()
+	if err == redis.Nil {
+		return nil, ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending hold: %w", err)
+	}
+
+	var hold Hold
+	if err := json.Unmarshal([]byte(data), &hold); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal hold: %w", err)
+	}
+	return &hold, nil
+}
+
+func (r *RedisHoldManager) Release(ctx context.Context, holdID string) (bool, error) {
+	hold, err := r.pending(ctx, holdID)
+	if errors.Is(err, ErrHoldNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_, counterKeys, counts, _, seatKeys := r.inventoryKeys(hold)
+
+	keys := []string{r.holdKey(hold.ID), r.expiryKey(), r.pendingKey()}
+	keys = append(keys, counterKeys...)
+	keys = append(keys, seatKeys...)
+
+	args := []interface{}{hold.ID, len(counterKeys)}
+	for _, count := range counts {
+		args = append(args, count)
+	}
+
+	released, err := releaseHoldScript.Run(ctx, r.client, keys, args...).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to release hold in Redis: %w", err)
+	}
+	if released == 1 {
+		r.publish(ctx, AvailabilityReleased, hold)
+	}
+	return released == 1, nil
+}
+
+func (r *RedisHoldManager) ReleaseExpired(ctx context.Context, now time.Time) (int, error) {
+	holdIDs, err := r.client.ZRangeByScore(ctx, r.expiryKey(), &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired holds: %w", err)
+	}
+
+	released := 0
+	for _, holdID := range holdIDs {
+		ok, err := r.Release(ctx, holdID)
+		if err != nil {
+			log.Printf("Failed to release expired hold %s: %v", holdID, err)
+			continue
+		}
+		if ok {
+			released++
+		}
+	}
+	return released, nil
+}
+
+func (r *RedisHoldManager) Taken(ctx context.Context, ticketTypeID uuid.UUID) (int, error) {
+	taken, err := r.client.Get(ctx, r.takenKey(ticketTypeID)).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get taken inventory: %w", err)
+	}
+	return taken, nil
+}
+
+func (r *RedisHoldManager) Claim(ctx context.Context, holdID string) (*Hold, error) {
+	keys := []string{r.holdKey(holdID), r.expiryKey(), r.pendingKey()}
+	result, err := claimHoldScript.Run(ctx, r.client, keys, holdID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim hold in Redis: %w", err)
+	}
+
+	switch value := result.(type) {
+	case string:
+		var hold Hold
+		if err := json.Unmarshal([]byte(value), &hold); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal hold: %w", err)
+		}
+		return &hold, nil
+	case int64:
+		if value == -1 {
+			return nil, ErrHoldExpired
+		}
+		return nil, ErrHoldNotFound
+	default:
+		return nil, fmt.Errorf("unexpected claim script result %v", result)
+	}
+}
+
+func (r *RedisHoldManager) ReturnInventory(ctx context.Context, hold *Hold) error {
+	_, counterKeys, counts, _, seatKeys := r.inventoryKeys(hold)
+
+	keys := append(counterKeys, seatKeys...)
+	args := []interface{}{hold.ID, len(counterKeys)}
+	for _, count := range counts {
+		args = append(args, count)
+	}
+
+	if err := returnInventoryScript.Run(ctx, r.client, keys, args...).Err(); err != nil {
+		return fmt.Errorf("failed to return inventory in Redis: %w", err)
+	}
+	r.publish(ctx, AvailabilityReleased, hold)
+	return nil
+}
+
+// reserveLimitScript counts tickets against a purchase limit unless it would go over it.
+// KEYS: limit counter
+// ARGV: quantity, capacity, initial taken
+var reserveLimitScript = redis.NewScript(`
+redis.call('SETNX', KEYS[1], ARGV[3])
+local taken = tonumber(redis.call('GET', KEYS[1]))
+if taken + tonumber(ARGV[1]) > tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('INCRBY', KEYS[1], tonumber(ARGV[1]))
+return 1
+`)
+
+// returnLimitScript gives back tickets counted against a purchase limit. A counter that
+// is gone is loaded again from the database when it is next needed.
+// KEYS: limit counter
+// ARGV: quantity
+var returnLimitScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('DECRBY', KEYS[1], tonumber(ARGV[1]))
+end
+return 1
+`)
+
+func (r *RedisHoldManager) ReserveLimit(ctx context.Context, limit PurchaseLimit, quantity int) error {
+	reserved, err := reserveLimitScript.Run(ctx, r.client,
+		[]string{r.limitKey(limit.Key)},
+		quantity, limit.capacity(), limit.Taken,
+	).Int()
+	if err != nil {
+		return fmt.Errorf("failed to reserve purchase limit in Redis: %w", err)
+	}
+	if reserved == 0 {
+		return &PurchaseLimitError{Key: limit.Key, Max: limit.Max}
+	}
+	return nil
+}
+
+func (r *RedisHoldManager) ReturnLimit(ctx context.Context, key string, quantity int) error {
+	if err := returnLimitScript.Run(ctx, r.client, []string{r.limitKey(key)}, quantity).Err(); err != nil {
+		return fmt.Errorf("failed to return purchase limit in Redis: %w", err)
+	}
+	return nil
+}
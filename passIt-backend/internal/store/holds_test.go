@@ -0,0 +1,392 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHoldManager(t *testing.T) (*RedisHoldManager, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewHoldRedisManager(client), mr
+}
+
+func TestRedisHoldManager_CreateAndGet(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestHoldManager(t)
+	ticketTypeID := uuid.New()
+
+	hold := &Hold{
+		EventID: uuid.New(),
+		UserID:  uuid.New(),
+		Items:   []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 3}},
+	}
+	err := manager.Create(ctx, hold, map[uuid.UUID]InventoryLimit{ticketTypeID: {Capacity: 10}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, hold.ID)
+	assert.WithinDuration(t, time.Now().Add(manager.defaultTTL), hold.ExpiresAt, time.Second)
+
+	stored, err := manager.Get(ctx, hold.ID)
+	require.NoError(t, err)
+	assert.Equal(t, hold.UserID, stored.UserID)
+	assert.Equal(t, hold.Items, stored.Items)
+
+	taken, err := manager.Taken(ctx, ticketTypeID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, taken)
+}
+
+func TestRedisHoldManager_CreateSoldOut(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestHoldManager(t)
+	available, soldOut := uuid.New(), uuid.New()
+	limits := map[uuid.UUID]InventoryLimit{
+		available: {Capacity: 10},
+		soldOut:   {Capacity: 5, Taken: 4},
+	}
+
+	hold := &Hold{Items: []HoldItem{
+		{TicketTypeID: available, Quantity: 2},
+		{TicketTypeID: soldOut, Quantity: 2},
+	}}
+	err := manager.Create(ctx, hold, limits)
+
+	var soldOutErr *SoldOutError
+	require.ErrorAs(t, err, &soldOutErr)
+	assert.ErrorIs(t, err, ErrSoldOut)
+	assert.Equal(t, soldOut, soldOutErr.TicketTypeID)
+
+	// Nothing is reserved when one item cannot be satisfied
+	taken, err := manager.Taken(ctx, available)
+	require.NoError(t, err)
+	assert.Equal(t, 0, taken)
+	taken, err = manager.Taken(ctx, soldOut)
+	require.NoError(t, err)
+	assert.Equal(t, 4, taken)
+}
+
+func TestRedisHoldManager_SeatTaken(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestHoldManager(t)
+	ticketTypeID, seatA, seatB := uuid.New(), uuid.New(), uuid.New()
+	limits := map[uuid.UUID]InventoryLimit{ticketTypeID: {Capacity: 10}}
+
+	first := &Hold{Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 1, SeatIDs: []uuid.UUID{seatA}}}}
+	require.NoError(t, manager.Create(ctx, first, limits))
+
+	second := &Hold{Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 2, SeatIDs: []uuid.UUID{seatB, seatA}}}}
+	err := manager.Create(ctx, second, limits)
+
+	var seatErr *SeatUnavailableError
+	require.ErrorAs(t, err, &seatErr)
+	assert.Equal(t, seatA, seatErr.SeatID)
+
+	taken, err := manager.Taken(ctx, ticketTypeID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, taken)
+
+	// seat B was not reserved by the failed hold
+	third := &Hold{Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 1, SeatIDs: []uuid.UUID{seatB}}}}
+	assert.NoError(t, manager.Create(ctx, third, limits))
+}
+
+func TestRedisHoldManager_Release(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestHoldManager(t)
+	ticketTypeID, seatID := uuid.New(), uuid.New()
+	limits := map[uuid.UUID]InventoryLimit{ticketTypeID: {Capacity: 1}}
+
+	hold := &Hold{Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 1, SeatIDs: []uuid.UUID{seatID}}}}
+	require.NoError(t, manager.Create(ctx, hold, limits))
+
+	released, err := manager.Release(ctx, hold.ID)
+	require.NoError(t, err)
+	assert.True(t, released)
+
+	// Releasing again must not hand the inventory back twice
+	released, err = manager.Release(ctx, hold.ID)
+	require.NoError(t, err)
+	assert.False(t, released)
+
+	taken, err := manager.Taken(ctx, ticketTypeID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, taken)
+
+	_, err = manager.Get(ctx, hold.ID)
+	assert.ErrorIs(t, err, ErrHoldNotFound)
+
+	again := &Hold{Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 1, SeatIDs: []uuid.UUID{seatID}}}}
+	assert.NoError(t, manager.Create(ctx, again, limits))
+}
+
+func TestRedisHoldManager_ReleaseExpired(t *testing.T) {
+	ctx := context.Background()
+	manager, mr := newTestHoldManager(t)
+	ticketTypeID := uuid.New()
+	limits := map[uuid.UUID]InventoryLimit{ticketTypeID: {Capacity: 5}}
+
+	hold := &Hold{Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 5}}}
+	require.NoError(t, manager.Create(ctx, hold, limits))
+
+	mr.FastForward(manager.defaultTTL + time.Second)
+
+	_, err := manager.Get(ctx, hold.ID)
+	assert.ErrorIs(t, err, ErrHoldExpired)
+
+	released, err := manager.ReleaseExpired(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, released, "holds are released only once their expiry has passed")
+
+	released, err = manager.ReleaseExpired(ctx, hold.ExpiresAt.Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 1, released)
+
+	taken, err := manager.Taken(ctx, ticketTypeID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, taken)
+
+	_, err = manager.Get(ctx, hold.ID)
+	assert.ErrorIs(t, err, ErrHoldNotFound)
+}
+
+func TestRedisHoldManager_CreateWithTTL(t *testing.T) {
+	ctx := context.Background()
+	manager, mr := newTestHoldManager(t)
+	ticketTypeID := uuid.New()
+	limits := map[uuid.UUID]InventoryLimit{ticketTypeID: {Capacity: 5}}
+	ttl := manager.defaultTTL * 3
+
+	hold := &Hold{Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 2}}}
+	require.NoError(t, manager.CreateWithTTL(ctx, hold, limits, ttl))
+	assert.WithinDuration(t, time.Now().Add(ttl), hold.ExpiresAt, time.Second)
+
+	mr.FastForward(manager.defaultTTL + time.Second)
+	_, err := manager.Get(ctx, hold.ID)
+	assert.NoError(t, err, "hold outlives the default TTL")
+
+	released, err := manager.ReleaseExpired(ctx, time.Now().Add(manager.defaultTTL+time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 0, released)
+
+	mr.FastForward(ttl)
+	_, err = manager.Get(ctx, hold.ID)
+	assert.ErrorIs(t, err, ErrHoldExpired)
+}
+
+func TestRedisHoldManager_ConcurrentHoldsNeverOversell(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestHoldManager(t)
+	ticketTypeID := uuid.New()
+	limits := map[uuid.UUID]InventoryLimit{ticketTypeID: {Capacity: 25}}
+
+	const buyers = 100
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		soldOut   int
+	)
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hold := &Hold{Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 1}}}
+			err := manager.Create(ctx, hold, limits)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case assert.ErrorIs(t, err, ErrSoldOut):
+				soldOut++
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 25, succeeded)
+	assert.Equal(t, buyers-25, soldOut)
+
+	taken, err := manager.Taken(ctx, ticketTypeID)
+	require.NoError(t, err)
+	assert.Equal(t, 25, taken)
+}
+
+func TestRedisHoldManager_ConcurrentSameSeat(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestHoldManager(t)
+	ticketTypeID, seatID := uuid.New(), uuid.New()
+	limits := map[uuid.UUID]InventoryLimit{ticketTypeID: {Capacity: 100}}
+
+	const buyers = 50
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hold := &Hold{Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 1, SeatIDs: []uuid.UUID{seatID}}}}
+			err := manager.Create(ctx, hold, limits)
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+				return
+			}
+			assert.ErrorIs(t, err, ErrSeatUnavailable)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+
+	taken, err := manager.Taken(ctx, ticketTypeID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, taken)
+}
+
+func TestRedisHoldManager_ClaimKeepsInventory(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestHoldManager(t)
+	ticketTypeID, seatID := uuid.New(), uuid.New()
+	limits := map[uuid.UUID]InventoryLimit{ticketTypeID: {Capacity: 5}}
+
+	hold := &Hold{UserID: uuid.New(), Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 1, SeatIDs: []uuid.UUID{seatID}}}}
+	require.NoError(t, manager.Create(ctx, hold, limits))
+
+	claimed, err := manager.Claim(ctx, hold.ID)
+	require.NoError(t, err)
+	assert.Equal(t, hold.UserID, claimed.UserID)
+
+	// A hold can only be claimed once and is no longer released by the reaper
+	_, err = manager.Claim(ctx, hold.ID)
+	assert.ErrorIs(t, err, ErrHoldNotFound)
+	released, err := manager.ReleaseExpired(ctx, hold.ExpiresAt.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, released)
+
+	taken, err := manager.Taken(ctx, ticketTypeID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, taken)
+
+	other := &Hold{Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 1, SeatIDs: []uuid.UUID{seatID}}}}
+	assert.ErrorIs(t, manager.Create(ctx, other, limits), ErrSeatUnavailable)
+
+	require.NoError(t, manager.ReturnInventory(ctx, claimed))
+	taken, err = manager.Taken(ctx, ticketTypeID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, taken)
+	assert.NoError(t, manager.Create(ctx, other, limits))
+}
+
+func TestRedisHoldManager_ClaimExpired(t *testing.T) {
+	ctx := context.Background()
+	manager, mr := newTestHoldManager(t)
+	ticketTypeID := uuid.New()
+
+	hold := &Hold{Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 1}}}
+	require.NoError(t, manager.Create(ctx, hold, map[uuid.UUID]InventoryLimit{ticketTypeID: {Capacity: 5}}))
+
+	mr.FastForward(manager.defaultTTL + time.Second)
+
+	_, err := manager.Claim(ctx, hold.ID)
+	assert.ErrorIs(t, err, ErrHoldExpired)
+
+	_, err = manager.Claim(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrHoldNotFound)
+}
+
+func TestRedisHoldManager_ConcurrentClaim(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestHoldManager(t)
+	ticketTypeID := uuid.New()
+
+	hold := &Hold{Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 1}}}
+	require.NoError(t, manager.Create(ctx, hold, map[uuid.UUID]InventoryLimit{ticketTypeID: {Capacity: 5}}))
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		claimed int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := manager.Claim(ctx, hold.ID); err == nil {
+				mu.Lock()
+				claimed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, claimed)
+}
+
+func TestRedisHoldManager_PurchaseLimit(t *testing.T) {
+	ctx := context.Background()
+	manager, mr := newTestHoldManager(t)
+	eventID, userID, ticketTypeID := uuid.New(), uuid.New(), uuid.New()
+	limits := map[uuid.UUID]InventoryLimit{ticketTypeID: {Capacity: 100}}
+	userLimit := PurchaseLimit{Key: UserLimitKey(eventID, userID), Max: 4, Taken: 1}
+
+	first := &Hold{EventID: eventID, UserID: userID, Limits: []PurchaseLimit{userLimit},
+		Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 2}}}
+	require.NoError(t, manager.Create(ctx, first, limits))
+
+	second := &Hold{EventID: eventID, UserID: userID, Limits: []PurchaseLimit{userLimit},
+		Items: []HoldItem{{TicketTypeID: ticketTypeID, Quantity: 2}}}
+	err := manager.Create(ctx, second, limits)
+	var limitErr *PurchaseLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.ErrorIs(t, err, ErrPurchaseLimitExceeded)
+	assert.Equal(t, userLimit.Key, limitErr.Key)
+
+	// Nothing is reserved when the buyer is over the limit
+	taken, err := manager.Taken(ctx, ticketTypeID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, taken)
+
+	// Releasing a hold gives its tickets back to the buyer's limit as well
+	_, err = manager.Release(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "1", mustGet(t, mr, manager.limitKey(userLimit.Key)))
+	assert.NoError(t, manager.Create(ctx, second, limits))
+}
+
+func TestRedisHoldManager_ReserveLimit(t *testing.T) {
+	ctx := context.Background()
+	manager, mr := newTestHoldManager(t)
+	limit := PurchaseLimit{Key: PaymentMethodLimitKey(uuid.New(), "fingerprint"), Max: 4}
+
+	require.NoError(t, manager.ReserveLimit(ctx, limit, 3))
+	assert.ErrorIs(t, manager.ReserveLimit(ctx, limit, 2), ErrPurchaseLimitExceeded)
+	require.NoError(t, manager.ReturnLimit(ctx, limit.Key, 3))
+	require.NoError(t, manager.ReserveLimit(ctx, limit, 4))
+	assert.Equal(t, "4", mustGet(t, mr, manager.limitKey(limit.Key)))
+
+	unlimited := PurchaseLimit{Key: PaymentMethodLimitKey(uuid.New(), "fingerprint")}
+	assert.NoError(t, manager.ReserveLimit(ctx, unlimited, 1000), "tickets are counted without a limit")
+}
+
+func mustGet(t *testing.T, mr *miniredis.Miniredis, key string) string {
+	t.Helper()
+	value, err := mr.Get(key)
+	require.NoError(t, err)
+	return value
+}
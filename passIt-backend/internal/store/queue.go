@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// QueueStore keeps the virtual queues buyers wait in during high-demand on-sales.
+// Users are admitted in order of arrival at a fixed rate per event.
+type QueueStore interface {
+	// Join puts the user at the end of the queue of the event and returns their position,
+	// counted from 1. Joining again returns the position the user already has.
+	Join(ctx context.Context, eventID, userID uuid.UUID, ttl time.Duration) (int64, error)
+	// Position returns the current place of the user in the queue, 0 when they have none
+	Position(ctx context.Context, eventID, userID uuid.UUID) (int64, error)
+	// Admitted lets one more user in every interval and returns the position up to which
+	// users are admitted. Admissions are not saved up while nobody is waiting.
+	Admitted(ctx context.Context, eventID uuid.UUID, interval time.Duration, now time.Time, ttl time.Duration) (int64, error)
+	// Admission records until when an admitted user may buy and returns it, the first
+	// recorded expiry is kept so polling again does not extend it
+	Admission(ctx context.Context, eventID, userID uuid.UUID, expiresAt time.Time, ttl time.Duration) (time.Time, error)
+	// Leave forgets the place and admission of the user, joining again puts them at the end
+	Leave(ctx context.Context, eventID, userID uuid.UUID) error
+	// Length returns how many users joined the queue of the event
+	Length(ctx context.Context, eventID uuid.UUID) (int64, error)
+	// Reset removes the queue of the event
+	Reset(ctx context.Context, eventID uuid.UUID) error
+}
+
+type RedisQueueManager struct {
+	client      *redis.Client
+	PrefixState string
+}
+
+func NewQueueRedisManager(rds *redis.Client) *RedisQueueManager {
+	return &RedisQueueManager{
+		client:      rds,
+		PrefixState: "queue",
+	}
+}
+
+// Ensure RedisQueueManager implements QueueStore
+var _ QueueStore = (*RedisQueueManager)(nil)
+
+// positionsKey is a hash of user ID to position in the queue
+func (r *RedisQueueManager) positionsKey(eventID uuid.UUID) string {
+	return fmt.Sprintf("%s:%s:positions", r.PrefixState, eventID)
+}
+
+// lengthKey counts the users who joined the queue, the last position handed out
+func (r *RedisQueueManager) lengthKey(eventID uuid.UUID) string {
+	return fmt.Sprintf("%s:%s:length", r.PrefixState, eventID)
+}
+
+// cursorKey is a hash with the last admitted position and the time it was admitted at
+func (r *RedisQueueManager) cursorKey(eventID uuid.UUID) string {
+	return fmt.Sprintf("%s:%s:cursor", r.PrefixState, eventID)
+}
+
+// admissionsKey is a hash of user ID to the expiry of their admission in milliseconds
+func (r *RedisQueueManager) admissionsKey(eventID uuid.UUID) string {
+	return fmt.Sprintf("%s:%s:admissions", r.PrefixState, eventID)
+}
+
+// joinScript hands out the next position unless the user has one already.
+// KEYS: positions hash, length counter
+// ARGV: user id, ttl ms
+var joinScript = redis.NewScript(`
+local position = redis.call('HGET', KEYS[1], ARGV[1])
+if position then
+	return tonumber(position)
+end
+position = redis.call('INCR', KEYS[2])
+redis.call('HSET', KEYS[1], ARGV[1], position)
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[2])
+return position
+`)
+
+// admitScript moves the admission cursor forward by one position per elapsed interval.
+// Once everyone in the queue is admitted at most one admission is kept in reserve, so
+// the next user to arrive gets in right away without a burst of saved up admissions.
+// KEYS: length counter, cursor hash
+// ARGV: now ms, interval ms, ttl ms
+var admitScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local length = tonumber(redis.call('GET', KEYS[1]) or 0)
+local cursor = tonumber(redis.call('HGET', KEYS[2], 'position') or 0)
+local at = tonumber(redis.call('HGET', KEYS[2], 'at') or (now - interval))
+
+local admitted = math.floor((now - at) / interval)
+if cursor + admitted >= length then
+	admitted = math.max(length - cursor, 0)
+	cursor = length
+	at = math.max(at + admitted * interval, now - interval)
+else
+	cursor = cursor + admitted
+	at = at + admitted * interval
+end
+
+redis.call('HSET', KEYS[2], 'position', cursor, 'at', string.format('%.3f', at))
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+return cursor
+`)
+
+// admissionScript keeps the first expiry recorded for a user.
+// KEYS: admissions hash
+// ARGV: user id, expires at ms, ttl ms
+var admissionScript = redis.NewScript(`
+redis.call('HSETNX', KEYS[1], ARGV[1], ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return tonumber(redis.call('HGET', KEYS[1], ARGV[1]))
+`)
+
+func (r *RedisQueueManager) Join(ctx context.Context, eventID, userID uuid.UUID, ttl time.Duration) (int64, error) {
+	position, err := joinScript.Run(ctx, r.client,
+		[]string{r.positionsKey(eventID), r.lengthKey(eventID)},
+		userID.String(), ttl.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to join queue in Redis: %w", err)
+	}
+	return position, nil
+}
+
+func (r *RedisQueueManager) Position(ctx context.Context, eventID, userID uuid.UUID) (int64, error) {
+	position, err := r.client.HGet(ctx, r.positionsKey(eventID), userID.String()).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get queue position from Redis: %w", err)
+	}
+	return position, nil
+}
+
+func (r *RedisQueueManager) Admitted(ctx context.Context, eventID uuid.UUID, interval time.Duration, now time.Time, ttl time.Duration) (int64, error) {
+	cursor, err := admitScript.Run(ctx, r.client,
+		[]string{r.lengthKey(eventID), r.cursorKey(eventID)},
+		now.UnixMilli(), interval.Milliseconds(), ttl.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to advance queue in Redis: %w", err)
+	}
+	return cursor, nil
+}
+
+func (r *RedisQueueManager) Admission(ctx context.Context, eventID, userID uuid.UUID, expiresAt time.Time, ttl time.Duration) (time.Time, error) {
+	stored, err := admissionScript.Run(ctx, r.client,
+		[]string{r.admissionsKey(eventID)},
+		userID.String(), expiresAt.UnixMilli(), ttl.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to record admission in Redis: %w", err)
+	}
+	return time.UnixMilli(stored), nil
+}
+
+func (r *RedisQueueManager) Leave(ctx context.Context, eventID, userID uuid.UUID) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, r.positionsKey(eventID), userID.String())
+		pipe.HDel(ctx, r.admissionsKey(eventID), userID.String())
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to leave queue in Redis: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisQueueManager) Length(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	length, err := r.client.Get(ctx, r.lengthKey(eventID)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get queue length from Redis: %w", err)
+	}
+	return length, nil
+}
+
+func (r *RedisQueueManager) Reset(ctx context.Context, eventID uuid.UUID) error {
+	err := r.client.Del(ctx,
+		r.positionsKey(eventID), r.lengthKey(eventID), r.cursorKey(eventID), r.admissionsKey(eventID),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("failed to reset queue in Redis: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestQueueManager(t *testing.T) (*RedisQueueManager, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewQueueRedisManager(client), mr
+}
+
+func TestRedisQueueManager_Join(t *testing.T) {
+	ctx := context.Background()
+	manager, mr := newTestQueueManager(t)
+	eventID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+
+	position, err := manager.Join(ctx, eventID, first, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), position)
+
+	position, err = manager.Join(ctx, eventID, second, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), position)
+
+	position, err = manager.Join(ctx, eventID, first, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), position, "joining again keeps the place in the queue")
+
+	length, err := manager.Length(ctx, eventID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), length)
+	assert.True(t, mr.TTL(manager.positionsKey(eventID)) > 0)
+
+	require.NoError(t, manager.Reset(ctx, eventID))
+	length, err = manager.Length(ctx, eventID)
+	require.NoError(t, err)
+	assert.Zero(t, length)
+}
+
+func TestRedisQueueManager_JoinConcurrent(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestQueueManager(t)
+	eventID := uuid.New()
+
+	const users = 20
+	positions := make(chan int64, users)
+	var wg sync.WaitGroup
+	for range users {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			position, err := manager.Join(ctx, eventID, uuid.New(), time.Hour)
+			assert.NoError(t, err)
+			positions <- position
+		}()
+	}
+	wg.Wait()
+	close(positions)
+
+	seen := make(map[int64]bool)
+	for position := range positions {
+		assert.False(t, seen[position], "position %d handed out twice", position)
+		seen[position] = true
+	}
+	assert.Len(t, seen, users)
+}
+
+func TestRedisQueueManager_Admitted(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestQueueManager(t)
+	eventID := uuid.New()
+	interval := 10 * time.Second
+	start := time.Now()
+
+	admitted, err := manager.Admitted(ctx, eventID, interval, start, time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, admitted, "nobody is waiting")
+
+	for range 5 {
+		_, err := manager.Join(ctx, eventID, uuid.New(), time.Hour)
+		require.NoError(t, err)
+	}
+
+	admitted, err = manager.Admitted(ctx, eventID, interval, start.Add(time.Second), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), admitted, "one admission is kept in reserve while the queue is empty")
+
+	admitted, err = manager.Admitted(ctx, eventID, interval, start.Add(25*time.Second), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), admitted)
+
+	admitted, err = manager.Admitted(ctx, eventID, interval, start.Add(time.Hour), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), admitted, "never admits more users than joined")
+
+	_, err = manager.Join(ctx, eventID, uuid.New(), time.Hour)
+	require.NoError(t, err)
+	_, err = manager.Join(ctx, eventID, uuid.New(), time.Hour)
+	require.NoError(t, err)
+
+	admitted, err = manager.Admitted(ctx, eventID, interval, start.Add(time.Hour), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), admitted, "an idle queue does not save up a burst of admissions")
+}
+
+func TestRedisQueueManager_Admission(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestQueueManager(t)
+	eventID, userID := uuid.New(), uuid.New()
+	expiresAt := time.Now().Add(15 * time.Minute).Truncate(time.Millisecond)
+
+	stored, err := manager.Admission(ctx, eventID, userID, expiresAt, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, expiresAt.Equal(stored))
+
+	stored, err = manager.Admission(ctx, eventID, userID, expiresAt.Add(time.Minute), time.Hour)
+	require.NoError(t, err)
+	assert.True(t, expiresAt.Equal(stored), "polling again does not extend the admission")
+
+	_, err = manager.Join(ctx, eventID, uuid.New(), time.Hour)
+	require.NoError(t, err)
+	position, err := manager.Join(ctx, eventID, userID, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), position)
+
+	require.NoError(t, manager.Leave(ctx, eventID, userID))
+	position, err = manager.Position(ctx, eventID, userID)
+	require.NoError(t, err)
+	assert.Zero(t, position, "leaving gives up the place in the queue")
+
+	position, err = manager.Join(ctx, eventID, userID, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), position, "joining again after leaving goes to the end of the queue")
+	current, err := manager.Position(ctx, eventID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, position, current)
+
+	later := expiresAt.Add(time.Hour)
+	stored, err = manager.Admission(ctx, eventID, userID, later, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, later.Equal(stored), "leaving forgets the previous admission")
+}
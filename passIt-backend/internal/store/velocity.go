@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// VelocityStore counts recent activity in sliding time windows to spot bots, e.g. how
+// many holds a user or an IP address made in the last minutes
+type VelocityStore interface {
+	// Record adds the member to the window of the key at now and returns how many distinct
+	// members the window holds. Recording a member again moves it to now.
+	Record(ctx context.Context, key, member string, now time.Time, window time.Duration) (int64, error)
+	// Count returns how many distinct members the window of the key holds at now
+	Count(ctx context.Context, key string, now time.Time, window time.Duration) (int64, error)
+}
+
+type RedisVelocityManager struct {
+	client      *redis.Client
+	PrefixState string
+}
+
+func NewVelocityRedisManager(rds *redis.Client) *RedisVelocityManager {
+	return &RedisVelocityManager{
+		client:      rds,
+		PrefixState: "velocity",
+	}
+}
+
+// Ensure RedisVelocityManager implements VelocityStore
+var _ VelocityStore = (*RedisVelocityManager)(nil)
+
+// windowKey is a sorted set of members scored by the time they were last recorded at in milliseconds
+func (r *RedisVelocityManager) windowKey(key string) string {
+	return fmt.Sprintf("%s:%s", r.PrefixState, key)
+}
+
+// recordScript adds a member, drops the members older than the window and counts the rest.
+// KEYS: window sorted set
+// ARGV: member, now ms, window ms
+var recordScript = redis.NewScript(`
+local now = tonumber(ARGV[2])
+local window = tonumber(ARGV[3])
+redis.call('ZADD', KEYS[1], now, ARGV[1])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+redis.call('PEXPIRE', KEYS[1], window)
+return redis.call('ZCARD', KEYS[1])
+`)
+
+func (r *RedisVelocityManager) Record(ctx context.Context, key, member string, now time.Time, window time.Duration) (int64, error) {
+	count, err := recordScript.Run(ctx, r.client,
+		[]string{r.windowKey(key)},
+		member, now.UnixMilli(), window.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to record activity in Redis: %w", err)
+	}
+	return count, nil
+}
+
+func (r *RedisVelocityManager) Count(ctx context.Context, key string, now time.Time, window time.Duration) (int64, error) {
+	count, err := r.client.ZCount(ctx, r.windowKey(key),
+		fmt.Sprintf("(%d", now.Add(-window).UnixMilli()), fmt.Sprintf("%d", now.UnixMilli()),
+	).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count activity in Redis: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVelocityManager(t *testing.T) (*RedisVelocityManager, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewVelocityRedisManager(client), mr
+}
+
+func TestRedisVelocityManager_SlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	manager, mr := newTestVelocityManager(t)
+	window := 10 * time.Minute
+	now := time.Now()
+
+	count, err := manager.Record(ctx, "user:1", "hold-1", now, window)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, err = manager.Record(ctx, "user:1", "hold-2", now.Add(5*time.Minute), window)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.True(t, mr.TTL(manager.windowKey("user:1")) > 0)
+
+	count, err = manager.Count(ctx, "user:1", now.Add(5*time.Minute), window)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = manager.Count(ctx, "user:1", now.Add(12*time.Minute), window)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count, "the first hold left the window")
+
+	count, err = manager.Record(ctx, "user:1", "hold-3", now.Add(12*time.Minute), window)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = manager.Count(ctx, "user:2", now, window)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestRedisVelocityManager_DistinctMembers(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newTestVelocityManager(t)
+	window := 10 * time.Minute
+	now := time.Now()
+
+	for i, member := range []string{"alice", "bob", "alice", "alice"} {
+		_, err := manager.Record(ctx, "accounts:10.0.0.1", member, now.Add(time.Duration(i)*time.Minute), window)
+		require.NoError(t, err)
+	}
+
+	count, err := manager.Count(ctx, "accounts:10.0.0.1", now.Add(3*time.Minute), window)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = manager.Count(ctx, "accounts:10.0.0.1", now.Add(11*time.Minute+30*time.Second), window)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count, "recording alice again kept her in the window")
+}
@@ -0,0 +1,27 @@
+package tickets
+
+import (
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	// DefaultQRSize is the width and height of QR code images in pixels
+	DefaultQRSize = 256
+	MinQRSize     = 128
+	MaxQRSize     = 1024
+)
+
+// QRCode renders a ticket token as a PNG image. Medium error correction keeps the
+// code readable from a cracked phone screen or a creased printout.
+func QRCode(token string, size int) ([]byte, error) {
+	if size < MinQRSize || size > MaxQRSize {
+		size = DefaultQRSize
+	}
+	png, err := qrcode.Encode(token, qrcode.Medium, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	return png, nil
+}
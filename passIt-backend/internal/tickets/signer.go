@@ -0,0 +1,146 @@
+package tickets
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Config struct {
+	SigningKey string // base64 encoded Ed25519 seed; a temporary key is generated when empty
+	Production bool   // the signing key is required in production
+}
+
+// TokenPrefix marks the payload format so it can change without breaking scanners
+const TokenPrefix = "PT1"
+
+var (
+	ErrInvalidToken       = errors.New("invalid ticket token")
+	ErrInvalidSigningKey  = errors.New("invalid ticket signing key")
+	ErrSigningKeyRequired = errors.New("TICKET_SIGNING_KEY is required in production")
+)
+
+// Claims are the ticket details carried by a QR code
+type Claims struct {
+	TicketID uuid.UUID `json:"tid"`
+	EventID  uuid.UUID `json:"eid"`
+	// Version changes whenever the ticket is reissued, older QR codes stop being valid
+	Version  int   `json:"v"`
+	IssuedAt int64 `json:"iat"`
+}
+
+// Signer creates and verifies tamper-proof ticket tokens with an Ed25519 key.
+// Scanners only need the public key to verify tickets offline.
+type Signer struct {
+	private ed25519.PrivateKey
+	public  ed25519.PublicKey
+	keyID   string
+}
+
+// NewSigner creates a signer from a 32 byte Ed25519 seed
+func NewSigner(seed []byte) (*Signer, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, ErrInvalidSigningKey
+	}
+	private := ed25519.NewKeyFromSeed(seed)
+	public := private.Public().(ed25519.PublicKey)
+	sum := sha256.Sum256(public)
+	return &Signer{
+		private: private,
+		public:  public,
+		keyID:   hex.EncodeToString(sum[:8]),
+	}, nil
+}
+
+// New creates the signer configured for the application
+func New(config *Config) (*Signer, error) {
+	if config.SigningKey == "" {
+		if config.Production {
+			return nil, ErrSigningKeyRequired
+		}
+		log.Println("Warning: TICKET_SIGNING_KEY not set, issued QR codes stop being valid on restart")
+		seed := make([]byte, ed25519.SeedSize)
+		if _, err := rand.Read(seed); err != nil {
+			return nil, fmt.Errorf("failed to generate ticket signing key: %w", err)
+		}
+		return NewSigner(seed)
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(config.SigningKey)
+	if err != nil {
+		return nil, ErrInvalidSigningKey
+	}
+	return NewSigner(seed)
+}
+
+// PublicKey returns the key scanners verify tokens with
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.public
+}
+
+// KeyID identifies the signing key so scanners can tell when it was rotated
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// Sign encodes the claims as "PT1.<payload>.<signature>" with base64url parts
+func (s *Signer) Sign(claims Claims) (string, error) {
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode ticket claims: %w", err)
+	}
+	signed := TokenPrefix + "." + base64.RawURLEncoding.EncodeToString(body)
+	signature := ed25519.Sign(s.private, []byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Verify checks the signature of a token and returns its claims
+func (s *Signer) Verify(token string) (*Claims, error) {
+	return VerifyToken(s.public, token)
+}
+
+// VerifyToken checks a token against a public key
+func VerifyToken(public ed25519.PublicKey, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != TokenPrefix {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !ed25519.Verify(public, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrInvalidToken
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(body, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+// NewClaims builds the claims of a ticket version issued now
+func NewClaims(ticketID, eventID uuid.UUID, version int) Claims {
+	return Claims{
+		TicketID: ticketID,
+		EventID:  eventID,
+		Version:  version,
+		IssuedAt: time.Now().Unix(),
+	}
+}
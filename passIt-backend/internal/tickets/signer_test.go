@@ -0,0 +1,92 @@
+package tickets
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSigner(t *testing.T, seed byte) *Signer {
+	signer, err := NewSigner(bytes.Repeat([]byte{seed}, ed25519.SeedSize))
+	require.NoError(t, err)
+	return signer
+}
+
+func TestSigner_SignAndVerify(t *testing.T) {
+	signer := testSigner(t, 1)
+	claims := NewClaims(uuid.New(), uuid.New(), 2)
+
+	token, err := signer.Sign(claims)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, TokenPrefix+"."))
+
+	verified, err := signer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, claims, *verified)
+
+	offline, err := VerifyToken(signer.PublicKey(), token)
+	require.NoError(t, err)
+	assert.Equal(t, claims.TicketID, offline.TicketID)
+}
+
+func TestSigner_RejectsTamperedTokens(t *testing.T) {
+	signer := testSigner(t, 1)
+	token, err := signer.Sign(NewClaims(uuid.New(), uuid.New(), 1))
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+
+	forged, err := testSigner(t, 2).Sign(NewClaims(uuid.New(), uuid.New(), 1))
+	require.NoError(t, err)
+	forgedParts := strings.Split(forged, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"Empty", ""},
+		{"Missing signature", parts[0] + "." + parts[1]},
+		{"Wrong prefix", "PT0." + parts[1] + "." + parts[2]},
+		{"Swapped payload", parts[0] + "." + forgedParts[1] + "." + parts[2]},
+		{"Other key", forged},
+		{"Bad encoding", parts[0] + "." + parts[1] + ".!!!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.Verify(tt.token)
+			assert.Equal(t, ErrInvalidToken, err)
+		})
+	}
+}
+
+func TestNew_SigningKey(t *testing.T) {
+	seed := bytes.Repeat([]byte{7}, ed25519.SeedSize)
+	signer, err := New(&Config{SigningKey: base64.StdEncoding.EncodeToString(seed)})
+	require.NoError(t, err)
+	assert.Equal(t, testSigner(t, 7).KeyID(), signer.KeyID())
+
+	_, err = New(&Config{SigningKey: "not base64"})
+	assert.Equal(t, ErrInvalidSigningKey, err)
+
+	_, err = New(&Config{SigningKey: base64.StdEncoding.EncodeToString([]byte("short"))})
+	assert.Equal(t, ErrInvalidSigningKey, err)
+
+	generated, err := New(&Config{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, generated.KeyID())
+
+	_, err = New(&Config{Production: true})
+	assert.Equal(t, ErrSigningKeyRequired, err, "a generated key would invalidate tickets on restart")
+}
+
+func TestQRCode(t *testing.T) {
+	png, err := QRCode("PT1.payload.signature", 0)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(png, []byte("\x89PNG")))
+}
@@ -0,0 +1,137 @@
+package waitingroom
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Config struct {
+	SigningKey string // base64 encoded secret of at least 32 bytes; a temporary key is generated when empty
+	Production bool   // the signing key is required in production
+}
+
+// TokenPrefix marks the payload format so it can change without breaking clients
+const TokenPrefix = "WR1"
+
+// minKeySize is the shortest signing key accepted, in bytes
+const minKeySize = 32
+
+var (
+	ErrInvalidToken       = errors.New("invalid waiting room token")
+	ErrExpiredToken       = errors.New("waiting room token has expired")
+	ErrInvalidSigningKey  = errors.New("invalid waiting room signing key")
+	ErrSigningKeyRequired = errors.New("QUEUE_SIGNING_KEY is required in production")
+)
+
+// TokenKind tells a place in the queue apart from an admission to buy
+type TokenKind string
+
+const (
+	// KindQueue proves a place in the queue of an event, it is used to poll for admission
+	KindQueue TokenKind = "queue"
+	// KindAdmission lets its holder reserve and check out tickets of the event
+	KindAdmission TokenKind = "admission"
+)
+
+// Claims are the details carried by a waiting room token
+type Claims struct {
+	Kind    TokenKind `json:"k"`
+	EventID uuid.UUID `json:"eid"`
+	UserID  uuid.UUID `json:"uid"`
+	// Position is the place of the holder in the queue, counted from 1 in order of arrival
+	Position  int64 `json:"pos,omitempty"`
+	ExpiresAt int64 `json:"exp"`
+}
+
+// Signer creates and verifies waiting room tokens with an HMAC key. Every instance of
+// the API must share the key so tokens are accepted whichever instance serves a request.
+type Signer struct {
+	key []byte
+}
+
+// NewSigner creates a signer from a secret key
+func NewSigner(key []byte) (*Signer, error) {
+	if len(key) < minKeySize {
+		return nil, ErrInvalidSigningKey
+	}
+	return &Signer{key: key}, nil
+}
+
+// New creates the signer configured for the application
+func New(config *Config) (*Signer, error) {
+	if config.SigningKey == "" {
+		if config.Production {
+			return nil, ErrSigningKeyRequired
+		}
+		log.Println("Warning: QUEUE_SIGNING_KEY not set, queue tokens stop being valid on restart")
+		key := make([]byte, minKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate waiting room signing key: %w", err)
+		}
+		return NewSigner(key)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(config.SigningKey)
+	if err != nil {
+		return nil, ErrInvalidSigningKey
+	}
+	return NewSigner(key)
+}
+
+// Sign encodes the claims as "WR1.<payload>.<signature>" with base64url parts
+func (s *Signer) Sign(claims Claims) (string, error) {
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode waiting room claims: %w", err)
+	}
+	signed := TokenPrefix + "." + base64.RawURLEncoding.EncodeToString(body)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(s.mac(signed)), nil
+}
+
+// Verify checks the signature and expiry of a token of the given kind and returns its claims
+func (s *Signer) Verify(token string, kind TokenKind, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != TokenPrefix {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !hmac.Equal(signature, s.mac(parts[0]+"."+parts[1])) {
+		return nil, ErrInvalidToken
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(body, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Kind != kind {
+		return nil, ErrInvalidToken
+	}
+	if !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, ErrExpiredToken
+	}
+	return &claims, nil
+}
+
+func (s *Signer) mac(signed string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(signed))
+	return mac.Sum(nil)
+}
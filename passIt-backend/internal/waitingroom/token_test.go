@@ -0,0 +1,90 @@
+package waitingroom
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSigner(t *testing.T, seed byte) *Signer {
+	signer, err := NewSigner(bytes.Repeat([]byte{seed}, minKeySize))
+	require.NoError(t, err)
+	return signer
+}
+
+func TestSigner_SignAndVerify(t *testing.T) {
+	signer := testSigner(t, 1)
+	now := time.Now()
+	claims := Claims{
+		Kind:      KindQueue,
+		EventID:   uuid.New(),
+		UserID:    uuid.New(),
+		Position:  42,
+		ExpiresAt: now.Add(time.Hour).Unix(),
+	}
+
+	token, err := signer.Sign(claims)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, TokenPrefix+"."))
+
+	verified, err := signer.Verify(token, KindQueue, now)
+	require.NoError(t, err)
+	assert.Equal(t, claims, *verified)
+
+	_, err = signer.Verify(token, KindAdmission, now)
+	assert.ErrorIs(t, err, ErrInvalidToken, "a place in the queue is not an admission")
+
+	_, err = signer.Verify(token, KindQueue, now.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
+
+func TestSigner_RejectsTamperedTokens(t *testing.T) {
+	signer := testSigner(t, 1)
+	now := time.Now()
+	token, err := signer.Sign(Claims{Kind: KindAdmission, EventID: uuid.New(), UserID: uuid.New(), ExpiresAt: now.Add(time.Hour).Unix()})
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+
+	forged := Claims{Kind: KindAdmission, EventID: uuid.New(), UserID: uuid.New(), ExpiresAt: now.Add(time.Hour).Unix()}
+	forgedToken, err := signer.Sign(forged)
+	require.NoError(t, err)
+	swapped := parts[0] + "." + strings.Split(forgedToken, ".")[1] + "." + parts[2]
+
+	otherKey, err := testSigner(t, 2).Sign(forged)
+	require.NoError(t, err)
+
+	for name, tampered := range map[string]string{
+		"Swapped payload":  swapped,
+		"Other key":        otherKey,
+		"Wrong prefix":     "PT1." + parts[1] + "." + parts[2],
+		"Missing part":     parts[0] + "." + parts[1],
+		"Invalid encoding": parts[0] + "." + parts[1] + ".!!!",
+		"Empty":            "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := signer.Verify(tampered, KindAdmission, now)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
+
+func TestNew_SigningKey(t *testing.T) {
+	signer, err := New(&Config{})
+	require.NoError(t, err, "a temporary key is generated in development")
+	assert.Len(t, signer.key, minKeySize)
+
+	_, err = New(&Config{Production: true})
+	assert.ErrorIs(t, err, ErrSigningKeyRequired, "instances would not accept each other's tokens")
+
+	_, err = New(&Config{SigningKey: "not base64"})
+	assert.ErrorIs(t, err, ErrInvalidSigningKey)
+
+	_, err = New(&Config{SigningKey: base64.StdEncoding.EncodeToString([]byte("too short"))})
+	assert.ErrorIs(t, err, ErrInvalidSigningKey)
+}